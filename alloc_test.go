@@ -0,0 +1,43 @@
+package ring
+
+import (
+	"testing"
+)
+
+// verifyAllocBudget is the maximum number of allocations made by Verify: a
+// fixed overhead plus a per-member cost. The per-member cost comes from the
+// go-dleq curve interfaces, which return newly allocated points and scalars
+// from each operation and encoding; the budgets are set to the current counts
+// so that any additional allocation in the verification loop fails the test.
+var verifyAllocBudget = map[CurveID]struct{ base, perMember float64 }{
+	CurveSecp256k1: {base: 12, perMember: 21},
+	CurveEd25519:   {base: 12, perMember: 16},
+}
+
+func TestVerify_Allocs(t *testing.T) {
+	if raceEnabled || testing.CoverMode() != "" {
+		t.Skip("allocation counts differ under the race detector and coverage")
+	}
+
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		budget := verifyAllocBudget[CurveIDOf(curve)]
+		for size := 2; size <= 128; size *= 2 {
+			sig := createSigWithCurve(t, curve, size, size/2)
+
+			// the first verification computes the ring's H_p values
+			if !sig.Verify(testMsg) {
+				t.Fatal("failed to verify signature")
+			}
+
+			allocs := testing.AllocsPerRun(3, func() {
+				sig.Verify(testMsg)
+			})
+
+			max := budget.base + budget.perMember*float64(size)
+			if allocs > max {
+				t.Errorf("%s: Verify with ring size %d made %v allocations, want at most %v",
+					CurveIDOf(curve), size, allocs, max)
+			}
+		}
+	}
+}
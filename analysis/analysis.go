@@ -0,0 +1,154 @@
+// Package analysis builds graphs of how ring signatures relate, for
+// investigators and researchers analyzing usage patterns in archived
+// signatures, eg. signers reusing the same rings.
+//
+// LinkGraph returns a graph whose nodes are the key images of the signatures,
+// ie. their signers, and whose edges connect signers that used the same rings.
+// Signatures by the same signer are linked by their key image, so they're
+// grouped in a single node. Graphs are exported as JSON, with encoding/json,
+// or in the DOT language of Graphviz with WriteDOT:
+//
+//	g, err := analysis.LinkGraph(sigs)
+//	...
+//	err = g.WriteDOT(f) // dot -Tsvg links.dot > links.svg
+package analysis
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// Node is a key image, ie. a signer.
+type Node struct {
+	// ID is the hex encoding of the curve ID (see ring.CurveID) followed by
+	// the normalized key image (see ring.NormalizeKeyImage).
+	ID string `json:"id"`
+	// Curve is the name of the signatures' curve.
+	Curve string `json:"curve"`
+	// Signatures are the indices of the signatures with this key image, in
+	// the slice passed to LinkGraph.
+	Signatures []int `json:"signatures"`
+	// Rings are the hex-encoded hashes (see ring.Ring.Hash) of the rings of
+	// these signatures, without duplicates, sorted.
+	Rings []string `json:"rings"`
+}
+
+// Edge connects two signers that signed with the same rings.
+type Edge struct {
+	// From and To are the IDs of the nodes; From is the one that appears
+	// first in the graph.
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Rings are the hex-encoded hashes of the rings both signers used,
+	// sorted.
+	Rings []string `json:"rings"`
+}
+
+// Graph is the linkage graph of a set of signatures.
+type Graph struct {
+	// Nodes are in order of the first signature of each signer.
+	Nodes []Node `json:"nodes"`
+	// Edges are in order of their From node, then of their To node.
+	Edges []Edge `json:"edges"`
+}
+
+// LinkGraph returns the linkage graph of the signatures, which may be over
+// different curves. They aren't verified, so callers analyzing untrusted
+// archives should verify them beforehand.
+func LinkGraph(sigs []*ring.RingSig) (*Graph, error) {
+	g := new(Graph)
+	nodeIdx := make(map[string]int)       // node ID -> index in g.Nodes
+	ringSigners := make(map[string][]int) // ring hash -> node indices
+	var ringOrder []string
+
+	for i, sig := range sigs {
+		if sig == nil || sig.Ring() == nil {
+			return nil, fmt.Errorf("signature %d is nil", i)
+		}
+		curveID := ring.CurveIDOf(sig.Ring().Curve())
+		if curveID == ring.CurveUnknown {
+			return nil, fmt.Errorf("signature %d: unsupported curve", i)
+		}
+		// the curve is part of the ID, as images on different curves are
+		// different signers even if their encodings were the same
+		id := hex.EncodeToString(append([]byte{byte(curveID)}, ring.NormalizeKeyImage(sig.KeyImage()).Encode()...))
+		ringHash := sig.Ring().Hash()
+		ringID := hex.EncodeToString(ringHash[:])
+
+		n, ok := nodeIdx[id]
+		if !ok {
+			n = len(g.Nodes)
+			nodeIdx[id] = n
+			g.Nodes = append(g.Nodes, Node{ID: id, Curve: curveID.String()})
+		}
+		node := &g.Nodes[n]
+		node.Signatures = append(node.Signatures, i)
+		if !slices.Contains(node.Rings, ringID) {
+			node.Rings = append(node.Rings, ringID)
+			if _, ok := ringSigners[ringID]; !ok {
+				ringOrder = append(ringOrder, ringID)
+			}
+			ringSigners[ringID] = append(ringSigners[ringID], n)
+		}
+	}
+
+	// shared[{a, b}] are the rings signed by both nodes a < b
+	type pair struct{ a, b int }
+	shared := make(map[pair][]string)
+	for _, ringID := range ringOrder {
+		signers := ringSigners[ringID]
+		for x, a := range signers {
+			for _, b := range signers[x+1:] {
+				p := pair{min(a, b), max(a, b)}
+				shared[p] = append(shared[p], ringID)
+			}
+		}
+	}
+	pairs := make([]pair, 0, len(shared))
+	for p := range shared {
+		pairs = append(pairs, p)
+	}
+	slices.SortFunc(pairs, func(p, q pair) int {
+		if p.a != q.a {
+			return p.a - q.a
+		}
+		return p.b - q.b
+	})
+	for _, p := range pairs {
+		rings := shared[p]
+		slices.Sort(rings)
+		g.Edges = append(g.Edges, Edge{From: g.Nodes[p.a].ID, To: g.Nodes[p.b].ID, Rings: rings})
+	}
+
+	for i := range g.Nodes {
+		slices.Sort(g.Nodes[i].Rings)
+	}
+	return g, nil
+}
+
+// shortIDLen is the number of hex digits of the IDs shown in DOT labels.
+const shortIDLen = 16
+
+// WriteDOT writes the graph in the DOT language, as an undirected graph whose
+// nodes are labeled with the beginning of their ID and their number of
+// signatures, and whose edges are labeled and weighted with their number of
+// shared rings.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "graph links {")
+	fmt.Fprintln(bw, "\tnode [shape=box];")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(bw, "\t%q [label=\"%s…\\n%s, %d signatures\"];\n",
+			n.ID, n.ID[:min(len(n.ID), shortIDLen)], n.Curve, len(n.Signatures))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(bw, "\t%q -- %q [label=\"%d rings\", weight=%d];\n", e.From, e.To, len(e.Rings), len(e.Rings))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
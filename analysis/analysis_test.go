@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+func TestLinkGraph(t *testing.T) {
+	curve := ring.Ed25519()
+	keys := make([]ring.Scalar, 4)
+	pubkeys := make([]ring.Point, 4)
+	for i := range keys {
+		keys[i] = curve.NewRandomScalar()
+		pubkeys[i] = curve.ScalarBaseMul(keys[i])
+	}
+	ringA, err := ring.NewFixedKeyRingFromPublicKeys(curve, pubkeys)
+	require.NoError(t, err)
+	ringB, err := ring.NewFixedKeyRingFromPublicKeys(curve, pubkeys[1:])
+	require.NoError(t, err)
+
+	sign := func(r *ring.Ring, signer int, msg byte) *ring.RingSig {
+		sig, err := r.Sign([32]byte{msg}, keys[signer])
+		require.NoError(t, err)
+		return sig
+	}
+	sigs := []*ring.RingSig{
+		sign(ringA, 0, 1),
+		sign(ringA, 1, 2),
+		sign(ringB, 1, 3),
+		sign(ringB, 2, 4),
+		sign(ringA, 2, 5),
+		sign(ringA, 0, 6),
+	}
+	// a signature over another curve is another signer
+	secpKey := ring.Secp256k1().NewRandomScalar()
+	secpRing, err := ring.NewKeyRing(ring.Secp256k1(), 3, secpKey, 0)
+	require.NoError(t, err)
+	secpSig, err := secpRing.Sign([32]byte{7}, secpKey)
+	require.NoError(t, err)
+	sigs = append(sigs, secpSig)
+
+	g, err := LinkGraph(sigs)
+	require.NoError(t, err)
+	require.Len(t, g.Nodes, 4)
+	require.Equal(t, []int{0, 5}, g.Nodes[0].Signatures)
+	require.Equal(t, []int{1, 2}, g.Nodes[1].Signatures)
+	require.Equal(t, []int{3, 4}, g.Nodes[2].Signatures)
+	require.Equal(t, "ed25519", g.Nodes[0].Curve)
+	require.Equal(t, []int{6}, g.Nodes[3].Signatures)
+	require.Equal(t, "secp256k1", g.Nodes[3].Curve)
+
+	hashA, hashB := ringA.Hash(), ringB.Hash()
+	require.Len(t, g.Nodes[0].Rings, 1)
+	require.Len(t, g.Nodes[1].Rings, 2)
+
+	// signers 1 and 2 shared both rings, signer 0 only ring A with each
+	require.Len(t, g.Edges, 3)
+	require.Equal(t, g.Nodes[0].ID, g.Edges[0].From)
+	require.Equal(t, g.Nodes[1].ID, g.Edges[0].To)
+	require.Len(t, g.Edges[0].Rings, 1)
+	require.Equal(t, g.Nodes[0].ID, g.Edges[1].From)
+	require.Equal(t, g.Nodes[2].ID, g.Edges[1].To)
+	require.Equal(t, g.Nodes[1].ID, g.Edges[2].From)
+	require.Equal(t, g.Nodes[2].ID, g.Edges[2].To)
+	require.ElementsMatch(t, []string{hex.EncodeToString(hashA[:]), hex.EncodeToString(hashB[:])}, g.Edges[2].Rings)
+
+	enc, err := json.Marshal(g)
+	require.NoError(t, err)
+	var decoded Graph
+	require.NoError(t, json.Unmarshal(enc, &decoded))
+	require.Equal(t, *g, decoded)
+
+	var dot bytes.Buffer
+	require.NoError(t, g.WriteDOT(&dot))
+	require.True(t, strings.HasPrefix(dot.String(), "graph links {\n"))
+	require.Contains(t, dot.String(), `"`+g.Nodes[1].ID+`" -- "`+g.Nodes[2].ID+`" [label="2 rings", weight=2];`)
+	require.Contains(t, dot.String(), "ed25519, 2 signatures")
+
+	_, err = LinkGraph([]*ring.RingSig{sigs[0], nil})
+	require.ErrorContains(t, err, "signature 1")
+}
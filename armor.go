@@ -0,0 +1,110 @@
+package ring
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ArmorBlockType is the type of armored ring signatures, which appears in their
+// "-----BEGIN RING SIGNATURE-----" line.
+const ArmorBlockType = "RING SIGNATURE"
+
+// Headers of armored ring signatures.
+const (
+	ArmorHeaderCurve    = "Curve"
+	ArmorHeaderRingHash = "Ring-Hash"
+	ArmorHeaderScope    = "Scope"
+)
+
+// Armor encodes the signature in a PEM-style text format, which can be pasted
+// into tickets, emails and commit trailers:
+//
+//	-----BEGIN RING SIGNATURE-----
+//	Curve: secp256k1
+//	Ring-Hash: 5f3a...
+//	Scope: release v1.2.0
+//
+//	<base64 of the serialized signature>
+//	-----END RING SIGNATURE-----
+//
+// The Ring-Hash header is the hex-encoded Ring.Hash of the signature's ring.
+// The Scope header is a free-form description of what was signed, which is
+// omitted if empty. The headers are not signed: the scope is informational,
+// unless it is bound into the signed message by the application.
+func (r *RingSig) Armor(scope string) ([]byte, error) {
+	if strings.ContainsAny(scope, "\r\n") {
+		return nil, errors.New("scope must be a single line")
+	}
+
+	id := CurveIDOf(r.ring.curve)
+	if id == CurveUnknown {
+		return nil, errors.New("unsupported curve")
+	}
+
+	enc, err := r.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	ringHash := r.ring.Hash()
+	headers := map[string]string{
+		ArmorHeaderCurve:    id.String(),
+		ArmorHeaderRingHash: hex.EncodeToString(ringHash[:]),
+	}
+	if scope = strings.TrimSpace(scope); scope != "" {
+		headers[ArmorHeaderScope] = scope
+	}
+
+	var buf bytes.Buffer
+	if err := pem.Encode(&buf, &pem.Block{
+		Type:    ArmorBlockType,
+		Headers: headers,
+		Bytes:   enc,
+	}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ParseArmored decodes the first armored ring signature in data, as created by
+// Armor, and returns it along with its scope. Text before and after the
+// armored block is ignored. It checks that the Curve and Ring-Hash headers
+// match the signature, but doesn't verify it.
+func ParseArmored(data []byte) (sig *RingSig, scope string, err error) {
+	var block *pem.Block
+	for {
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, "", errors.New("no armored ring signature found")
+		}
+		if block.Type == ArmorBlockType {
+			break
+		}
+	}
+
+	id, err := ParseCurveID(block.Headers[ArmorHeaderCurve])
+	if err != nil {
+		return nil, "", err
+	}
+	curve, err := id.Curve()
+	if err != nil {
+		return nil, "", err
+	}
+
+	sig = new(RingSig)
+	if err := sig.Deserialize(curve, block.Bytes); err != nil {
+		return nil, "", fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	ringHash := sig.ring.Hash()
+	if block.Headers[ArmorHeaderRingHash] != hex.EncodeToString(ringHash[:]) {
+		return nil, "", errors.New("ring hash header does not match the signature's ring")
+	}
+
+	return sig, block.Headers[ArmorHeaderScope], nil
+}
@@ -0,0 +1,66 @@
+package ring
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArmor(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		sig := createSigWithCurve(t, curve, 5, 2)
+
+		armored, err := sig.Armor("release v1.2.0")
+		require.NoError(t, err)
+		require.True(t, bytes.HasPrefix(armored, []byte("-----BEGIN RING SIGNATURE-----\n")))
+		require.Contains(t, string(armored), "Curve: "+CurveIDOf(curve).String()+"\n")
+		ringHash := sig.Ring().Hash()
+		require.Contains(t, string(armored), "Ring-Hash: "+hex.EncodeToString(ringHash[:])+"\n")
+		require.Contains(t, string(armored), "Scope: release v1.2.0\n")
+
+		// surrounding text, eg. in an email, is ignored
+		text := append([]byte("Signed-off-by: one of the maintainers\n\n"), armored...)
+		text = append(text, "\nthanks!\n"...)
+		parsed, scope, err := ParseArmored(text)
+		require.NoError(t, err)
+		require.Equal(t, "release v1.2.0", scope)
+		require.True(t, parsed.Equal(sig))
+		require.True(t, parsed.Verify(testMsg))
+	}
+}
+
+func TestArmor_NoScope(t *testing.T) {
+	sig := createSigWithCurve(t, Ed25519(), 2, 0)
+	armored, err := sig.Armor("")
+	require.NoError(t, err)
+	require.NotContains(t, string(armored), ArmorHeaderScope)
+
+	_, scope, err := ParseArmored(armored)
+	require.NoError(t, err)
+	require.Empty(t, scope)
+
+	_, err = sig.Armor("two\nlines")
+	require.Error(t, err)
+}
+
+func TestParseArmored_Errors(t *testing.T) {
+	sig := createSigWithCurve(t, Secp256k1(), 3, 1)
+	armored, err := sig.Armor("x")
+	require.NoError(t, err)
+
+	_, _, err = ParseArmored([]byte("no signature here"))
+	require.Error(t, err)
+
+	// mismatched headers
+	wrongCurve := bytes.Replace(armored, []byte("Curve: secp256k1"), []byte("Curve: ed25519"), 1)
+	_, _, err = ParseArmored(wrongCurve)
+	require.Error(t, err)
+
+	ringHash := sig.Ring().Hash()
+	h := hex.EncodeToString(ringHash[:])
+	wrongHash := bytes.Replace(armored, []byte(h), []byte(h[:63]+"x"), 1)
+	_, _, err = ParseArmored(wrongHash)
+	require.Error(t, err)
+}
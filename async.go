@@ -0,0 +1,146 @@
+package ring
+
+import (
+	"context"
+	"errors"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// progressInterval is the number of ring members processed between calls to
+// the ProgressFunc of SignAsync and VerifyAsync, and checks for cancellation.
+const progressInterval = 16
+
+// ProgressFunc is called by SignAsync and VerifyAsync with the number of ring
+// members processed so far and the size of the ring, every few members and
+// once the operation completes. It's called from the goroutine running the
+// operation, which it delays, so it should only hand the values over, eg. to
+// a UI's event loop.
+type ProgressFunc func(done, total int)
+
+// Future is the result of an operation running in the background, such as
+// SignAsync, for UIs that show progress bars while signing or verifying with
+// large rings instead of blocking.
+type Future[T any] struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	value  T
+	err    error
+}
+
+// startFuture runs fn in a new goroutine, with a context canceled by
+// Future.Cancel.
+func startFuture[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) *Future[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	f := &Future[T]{
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+	go func() {
+		defer close(f.done)
+		defer cancel()
+		if err := ctx.Err(); err != nil {
+			f.err = err
+			return
+		}
+		f.value, f.err = fn(ctx)
+	}()
+	return f
+}
+
+// Done returns a channel that's closed when the operation completes.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait waits for the operation to complete and returns its result. If it was
+// canceled, the error is that of the context, ie. context.Canceled or
+// context.DeadlineExceeded.
+func (f *Future[T]) Wait() (T, error) {
+	<-f.done
+	return f.value, f.err
+}
+
+// Cancel cancels the operation, unless it already completed. It returns
+// without waiting for the operation to stop; Wait does.
+func (f *Future[T]) Cancel() {
+	f.cancel()
+}
+
+// SignAsync signs the message like Sign, in a new goroutine. Signing stops
+// when ctx is done or the future is canceled. The progress function, if not
+// nil, is called with the number of ring members processed.
+func (r *Ring) SignAsync(ctx context.Context, m [32]byte, privKey types.Scalar, progress ProgressFunc, opts ...SignOption) *Future[*RingSig] {
+	return startFuture(ctx, func(ctx context.Context) (*RingSig, error) {
+		size := len(r.pubkeys)
+		opts = append(opts[:len(opts):len(opts)], func(o *signOptions) {
+			o.progress = func(done int) error {
+				return reportProgress(ctx, progress, done, size)
+			}
+		})
+
+		sig, err := r.Sign(m, privKey, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if progress != nil {
+			progress(size, size)
+		}
+		return sig, nil
+	})
+}
+
+// VerifyAsync verifies the signature for the message like VerifyWithOpts, in
+// a new goroutine. The options may be nil. Verification stops when ctx is
+// done or the future is canceled. The progress function, if not nil, is called
+// with the number of ring members processed.
+//
+// The future's value is true if the signature is valid, in which case the
+// error is nil.
+func (sig *RingSig) VerifyAsync(ctx context.Context, m [32]byte, opts *VerifyOpts, progress ProgressFunc) *Future[bool] {
+	return startFuture(ctx, func(ctx context.Context) (bool, error) {
+		size := len(sig.ring.pubkeys)
+		withProgress := VerifyOpts{}
+		if opts != nil {
+			withProgress = *opts
+		}
+		policy := withProgress.Policy
+		withProgress.Policy = func(i int, pub types.Point) error {
+			if err := reportProgress(ctx, progress, i, size); err != nil {
+				return err
+			}
+			if policy != nil {
+				return policy(i, pub)
+			}
+			return nil
+		}
+
+		err := sig.VerifyWithOpts(m, &withProgress)
+		if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+			// the policy's error is wrapped with the member's index
+			return false, ctxErr
+		}
+		if err != nil {
+			return false, err
+		}
+		if progress != nil {
+			progress(size, size)
+		}
+		return true, nil
+	})
+}
+
+// reportProgress checks for cancellation and calls progress every
+// progressInterval members.
+func reportProgress(ctx context.Context, progress ProgressFunc, done, total int) error {
+	if done == 0 || done%progressInterval != 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if progress != nil {
+		progress(done, total)
+	}
+	return nil
+}
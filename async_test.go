@@ -0,0 +1,104 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAsync(t *testing.T) {
+	const size = 100
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, size, privKey, 40)
+	require.NoError(t, err)
+
+	var reported [][2]int
+	f := keyring.SignAsync(context.Background(), testMsg, privKey, func(done, total int) {
+		reported = append(reported, [2]int{done, total})
+	}, WithChainID(3))
+	<-f.Done()
+	sig, err := f.Wait()
+	require.NoError(t, err)
+	require.True(t, sig.Verify(testMsg))
+	chainID, ok := sig.ChainID()
+	require.True(t, ok)
+	require.Equal(t, uint64(3), chainID)
+
+	require.Len(t, reported, (size-1)/progressInterval+1)
+	for i, r := range reported[:len(reported)-1] {
+		require.Equal(t, [2]int{(i + 1) * progressInterval, size}, r)
+	}
+	require.Equal(t, [2]int{size, size}, reported[len(reported)-1])
+
+	// errors of Sign are returned
+	_, err = keyring.SignAsync(context.Background(), testMsg, curve.NewRandomScalar(), nil).Wait()
+	require.ErrorIs(t, err, ErrSignerNotInRing)
+}
+
+func TestSignAsync_Cancel(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 100, privKey, 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = keyring.SignAsync(ctx, testMsg, privKey, nil).Wait()
+	require.ErrorIs(t, err, context.Canceled)
+
+	// canceled from the progress function, after the first members
+	var (
+		f     *Future[*RingSig]
+		calls int
+		ready = make(chan struct{})
+	)
+	f = keyring.SignAsync(context.Background(), testMsg, privKey, func(done, total int) {
+		<-ready
+		calls++
+		f.Cancel()
+	})
+	close(ready)
+	sig, err := f.Wait()
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, sig)
+	require.Equal(t, 1, calls)
+}
+
+func TestVerifyAsync(t *testing.T) {
+	sig := createSigWithCurve(t, Secp256k1(), 64, 5)
+
+	var last [2]int
+	valid, err := sig.VerifyAsync(context.Background(), testMsg, nil, func(done, total int) {
+		require.Greater(t, done, last[0])
+		last = [2]int{done, total}
+	}).Wait()
+	require.NoError(t, err)
+	require.True(t, valid)
+	require.Equal(t, [2]int{64, 64}, last)
+
+	valid, err = sig.VerifyAsync(context.Background(), [32]byte{}, nil, nil).Wait()
+	require.ErrorIs(t, err, ErrInvalidSignature)
+	require.False(t, valid)
+
+	// the options' policy still applies
+	errRejected := errors.New("rejected")
+	valid, err = sig.VerifyAsync(context.Background(), testMsg, &VerifyOpts{
+		Policy: func(i int, _ types.Point) error {
+			if i == 50 {
+				return errRejected
+			}
+			return nil
+		},
+	}, nil).Wait()
+	require.ErrorIs(t, err, errRejected)
+	require.False(t, valid)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	valid, err = sig.VerifyAsync(ctx, testMsg, nil, func(int, int) { cancel() }).Wait()
+	require.Equal(t, context.Canceled, err)
+	require.False(t, valid)
+}
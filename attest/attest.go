@@ -0,0 +1,134 @@
+// Package attest signs artifacts, such as release files and Git commits, as
+// "one of the maintainers in this ring", without revealing which one.
+//
+// An attestation is a ring signature over a domain-separated digest of the
+// subject, made with the ring of maintainers' keys:
+//
+//	subject, err := attest.FileSubject(f)
+//	sig, err := attest.Sign(maintainers, privKey, subject)
+//	...
+//	err = attest.Verify(maintainers, subject, sig)
+//
+// Attestations by the same maintainer share a key image, so a maintainer
+// attesting twice can be detected with ring.Link.
+package attest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+const messageDomain = "ring-go/attest"
+
+// Kinds of subjects.
+const (
+	KindFile      = "file"
+	KindGitCommit = "git-commit"
+)
+
+// ErrWrongRing is returned by Verify when an attestation was made with a ring
+// other than the maintainers' ring.
+var ErrWrongRing = errors.New("attestation is not signed by the maintainers' ring")
+
+// Subject is an attested artifact, identified by its kind and digest.
+type Subject struct {
+	Kind   string
+	Digest []byte
+}
+
+// FileSubject returns the subject for a file with the given contents, which
+// is identified by its SHA-256 digest.
+func FileSubject(r io.Reader) (Subject, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return Subject{}, err
+	}
+
+	return Subject{Kind: KindFile, Digest: h.Sum(nil)}, nil
+}
+
+// GitCommitSubject returns the subject for the Git commit with the given
+// hex-encoded hash, which can be a SHA-1 or SHA-256 object name.
+func GitCommitSubject(hash string) (Subject, error) {
+	digest, err := hex.DecodeString(strings.TrimSpace(hash))
+	if err != nil {
+		return Subject{}, fmt.Errorf("invalid commit hash: %w", err)
+	}
+	if len(digest) != sha1Size && len(digest) != sha256.Size {
+		return Subject{}, fmt.Errorf("invalid commit hash length %d", len(digest))
+	}
+
+	return Subject{Kind: KindGitCommit, Digest: digest}, nil
+}
+
+const sha1Size = 20
+
+// String returns a description of the subject, eg. "git-commit 1a2b...".
+func (s Subject) String() string {
+	switch s.Kind {
+	case KindFile:
+		return fmt.Sprintf("%s sha256:%x", s.Kind, s.Digest)
+	default:
+		return fmt.Sprintf("%s %x", s.Kind, s.Digest)
+	}
+}
+
+// Message returns the message signed by attestations of the subject, which
+// binds the subject's kind and digest.
+func (s Subject) Message() [32]byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte(messageDomain))
+	for _, field := range [][]byte{[]byte(s.Kind), s.Digest} {
+		var l [2]byte
+		binary.BigEndian.PutUint16(l[:], uint16(len(field)))
+		_, _ = h.Write(l[:])
+		_, _ = h.Write(field)
+	}
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+func (s Subject) validate() error {
+	if s.Kind == "" {
+		return errors.New("subject kind is empty")
+	}
+	if len(s.Digest) == 0 || len(s.Digest) > 0xffff || len(s.Kind) > 0xffff {
+		return errors.New("invalid subject digest")
+	}
+	return nil
+}
+
+// Sign attests the subject with the private key of one of the maintainers.
+func Sign(maintainers *ring.Ring, privKey ring.Scalar, subject Subject, opts ...ring.SignOption) (*ring.RingSig, error) {
+	if err := subject.validate(); err != nil {
+		return nil, err
+	}
+
+	return maintainers.Sign(subject.Message(), privKey, opts...)
+}
+
+// Verify checks that sig is a valid attestation of the subject by one of the
+// maintainers. The signature's ring must be the maintainers' ring, with the
+// keys in the same order.
+func Verify(maintainers *ring.Ring, subject Subject, sig *ring.RingSig) error {
+	if err := subject.validate(); err != nil {
+		return err
+	}
+
+	if !sig.Ring().Equals(maintainers) {
+		return ErrWrongRing
+	}
+
+	return sig.VerifyWithPolicy(subject.Message(), nil)
+}
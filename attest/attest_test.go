@@ -0,0 +1,72 @@
+package attest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+func newMaintainers(t *testing.T) (*ring.Ring, ring.Scalar) {
+	curve := ring.Ed25519()
+	privKey := curve.NewRandomScalar()
+	maintainers, err := ring.NewKeyRing(curve, 5, privKey, 3)
+	require.NoError(t, err)
+	return maintainers, privKey
+}
+
+func TestSignAndVerify(t *testing.T) {
+	maintainers, privKey := newMaintainers(t)
+
+	file, err := FileSubject(strings.NewReader("release tarball"))
+	require.NoError(t, err)
+	commit, err := GitCommitSubject("2fd4e1c67a2d28fced849ee1bb76e7391b93eb12")
+	require.NoError(t, err)
+
+	for _, subject := range []Subject{file, commit} {
+		sig, err := Sign(maintainers, privKey, subject)
+		require.NoError(t, err)
+		require.NoError(t, Verify(maintainers, subject, sig))
+	}
+
+	// attestations are bound to their subject
+	sig, err := Sign(maintainers, privKey, file)
+	require.NoError(t, err)
+	require.ErrorIs(t, Verify(maintainers, commit, sig), ring.ErrInvalidSignature)
+
+	other, err := FileSubject(strings.NewReader("another tarball"))
+	require.NoError(t, err)
+	require.ErrorIs(t, Verify(maintainers, other, sig), ring.ErrInvalidSignature)
+
+	// same digest, different kind
+	require.ErrorIs(t, Verify(maintainers, Subject{Kind: KindGitCommit, Digest: file.Digest}, sig), ring.ErrInvalidSignature)
+}
+
+func TestVerify_WrongRing(t *testing.T) {
+	maintainers, _ := newMaintainers(t)
+	impostors, privKey := newMaintainers(t)
+
+	subject, err := GitCommitSubject(strings.Repeat("ab", 32))
+	require.NoError(t, err)
+	sig, err := Sign(impostors, privKey, subject)
+	require.NoError(t, err)
+	require.ErrorIs(t, Verify(maintainers, subject, sig), ErrWrongRing)
+}
+
+func TestGitCommitSubject_Invalid(t *testing.T) {
+	_, err := GitCommitSubject("xyz")
+	require.Error(t, err)
+	_, err = GitCommitSubject("abcd")
+	require.Error(t, err)
+}
+
+func TestSubject_String(t *testing.T) {
+	subject, err := GitCommitSubject("2fd4e1c67a2d28fced849ee1bb76e7391b93eb12")
+	require.NoError(t, err)
+	require.Equal(t, "git-commit 2fd4e1c67a2d28fced849ee1bb76e7391b93eb12", subject.String())
+
+	file := Subject{Kind: KindFile, Digest: []byte{0xab}}
+	require.Equal(t, "file sha256:ab", file.String())
+}
@@ -0,0 +1,150 @@
+package ring
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	auditCommitmentLen = 32
+
+	auditCommitDomain = "ring-go/audit-commitment"
+	auditDecoyDomain  = "ring-go/audit-decoy"
+)
+
+// Errors returned by RingSig.AuditDecoys.
+var (
+	// ErrNotAuditable is returned for signatures created without
+	// WithAuditableDecoys.
+	ErrNotAuditable = errors.New("signature has no decoy commitment")
+	// ErrAuditFailed is returned when the revealed seed doesn't match the
+	// signature's commitment, or the decoy responses weren't derived from it.
+	ErrAuditFailed = errors.New("decoy responses were not derived from the seed")
+)
+
+// AuditSeed is the secret seed from which the decoy responses of an auditable
+// signature are derived; see WithAuditableDecoys.
+type AuditSeed [32]byte
+
+// NewAuditSeed returns a random AuditSeed.
+func NewAuditSeed() (AuditSeed, error) {
+	var seed AuditSeed
+	if _, err := rand.Read(seed[:]); err != nil {
+		return seed, err
+	}
+	return seed, nil
+}
+
+// Commitment returns the commitment to the seed that is bound into the
+// signatures created with it.
+func (seed AuditSeed) Commitment() [auditCommitmentLen]byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte(auditCommitDomain))
+	_, _ = h.Write(seed[:])
+
+	var out [auditCommitmentLen]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// decoy returns the response of the ring member with the given index, derived
+// from the seed and the signed message m, which binds the signature's
+// extensions and thus the commitment.
+func (seed AuditSeed) decoy(curve types.Curve, m [32]byte, idx int) (types.Scalar, error) {
+	buf := make([]byte, 0, len(auditDecoyDomain)+len(seed)+len(m)+4)
+	buf = append(buf, auditDecoyDomain...)
+	buf = append(buf, seed[:]...)
+	buf = append(buf, m[:]...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(idx))
+	return curve.HashToScalar(buf)
+}
+
+// WithAuditableDecoys derives the responses s_i of the decoy ring members from
+// the given seed, instead of picking them at random, and binds a commitment to
+// the seed into the signature. This is meant for regulated deployments: the
+// signer can later reveal the seed to an auditor, who checks with
+// RingSig.AuditDecoys that the decoy responses were generated honestly, and
+// thus can't carry a covert channel.
+//
+// Revealing the seed reveals which ring member signed, since the signer's
+// response is the only one not derived from it, so it must only be revealed
+// to a party allowed to learn that. Until then, the signature is as anonymous
+// as any other. The seed should be random (see NewAuditSeed) and kept secret.
+// It may be reused across signatures, as the responses also depend on the
+// signed message, but then revealing it deanonymizes all of them.
+//
+// The signer's own nonce is still picked at random, as deriving it from a seed
+// known to the auditor would reveal the private key.
+//
+// The commitment is part of the signed transcript, and sets a flag in the
+// encoding's header, so the signatures can't be decoded by versions of this
+// package that predate it.
+func WithAuditableDecoys(seed AuditSeed) SignOption {
+	return func(o *signOptions) {
+		o.auditSeed = &seed
+		commitment := seed.Commitment()
+		o.ext.audit = &commitment
+	}
+}
+
+// AuditCommitment returns the commitment to the seed the decoy responses were
+// derived from, for signatures created with WithAuditableDecoys. ok is false
+// for other signatures.
+func (sig *RingSig) AuditCommitment() (commitment [32]byte, ok bool) {
+	if sig.ext.audit == nil {
+		return commitment, false
+	}
+	return *sig.ext.audit, true
+}
+
+// AuditDecoys checks, given the seed revealed by the signer, that the seed
+// matches the signature's commitment, that the signature is valid for the
+// message m, and that the responses of all ring members but one were derived
+// from the seed. It returns the index of that remaining member, which is the
+// signer. The validity window isn't checked, as audits usually happen after the
+// fact.
+//
+// It returns ErrNotAuditable if the signature wasn't created with
+// WithAuditableDecoys, ErrInvalidSignature if it's invalid, and ErrAuditFailed
+// if the seed or the responses don't match.
+func (sig *RingSig) AuditDecoys(m [32]byte, seed AuditSeed) (signerIdx int, err error) {
+	if sig.ext.audit == nil {
+		return -1, ErrNotAuditable
+	}
+	commitment := seed.Commitment()
+	if subtle.ConstantTimeCompare(commitment[:], sig.ext.audit[:]) != 1 {
+		return -1, fmt.Errorf("%w: seed does not match the commitment", ErrAuditFailed)
+	}
+	if err := sig.verifyTranscript(m, nil, nil, RequireTorsionFree); err != nil {
+		return -1, err
+	}
+
+	curve := sig.ring.curve
+	msg := sig.ext.message(m)
+	signerIdx = -1
+	for i, s := range sig.s {
+		decoy, err := seed.decoy(curve, msg, i)
+		if err != nil {
+			return -1, err
+		}
+		if decoy.Eq(s) {
+			continue
+		}
+		if signerIdx >= 0 {
+			return -1, fmt.Errorf("%w: responses %d and %d are not derived from it", ErrAuditFailed, signerIdx, i)
+		}
+		signerIdx = i
+	}
+
+	if signerIdx < 0 {
+		// every response matches, which an honest signer can't produce
+		return -1, ErrAuditFailed
+	}
+	return signerIdx, nil
+}
@@ -0,0 +1,83 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAuditableDecoys(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 6, privKey, 4)
+		require.NoError(t, err)
+
+		seed, err := NewAuditSeed()
+		require.NoError(t, err)
+		sig, err := keyring.Sign(testMsg, privKey, WithAuditableDecoys(seed), WithChainID(7))
+		require.NoError(t, err)
+		require.True(t, sig.Verify(testMsg))
+
+		enc, err := sig.Serialize()
+		require.NoError(t, err)
+		res := new(RingSig)
+		require.NoError(t, res.Deserialize(curve, enc))
+		require.True(t, res.Equal(sig))
+
+		commitment, ok := res.AuditCommitment()
+		require.True(t, ok)
+		require.Equal(t, seed.Commitment(), commitment)
+
+		idx, err := res.AuditDecoys(testMsg, seed)
+		require.NoError(t, err)
+		require.Equal(t, 4, idx)
+
+		// the same seed gives different responses for another message
+		other, err := keyring.Sign([32]byte{9}, privKey, WithAuditableDecoys(seed))
+		require.NoError(t, err)
+		require.False(t, other.s[0].Eq(sig.s[0]))
+	}
+}
+
+func TestAuditDecoys_Rejects(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 1)
+	require.NoError(t, err)
+
+	plain, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	_, ok := plain.AuditCommitment()
+	require.False(t, ok)
+	_, err = plain.AuditDecoys(testMsg, AuditSeed{})
+	require.ErrorIs(t, err, ErrNotAuditable)
+
+	seed, err := NewAuditSeed()
+	require.NoError(t, err)
+	sig, err := keyring.Sign(testMsg, privKey, WithAuditableDecoys(seed))
+	require.NoError(t, err)
+
+	// another seed
+	_, err = sig.AuditDecoys(testMsg, AuditSeed{1})
+	require.ErrorIs(t, err, ErrAuditFailed)
+
+	// another message
+	_, err = sig.AuditDecoys([32]byte{1}, seed)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	// the commitment is part of the transcript
+	tampered := *sig
+	otherCommitment := AuditSeed{2}.Commitment()
+	tampered.ext.audit = &otherCommitment
+	require.False(t, tampered.Verify(testMsg))
+
+	// a signer committing to a seed without using it for the decoys
+	committed := seed.Commitment()
+	opts := newSignOptions(nil)
+	opts.ext.audit = &committed
+	covert, err := sign(keyring, testMsg, privKey, curve.ScalarBaseMul(privKey), 1, opts)
+	require.NoError(t, err)
+	require.True(t, covert.Verify(testMsg))
+	_, err = covert.AuditDecoys(testMsg, seed)
+	require.ErrorIs(t, err, ErrAuditFailed)
+}
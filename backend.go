@@ -0,0 +1,224 @@
+package ring
+
+import (
+	"strings"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// CurveBackend is a Curve that reports which accelerated operations its
+// implementation provides, so that signing and verification can select the
+// fastest algorithms available. The curves returned by Secp256k1 and Ed25519
+// implement it; other curves are used through the Curve interface only.
+type CurveBackend interface {
+	types.Curve
+
+	// DoubleScalarBaseMul returns a*P + b*G, where G is the base point. It may
+	// run in variable time, so it must only be used with public inputs, such
+	// as during verification.
+	DoubleScalarBaseMul(a types.Scalar, p types.Point, b types.Scalar) types.Point
+
+	// ScalarSize returns the length of the curve's scalar encoding, as
+	// returned by Scalar.Encode and accepted by DecodeToScalar.
+	ScalarSize() int
+
+	// Capabilities reports the optional operations and properties of the
+	// implementation. Signing and verification select their algorithms from
+	// the operations, so a backend must only report the capabilities it
+	// provides.
+	Capabilities() Capabilities
+}
+
+// Capabilities is a set of optional operations and properties of a
+// CurveBackend.
+type Capabilities uint32
+
+const (
+	// CapConstantTime means that ScalarMul and ScalarBaseMul run in constant
+	// time, so that they don't leak secret scalars through timing. It is
+	// informational only: signing uses the backend's multiplications whether
+	// or not it reports it, so callers that require constant-time
+	// implementations check it themselves, eg. with CapabilitiesOf.
+	CapConstantTime Capabilities = 1 << iota
+	// CapEncodeInto means that the backend implements EncodeIntoBackend.
+	CapEncodeInto
+	// CapMultiScalarMul means that the backend implements
+	// MultiScalarMulBackend.
+	CapMultiScalarMul
+	// CapDoubleBaseMul means that DoubleScalarBaseMul is faster than separate
+	// multiplications; otherwise, the generic implementation is used.
+	CapDoubleBaseMul
+)
+
+var capabilityNames = []string{"ConstantTime", "EncodeInto", "MultiScalarMul", "DoubleBaseMul"}
+
+// Has returns whether c has all the capabilities of flags.
+func (c Capabilities) Has(flags Capabilities) bool {
+	return c&flags == flags
+}
+
+// String returns the names of the capabilities, separated by "|", eg.
+// "ConstantTime|DoubleBaseMul", or "none".
+func (c Capabilities) String() string {
+	var names []string
+	for i, name := range capabilityNames {
+		if c.Has(1 << i) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// CapabilitiesOf returns the capabilities of the curve: those reported by
+// its implementation if it's a CurveBackend, and none otherwise.
+func CapabilitiesOf(curve types.Curve) Capabilities {
+	if backend, ok := curve.(CurveBackend); ok {
+		return backend.Capabilities()
+	}
+	return 0
+}
+
+// EncodeIntoBackend is implemented by backends reporting CapEncodeInto.
+type EncodeIntoBackend interface {
+	// EncodeInto appends the encoding of the point, as returned by Encode,
+	// to dst, and returns the extended slice.
+	EncodeInto(dst []byte, p types.Point) []byte
+}
+
+// MultiScalarMulBackend is implemented by backends reporting
+// CapMultiScalarMul.
+type MultiScalarMulBackend interface {
+	// MultiScalarMul returns the sum of scalars[i]*points[i]; both slices
+	// have the same length. It may run in variable time, so it must only be
+	// used with public inputs.
+	MultiScalarMul(scalars []types.Scalar, points []types.Point) types.Point
+}
+
+// curveOps are the implementations of the operations that have accelerated
+// variants, selected from the curve's capabilities, so that loops over the
+// ring members don't have to select them at each step.
+type curveOps struct {
+	curve   types.Curve
+	double  CurveBackend
+	multi   MultiScalarMulBackend
+	encoder EncodeIntoBackend
+}
+
+func opsOf(curve types.Curve) curveOps {
+	ops := curveOps{curve: curve}
+	backend, ok := curve.(CurveBackend)
+	if !ok {
+		return ops
+	}
+	caps := backend.Capabilities()
+	if caps.Has(CapDoubleBaseMul) {
+		ops.double = backend
+	}
+	if caps.Has(CapMultiScalarMul) {
+		ops.multi, _ = backend.(MultiScalarMulBackend)
+	}
+	if caps.Has(CapEncodeInto) {
+		ops.encoder, _ = backend.(EncodeIntoBackend)
+	}
+	return ops
+}
+
+// doubleScalarBaseMul returns a*P + b*G. It must only be used with public
+// inputs.
+func (o curveOps) doubleScalarBaseMul(a types.Scalar, p types.Point, b types.Scalar) types.Point {
+	if o.double != nil {
+		return o.double.DoubleScalarBaseMul(a, p, b)
+	}
+	return genericDoubleScalarBaseMul(o.curve, a, p, b)
+}
+
+// twoScalarMul returns a*P + b*Q. It must only be used with public inputs.
+func (o curveOps) twoScalarMul(a types.Scalar, p types.Point, b types.Scalar, q types.Point) types.Point {
+	if o.multi != nil {
+		return o.multi.MultiScalarMul([]types.Scalar{a, b}, []types.Point{p, q})
+	}
+	return o.curve.ScalarMul(a, p).Add(o.curve.ScalarMul(b, q))
+}
+
+// appendPoint appends the encoding of the point to dst.
+func (o curveOps) appendPoint(dst []byte, p types.Point) []byte {
+	if o.encoder != nil {
+		return o.encoder.EncodeInto(dst, p)
+	}
+	return append(dst, p.Encode()...)
+}
+
+// ScalarSize returns the length of the encoding of the curve's scalars: that
+// reported by the curve if it's a CurveBackend, and otherwise the length of
+// an encoded scalar, as go-dleq curves don't report it.
+func ScalarSize(curve types.Curve) int {
+	if backend, ok := curve.(CurveBackend); ok {
+		return backend.ScalarSize()
+	}
+	return len(curve.ScalarFromInt(1).Encode())
+}
+
+// doubleScalarBaseMul returns a*P + b*G, using the curve's DoubleScalarBaseMul
+// if it reports CapDoubleBaseMul. Like it, it must only be used with public
+// inputs.
+func doubleScalarBaseMul(curve types.Curve, a types.Scalar, p types.Point, b types.Scalar) types.Point {
+	return opsOf(curve).doubleScalarBaseMul(a, p, b)
+}
+
+// genericDoubleScalarBaseMul computes a*P + b*G with two separate scalar
+// multiplications, for curves without a double-scalar multiplication.
+func genericDoubleScalarBaseMul(curve types.Curve, a types.Scalar, p types.Point, b types.Scalar) types.Point {
+	return curve.ScalarMul(a, p).Add(curve.ScalarBaseMul(b))
+}
+
+// secp256k1Backend is the secp256k1 curve implemented by go-dleq, on top of the
+// decred secp256k1 package. Its ScalarMul uses decred's ScalarMultNonConst,
+// which splits the scalar with the GLV endomorphism and computes the two
+// half-length multiplications jointly, roughly halving the number of point
+// doublings.
+type secp256k1Backend struct {
+	types.Curve
+}
+
+func (*secp256k1Backend) ScalarSize() int {
+	return 32
+}
+
+// Capabilities reports none: decred's variable-base and base point
+// multiplications run in variable time, and the other operations use the
+// generic implementations.
+func (*secp256k1Backend) Capabilities() Capabilities {
+	return 0
+}
+
+// DoubleScalarBaseMul uses the generic implementation, as go-dleq doesn't
+// expose the underlying decred points.
+func (c *secp256k1Backend) DoubleScalarBaseMul(a types.Scalar, p types.Point, b types.Scalar) types.Point {
+	return genericDoubleScalarBaseMul(c.Curve, a, p, b)
+}
+
+// ed25519Backend is the ed25519 curve implemented by go-dleq, on top of
+// filippo.io/edwards25519.
+type ed25519Backend struct {
+	types.Curve
+}
+
+func (*ed25519Backend) ScalarSize() int {
+	return 32
+}
+
+// Capabilities reports CapConstantTime, as edwards25519's multiplications run
+// in constant time.
+func (*ed25519Backend) Capabilities() Capabilities {
+	return CapConstantTime
+}
+
+// DoubleScalarBaseMul uses the generic implementation, as go-dleq doesn't
+// expose the underlying edwards25519 points. The implementation selected by
+// Ed25519ImplEdwards25519 provides a faster one.
+func (c *ed25519Backend) DoubleScalarBaseMul(a types.Scalar, p types.Point, b types.Scalar) types.Point {
+	return genericDoubleScalarBaseMul(c.Curve, a, p, b)
+}
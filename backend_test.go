@@ -0,0 +1,190 @@
+package ring
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/athanorlabs/go-dleq/ed25519"
+	"github.com/athanorlabs/go-dleq/secp256k1"
+	"github.com/athanorlabs/go-dleq/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurveBackend(t *testing.T) {
+	_, ok := Secp256k1().(CurveBackend)
+	require.True(t, ok)
+	_, ok = Ed25519().(CurveBackend)
+	require.True(t, ok)
+}
+
+func TestCapabilities(t *testing.T) {
+	edwards, err := NewEd25519(Ed25519ImplEdwards25519)
+	require.NoError(t, err)
+
+	require.Equal(t, Capabilities(0), CapabilitiesOf(Secp256k1()))
+	require.Equal(t, CapConstantTime, CapabilitiesOf(Ed25519()))
+	require.Equal(t, CapConstantTime|CapEncodeInto|CapMultiScalarMul|CapDoubleBaseMul, CapabilitiesOf(edwards))
+	require.Equal(t, Capabilities(0), CapabilitiesOf(ed25519.NewCurve()))
+
+	caps := CapabilitiesOf(edwards)
+	require.True(t, caps.Has(CapMultiScalarMul|CapEncodeInto))
+	require.False(t, CapabilitiesOf(Ed25519()).Has(CapConstantTime|CapEncodeInto))
+	require.Equal(t, "ConstantTime|EncodeInto|MultiScalarMul|DoubleBaseMul", caps.String())
+	require.Equal(t, "DoubleBaseMul", CapDoubleBaseMul.String())
+	require.Equal(t, "none", Capabilities(0).String())
+
+	// backends must implement the interfaces of their capabilities
+	for _, curve := range []types.Curve{Secp256k1(), Ed25519(), edwards} {
+		caps := CapabilitiesOf(curve)
+		_, ok := curve.(EncodeIntoBackend)
+		require.Equal(t, caps.Has(CapEncodeInto), ok)
+		_, ok = curve.(MultiScalarMulBackend)
+		require.Equal(t, caps.Has(CapMultiScalarMul), ok)
+	}
+}
+
+// uncapableBackend implements the accelerated operations of edwards25519, but
+// reports no capabilities, so they must not be used.
+type uncapableBackend struct {
+	*edwards25519Backend
+}
+
+func (uncapableBackend) Capabilities() Capabilities {
+	return 0
+}
+
+func (uncapableBackend) DoubleScalarBaseMul(types.Scalar, types.Point, types.Scalar) types.Point {
+	panic("DoubleScalarBaseMul used without CapDoubleBaseMul")
+}
+
+func (uncapableBackend) MultiScalarMul([]types.Scalar, []types.Point) types.Point {
+	panic("MultiScalarMul used without CapMultiScalarMul")
+}
+
+func (uncapableBackend) EncodeInto([]byte, types.Point) []byte {
+	panic("EncodeInto used without CapEncodeInto")
+}
+
+func TestCapabilities_Select(t *testing.T) {
+	edwards, err := NewEd25519(Ed25519ImplEdwards25519)
+	require.NoError(t, err)
+	uncapable := uncapableBackend{edwards.(*edwards25519Backend)}
+
+	a, b := edwards.NewRandomScalar(), edwards.NewRandomScalar()
+	p, q := edwards.ScalarBaseMul(edwards.NewRandomScalar()), edwards.ScalarBaseMul(edwards.NewRandomScalar())
+	expected := edwards.ScalarMul(a, p).Add(edwards.ScalarMul(b, q))
+	for _, curve := range []types.Curve{edwards, uncapable} {
+		ops := opsOf(curve)
+		require.True(t, ops.twoScalarMul(a, p, b, q).Equals(expected))
+		require.True(t, ops.doubleScalarBaseMul(a, p, b).Equals(edwards.ScalarMul(a, p).Add(edwards.ScalarBaseMul(b))))
+		require.Equal(t, append([]byte{1}, p.Encode()...), ops.appendPoint([]byte{1}, p))
+	}
+
+	// signatures verify with the generic implementations
+	privKey := edwards.NewRandomScalar()
+	keyring, err := NewKeyRing(edwards, 8, privKey, 3)
+	require.NoError(t, err)
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+
+	decoded := new(RingSig)
+	require.NoError(t, decoded.Deserialize(uncapable, enc))
+	require.True(t, decoded.Verify(testMsg))
+}
+
+func TestScalarSize(t *testing.T) {
+	edwards, err := NewEd25519(Ed25519ImplEdwards25519)
+	require.NoError(t, err)
+
+	// go-dleq curves don't implement CurveBackend
+	for _, curve := range []types.Curve{Secp256k1(), Ed25519(), edwards, secp256k1.NewCurve(), ed25519.NewCurve()} {
+		size := ScalarSize(curve)
+		require.Equal(t, 32, size)
+		require.Len(t, curve.NewRandomScalar().Encode(), size)
+	}
+}
+
+// testScalarMulAgainstReference checks the curve's variable-base ScalarMul
+// against the reference double-and-add implementation.
+func testScalarMulAgainstReference(t *testing.T, curve types.Curve, ref refCurve, order *big.Int, toBigInt func(types.Scalar) *big.Int) {
+	scalars := []*big.Int{
+		big.NewInt(1),
+		big.NewInt(2),
+		new(big.Int).Sub(order, big.NewInt(1)),
+		new(big.Int).Rsh(order, 1),
+		new(big.Int).Lsh(big.NewInt(1), 128),
+	}
+	for i := 0; i < 8; i++ {
+		scalars = append(scalars, toBigInt(curve.NewRandomScalar()))
+	}
+
+	for _, k := range scalars {
+		p := curve.ScalarBaseMul(curve.NewRandomScalar())
+		refP, ok := ref.decodePoint(p.Encode())
+		require.True(t, ok)
+
+		var kb [32]byte
+		k.FillBytes(kb[:])
+		s, err := curve.DecodeToScalar(scalarBytes(curve, kb))
+		require.NoError(t, err)
+
+		expected := ref.encodePoint(refMul(ref, k, refP))
+		require.Equal(t, expected, curve.ScalarMul(s, p).Encode(), "k = %x", k)
+	}
+}
+
+// scalarBytes converts a big-endian scalar to the curve's scalar encoding.
+func scalarBytes(curve types.Curve, be [32]byte) []byte {
+	if CurveIDOf(curve) == CurveEd25519 {
+		for i := 0; i < 16; i++ {
+			be[i], be[31-i] = be[31-i], be[i]
+		}
+	}
+	return be[:]
+}
+
+func TestScalarMul_Secp256k1_GLV(t *testing.T) {
+	ref := newRefSecp256k1()
+	testScalarMulAgainstReference(t, Secp256k1(), ref, ref.n, func(s types.Scalar) *big.Int {
+		return new(big.Int).SetBytes(s.Encode())
+	})
+}
+
+func TestScalarMul_Ed25519(t *testing.T) {
+	ref := newRefEd25519()
+	order, _ := new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	testScalarMulAgainstReference(t, Ed25519(), ref, order, func(s types.Scalar) *big.Int {
+		var be [32]byte
+		copy(be[:], scalarBytes(Ed25519(), [32]byte(s.Encode())))
+		return new(big.Int).SetBytes(be[:])
+	})
+}
+
+func TestDoubleScalarBaseMul(t *testing.T) {
+	curves := []types.Curve{
+		Secp256k1(),
+		Ed25519(),
+		newEdwards25519(t),
+		// not a CurveBackend, so the generic implementation is used
+		secp256k1.NewCurve(),
+	}
+
+	for _, curve := range curves {
+		for i := 0; i < 8; i++ {
+			a, b := curve.NewRandomScalar(), curve.NewRandomScalar()
+			p := curve.ScalarBaseMul(curve.NewRandomScalar())
+
+			expected := curve.ScalarMul(a, p).Add(curve.ScalarBaseMul(b))
+			require.Equal(t, expected.Encode(), doubleScalarBaseMul(curve, a, p, b).Encode())
+		}
+
+		// a = 0 and b = 0
+		zero := curve.ScalarFromInt(0)
+		one := curve.ScalarFromInt(1)
+		p := curve.ScalarBaseMul(curve.NewRandomScalar())
+		require.Equal(t, p.Encode(), doubleScalarBaseMul(curve, one, p, zero).Encode())
+		require.Equal(t, curve.BasePoint().Encode(), doubleScalarBaseMul(curve, zero, p, one).Encode())
+	}
+}
@@ -0,0 +1,33 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestBatchVerify(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 16, privKey, 4)
+	require.NoError(t, err)
+
+	const n = 10
+	sigs := make([]*RingSig, n)
+	msgs := make([][32]byte, n)
+	for i := range sigs {
+		msgs[i] = sha3.Sum256([]byte{byte(i)})
+		sigs[i], err = keyring.Sign(msgs[i], privKey)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, BatchVerify(sigs, msgs))
+	require.NoError(t, BatchVerify(nil, nil))
+
+	msgs[7][0] ^= 1
+	msgs[3][0] ^= 1
+	require.EqualError(t, BatchVerify(sigs, msgs), "invalid signature at index 3")
+
+	require.Error(t, BatchVerify(sigs, msgs[1:]))
+}
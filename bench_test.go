@@ -25,6 +25,38 @@ func mustKeyRing(curve types.Curve, privKey types.Scalar, size, idx int) *Ring {
 	return keyring
 }
 
+func benchmarkNewKeyRing(b *testing.B, curve types.Curve, size int) {
+	privKey := curve.NewRandomScalar()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mustKeyRing(curve, privKey, size, idx)
+	}
+}
+
+func BenchmarkNewKeyRing128_Secp256k1(b *testing.B) {
+	benchmarkNewKeyRing(b, Secp256k1(), 128)
+}
+
+func BenchmarkNewKeyRing128_Ed25519(b *testing.B) {
+	benchmarkNewKeyRing(b, Ed25519(), 128)
+}
+
+func BenchmarkNewKeyRing1024_Secp256k1(b *testing.B) {
+	benchmarkNewKeyRing(b, Secp256k1(), 1024)
+}
+
+func BenchmarkNewKeyRing1024_Ed25519(b *testing.B) {
+	benchmarkNewKeyRing(b, Ed25519(), 1024)
+}
+
+func BenchmarkNewKeyRing16384_Secp256k1(b *testing.B) {
+	benchmarkNewKeyRing(b, Secp256k1(), 16384)
+}
+
+func BenchmarkNewKeyRing16384_Ed25519(b *testing.B) {
+	benchmarkNewKeyRing(b, Ed25519(), 16384)
+}
+
 func BenchmarkSign2_Secp256k1(b *testing.B) {
 	const size = 2
 	curve := Secp256k1()
@@ -255,3 +287,62 @@ func BenchmarkVerify128_Ed25519(b *testing.B) {
 	sig := mustSig(curve, size)
 	benchmarkVerify(b, sig)
 }
+
+func BenchmarkVerify1024_Secp256k1(b *testing.B) {
+	const size = 1024
+	curve := Secp256k1()
+	sig := mustSig(curve, size)
+	b.ResetTimer()
+	benchmarkVerify(b, sig)
+}
+
+func BenchmarkVerify1024_Ed25519(b *testing.B) {
+	const size = 1024
+	curve := Ed25519()
+	sig := mustSig(curve, size)
+	b.ResetTimer()
+	benchmarkVerify(b, sig)
+}
+
+func BenchmarkVerify4096_Secp256k1(b *testing.B) {
+	const size = 4096
+	curve := Secp256k1()
+	sig := mustSig(curve, size)
+	b.ResetTimer()
+	benchmarkVerify(b, sig)
+}
+
+func BenchmarkVerify4096_Ed25519(b *testing.B) {
+	const size = 4096
+	curve := Ed25519()
+	sig := mustSig(curve, size)
+	b.ResetTimer()
+	benchmarkVerify(b, sig)
+}
+
+func BenchmarkVerify16384_Secp256k1(b *testing.B) {
+	const size = 16384
+	curve := Secp256k1()
+	sig := mustSig(curve, size)
+	b.ResetTimer()
+	benchmarkVerify(b, sig)
+}
+
+func BenchmarkVerify16384_Ed25519(b *testing.B) {
+	const size = 16384
+	curve := Ed25519()
+	sig := mustSig(curve, size)
+	b.ResetTimer()
+	benchmarkVerify(b, sig)
+}
+
+func BenchmarkVerify128_Edwards25519(b *testing.B) {
+	const size = 128
+	curve, err := NewEd25519(Ed25519ImplEdwards25519)
+	if err != nil {
+		panic(err)
+	}
+	sig := mustSig(curve, size)
+	b.ResetTimer()
+	benchmarkVerify(b, sig)
+}
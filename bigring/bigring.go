@@ -0,0 +1,299 @@
+// Package bigring stores rings with a very large number of members, such as
+// chain-wide anonymity sets of 100k+ keys, in a file that is memory-mapped
+// rather than loaded, along with the precomputed H_p value of each member.
+// Public keys are only decoded when used, and signatures are verified from a
+// stream, so memory usage stays bounded regardless of the ring size:
+//
+//	w, err := bigring.Create(path, curve)
+//	for _, pk := range pubkeys {
+//		err = w.Add(pk)
+//	}
+//	err = w.Close()
+//	...
+//	r, err := bigring.Open(path)
+//	defer r.Close()
+//	err = r.Verify(msg, sigReader)
+package bigring
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// The file starts with a header:
+//
+//	magic (8 bytes) | version (1) | curve ID (1) | reserved (2) | size (4)
+//
+// followed by one entry per member, in ring order, of the compressed public key
+// followed by the compressed H_p of the public key.
+const (
+	magic      = "RINGBIG\x00"
+	version    = 1
+	headerSize = 16
+)
+
+// Writer writes a ring file. It must be closed to complete the file.
+type Writer struct {
+	f     *os.File
+	w     *bufio.Writer
+	curve ring.Curve
+	id    ring.CurveID
+	size  uint32
+	err   error
+}
+
+// Create creates a ring file at path for a ring over the given curve, to which
+// the members are added with Add.
+func Create(path string, curve ring.Curve) (*Writer, error) {
+	id := ring.CurveIDOf(curve)
+	if id == ring.CurveUnknown {
+		return nil, errors.New("unsupported curve")
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Writer{
+		f:     f,
+		w:     bufio.NewWriterSize(f, 1<<16),
+		curve: curve,
+		id:    id,
+	}
+
+	// the header is rewritten with the final size by Close
+	if _, err := w.w.Write(make([]byte, headerSize)); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Add appends a member to the ring, computing its H_p value.
+func (w *Writer) Add(pk ring.Point) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.size == ring.MaxRingSize {
+		w.err = errors.New("ring size exceeds MaxRingSize")
+		return w.err
+	}
+
+	if _, err := w.w.Write(pk.Copy().Encode()); err != nil {
+		w.err = err
+		return err
+	}
+	if _, err := w.w.Write(ring.HashToCurve(pk).Encode()); err != nil {
+		w.err = err
+		return err
+	}
+
+	w.size++
+	return nil
+}
+
+// Close writes the header and closes the file.
+func (w *Writer) Close() error {
+	defer w.f.Close()
+	if w.err != nil {
+		return w.err
+	}
+	if w.size < 2 {
+		return errors.New("ring must have at least two members")
+	}
+
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+
+	header := make([]byte, headerSize)
+	copy(header, magic)
+	header[8] = version
+	header[9] = byte(w.id)
+	binary.BigEndian.PutUint32(header[12:], w.size)
+	if _, err := w.f.WriteAt(header, 0); err != nil {
+		return err
+	}
+
+	return w.f.Sync()
+}
+
+// storage provides access to the contents of a ring file.
+type storage interface {
+	// slice returns n bytes at offset off. The returned slice must not be
+	// modified, and is only valid until the storage is closed.
+	slice(off int64, n int) ([]byte, error)
+	Close() error
+}
+
+// Ring is a ring stored in a file opened with Open. It's safe for concurrent
+// use, and must be closed when no longer used.
+type Ring struct {
+	storage  storage
+	curve    ring.Curve
+	size     int
+	pointLen int
+}
+
+// Open opens the ring file at path. On Unix systems, the file is memory-mapped;
+// elsewhere, entries are read from the file as needed.
+func Open(path string) (*Ring, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	st, err := openStorage(f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	r, err := newRing(st, info.Size())
+	if err != nil {
+		st.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return r, nil
+}
+
+func newRing(st storage, fileSize int64) (*Ring, error) {
+	header, err := st.slice(0, headerSize)
+	if err != nil {
+		return nil, errors.New("file too short")
+	}
+	if !bytes.Equal(header[:8], []byte(magic)) {
+		return nil, errors.New("not a ring file")
+	}
+	if header[8] != version {
+		return nil, fmt.Errorf("unsupported ring file version %d", header[8])
+	}
+
+	curve, err := ring.CurveID(header[9]).Curve()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Ring{
+		storage:  st,
+		curve:    curve,
+		size:     int(binary.BigEndian.Uint32(header[12:])),
+		pointLen: curve.CompressedPointSize(),
+	}
+	if r.size < 2 || r.size > ring.MaxRingSize {
+		return nil, errors.New("invalid ring size")
+	}
+	if expected := headerSize + int64(r.size)*int64(2*r.pointLen); fileSize != expected {
+		return nil, fmt.Errorf("file size is %d, expected %d", fileSize, expected)
+	}
+
+	return r, nil
+}
+
+// Close releases the ring's file.
+func (r *Ring) Close() error {
+	return r.storage.Close()
+}
+
+// Size returns the number of members of the ring.
+func (r *Ring) Size() int {
+	return r.size
+}
+
+// Curve returns the ring's curve.
+func (r *Ring) Curve() ring.Curve {
+	return r.curve
+}
+
+// entry returns the encoded public key and H_p value of the i-th member.
+func (r *Ring) entry(i int) (pubkey, hp []byte, err error) {
+	b, err := r.storage.slice(headerSize+int64(i)*int64(2*r.pointLen), 2*r.pointLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b[:r.pointLen], b[r.pointLen:], nil
+}
+
+// PublicKey decodes and returns the public key of the i-th member.
+func (r *Ring) PublicKey(i int) (ring.Point, error) {
+	if i < 0 || i >= r.size {
+		return nil, errors.New("index out of range")
+	}
+
+	pubkey, _, err := r.entry(i)
+	if err != nil {
+		return nil, err
+	}
+	return r.curve.DecodeToPoint(pubkey)
+}
+
+// Range calls fn with the index and public key of each member, in order,
+// stopping at the first error, which it returns.
+func (r *Ring) Range(fn func(i int, pk ring.Point) error) error {
+	for i := 0; i < r.size; i++ {
+		pk, err := r.PublicKey(i)
+		if err != nil {
+			return err
+		}
+		if err := fn(i, pk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Verify verifies a serialized signature read from sig for the message, which
+// must be over this ring, with the same members in the same order: signatures
+// over a prefix of the ring are rejected, as their anonymity set is smaller.
+// The signature is processed one member at a time (see ring.VerifyStream),
+// with the public keys and their H_p values decoded from the file.
+func (r *Ring) Verify(m [32]byte, sig io.Reader) error {
+	members := 0
+	err := ring.VerifyStream(r.curve, m, sig, func(i int, pubkey []byte) (ring.Point, ring.Point, error) {
+		if i >= r.size {
+			return nil, nil, errors.New("signature ring is larger than the ring")
+		}
+		members = i + 1
+
+		stored, hp, err := r.entry(i)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !bytes.Equal(pubkey, stored) {
+			return nil, nil, errors.New("signature is over a different ring")
+		}
+
+		pk, err := r.curve.DecodeToPoint(stored)
+		if err != nil {
+			return nil, nil, err
+		}
+		hpPoint, err := r.curve.DecodeToPoint(hp)
+		if err != nil {
+			return nil, nil, err
+		}
+		return pk, hpPoint, nil
+	})
+	if err != nil {
+		return err
+	}
+	if members != r.size {
+		return errors.New("signature ring is smaller than the ring")
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package bigring
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+var testMsg = [32]byte{1, 2, 3}
+
+// createRing writes a ring file with the keys of keyring, and returns its path.
+func createRing(t *testing.T, keyring *ring.Ring) string {
+	path := filepath.Join(t.TempDir(), "ring.bin")
+	w, err := Create(path, keyring.Curve())
+	require.NoError(t, err)
+	for _, pk := range keyring.PublicKeys() {
+		require.NoError(t, w.Add(pk))
+	}
+	require.NoError(t, w.Close())
+	return path
+}
+
+func newSignedRing(t *testing.T, curve ring.Curve, size int) (*ring.Ring, []byte) {
+	privKey := curve.NewRandomScalar()
+	keyring, err := ring.NewKeyRing(curve, size, privKey, size/3)
+	require.NoError(t, err)
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+	return keyring, enc
+}
+
+func TestRing_Verify(t *testing.T) {
+	for _, curve := range []ring.Curve{ring.Secp256k1(), ring.Ed25519()} {
+		keyring, enc := newSignedRing(t, curve, 64)
+		path := createRing(t, keyring)
+
+		r, err := Open(path)
+		require.NoError(t, err)
+		defer r.Close()
+
+		require.Equal(t, 64, r.Size())
+		require.Equal(t, ring.CurveIDOf(curve), ring.CurveIDOf(r.Curve()))
+		require.NoError(t, r.Verify(testMsg, bytes.NewReader(enc)))
+		require.ErrorIs(t, r.Verify([32]byte{}, bytes.NewReader(enc)), ring.ErrInvalidSignature)
+
+		// a signature over another ring
+		_, other := newSignedRing(t, curve, 64)
+		require.Error(t, r.Verify(testMsg, bytes.NewReader(other)))
+		_, smaller := newSignedRing(t, curve, 3)
+		require.Error(t, r.Verify(testMsg, bytes.NewReader(smaller)))
+	}
+}
+
+func TestRing_VerifyPrefix(t *testing.T) {
+	curve := ring.Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := ring.NewKeyRing(curve, 8, privKey, 0)
+	require.NoError(t, err)
+	r, err := Open(createRing(t, keyring))
+	require.NoError(t, err)
+	defer r.Close()
+
+	// a valid signature over the first 2 members of the ring isn't over the
+	// ring
+	prefix, err := ring.NewFixedKeyRingFromPublicKeys(curve, keyring.PublicKeys()[:2])
+	require.NoError(t, err)
+	sig, err := prefix.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+	require.NoError(t, ring.VerifyStream(curve, testMsg, bytes.NewReader(enc), nil))
+	require.EqualError(t, r.Verify(testMsg, bytes.NewReader(enc)), "signature ring is smaller than the ring")
+}
+
+func TestRing_FileStorage(t *testing.T) {
+	keyring, enc := newSignedRing(t, ring.Ed25519(), 8)
+	path := createRing(t, keyring)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	info, err := f.Stat()
+	require.NoError(t, err)
+	r, err := newRing(&fileStorage{f: f}, info.Size())
+	require.NoError(t, err)
+	defer r.Close()
+
+	require.NoError(t, r.Verify(testMsg, bytes.NewReader(enc)))
+}
+
+func TestRing_PublicKeys(t *testing.T) {
+	keyring, _ := newSignedRing(t, ring.Secp256k1(), 5)
+	r, err := Open(createRing(t, keyring))
+	require.NoError(t, err)
+	defer r.Close()
+
+	for i, expected := range keyring.PublicKeys() {
+		pk, err := r.PublicKey(i)
+		require.NoError(t, err)
+		require.True(t, pk.Equals(expected))
+	}
+	_, err = r.PublicKey(5)
+	require.Error(t, err)
+
+	var n int
+	require.NoError(t, r.Range(func(i int, pk ring.Point) error {
+		require.True(t, pk.Equals(keyring.PublicKeys()[i]))
+		n++
+		return nil
+	}))
+	require.Equal(t, 5, n)
+}
+
+func TestOpen_Invalid(t *testing.T) {
+	keyring, _ := newSignedRing(t, ring.Ed25519(), 4)
+	data, err := os.ReadFile(createRing(t, keyring))
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	for name, contents := range map[string][]byte{
+		"empty":     nil,
+		"truncated": data[:len(data)-1],
+		"magic":     append([]byte("NOTARING"), data[8:]...),
+		"version":   append(append(append([]byte{}, data[:8]...), 2), data[9:]...),
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, contents, 0o600))
+		_, err := Open(path)
+		require.Error(t, err, name)
+	}
+
+	_, err = Open(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
+
+func TestCreate_TooSmall(t *testing.T) {
+	curve := ring.Ed25519()
+	w, err := Create(filepath.Join(t.TempDir(), "ring.bin"), curve)
+	require.NoError(t, err)
+	require.NoError(t, w.Add(curve.BasePoint()))
+	require.Error(t, w.Close())
+}
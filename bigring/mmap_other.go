@@ -0,0 +1,13 @@
+//go:build !unix
+
+package bigring
+
+import (
+	"os"
+)
+
+// openStorage reads from the file as needed, as memory mapping isn't supported
+// on this platform.
+func openStorage(f *os.File, _ int64) (storage, error) {
+	return &fileStorage{f: f}, nil
+}
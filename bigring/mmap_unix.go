@@ -0,0 +1,24 @@
+//go:build unix
+
+package bigring
+
+import (
+	"os"
+	"syscall"
+)
+
+// openStorage memory-maps the file, which is closed once mapped.
+func openStorage(f *os.File, size int64) (storage, error) {
+	if size == 0 {
+		return &fileStorage{f: f}, nil
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+
+	// the mapping stays valid after the file is closed
+	f.Close()
+	return &mmapStorage{data: data, unmap: syscall.Munmap}, nil
+}
@@ -0,0 +1,46 @@
+package bigring
+
+import (
+	"errors"
+	"os"
+)
+
+// fileStorage reads entries from the file as needed, for platforms without
+// memory mapping.
+type fileStorage struct {
+	f *os.File
+}
+
+func (s *fileStorage) slice(off int64, n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := s.f.ReadAt(b, off); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (s *fileStorage) Close() error {
+	return s.f.Close()
+}
+
+// mmapStorage is a memory-mapped file.
+type mmapStorage struct {
+	data  []byte
+	unmap func([]byte) error
+}
+
+func (s *mmapStorage) slice(off int64, n int) ([]byte, error) {
+	if off < 0 || off+int64(n) > int64(len(s.data)) {
+		return nil, errors.New("read out of range")
+	}
+	return s.data[off : off+int64(n) : off+int64(n)], nil
+}
+
+func (s *mmapStorage) Close() error {
+	if s.data == nil {
+		return nil
+	}
+	err := s.unmap(s.data)
+	s.data = nil
+	return err
+}
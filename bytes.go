@@ -0,0 +1,163 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// This file mirrors the core operations with functions taking and returning
+// only encodings and curve IDs, for foreign function interfaces, mobile
+// bindings and scripting languages, and as an API that doesn't depend on the
+// go-dleq curve interfaces. The encodings are:
+//
+//   - rings: the compressed encodings of the public keys, concatenated, as
+//     returned by Ring.Bytes;
+//   - private keys: the curve's scalar encoding (32 bytes; for ed25519, the
+//     little-endian scalar, not the RFC 8032 seed — see
+//     ScalarFromEd25519PrivateKey);
+//   - signatures: as returned by RingSig.Serialize;
+//   - messages: 32-byte digests.
+
+// Bytes returns the encoding of the ring: the compressed encodings of its
+// public keys, concatenated in order. It's decoded by RingFromBytes.
+func (r *Ring) Bytes() []byte {
+	out := make([]byte, 0, len(r.pubkeys)*33)
+	for _, pk := range r.pubkeys {
+		out = append(out, encodePoint(pk)...)
+	}
+	return out
+}
+
+// RingFromBytes decodes a ring encoded by Ring.Bytes over the given curve.
+func RingFromBytes(curveID CurveID, in []byte) (*Ring, error) {
+	curve, err := curveID.Curve()
+	if err != nil {
+		return nil, err
+	}
+	return ringFromBytes(curve, in)
+}
+
+func ringFromBytes(curve types.Curve, in []byte) (*Ring, error) {
+	pointLen, err := PointCompressed.pointLen(curve)
+	if err != nil {
+		return nil, err
+	}
+	if len(in) == 0 || len(in)%pointLen != 0 {
+		return nil, fmt.Errorf("ring encoding length %d is not a multiple of %d", len(in), pointLen)
+	}
+
+	pubkeys := make([]types.Point, len(in)/pointLen)
+	for i := range pubkeys {
+		pubkeys[i], err = curve.DecodeToPoint(in[i*pointLen : (i+1)*pointLen])
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key at index %d: %w", i, err)
+		}
+	}
+	return NewFixedKeyRingFromPublicKeys(curve, pubkeys)
+}
+
+// SignBytes signs the 32-byte message with the encoded private key, whose
+// public key must be a member of the encoded ring, and returns the serialized
+// signature.
+func SignBytes(curveID CurveID, ringBytes, privBytes, msg []byte) ([]byte, error) {
+	curve, err := curveID.Curve()
+	if err != nil {
+		return nil, err
+	}
+	if len(msg) != 32 {
+		return nil, fmt.Errorf("message must be 32 bytes, got %d", len(msg))
+	}
+
+	r, err := ringFromBytes(curve, ringBytes)
+	if err != nil {
+		return nil, err
+	}
+	privKey, err := curve.DecodeToScalar(privBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	sig, err := r.Sign([32]byte(msg), privKey)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Serialize()
+}
+
+// VerifyBytes returns whether the serialized signature is valid for the
+// 32-byte message. It verifies the signature against the ring it contains,
+// so callers must also check that this is the expected ring, with
+// SignatureRingBytes.
+func VerifyBytes(curveID CurveID, sigBytes, msg []byte) bool {
+	sig, err := sigFromBytes(curveID, sigBytes)
+	if err != nil || len(msg) != 32 {
+		return false
+	}
+	return sig.Verify([32]byte(msg))
+}
+
+// SignatureRingBytes returns the encoding of the ring of the serialized
+// signature, as returned by Ring.Bytes.
+func SignatureRingBytes(curveID CurveID, sigBytes []byte) ([]byte, error) {
+	sig, err := sigFromBytes(curveID, sigBytes)
+	if err != nil {
+		return nil, err
+	}
+	return sig.ring.Bytes(), nil
+}
+
+// KeyImageBytes returns the encoding of the normalized key image of the
+// serialized signature, which identifies its signer across signatures; see
+// NormalizeKeyImage.
+func KeyImageBytes(curveID CurveID, sigBytes []byte) ([]byte, error) {
+	sig, err := sigFromBytes(curveID, sigBytes)
+	if err != nil {
+		return nil, err
+	}
+	return encodePoint(NormalizeKeyImage(sig.image)), nil
+}
+
+// LinkBytes returns whether the two serialized signatures were created by
+// the same signer, like Link. It returns false if either can't be decoded.
+func LinkBytes(curveID CurveID, sigA, sigB []byte) bool {
+	a, err := sigFromBytes(curveID, sigA)
+	if err != nil {
+		return false
+	}
+	b, err := sigFromBytes(curveID, sigB)
+	if err != nil {
+		return false
+	}
+	return Link(a, b)
+}
+
+// PublicKeyBytes returns the compressed encoding of the public key of the
+// encoded private key.
+func PublicKeyBytes(curveID CurveID, privBytes []byte) ([]byte, error) {
+	curve, err := curveID.Curve()
+	if err != nil {
+		return nil, err
+	}
+	privKey, err := curve.DecodeToScalar(privBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	if privKey.IsZero() {
+		return nil, errors.New("private key is zero")
+	}
+	return encodePoint(curve.ScalarBaseMul(privKey)), nil
+}
+
+func sigFromBytes(curveID CurveID, in []byte) (*RingSig, error) {
+	curve, err := curveID.Curve()
+	if err != nil {
+		return nil, err
+	}
+	sig := new(RingSig)
+	if err := sig.Deserialize(curve, in); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
@@ -0,0 +1,83 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignBytes(t *testing.T) {
+	for _, id := range []CurveID{CurveSecp256k1, CurveEd25519} {
+		curve, err := id.Curve()
+		require.NoError(t, err)
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 5, privKey, 3)
+		require.NoError(t, err)
+
+		ringBytes := keyring.Bytes()
+		decoded, err := RingFromBytes(id, ringBytes)
+		require.NoError(t, err)
+		require.True(t, decoded.Equals(keyring))
+
+		pub, err := PublicKeyBytes(id, privKey.Encode())
+		require.NoError(t, err)
+		require.Equal(t, encodePoint(curve.ScalarBaseMul(privKey)), pub)
+
+		sig1, err := SignBytes(id, ringBytes, privKey.Encode(), testMsg[:])
+		require.NoError(t, err)
+		require.True(t, VerifyBytes(id, sig1, testMsg[:]))
+		require.False(t, VerifyBytes(id, sig1, make([]byte, 32)))
+		require.False(t, VerifyBytes(id, sig1, testMsg[:31]))
+
+		sigRing, err := SignatureRingBytes(id, sig1)
+		require.NoError(t, err)
+		require.Equal(t, ringBytes, sigRing)
+
+		sig2, err := SignBytes(id, ringBytes, privKey.Encode(), make([]byte, 32))
+		require.NoError(t, err)
+		require.True(t, LinkBytes(id, sig1, sig2))
+
+		image, err := KeyImageBytes(id, sig1)
+		require.NoError(t, err)
+		sig := new(RingSig)
+		require.NoError(t, sig.Deserialize(curve, sig2))
+		require.Equal(t, encodePoint(NormalizeKeyImage(sig.KeyImage())), image)
+
+		otherKey := curve.NewRandomScalar()
+		other, err := NewKeyRing(curve, 5, otherKey, 0)
+		require.NoError(t, err)
+		sig3, err := SignBytes(id, other.Bytes(), otherKey.Encode(), testMsg[:])
+		require.NoError(t, err)
+		require.False(t, LinkBytes(id, sig1, sig3))
+	}
+}
+
+func TestSignBytes_Errors(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 3, privKey, 0)
+	require.NoError(t, err)
+	ringBytes := keyring.Bytes()
+
+	_, err = SignBytes(CurveUnknown, ringBytes, privKey.Encode(), testMsg[:])
+	require.Error(t, err)
+	_, err = SignBytes(CurveSecp256k1, ringBytes, privKey.Encode(), testMsg[:16])
+	require.Error(t, err)
+	_, err = SignBytes(CurveSecp256k1, ringBytes[1:], privKey.Encode(), testMsg[:])
+	require.Error(t, err)
+	_, err = SignBytes(CurveSecp256k1, nil, privKey.Encode(), testMsg[:])
+	require.Error(t, err)
+	_, err = SignBytes(CurveSecp256k1, ringBytes, curve.NewRandomScalar().Encode(), testMsg[:])
+	require.ErrorIs(t, err, ErrSignerNotInRing)
+	// a ring of another curve
+	_, err = SignBytes(CurveEd25519, ringBytes, privKey.Encode(), testMsg[:])
+	require.Error(t, err)
+
+	_, err = PublicKeyBytes(CurveSecp256k1, make([]byte, 32))
+	require.Error(t, err)
+
+	require.False(t, VerifyBytes(CurveSecp256k1, []byte{1, 2, 3}, testMsg[:]))
+	require.False(t, LinkBytes(CurveSecp256k1, nil, nil))
+	_, err = KeyImageBytes(CurveEd25519, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package ring
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// EqualsSet checks whether the supplied ring has the same public keys as the
+// current ring, in any order. Unlike Equals, it doesn't depend on the order
+// in which the rings were assembled. Rings over different curves are never
+// equal.
+func (r *Ring) EqualsSet(other *Ring) bool {
+	if r.Size() != other.Size() || !sameCurve(r.curve, other.curve) {
+		return false
+	}
+
+	a, b := canonicalEncodings(r.pubkeys), canonicalEncodings(other.pubkeys)
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Canonicalize returns a copy of the ring with its public keys sorted by
+// encoding, and the mapping of its indices: the i-th public key of the
+// returned ring is the perm[i]-th public key of r. Rings with the same public
+// keys have the same canonical form, so it can be used to agree on the order
+// of a ring assembled independently by several parties.
+func (r *Ring) Canonicalize() (canonical *Ring, perm []int) {
+	perm, _ = canonicalOrder(r.pubkeys)
+	return r.permute(perm), perm
+}
+
+// canonicalOrder returns the indices of pubkeys sorted by the encodings of
+// the keys, and the encodings.
+func canonicalOrder(pubkeys []types.Point) ([]int, [][]byte) {
+	encs := make([][]byte, len(pubkeys))
+	for i, pk := range pubkeys {
+		encs[i] = encodePoint(pk)
+	}
+
+	perm := make([]int, len(pubkeys))
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.Slice(perm, func(i, j int) bool {
+		return bytes.Compare(encs[perm[i]], encs[perm[j]]) < 0
+	})
+	return perm, encs
+}
+
+// canonicalEncodings returns the encodings of pubkeys, sorted.
+func canonicalEncodings(pubkeys []types.Point) [][]byte {
+	perm, encs := canonicalOrder(pubkeys)
+	sorted := make([][]byte, len(perm))
+	for i, j := range perm {
+		sorted[i] = encs[j]
+	}
+	return sorted
+}
+
+// permute returns a new ring whose i-th public key is the perm[i]-th public
+// key of r. The cached H_p(P_i) values and the weights, if any, are carried
+// over.
+func (r *Ring) permute(perm []int) *Ring {
+	permuted := &Ring{
+		pubkeys: make([]types.Point, len(perm)),
+		curve:   r.curve,
+	}
+	for i, j := range perm {
+		permuted.pubkeys[i] = r.pubkeys[j]
+	}
+	if r.weights != nil {
+		permuted.weights = make([]uint64, len(perm))
+		for i, j := range perm {
+			permuted.weights[i] = r.weights[j]
+		}
+	}
+
+	if cached := r.cachedHP(); cached != nil {
+		hp := make([]types.Point, len(perm))
+		for i, j := range perm {
+			hp[i] = cached[j]
+		}
+		permuted.setHP(hp)
+	}
+
+	return permuted
+}
@@ -0,0 +1,61 @@
+package ring
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEqualsSet(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 6, privKey, 2)
+	require.NoError(t, err)
+
+	proof, err := NewShuffleProof(bytes.NewReader(make([]byte, 32)))
+	require.NoError(t, err)
+	shuffled := keyring.Shuffle(proof)
+	require.True(t, keyring.EqualsSet(shuffled))
+	require.True(t, shuffled.EqualsSet(keyring))
+
+	other, err := NewKeyRing(curve, 6, privKey, 2)
+	require.NoError(t, err)
+	require.False(t, keyring.EqualsSet(other))
+
+	smaller, err := NewKeyRingFromPublicKeys(curve, keyring.pubkeys[:2], curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+	require.False(t, keyring.EqualsSet(smaller))
+
+	edRing, err := NewKeyRing(Ed25519(), 6, Ed25519().NewRandomScalar(), 0)
+	require.NoError(t, err)
+	require.False(t, keyring.EqualsSet(edRing))
+}
+
+func TestCanonicalize(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 8, privKey, 5)
+		require.NoError(t, err)
+
+		canonical, perm := keyring.Canonicalize()
+		require.Len(t, perm, keyring.Size())
+		require.True(t, canonical.EqualsSet(keyring))
+		for i, j := range perm {
+			require.True(t, canonical.pubkeys[i].Equals(keyring.pubkeys[j]))
+		}
+		for i := 1; i < canonical.Size(); i++ {
+			require.Negative(t, bytes.Compare(encodePoint(canonical.pubkeys[i-1]), encodePoint(canonical.pubkeys[i])))
+		}
+
+		// the canonical form doesn't depend on the order
+		proof, err := NewShuffleProof(bytes.NewReader(bytes.Repeat([]byte{1}, 32)))
+		require.NoError(t, err)
+		again, _ := keyring.Shuffle(proof).Canonicalize()
+		require.True(t, again.Equals(canonical))
+
+		sig, err := canonical.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		require.True(t, sig.Verify(testMsg))
+	}
+}
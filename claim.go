@@ -0,0 +1,117 @@
+package ring
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+const imageClaimDomain = "ring-go/image-claim"
+
+// ErrInvalidClaim is returned by VerifyClaim when the claim doesn't prove that
+// the key image belongs to the public key.
+var ErrInvalidClaim = errors.New("invalid key image claim")
+
+// ImageClaim is a voluntary deanonymization: a proof by the owner of a public
+// key P that a key image I is its own, ie. that I = x*H_p(P) for the private
+// key x of P. It lets a signer claim past anonymous signatures, eg. to collect
+// a reward or to accept blame in a slashing protocol, without revealing x.
+//
+// It's a Chaum-Pedersen proof like KeyImageProof, over a separate domain and
+// bound to a context chosen by the application, eg. the action being claimed
+// and the address a reward is paid to, so that a claim can't be replayed in
+// another context.
+type ImageClaim struct {
+	e, s types.Scalar
+}
+
+// ClaimImage creates a claim that image, eg. the key image of a past
+// signature, belongs to the public key of privKey, in the given context. The
+// image is normalized (see NormalizeKeyImage) first, so that the images of
+// signatures accepted under any CofactorPolicy can be claimed. It returns
+// ErrInvalidKeyImage if the image isn't that of privKey.
+func ClaimImage(curve types.Curve, privKey types.Scalar, image types.Point, context []byte) (_ *ImageClaim, err error) {
+	defer recoverInternal(&err)
+
+	if privKey.IsZero() {
+		return nil, errors.New("private key is zero")
+	}
+	if image == nil || CurveIDOfPoint(image) != CurveIDOf(curve) {
+		return nil, fmt.Errorf("%w: not a point of the curve", ErrInvalidKeyImage)
+	}
+
+	pubkey := curve.ScalarBaseMul(privKey)
+	hp := hashToCurve(pubkey)
+	image = NormalizeKeyImage(image)
+	if !equalPoints(curve.ScalarMul(privKey, hp), image) {
+		return nil, fmt.Errorf("%w: not the key image of the private key", ErrInvalidKeyImage)
+	}
+
+	k := curve.NewRandomScalar()
+	e := imageClaimChallenge(curve, pubkey, image, context, curve.ScalarBaseMul(k), curve.ScalarMul(k, hp))
+	return &ImageClaim{e: e, s: k.Sub(e.Mul(privKey))}, nil
+}
+
+// VerifyClaim checks that the claim proves that image belongs to pubkey, in
+// the given context. It returns ErrInvalidClaim if it doesn't.
+func VerifyClaim(curve types.Curve, pubkey, image types.Point, context []byte, claim *ImageClaim) (err error) {
+	defer recoverInternal(&err)
+
+	switch {
+	case claim == nil || pubkey == nil || image == nil:
+		return fmt.Errorf("%w: missing claim, public key or image", ErrInvalidClaim)
+	case CurveIDOfPoint(pubkey) != CurveIDOf(curve) || CurveIDOfPoint(image) != CurveIDOf(curve):
+		return fmt.Errorf("%w: not a point of the curve", ErrInvalidClaim)
+	case isIdentity(pubkey) || isIdentity(image):
+		return fmt.Errorf("%w: identity point", ErrInvalidClaim)
+	}
+
+	image = NormalizeKeyImage(image)
+	t1 := doubleScalarBaseMul(curve, claim.e, pubkey, claim.s)
+	t2 := curve.ScalarMul(claim.s, hashToCurve(pubkey)).Add(curve.ScalarMul(claim.e, image))
+	if !imageClaimChallenge(curve, pubkey, image, context, t1, t2).Eq(claim.e) {
+		return ErrInvalidClaim
+	}
+	return nil
+}
+
+// Bytes returns the encoding of the claim: its two scalars, concatenated.
+func (c *ImageClaim) Bytes() []byte {
+	return append(c.e.Encode(), c.s.Encode()...)
+}
+
+// DecodeImageClaim decodes a claim encoded by ImageClaim.Bytes.
+func DecodeImageClaim(curve types.Curve, in []byte) (*ImageClaim, error) {
+	scalarLen := ScalarSize(curve)
+	if len(in) != 2*scalarLen {
+		return nil, fmt.Errorf("key image claim must be %d bytes, got %d", 2*scalarLen, len(in))
+	}
+	e, err := curve.DecodeToScalar(in[:scalarLen])
+	if err != nil {
+		return nil, err
+	}
+	s, err := curve.DecodeToScalar(in[scalarLen:])
+	if err != nil {
+		return nil, err
+	}
+	return &ImageClaim{e: e, s: s}, nil
+}
+
+func imageClaimChallenge(curve types.Curve, pubkey, image types.Point, context []byte, t1, t2 types.Point) types.Scalar {
+	buf := []byte(imageClaimDomain)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(context)))
+	buf = append(buf, context...)
+	buf = append(buf, encodePoint(pubkey)...)
+	buf = append(buf, encodePoint(image)...)
+	buf = append(buf, encodePoint(t1)...)
+	buf = append(buf, encodePoint(t2)...)
+
+	e, err := curve.HashToScalar(buf)
+	if err != nil {
+		// this should not happen
+		panic(err)
+	}
+	return e
+}
@@ -0,0 +1,54 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimImage(t *testing.T) {
+	context := []byte("reward for block 42 to 0xabcd")
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 4, privKey, 2)
+		require.NoError(t, err)
+		sig, err := keyring.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		pubkey := curve.ScalarBaseMul(privKey)
+
+		claim, err := ClaimImage(curve, privKey, sig.KeyImage(), context)
+		require.NoError(t, err)
+		require.NoError(t, VerifyClaim(curve, pubkey, sig.KeyImage(), context, claim))
+
+		decoded, err := DecodeImageClaim(curve, claim.Bytes())
+		require.NoError(t, err)
+		require.NoError(t, VerifyClaim(curve, pubkey, sig.KeyImage(), context, decoded))
+
+		// another context, member or image
+		require.ErrorIs(t, VerifyClaim(curve, pubkey, sig.KeyImage(), []byte("other"), claim), ErrInvalidClaim)
+		require.ErrorIs(t, VerifyClaim(curve, keyring.pubkeys[0], sig.KeyImage(), context, claim), ErrInvalidClaim)
+		other := createSigWithCurve(t, curve, 2, 0)
+		require.ErrorIs(t, VerifyClaim(curve, pubkey, other.KeyImage(), context, claim), ErrInvalidClaim)
+		require.ErrorIs(t, VerifyClaim(curve, pubkey, sig.KeyImage(), context, nil), ErrInvalidClaim)
+
+		// only the owner can claim an image
+		_, err = ClaimImage(curve, curve.NewRandomScalar(), sig.KeyImage(), context)
+		require.ErrorIs(t, err, ErrInvalidKeyImage)
+
+		_, err = DecodeImageClaim(curve, claim.Bytes()[1:])
+		require.Error(t, err)
+	}
+}
+
+func TestClaimImage_Torsioned(t *testing.T) {
+	curve := Ed25519()
+	torsioned, regular, privKey := signWithTorsion(t, 3, 0)
+	require.False(t, torsioned.KeyImage().Equals(regular.KeyImage()))
+	pubkey := curve.ScalarBaseMul(privKey)
+
+	// a claim of either image covers both
+	claim, err := ClaimImage(curve, privKey, torsioned.KeyImage(), nil)
+	require.NoError(t, err)
+	require.NoError(t, VerifyClaim(curve, pubkey, torsioned.KeyImage(), nil, claim))
+	require.NoError(t, VerifyClaim(curve, pubkey, regular.KeyImage(), nil, claim))
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	ring "github.com/pokt-network/ring-go"
+	"github.com/pokt-network/ring-go/attest"
+)
+
+func attestCmd(args []string, _ io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("attest", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var (
+		keyringPath = fs.String("keyring", "", "maintainers keyring file")
+		keyPath     = fs.String("key", "", "file containing the hex-encoded private key")
+		commit      = fs.String("commit", "", "hash of the Git commit to attest")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	maintainers, err := ring.LoadKeyringFile(*keyringPath)
+	if err != nil {
+		return err
+	}
+
+	privKey, err := readPrivateKeyFile(maintainers.Curve(), *keyPath)
+	if err != nil {
+		return err
+	}
+
+	subject, err := parseSubject(fs, *commit)
+	if err != nil {
+		return err
+	}
+
+	sig, err := attest.Sign(maintainers, privKey, subject)
+	if err != nil {
+		return err
+	}
+
+	armored, err := sig.Armor(subject.String())
+	if err != nil {
+		return err
+	}
+
+	_, err = stdout.Write(armored)
+	return err
+}
+
+func verifyAttestation(args []string, _ io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("verify-attestation", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var (
+		keyringPath = fs.String("keyring", "", "maintainers keyring file")
+		sigPath     = fs.String("sig", "", "armored attestation file")
+		commit      = fs.String("commit", "", "hash of the attested Git commit")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	maintainers, err := ring.LoadKeyringFile(*keyringPath)
+	if err != nil {
+		return err
+	}
+
+	subject, err := parseSubject(fs, *commit)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(*sigPath)
+	if err != nil {
+		return err
+	}
+	sig, _, err := ring.ParseArmored(data)
+	if err != nil {
+		return err
+	}
+
+	if err := attest.Verify(maintainers, subject, sig); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "valid attestation of %s by one of %d maintainers\n", subject, maintainers.Size())
+	fmt.Fprintf(stdout, "key image: %x\n", ring.NormalizeKeyImage(sig.KeyImage()).Encode())
+	return nil
+}
+
+// parseSubject returns the subject given by the -commit flag, or the file
+// given as the only argument.
+func parseSubject(fs *flag.FlagSet, commit string) (attest.Subject, error) {
+	switch {
+	case commit != "" && fs.NArg() == 0:
+		return attest.GitCommitSubject(commit)
+	case commit == "" && fs.NArg() == 1:
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return attest.Subject{}, err
+		}
+		defer f.Close()
+		return attest.FileSubject(f)
+	default:
+		return attest.Subject{}, errors.New("expected either -commit or a single file")
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// writeMaintainers writes a keyring file with size keys on the curve, and a key
+// file with the private key of one of them.
+func writeMaintainers(t *testing.T, dir string, curve ring.Curve, size int) (keyringPath, keyPath string) {
+	privKey := curve.NewRandomScalar()
+	keyring, err := ring.NewKeyRing(curve, size, privKey, size-1)
+	require.NoError(t, err)
+
+	keyringPath = filepath.Join(dir, "maintainers.txt")
+	require.NoError(t, ring.SaveKeyringFile(keyringPath, keyring, nil))
+	keyPath = filepath.Join(dir, "key.hex")
+	require.NoError(t, os.WriteFile(keyPath, []byte(hex.EncodeToString(privKey.Encode())+"\n"), 0o600))
+	return keyringPath, keyPath
+}
+
+func TestAttest(t *testing.T) {
+	dir := t.TempDir()
+	keyringPath, keyPath := writeMaintainers(t, dir, ring.Ed25519(), 4)
+	artifact := filepath.Join(dir, "release.tar.gz")
+	require.NoError(t, os.WriteFile(artifact, []byte("release"), 0o600))
+
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"attest", "-keyring", keyringPath, "-key", keyPath, artifact}, nil, &out))
+	require.Contains(t, out.String(), "Scope: file sha256:")
+	sigPath := filepath.Join(dir, "release.tar.gz.asc")
+	require.NoError(t, os.WriteFile(sigPath, out.Bytes(), 0o600))
+
+	out.Reset()
+	require.NoError(t, run([]string{"verify-attestation", "-keyring", keyringPath, "-sig", sigPath, artifact}, nil, &out))
+	require.Contains(t, out.String(), "valid attestation")
+
+	// a modified artifact
+	require.NoError(t, os.WriteFile(artifact, []byte("backdoored release"), 0o600))
+	require.Error(t, run([]string{"verify-attestation", "-keyring", keyringPath, "-sig", sigPath, artifact}, nil, &out))
+}
+
+func TestAttest_Commit(t *testing.T) {
+	dir := t.TempDir()
+	keyringPath, keyPath := writeMaintainers(t, dir, ring.Secp256k1(), 3)
+	const commit = "2fd4e1c67a2d28fced849ee1bb76e7391b93eb12"
+
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"attest", "-keyring", keyringPath, "-key", keyPath, "-commit", commit}, nil, &out))
+	sigPath := filepath.Join(dir, "commit.asc")
+	require.NoError(t, os.WriteFile(sigPath, out.Bytes(), 0o600))
+
+	require.NoError(t, run([]string{"verify-attestation", "-keyring", keyringPath, "-sig", sigPath, "-commit", commit}, nil, &out))
+	require.Error(t, run([]string{"verify-attestation", "-keyring", keyringPath, "-sig", sigPath,
+		"-commit", strings.Repeat("0", 40)}, nil, &out))
+
+	// either a commit or a file
+	require.Error(t, run([]string{"attest", "-keyring", keyringPath, "-key", keyPath}, nil, &out))
+	require.Error(t, run([]string{"attest", "-keyring", keyringPath, "-key", keyPath, "-commit", commit, sigPath}, nil, &out))
+}
+
+func TestAttest_NotAMaintainer(t *testing.T) {
+	dir := t.TempDir()
+	keyringPath, _ := writeMaintainers(t, dir, ring.Ed25519(), 3)
+	keyPath := filepath.Join(dir, "other.hex")
+	require.NoError(t, os.WriteFile(keyPath, []byte(hex.EncodeToString(ring.Ed25519().NewRandomScalar().Encode())), 0o600))
+
+	var out bytes.Buffer
+	require.Error(t, run([]string{"attest", "-keyring", keyringPath, "-key", keyPath, "-commit", strings.Repeat("a", 40)}, nil, &out))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+func auditDecoys(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("audit-decoys", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var (
+		digestHex = fs.String("digest", "", "hex-encoded 32-byte message digest")
+		seedHex   = fs.String("seed", "", "hex-encoded 32-byte audit seed revealed by the signer")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	digest, err := parseHash(*digestHex)
+	if err != nil {
+		return fmt.Errorf("invalid -digest: %w", err)
+	}
+	seed, err := parseHash(*seedHex)
+	if err != nil {
+		return fmt.Errorf("invalid -seed: %w", err)
+	}
+
+	in := stdin
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	sig, _, err := ring.ParseArmored(data)
+	if err != nil {
+		return err
+	}
+
+	idx, err := sig.AuditDecoys(digest, ring.AuditSeed(seed))
+	switch {
+	case errors.Is(err, ring.ErrInvalidSignature):
+		return errors.New("invalid signature")
+	case err != nil:
+		return err
+	}
+
+	commitment, _ := sig.AuditCommitment()
+	fmt.Fprintf(stdout, "decoy responses derived from seed with commitment %x\n", commitment)
+	fmt.Fprintf(stdout, "signer: member %d of %d, public key %x\n", idx, sig.Ring().Size(), sig.Ring().PublicKeys()[idx].Encode())
+	return nil
+}
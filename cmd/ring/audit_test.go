@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+func TestAuditDecoys(t *testing.T) {
+	curve := ring.Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := ring.NewKeyRing(curve, 5, privKey, 3)
+	require.NoError(t, err)
+
+	seed, err := ring.NewAuditSeed()
+	require.NoError(t, err)
+	digest := [32]byte{1, 2, 3}
+	sig, err := keyring.Sign(digest, privKey, ring.WithAuditableDecoys(seed))
+	require.NoError(t, err)
+	armored, err := sig.Armor("")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	err = run([]string{"audit-decoys", "-digest", hex.EncodeToString(digest[:]),
+		"-seed", hex.EncodeToString(seed[:])}, bytes.NewReader(armored), &out)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "signer: member 3 of 5, public key "+
+		hex.EncodeToString(curve.ScalarBaseMul(privKey).Encode()))
+
+	other := [32]byte{4}
+	err = run([]string{"audit-decoys", "-digest", hex.EncodeToString(digest[:]),
+		"-seed", hex.EncodeToString(other[:])}, bytes.NewReader(armored), &out)
+	require.ErrorIs(t, err, ring.ErrAuditFailed)
+
+	err = run([]string{"audit-decoys", "-digest", hex.EncodeToString(other[:]),
+		"-seed", hex.EncodeToString(seed[:])}, bytes.NewReader(armored), &out)
+	require.EqualError(t, err, "invalid signature")
+
+	err = run([]string{"audit-decoys", "-digest", hex.EncodeToString(digest[:]),
+		"-seed", "abcd"}, bytes.NewReader(armored), &out)
+	require.Error(t, err)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// readPrivateKeyFile reads a hex-encoded private key on the given curve.
+func readPrivateKeyFile(curve ring.Curve, path string) (ring.Scalar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	return curve.DecodeToScalar(b)
+}
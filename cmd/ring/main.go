@@ -0,0 +1,184 @@
+// Command ring works with ring signatures from the command line.
+//
+// Usage:
+//
+//	ring verify-armored -digest <hex> [-ring-hash <hex>] [file]
+//	ring attest -keyring <file> -key <file> (-commit <hash> | <file>)
+//	ring verify-attestation -keyring <file> -sig <file> (-commit <hash> | <file>)
+//	ring audit-decoys -digest <hex> -seed <hex> [file]
+//	ring selftest [-curve <name>] [-json]
+//
+// verify-armored verifies an armored ring signature (see ring.RingSig.Armor)
+// read from the file, or from stdin, over the given 32-byte message digest.
+//
+// attest signs a file or a Git commit as one of the maintainers listed in the
+// keyring file (see package attest), with the hex-encoded private key read
+// from the key file, and writes the armored attestation to stdout.
+// verify-attestation verifies such an attestation against the keyring file.
+//
+// audit-decoys checks that the decoy responses of an armored signature created
+// with ring.WithAuditableDecoys were derived from the hex-encoded seed revealed
+// by the signer (see ring.RingSig.AuditDecoys), and prints the signer's index
+// and public key.
+//
+// selftest runs ring.SelfTest on the given curve, or on all the curves of the
+// package, and prints the result of each check, as JSON with -json.
+//
+// Keyring files are in the format read by ring.LoadKeyringFile, listing one
+// public key per line, as a curve name followed by the hex-encoded compressed
+// public key; text after a '#' is ignored:
+//
+//	# maintainers
+//	ed25519 d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a  # alice
+//
+// The commands exit with status 0 on success, and 1 otherwise, eg. if a
+// signature is invalid.
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// command is a subcommand of ring.
+type command struct {
+	usage string
+	run   func(args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = map[string]command{
+	"verify-armored": {
+		usage: "verify-armored -digest <hex> [-ring-hash <hex>] [file]",
+		run:   verifyArmored,
+	},
+	"attest": {
+		usage: "attest -keyring <file> -key <file> (-commit <hash> | <file>)",
+		run:   attestCmd,
+	},
+	"verify-attestation": {
+		usage: "verify-attestation -keyring <file> -sig <file> (-commit <hash> | <file>)",
+		run:   verifyAttestation,
+	},
+	"audit-decoys": {
+		usage: "audit-decoys -digest <hex> -seed <hex> [file]",
+		run:   auditDecoys,
+	},
+	"selftest": {
+		usage: "selftest [-curve <name>] [-json]",
+		run:   selfTestCmd,
+	},
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "ring: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("no command given\n" + usage())
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage())
+	}
+
+	return cmd.run(args[1:], stdin, stdout)
+}
+
+func usage() string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	s := "usage:"
+	for _, name := range names {
+		s += "\n  ring " + commands[name].usage
+	}
+	return s
+}
+
+func verifyArmored(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("verify-armored", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var (
+		digestHex   = fs.String("digest", "", "hex-encoded 32-byte message digest")
+		ringHashHex = fs.String("ring-hash", "", "hex-encoded hash of the expected ring")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	digest, err := parseHash(*digestHex)
+	if err != nil {
+		return fmt.Errorf("invalid -digest: %w", err)
+	}
+
+	in := stdin
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	sig, scope, err := ring.ParseArmored(data)
+	if err != nil {
+		return err
+	}
+
+	ringHash := sig.Ring().Hash()
+	if *ringHashHex != "" {
+		expected, err := parseHash(*ringHashHex)
+		if err != nil {
+			return fmt.Errorf("invalid -ring-hash: %w", err)
+		}
+		if expected != ringHash {
+			return errors.New("signature is over a different ring")
+		}
+	}
+
+	if !sig.Verify(digest) {
+		return errors.New("invalid signature")
+	}
+
+	fmt.Fprintf(stdout, "valid signature by a member of ring %x (%d members)\n", ringHash, sig.Ring().Size())
+	if scope != "" {
+		fmt.Fprintf(stdout, "scope: %s\n", scope)
+	}
+	fmt.Fprintf(stdout, "key image: %x\n", ring.NormalizeKeyImage(sig.KeyImage()).Encode())
+	return nil
+}
+
+// parseHash decodes a hex-encoded 32-byte value.
+func parseHash(s string) ([32]byte, error) {
+	var out [32]byte
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return out, err
+	}
+	if len(b) != len(out) {
+		return out, fmt.Errorf("expected 32 bytes, got %d", len(b))
+	}
+	copy(out[:], b)
+	return out, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+func armoredSig(t *testing.T, digest [32]byte) (*ring.RingSig, []byte) {
+	curve := ring.Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := ring.NewKeyRing(curve, 4, privKey, 2)
+	require.NoError(t, err)
+	sig, err := keyring.Sign(digest, privKey)
+	require.NoError(t, err)
+	armored, err := sig.Armor("test scope")
+	require.NoError(t, err)
+	return sig, armored
+}
+
+func TestVerifyArmored(t *testing.T) {
+	digest := [32]byte{1, 2, 3}
+	sig, armored := armoredSig(t, digest)
+	ringHash := sig.Ring().Hash()
+
+	var out bytes.Buffer
+	err := run([]string{"verify-armored", "-digest", hex.EncodeToString(digest[:]),
+		"-ring-hash", hex.EncodeToString(ringHash[:])}, bytes.NewReader(armored), &out)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "valid signature")
+	require.Contains(t, out.String(), "scope: test scope")
+
+	// from a file
+	path := filepath.Join(t.TempDir(), "sig.asc")
+	require.NoError(t, os.WriteFile(path, armored, 0o600))
+	out.Reset()
+	err = run([]string{"verify-armored", "-digest", hex.EncodeToString(digest[:]), path}, nil, &out)
+	require.NoError(t, err)
+}
+
+func TestVerifyArmored_Invalid(t *testing.T) {
+	digest := [32]byte{1, 2, 3}
+	_, armored := armoredSig(t, digest)
+
+	var out bytes.Buffer
+	other := [32]byte{4}
+	err := run([]string{"verify-armored", "-digest", hex.EncodeToString(other[:])}, bytes.NewReader(armored), &out)
+	require.EqualError(t, err, "invalid signature")
+
+	err = run([]string{"verify-armored", "-digest", hex.EncodeToString(digest[:]),
+		"-ring-hash", hex.EncodeToString(other[:])}, bytes.NewReader(armored), &out)
+	require.Error(t, err)
+
+	err = run([]string{"verify-armored", "-digest", "abcd"}, bytes.NewReader(armored), &out)
+	require.Error(t, err)
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	require.Error(t, run(nil, nil, nil))
+	require.Error(t, run([]string{"nope"}, nil, nil))
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// selfTestJSON is the JSON output of selftest for a check.
+type selfTestJSON struct {
+	Curve       string `json:"curve"`
+	Check       string `json:"check"`
+	Passed      bool   `json:"passed"`
+	Error       string `json:"error,omitempty"`
+	KnownAnswer bool   `json:"known_answer"`
+	DurationNS  int64  `json:"duration_ns"`
+}
+
+func selfTestCmd(args []string, _ io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("selftest", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var (
+		curveName = fs.String("curve", "", "curve to test (default: all)")
+		asJSON    = fs.Bool("json", false, "print the results as JSON")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	curves := []ring.CurveID{ring.CurveSecp256k1, ring.CurveEd25519}
+	if *curveName != "" {
+		id, err := ring.ParseCurveID(*curveName)
+		if err != nil {
+			return err
+		}
+		curves = []ring.CurveID{id}
+	}
+
+	var (
+		results []selfTestJSON
+		errs    []error
+	)
+	for _, id := range curves {
+		curve, err := id.Curve()
+		if err != nil {
+			return err
+		}
+		report := ring.SelfTest(curve)
+		errs = append(errs, report.Err())
+		for _, res := range report.Results {
+			out := selfTestJSON{
+				Curve:       report.Curve,
+				Check:       res.Name,
+				Passed:      res.Err == nil,
+				KnownAnswer: res.KnownAnswer,
+				DurationNS:  res.Duration.Nanoseconds(),
+			}
+			if res.Err != nil {
+				out.Error = res.Err.Error()
+			}
+			results = append(results, out)
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		for _, res := range results {
+			status := "ok"
+			if !res.Passed {
+				status = "FAIL: " + res.Error
+			}
+			fmt.Fprintf(stdout, "%-10s %-14s %s\n", res.Curve, res.Check, status)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return errors.New("self-test failed")
+	}
+	return nil
+}
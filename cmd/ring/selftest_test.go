@@ -0,0 +1,28 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"selftest"}, nil, &out))
+	require.Contains(t, out.String(), "secp256k1  sign-verify    ok\n")
+	require.Contains(t, out.String(), "ed25519    serialize      ok\n")
+
+	out.Reset()
+	require.NoError(t, run([]string{"selftest", "-curve", "ed25519", "-json"}, nil, &out))
+	var results []selfTestJSON
+	require.NoError(t, json.Unmarshal(out.Bytes(), &results))
+	require.Len(t, results, 6)
+	for _, res := range results {
+		require.Equal(t, "ed25519", res.Curve)
+		require.True(t, res.Passed, res.Check)
+	}
+
+	require.Error(t, run([]string{"selftest", "-curve", "p256"}, nil, &out))
+}
@@ -0,0 +1,341 @@
+// Command ringbench benchmarks ring signature signing and verification across
+// curves, ring sizes and backends, and writes the results as JSON or CSV.
+//
+// The output format is stable so that results can be compared across releases:
+//
+//	ringbench -sizes 2,16,128 -format csv -out results.csv
+//	ringbench -curves ed25519 -cpuprofile cpu.prof -memprofile mem.prof
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// formatVersion is the version of the JSON and CSV output formats. It must be
+// incremented whenever a field is removed or its meaning changes.
+const formatVersion = 1
+
+// backend is a named set of curve implementations.
+type backend struct {
+	name   string
+	curves map[string]func() ring.Curve
+}
+
+var backends = []backend{
+	{
+		name: "go-dleq",
+		curves: map[string]func() ring.Curve{
+			"secp256k1": ring.Secp256k1,
+			"ed25519":   ring.Ed25519,
+		},
+	},
+	{
+		name: "edwards25519",
+		curves: map[string]func() ring.Curve{
+			"ed25519": func() ring.Curve {
+				curve, _ := ring.NewEd25519(ring.Ed25519ImplEdwards25519)
+				return curve
+			},
+		},
+	},
+}
+
+// Report is the JSON output of ringbench.
+type Report struct {
+	Version   int       `json:"version"`
+	GoVersion string    `json:"go_version"`
+	GOOS      string    `json:"goos"`
+	GOARCH    string    `json:"goarch"`
+	CPUs      int       `json:"cpus"`
+	Results   []*Result `json:"results"`
+}
+
+// Result is the result of benchmarking a single operation.
+type Result struct {
+	Curve       string  `json:"curve"`
+	Backend     string  `json:"backend"`
+	Size        int     `json:"size"`
+	Op          string  `json:"op"`
+	Iterations  int     `json:"iterations"`
+	NsPerOp     int64   `json:"ns_per_op"`
+	NsPerMember float64 `json:"ns_per_member"`
+	AllocsPerOp uint64  `json:"allocs_per_op"`
+	BytesPerOp  uint64  `json:"bytes_per_op"`
+}
+
+type config struct {
+	curves     []string
+	backends   []string
+	sizes      []int
+	iterations int
+}
+
+func main() {
+	var (
+		curves     = flag.String("curves", "secp256k1,ed25519", "comma-separated list of curves to benchmark")
+		backendsF  = flag.String("backends", "go-dleq", "comma-separated list of backends to benchmark")
+		sizes      = flag.String("sizes", "2,4,8,16,32,64,128", "comma-separated list of ring sizes to benchmark")
+		iterations = flag.Int("iterations", 10, "number of iterations per operation")
+		format     = flag.String("format", "json", "output format: json or csv")
+		out        = flag.String("out", "", "output file, defaults to stdout")
+		cpuProfile = flag.String("cpuprofile", "", "write a CPU profile to this file")
+		memProfile = flag.String("memprofile", "", "write a heap profile to this file")
+	)
+	flag.Parse()
+
+	if err := run(*curves, *backendsF, *sizes, *iterations, *format, *out, *cpuProfile, *memProfile); err != nil {
+		fmt.Fprintf(os.Stderr, "ringbench: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(curves, backendNames, sizes string, iterations int, format, out, cpuProfile, memProfile string) error {
+	cfg, err := parseConfig(curves, backendNames, sizes, iterations)
+	if err != nil {
+		return err
+	}
+
+	if format != "json" && format != "csv" {
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	w := io.Writer(os.Stdout)
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if cpuProfile != "" {
+		f, err := os.Create(cpuProfile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return err
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	report, err := benchmark(cfg)
+	if err != nil {
+		return err
+	}
+
+	if memProfile != "" {
+		f, err := os.Create(memProfile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			return err
+		}
+	}
+
+	if format == "csv" {
+		return writeCSV(w, report)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func parseConfig(curves, backendNames, sizes string, iterations int) (*config, error) {
+	if iterations < 1 {
+		return nil, errors.New("iterations must be at least 1")
+	}
+
+	cfg := &config{
+		curves:     strings.Split(curves, ","),
+		backends:   strings.Split(backendNames, ","),
+		iterations: iterations,
+	}
+
+	for _, s := range strings.Split(sizes, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ring size %q: %w", s, err)
+		}
+		if size < 2 || size > ring.MaxRingSize {
+			return nil, fmt.Errorf("ring size %d out of range", size)
+		}
+		cfg.sizes = append(cfg.sizes, size)
+	}
+
+	return cfg, nil
+}
+
+func findBackend(name string) (*backend, error) {
+	for i := range backends {
+		if backends[i].name == name {
+			return &backends[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown backend %q", name)
+}
+
+func benchmark(cfg *config) (*Report, error) {
+	report := &Report{
+		Version:   formatVersion,
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		CPUs:      runtime.NumCPU(),
+	}
+
+	for _, backendName := range cfg.backends {
+		b, err := findBackend(strings.TrimSpace(backendName))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, curveName := range cfg.curves {
+			curveName = strings.TrimSpace(curveName)
+			newCurve, ok := b.curves[curveName]
+			if !ok {
+				return nil, fmt.Errorf("backend %s does not support curve %q", b.name, curveName)
+			}
+
+			for _, size := range cfg.sizes {
+				results, err := benchmarkCase(newCurve(), size, cfg.iterations)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, res := range results {
+					res.Curve = curveName
+					res.Backend = b.name
+					report.Results = append(report.Results, res)
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// benchmarkCase benchmarks signing and verification for a single curve and
+// ring size.
+func benchmarkCase(curve ring.Curve, size, iterations int) ([]*Result, error) {
+	privKey := curve.NewRandomScalar()
+	keyring, err := ring.NewKeyRing(curve, size, privKey, size/2)
+	if err != nil {
+		return nil, err
+	}
+
+	var msg [32]byte
+	// warm up, which also precomputes the ring's hash-to-curve values
+	sig, err := keyring.Sign(msg, privKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sign := measure(size, iterations, func() error {
+		sig, err = keyring.Sign(msg, privKey)
+		return err
+	})
+	if sign.err != nil {
+		return nil, sign.err
+	}
+	sign.Op = "sign"
+
+	verify := measure(size, iterations, func() error {
+		if !sig.Verify(msg) {
+			return errors.New("failed to verify signature")
+		}
+		return nil
+	})
+	if verify.err != nil {
+		return nil, verify.err
+	}
+	verify.Op = "verify"
+
+	return []*Result{sign.Result, verify.Result}, nil
+}
+
+type measurement struct {
+	*Result
+	err error
+}
+
+func measure(size, iterations int, fn func() error) measurement {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	start := time.Now()
+	for i := 0; i < iterations; i++ {
+		if err := fn(); err != nil {
+			return measurement{err: err}
+		}
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	nsPerOp := elapsed.Nanoseconds() / int64(iterations)
+	return measurement{
+		Result: &Result{
+			Size:        size,
+			Iterations:  iterations,
+			NsPerOp:     nsPerOp,
+			NsPerMember: float64(nsPerOp) / float64(size),
+			AllocsPerOp: (after.Mallocs - before.Mallocs) / uint64(iterations),
+			BytesPerOp:  (after.TotalAlloc - before.TotalAlloc) / uint64(iterations),
+		},
+	}
+}
+
+var csvHeader = []string{
+	"version", "curve", "backend", "size", "op", "iterations",
+	"ns_per_op", "ns_per_member", "allocs_per_op", "bytes_per_op",
+}
+
+func writeCSV(w io.Writer, report *Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, res := range report.Results {
+		err := cw.Write([]string{
+			strconv.Itoa(report.Version),
+			res.Curve,
+			res.Backend,
+			strconv.Itoa(res.Size),
+			res.Op,
+			strconv.Itoa(res.Iterations),
+			strconv.FormatInt(res.NsPerOp, 10),
+			strconv.FormatFloat(res.NsPerMember, 'f', 1, 64),
+			strconv.FormatUint(res.AllocsPerOp, 10),
+			strconv.FormatUint(res.BytesPerOp, 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_JSON(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.json")
+	cpu := filepath.Join(dir, "cpu.prof")
+	mem := filepath.Join(dir, "mem.prof")
+
+	err := run("secp256k1,ed25519", "go-dleq", "2,3", 1, "json", out, cpu, mem)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+
+	var report Report
+	require.NoError(t, json.Unmarshal(data, &report))
+	require.Equal(t, formatVersion, report.Version)
+	require.Len(t, report.Results, 8) // 2 curves * 2 sizes * (sign + verify)
+	for _, res := range report.Results {
+		require.Equal(t, "go-dleq", res.Backend)
+		require.Positive(t, res.NsPerOp)
+	}
+
+	for _, f := range []string{cpu, mem} {
+		info, err := os.Stat(f)
+		require.NoError(t, err)
+		require.Positive(t, info.Size())
+	}
+}
+
+func TestRun_CSV(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.csv")
+	err := run("ed25519", "go-dleq", "4", 2, "csv", out, "", "")
+	require.NoError(t, err)
+
+	f, err := os.Open(out)
+	require.NoError(t, err)
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, csvHeader, records[0])
+	require.Equal(t, "sign", records[1][4])
+	require.Equal(t, "verify", records[2][4])
+}
+
+func TestRun_InvalidConfig(t *testing.T) {
+	require.Error(t, run("secp256k1", "go-dleq", "1", 1, "json", "", "", ""))
+	require.Error(t, run("secp256k1", "go-dleq", "2", 0, "json", "", "", ""))
+	require.Error(t, run("p256", "go-dleq", "2", 1, "json", "", "", ""))
+	require.Error(t, run("secp256k1", "cgo", "2", 1, "json", "", "", ""))
+	require.Error(t, run("secp256k1", "go-dleq", "2", 1, "xml", "", "", ""))
+}
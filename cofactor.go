@@ -0,0 +1,88 @@
+package ring
+
+import (
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// CofactorPolicy selects how key images with a small-order (torsion)
+// component are handled. Such images can only occur on curves with a cofactor,
+// ie. ed25519, whose group has points of order 2, 4 and 8 besides the
+// prime-order subgroup; on secp256k1, whose cofactor is 1, all policies are
+// the same.
+//
+// Honest signers always produce torsion-free key images. A dishonest signer
+// can add a small-order point T to its key image I, and still produce a valid
+// signature about one time in eight: I+T is then a different point from I,
+// so registries comparing raw images would accept it as a new signer. The
+// policies close this gap at different places.
+type CofactorPolicy int
+
+const (
+	// RequireTorsionFree rejects signatures whose key image has a torsion
+	// component, so that the key images of valid signatures can be compared
+	// as is. It's the default, and the behavior of Verify.
+	RequireTorsionFree CofactorPolicy = iota
+	// ClearOnImage accepts signatures whose key image has a torsion
+	// component, and clears it from the key image returned by
+	// CofactorPolicy.KeyImage, so that registries keyed by it link them with
+	// the signer's other signatures.
+	ClearOnImage
+	// ClearOnLink accepts signatures whose key image has a torsion component,
+	// and only clears it when comparing images in CofactorPolicy.Link, the
+	// behavior of earlier versions of this package. CofactorPolicy.KeyImage
+	// returns the image as signed, so registries must normalize it
+	// themselves, with NormalizeKeyImage, or a signer can evade them.
+	ClearOnLink
+)
+
+// String returns the name of the policy.
+func (p CofactorPolicy) String() string {
+	switch p {
+	case RequireTorsionFree:
+		return "RequireTorsionFree"
+	case ClearOnImage:
+		return "ClearOnImage"
+	case ClearOnLink:
+		return "ClearOnLink"
+	default:
+		return fmt.Sprintf("CofactorPolicy(%d)", int(p))
+	}
+}
+
+// accepts returns whether verification accepts the key image under the
+// policy. Unknown policies are treated as RequireTorsionFree.
+func (p CofactorPolicy) accepts(image types.Point) bool {
+	switch p {
+	case ClearOnImage, ClearOnLink:
+		return true
+	default:
+		return isTorsionFree(image)
+	}
+}
+
+// KeyImage returns the key image of the signature under the policy: the
+// normalized image (see NormalizeKeyImage) for ClearOnImage, and the image as
+// signed otherwise.
+func (p CofactorPolicy) KeyImage(sig *RingSig) types.Point {
+	if p == ClearOnImage {
+		return NormalizeKeyImage(sig.image)
+	}
+	return sig.KeyImage()
+}
+
+// Link returns whether the two signatures were created by the same signer
+// under the policy. With RequireTorsionFree, it returns false if either key
+// image has a torsion component, as such signatures aren't valid under it;
+// with the other policies, it compares the normalized images.
+func (p CofactorPolicy) Link(sigA, sigB *RingSig) bool {
+	if !sameCurve(sigA.ring.curve, sigB.ring.curve) {
+		return false
+	}
+
+	if p.accepts(sigA.image) && p.accepts(sigB.image) {
+		return NormalizeKeyImage(sigA.image).Equals(NormalizeKeyImage(sigB.image))
+	}
+	return false
+}
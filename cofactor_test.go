@@ -0,0 +1,111 @@
+package ring
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// signWithTorsion returns a signature by a random key over a ring of the given
+// size, whose key image has an order-2 component, a regular signature by the
+// same key, and the key. The torsioned signature is valid when the challenge
+// of the signer's step is even, so it's retried until it is.
+func signWithTorsion(t *testing.T, size, idx int) (torsioned, regular *RingSig, privKey Scalar) {
+	t.Helper()
+	// (0, -1), of order 2
+	order2Bytes, err := hex.DecodeString("ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	require.NoError(t, err)
+	curve := Ed25519()
+	order2, err := curve.DecodeToPoint(order2Bytes)
+	require.NoError(t, err)
+
+	privKey = curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, size, privKey, idx)
+	require.NoError(t, err)
+	regular, err = keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+
+	hps := keyring.hashPoints()
+	for {
+		sig := &RingSig{
+			ring:  keyring,
+			image: curve.ScalarMul(privKey, hps[idx]).Add(order2),
+			s:     make([]Scalar, size),
+		}
+		ch := newChallenger(curve, testMsg)
+		u := curve.NewRandomScalar()
+		c := ch.challenge(curve.ScalarBaseMul(u), curve.ScalarMul(u, hps[idx]))
+		for n := 1; n < size; n++ {
+			i := (idx + n) % size
+			if i == 0 {
+				sig.c = c
+			}
+			sig.s[i] = curve.NewRandomScalar()
+			l := doubleScalarBaseMul(curve, c, keyring.pubkeys[i], sig.s[i])
+			r := curve.ScalarMul(sig.s[i], hps[i]).Add(curve.ScalarMul(c, sig.image))
+			c = ch.challenge(l, r)
+		}
+		if idx == 0 {
+			sig.c = c
+		}
+		if c.Encode()[0]&1 != 0 {
+			continue
+		}
+		sig.s[idx] = u.Sub(c.Mul(privKey))
+		return sig, regular, privKey
+	}
+}
+
+func TestCofactorPolicy(t *testing.T) {
+	torsioned, regular, _ := signWithTorsion(t, 3, 1)
+	require.False(t, torsioned.Verify(testMsg))
+	require.False(t, isTorsionFree(torsioned.image))
+
+	for _, tc := range []struct {
+		policy   CofactorPolicy
+		accepted bool
+		linked   bool
+		// whether the images returned by KeyImage are equal
+		sameImage bool
+	}{
+		{RequireTorsionFree, false, false, false},
+		{ClearOnImage, true, true, true},
+		{ClearOnLink, true, true, false},
+	} {
+		t.Run(tc.policy.String(), func(t *testing.T) {
+			err := torsioned.VerifyWithOpts(testMsg, &VerifyOpts{Cofactor: tc.policy})
+			if tc.accepted {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrInvalidSignature)
+			}
+			require.NoError(t, regular.VerifyWithOpts(testMsg, &VerifyOpts{Cofactor: tc.policy}))
+
+			require.Equal(t, tc.linked, tc.policy.Link(torsioned, regular))
+			require.True(t, tc.policy.Link(regular, regular))
+			require.Equal(t, tc.sameImage, tc.policy.KeyImage(torsioned).Equals(tc.policy.KeyImage(regular)))
+
+			ctx, err := NewContext(Ed25519(), WithCofactorPolicy(tc.policy))
+			require.NoError(t, err)
+			require.Equal(t, tc.accepted, ctx.Verify(torsioned, testMsg) == nil)
+			require.Equal(t, tc.linked, ctx.Link(torsioned, regular))
+			require.True(t, ctx.KeyImage(torsioned).Equals(tc.policy.KeyImage(torsioned)))
+		})
+	}
+
+	// Link clears the torsion whatever the policy
+	require.True(t, Link(torsioned, regular))
+
+	_, err := NewContext(Ed25519(), WithCofactorPolicy(CofactorPolicy(7)))
+	require.Error(t, err)
+}
+
+func TestCofactorPolicy_Secp256k1(t *testing.T) {
+	sig := createSigWithCurve(t, Secp256k1(), 3, 0)
+	for _, p := range []CofactorPolicy{RequireTorsionFree, ClearOnImage, ClearOnLink} {
+		require.NoError(t, sig.VerifyWithOpts(testMsg, &VerifyOpts{Cofactor: p}))
+		require.True(t, p.KeyImage(sig).Equals(sig.KeyImage()))
+		require.True(t, p.Link(sig, sig))
+	}
+}
@@ -0,0 +1,258 @@
+// Package cometring builds rings from CometBFT (Tendermint) validator sets, for
+// "anonymous validator attestation": a message signed by one of the validators
+// of a chain at a given height, without revealing which.
+//
+// Validator sets are read from the response of the CometBFT RPC /validators
+// endpoint, so the package doesn't depend on CometBFT:
+//
+//	set, err := cometring.ParseValidators(rpcResponse)
+//	vr, err := cometring.NewValidatorRing(chainID, set)
+//	sig, err := vr.Sign(msg, privKey)
+//	...
+//	err = vr.Verify(msg, sig)
+//
+// The ring holds the validators' ed25519 keys in canonical order (sorted by
+// key), so that every node builds the same ring from the same set, whatever
+// the order of the RPC response.
+package cometring
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"golang.org/x/crypto/sha3"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+const (
+	// PubKeyTypeEd25519 is the amino type of ed25519 validator keys.
+	PubKeyTypeEd25519 = "tendermint/PubKeyEd25519"
+	// PrivKeyTypeEd25519 is the amino type of ed25519 validator private keys.
+	PrivKeyTypeEd25519 = "tendermint/PrivKeyEd25519"
+
+	hashDomain    = "ring-go/cometbft/ring"
+	messageDomain = "ring-go/cometbft/message"
+)
+
+// ErrWrongRing is returned by Verify when a signature is made with a ring
+// other than the validator ring.
+var ErrWrongRing = errors.New("signature is not made with the validator ring")
+
+// Validator is a validator of a CometBFT validator set.
+type Validator struct {
+	// Address is the hex-encoded address of the validator, the first 20
+	// bytes of the SHA-256 digest of its public key.
+	Address string
+	// PubKey is the validator's ed25519 public key.
+	PubKey []byte
+	// VotingPower is the validator's voting power.
+	VotingPower int64
+}
+
+// ValidatorSet is the validator set of a chain at a given height.
+type ValidatorSet struct {
+	Height     int64
+	Validators []Validator
+}
+
+type rpcValidators struct {
+	BlockHeight string `json:"block_height"`
+	Validators  []struct {
+		Address string `json:"address"`
+		PubKey  struct {
+			Type  string `json:"type"`
+			Value []byte `json:"value"`
+		} `json:"pub_key"`
+		VotingPower string `json:"voting_power"`
+	} `json:"validators"`
+	Count string `json:"count"`
+	Total string `json:"total"`
+}
+
+// ParseValidators parses the response of the CometBFT RPC /validators
+// endpoint, either the JSON-RPC envelope or its result. The response must hold
+// the whole validator set, ie. not a single page of a larger set.
+//
+// Validator addresses are checked against their public keys.
+func ParseValidators(response []byte) (*ValidatorSet, error) {
+	var envelope struct {
+		Result *json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(response, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Result != nil {
+		response = *envelope.Result
+	}
+
+	var res rpcValidators
+	if err := json.Unmarshal(response, &res); err != nil {
+		return nil, err
+	}
+
+	height, err := strconv.ParseInt(res.BlockHeight, 10, 64)
+	if err != nil || height <= 0 {
+		return nil, fmt.Errorf("invalid block height %q", res.BlockHeight)
+	}
+	if res.Total != "" && res.Total != strconv.Itoa(len(res.Validators)) {
+		return nil, fmt.Errorf("incomplete validator set: got %d of %s validators", len(res.Validators), res.Total)
+	}
+
+	set := &ValidatorSet{Height: height, Validators: make([]Validator, len(res.Validators))}
+	for i, v := range res.Validators {
+		if v.PubKey.Type != PubKeyTypeEd25519 {
+			return nil, fmt.Errorf("validator %d: unsupported key type %q", i, v.PubKey.Type)
+		}
+		power, err := strconv.ParseInt(v.VotingPower, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("validator %d: invalid voting power: %w", i, err)
+		}
+
+		addr := Address(v.PubKey.Value)
+		if v.Address != "" && !bytes.EqualFold([]byte(v.Address), []byte(addr)) {
+			return nil, fmt.Errorf("validator %d: address does not match public key", i)
+		}
+
+		set.Validators[i] = Validator{Address: addr, PubKey: v.PubKey.Value, VotingPower: power}
+	}
+	return set, nil
+}
+
+// Address returns the CometBFT address of an ed25519 validator key, in
+// upper-case hex.
+func Address(pubKey []byte) string {
+	sum := sha256.Sum256(pubKey)
+	return fmt.Sprintf("%X", sum[:20])
+}
+
+// ParsePrivValidatorKey returns the private key of a validator's
+// priv_validator_key.json file, to sign with a ValidatorRing.
+func ParsePrivValidatorKey(b []byte) (ring.Scalar, error) {
+	var key struct {
+		PrivKey struct {
+			Type  string `json:"type"`
+			Value []byte `json:"value"`
+		} `json:"priv_key"`
+	}
+	if err := json.Unmarshal(b, &key); err != nil {
+		return nil, err
+	}
+	if key.PrivKey.Type != PrivKeyTypeEd25519 {
+		return nil, fmt.Errorf("unsupported key type %q", key.PrivKey.Type)
+	}
+	return ring.ScalarFromEd25519PrivateKey(key.PrivKey.Value)
+}
+
+// ValidatorRing is the ring of the validators of a chain at a given height.
+type ValidatorRing struct {
+	// ChainID and Height identify the validator set.
+	ChainID string
+	Height  int64
+	// Ring holds the validators' keys, sorted by encoding.
+	Ring *ring.Ring
+	// Addresses holds the address of each member of Ring, in the same order.
+	Addresses []string
+}
+
+// NewValidatorRing returns the ring of the validators of the set, which must
+// have at least two validators with distinct keys. Validators with no voting
+// power are excluded.
+func NewValidatorRing(chainID string, set *ValidatorSet) (*ValidatorRing, error) {
+	var (
+		pubkeys   []ring.Point
+		addresses []string
+		seen      = make(map[string]bool)
+	)
+	for i, v := range set.Validators {
+		if v.VotingPower <= 0 {
+			continue
+		}
+		pk, err := ring.PointFromEd25519PublicKey(v.PubKey)
+		if err != nil {
+			return nil, fmt.Errorf("validator %d: %w", i, err)
+		}
+		if seen[string(v.PubKey)] {
+			return nil, fmt.Errorf("validator %d: duplicate public key", i)
+		}
+		seen[string(v.PubKey)] = true
+
+		pubkeys = append(pubkeys, pk)
+		addresses = append(addresses, Address(v.PubKey))
+	}
+	if len(pubkeys) < 2 {
+		return nil, errors.New("validator set has fewer than two validators with voting power")
+	}
+
+	r, err := ring.NewFixedKeyRingFromPublicKeys(ring.Ed25519(), pubkeys)
+	if err != nil {
+		return nil, err
+	}
+	canonical, perm := r.Canonicalize()
+
+	vr := &ValidatorRing{
+		ChainID:   chainID,
+		Height:    set.Height,
+		Ring:      canonical,
+		Addresses: make([]string, len(perm)),
+	}
+	for i, j := range perm {
+		vr.Addresses[i] = addresses[j]
+	}
+	return vr, nil
+}
+
+// Hash returns a digest of the ring and the chain ID and height it's for.
+func (vr *ValidatorRing) Hash() [32]byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte(hashDomain))
+	_, _ = h.Write(binary.BigEndian.AppendUint16(nil, uint16(len(vr.ChainID))))
+	_, _ = h.Write([]byte(vr.ChainID))
+	_, _ = h.Write(binary.BigEndian.AppendUint64(nil, uint64(vr.Height)))
+	ringHash := vr.Ring.Hash()
+	_, _ = h.Write(ringHash[:])
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// String returns the hex encoding of the ring's hash.
+func (vr *ValidatorRing) String() string {
+	hash := vr.Hash()
+	return hex.EncodeToString(hash[:])
+}
+
+// Message returns the message signed for msg, which binds the ring's hash, so
+// that signatures can't be replayed for another chain or height.
+func (vr *ValidatorRing) Message(msg []byte) [32]byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte(messageDomain))
+	hash := vr.Hash()
+	_, _ = h.Write(hash[:])
+	_, _ = h.Write(msg)
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// Sign signs msg with the private key of one of the validators.
+func (vr *ValidatorRing) Sign(msg []byte, privKey ring.Scalar, opts ...ring.SignOption) (*ring.RingSig, error) {
+	return vr.Ring.Sign(vr.Message(msg), privKey, opts...)
+}
+
+// Verify checks that sig is a signature of msg by one of the validators. The
+// signature's ring must be the validator ring.
+func (vr *ValidatorRing) Verify(msg []byte, sig *ring.RingSig) error {
+	if !sig.Ring().Equals(vr.Ring) {
+		return ErrWrongRing
+	}
+	return sig.VerifyWithPolicy(vr.Message(msg), nil)
+}
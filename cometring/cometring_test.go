@@ -0,0 +1,130 @@
+package cometring
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+type testValidator struct {
+	pub  ed25519.PublicKey
+	priv ed25519.PrivateKey
+}
+
+func newValidators(t *testing.T, n int) []testValidator {
+	vals := make([]testValidator, n)
+	for i := range vals {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		vals[i] = testValidator{pub, priv}
+	}
+	return vals
+}
+
+func rpcResponse(height int64, vals []testValidator, total int) []byte {
+	entries := make([]string, len(vals))
+	for i, v := range vals {
+		entries[i] = fmt.Sprintf(`{"address":%q,"pub_key":{"type":"tendermint/PubKeyEd25519","value":%q},"voting_power":"%d","proposer_priority":"0"}`,
+			Address(v.pub), base64.StdEncoding.EncodeToString(v.pub), 10+i)
+	}
+	return []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":-1,"result":{"block_height":"%d","validators":[%s],"count":"%d","total":"%d"}}`,
+		height, strings.Join(entries, ","), len(vals), total))
+}
+
+func TestParseValidators(t *testing.T) {
+	vals := newValidators(t, 3)
+	set, err := ParseValidators(rpcResponse(42, vals, 3))
+	require.NoError(t, err)
+	require.Equal(t, int64(42), set.Height)
+	require.Len(t, set.Validators, 3)
+	for i, v := range set.Validators {
+		require.Equal(t, []byte(vals[i].pub), v.PubKey)
+		require.Equal(t, int64(10+i), v.VotingPower)
+	}
+
+	// the result without the envelope
+	var envelope struct{ Result json.RawMessage }
+	require.NoError(t, json.Unmarshal(rpcResponse(42, vals, 3), &envelope))
+	set2, err := ParseValidators(envelope.Result)
+	require.NoError(t, err)
+	require.Equal(t, set, set2)
+
+	_, err = ParseValidators(rpcResponse(42, vals, 4))
+	require.ErrorContains(t, err, "incomplete validator set")
+	_, err = ParseValidators(rpcResponse(0, vals, 3))
+	require.Error(t, err)
+
+	bad := strings.Replace(string(rpcResponse(42, vals, 3)), Address(vals[0].pub), Address(vals[1].pub), 1)
+	_, err = ParseValidators([]byte(bad))
+	require.ErrorContains(t, err, "address does not match")
+}
+
+func TestValidatorRing(t *testing.T) {
+	vals := newValidators(t, 4)
+	set, err := ParseValidators(rpcResponse(100, vals, 4))
+	require.NoError(t, err)
+
+	vr, err := NewValidatorRing("test-chain", set)
+	require.NoError(t, err)
+	require.Equal(t, 4, vr.Ring.Size())
+
+	// the ring doesn't depend on the order of the validators
+	reversed := []testValidator{vals[3], vals[2], vals[1], vals[0]}
+	set2, err := ParseValidators(rpcResponse(100, reversed, 4))
+	require.NoError(t, err)
+	vr2, err := NewValidatorRing("test-chain", set2)
+	require.NoError(t, err)
+	require.True(t, vr.Ring.Equals(vr2.Ring))
+	require.Equal(t, vr.Addresses, vr2.Addresses)
+	require.Equal(t, vr.Hash(), vr2.Hash())
+
+	for i, pk := range vr.Ring.PublicKeys() {
+		require.Equal(t, Address(pk.Encode()), vr.Addresses[i])
+	}
+
+	// but the hash depends on the height and chain
+	vr2.Height++
+	require.NotEqual(t, vr.Hash(), vr2.Hash())
+	vr2.Height--
+	vr2.ChainID = "other-chain"
+	require.NotEqual(t, vr.Hash(), vr2.Hash())
+
+	key := fmt.Sprintf(`{"address":%q,"pub_key":{},"priv_key":{"type":"tendermint/PrivKeyEd25519","value":%q}}`,
+		Address(vals[1].pub), base64.StdEncoding.EncodeToString(vals[1].priv))
+	privKey, err := ParsePrivValidatorKey([]byte(key))
+	require.NoError(t, err)
+
+	sig, err := vr.Sign([]byte("block 100 is final"), privKey)
+	require.NoError(t, err)
+	require.NoError(t, vr.Verify([]byte("block 100 is final"), sig))
+	require.ErrorIs(t, vr.Verify([]byte("block 101 is final"), sig), ring.ErrInvalidSignature)
+
+	// signatures can't be replayed for another chain
+	require.ErrorIs(t, vr2.Verify([]byte("block 100 is final"), sig), ring.ErrInvalidSignature)
+
+	other, err := NewValidatorRing("test-chain", &ValidatorSet{Height: 100, Validators: set.Validators[:3]})
+	require.NoError(t, err)
+	require.ErrorIs(t, other.Verify([]byte("block 100 is final"), sig), ErrWrongRing)
+}
+
+func TestNewValidatorRing_Errors(t *testing.T) {
+	vals := newValidators(t, 2)
+	set, err := ParseValidators(rpcResponse(1, vals, 2))
+	require.NoError(t, err)
+
+	set.Validators[1].VotingPower = 0
+	_, err = NewValidatorRing("c", set)
+	require.Error(t, err)
+
+	set.Validators[1] = set.Validators[0]
+	_, err = NewValidatorRing("c", set)
+	require.ErrorContains(t, err, "duplicate")
+}
@@ -0,0 +1,108 @@
+package ring
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The tests in this file exercise the concurrency guarantees documented on Ring
+// and RingSig. They are most useful when run with the race detector:
+//
+//	go test -race -run Concurrent .
+
+const concurrency = 8
+
+func runConcurrently(t *testing.T, fn func() error) {
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrency)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- fn()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+func TestConcurrentSign(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 8, privKey, 5)
+		require.NoError(t, err)
+
+		sigs := make(chan *RingSig, concurrency)
+		runConcurrently(t, func() error {
+			sig, err := keyring.Sign(testMsg, privKey)
+			sigs <- sig
+			return err
+		})
+		close(sigs)
+
+		var first *RingSig
+		for sig := range sigs {
+			require.True(t, sig.Verify(testMsg))
+			if first != nil {
+				require.True(t, Link(first, sig))
+			}
+			first = sig
+		}
+	}
+}
+
+func TestConcurrentVerify(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		sig := createSigWithCurve(t, curve, 8, 2)
+		other := createSigWithCurve(t, curve, 8, 2)
+
+		runConcurrently(t, func() error {
+			require.True(t, sig.Verify(testMsg))
+			require.False(t, Link(sig, other))
+			require.True(t, sig.Ring().Equals(sig.Ring()))
+			require.False(t, sig.Equal(other))
+			_ = sig.Hash()
+			_ = sig.Ring().Hash()
+			_ = sig.KeyImage()
+			_ = sig.PublicKeys()
+			_, err := sig.Serialize()
+			return err
+		})
+	}
+}
+
+func TestConcurrentVerify_UncachedHP(t *testing.T) {
+	defaultMax := hpCacheMaxSize
+	hpCacheMaxSize = 2
+	t.Cleanup(func() { hpCacheMaxSize = defaultMax })
+
+	sig := createSig(t, 4, 1)
+	runConcurrently(t, func() error {
+		require.True(t, sig.Verify(testMsg))
+		return nil
+	})
+}
+
+func TestConcurrentDeserializeVerify(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		sig := createSigWithCurve(t, curve, 8, 7)
+		enc, err := sig.Serialize()
+		require.NoError(t, err)
+
+		runConcurrently(t, func() error {
+			res := new(RingSig)
+			if err := res.Deserialize(curve, enc); err != nil {
+				return err
+			}
+			require.True(t, res.Verify(testMsg))
+			require.True(t, res.Equal(sig))
+			return nil
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package ring
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// Conflict is a pair of signatures by the same signer, ie. with the same
+// normalized key image, over different messages: evidence of double-signing,
+// eg. for slashing protocols. Serialize encodes it for submission.
+type Conflict struct {
+	A, B       *RingSig
+	MsgA, MsgB [32]byte
+}
+
+// DetectConflicts returns the conflicts among the signatures, each signed
+// over the message at the same index: for each signer, the first of its
+// signatures is paired with the first signature over each other message, so
+// that a signer who signed k different messages has k-1 conflicts.
+// Signatures over different curves never conflict, and duplicates of a
+// signature, or other signatures over the same message, aren't conflicts.
+//
+// Conflicts are in the order of the signers' first signatures, then of the
+// second signature of the pair. The signatures aren't verified, so callers
+// detecting conflicts among untrusted signatures should verify them
+// beforehand, eg. with BatchVerify.
+func DetectConflicts(sigs []*RingSig, msgs [][32]byte) ([]Conflict, error) {
+	if len(sigs) != len(msgs) {
+		return nil, errors.New("number of signatures and messages differ")
+	}
+
+	// signers[image] holds the indices of the first signature of each
+	// message of the signer with the given curve and normalized image
+	signers := make(map[string][]int)
+	var order []string
+	for i, sig := range sigs {
+		if sig == nil || sig.ring == nil || sig.image == nil {
+			return nil, fmt.Errorf("signature %d is nil", i)
+		}
+		key := conflictKey(sig)
+		first, ok := signers[key]
+		if !ok {
+			order = append(order, key)
+		}
+		seen := false
+		for _, j := range first {
+			if msgs[j] == msgs[i] {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			signers[key] = append(first, i)
+		}
+	}
+
+	var conflicts []Conflict
+	for _, key := range order {
+		first := signers[key]
+		for _, j := range first[1:] {
+			conflicts = append(conflicts, Conflict{
+				A:    sigs[first[0]],
+				B:    sigs[j],
+				MsgA: msgs[first[0]],
+				MsgB: msgs[j],
+			})
+		}
+	}
+	return conflicts, nil
+}
+
+// conflictKey identifies the signer of a signature: its curve's base point
+// and normalized key image.
+func conflictKey(sig *RingSig) string {
+	return string(encodePoint(sig.ring.curve.BasePoint())) + string(encodePoint(NormalizeKeyImage(sig.image)))
+}
+
+// Serialize encodes the conflict as the two messages, followed by each
+// serialized signature prefixed with its length, as a big-endian uint32.
+func (c *Conflict) Serialize() ([]byte, error) {
+	if c.A == nil || c.B == nil {
+		return nil, errors.New("conflict signature is nil")
+	}
+	out := append(bytes.Clone(c.MsgA[:]), c.MsgB[:]...)
+	for _, sig := range []*RingSig{c.A, c.B} {
+		enc, err := sig.Serialize()
+		if err != nil {
+			return nil, err
+		}
+		out = binary.BigEndian.AppendUint32(out, uint32(len(enc)))
+		out = append(out, enc...)
+	}
+	return out, nil
+}
+
+// Deserialize decodes a conflict between signatures over the given curve
+// encoded with Serialize. It doesn't check that the signatures are valid or
+// conflict; see VerifyEvidence.
+func (c *Conflict) Deserialize(curve types.Curve, in []byte) error {
+	r := bytes.NewBuffer(in)
+	if r.Len() < 2*32 {
+		return errors.New("input too short")
+	}
+	var res Conflict
+	copy(res.MsgA[:], r.Next(32))
+	copy(res.MsgB[:], r.Next(32))
+
+	var sigs [2]*RingSig
+	for i := range sigs {
+		if r.Len() < 4 {
+			return errors.New("input too short")
+		}
+		n := binary.BigEndian.Uint32(r.Next(4))
+		if uint64(r.Len()) < uint64(n) {
+			return errors.New("input too short")
+		}
+		sigs[i] = new(RingSig)
+		if err := sigs[i].Deserialize(curve, r.Next(int(n))); err != nil {
+			return fmt.Errorf("invalid signature %c: %w", 'A'+i, err)
+		}
+	}
+	if r.Len() != 0 {
+		return errors.New("input too long")
+	}
+	res.A, res.B = sigs[0], sigs[1]
+
+	*c = res
+	return nil
+}
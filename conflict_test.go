@@ -0,0 +1,109 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectConflicts(t *testing.T) {
+	curve := Secp256k1()
+	alice, bob := curve.NewRandomScalar(), curve.NewRandomScalar()
+	aliceRing, err := NewKeyRing(curve, 4, alice, 1)
+	require.NoError(t, err)
+	bobRing, err := NewKeyRing(curve, 4, bob, 2)
+	require.NoError(t, err)
+
+	sign := func(keyring *Ring, privKey Scalar, m [32]byte) *RingSig {
+		sig, err := keyring.Sign(m, privKey)
+		require.NoError(t, err)
+		return sig
+	}
+	m1, m2, m3 := [32]byte{1}, [32]byte{2}, [32]byte{3}
+	sigs := []*RingSig{
+		sign(aliceRing, alice, m1), // 0
+		sign(bobRing, bob, m1),     // 1
+		sign(aliceRing, alice, m1), // 2: same message, not a conflict
+		sign(aliceRing, alice, m2), // 3
+		sign(bobRing, bob, m1),     // 4
+		sign(aliceRing, alice, m3), // 5
+		sign(aliceRing, alice, m2), // 6: same message as 3
+	}
+	msgs := [][32]byte{m1, m1, m1, m2, m1, m3, m2}
+
+	conflicts, err := DetectConflicts(sigs, msgs)
+	require.NoError(t, err)
+	require.Len(t, conflicts, 2)
+	require.Equal(t, Conflict{A: sigs[0], B: sigs[3], MsgA: m1, MsgB: m2}, conflicts[0])
+	require.Equal(t, Conflict{A: sigs[0], B: sigs[5], MsgA: m1, MsgB: m3}, conflicts[1])
+
+	// no conflicts
+	conflicts, err = DetectConflicts(sigs[:3], msgs[:3])
+	require.NoError(t, err)
+	require.Empty(t, conflicts)
+
+	_, err = DetectConflicts(sigs, msgs[:2])
+	require.EqualError(t, err, "number of signatures and messages differ")
+	_, err = DetectConflicts([]*RingSig{sigs[0], nil}, msgs[:2])
+	require.EqualError(t, err, "signature 1 is nil")
+}
+
+func TestDetectConflicts_Torsion(t *testing.T) {
+	torsioned, regular, privKey := signWithTorsion(t, 3, 1)
+	other, err := regular.Ring().Sign([32]byte{2}, privKey)
+	require.NoError(t, err)
+
+	// the torsioned image is normalized, so it conflicts with the regular
+	// signatures
+	conflicts, err := DetectConflicts([]*RingSig{torsioned, other}, [][32]byte{testMsg, {2}})
+	require.NoError(t, err)
+	require.Len(t, conflicts, 1)
+}
+
+func TestDetectConflicts_Curves(t *testing.T) {
+	// the same scalar on different curves is a different signer
+	privKey, secpKey := Ed25519().ScalarFromInt(7), Secp256k1().ScalarFromInt(7)
+	edRing, err := NewKeyRing(Ed25519(), 3, privKey, 0)
+	require.NoError(t, err)
+	secpRing, err := NewKeyRing(Secp256k1(), 3, secpKey, 0)
+	require.NoError(t, err)
+	edSig, err := edRing.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	secpSig, err := secpRing.Sign([32]byte{2}, secpKey)
+	require.NoError(t, err)
+
+	conflicts, err := DetectConflicts([]*RingSig{edSig, secpSig}, [][32]byte{testMsg, {2}})
+	require.NoError(t, err)
+	require.Empty(t, conflicts)
+}
+
+func TestConflict_Serialize(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 5, privKey, 3)
+		require.NoError(t, err)
+		a, err := keyring.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		b, err := keyring.Sign([32]byte{2}, privKey)
+		require.NoError(t, err)
+
+		conflict := &Conflict{A: a, B: b, MsgA: testMsg, MsgB: [32]byte{2}}
+		enc, err := conflict.Serialize()
+		require.NoError(t, err)
+
+		decoded := new(Conflict)
+		require.NoError(t, decoded.Deserialize(curve, enc))
+		require.True(t, decoded.A.Equal(a))
+		require.True(t, decoded.B.Equal(b))
+		require.Equal(t, testMsg, decoded.MsgA)
+		require.Equal(t, [32]byte{2}, decoded.MsgB)
+
+		for i := 0; i < len(enc); i++ {
+			require.Error(t, new(Conflict).Deserialize(curve, enc[:i]))
+		}
+		require.EqualError(t, new(Conflict).Deserialize(curve, append(enc, 0)), "input too long")
+
+		_, err = (&Conflict{A: a}).Serialize()
+		require.Error(t, err)
+	}
+}
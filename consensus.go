@@ -0,0 +1,80 @@
+package ring
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq/ed25519"
+	"github.com/athanorlabs/go-dleq/secp256k1"
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// VerifyVersion1 is the first version of the rules of VerifyEncoded.
+const VerifyVersion1 byte = 1
+
+// ErrNonCanonical is returned by VerifyEncoded for signatures that decode, but
+// not from their canonical encoding, eg. with a scalar that isn't reduced.
+var ErrNonCanonical = errors.New("non-canonical signature encoding")
+
+// VerifyEncoded returns whether the serialized signature over the curve with
+// the given ID is valid for the message, under the given version of the
+// verification rules. It's meant for state-transition logic, such as that of
+// blockchains, where all the nodes must reach the same result: it depends only
+// on its arguments, never on the clock, process-wide settings or the curve
+// implementations selected elsewhere, and always uses the pure-Go go-dleq
+// curves. Only CurveSecp256k1 and CurveEd25519 are supported: curves
+// registered with RegisterCurve are rejected with an error.
+//
+// Under VerifyVersion1, the signature must be the canonical encoding returned
+// by Serialize, with compressed points, its key image must be torsion-free,
+// and it must not have a validity window, as checking it would depend on the
+// clock; signatures with validity windows are rejected with an error.
+//
+// It returns an error if the version or the curve is unknown or the signature
+// can't be decoded, and false with a nil error if the signature is well
+// formed but invalid. Callers that only need a decision treat both as
+// rejection.
+func VerifyEncoded(version byte, curveID byte, sig []byte, msg [32]byte) (bool, error) {
+	switch version {
+	case VerifyVersion1:
+		return verifyEncodedV1(CurveID(curveID), sig, msg)
+	default:
+		return false, fmt.Errorf("unknown verification rules version %d", version)
+	}
+}
+
+func verifyEncodedV1(curveID CurveID, in []byte, m [32]byte) (bool, error) {
+	// only the built-in curves, never registered ones, and always the go-dleq
+	// implementations, which don't depend on process-wide settings
+	var curve types.Curve
+	switch curveID {
+	case CurveSecp256k1:
+		curve = secp256k1.NewCurve()
+	case CurveEd25519:
+		curve = ed25519.NewCurve()
+	default:
+		return false, fmt.Errorf("unsupported curve ID %d", curveID)
+	}
+
+	sig := new(RingSig)
+	if err := sig.Deserialize(curve, in); err != nil {
+		return false, err
+	}
+	if !bytes.Equal(sig.encode(), in) {
+		return false, ErrNonCanonical
+	}
+	if sig.ext.validity != nil {
+		return false, errors.New("signatures with a validity window can't be verified deterministically")
+	}
+
+	err := sig.verifyTranscript(m, nil, nil, RequireTorsionFree)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, ErrInvalidSignature):
+		return false, nil
+	default:
+		return false, err
+	}
+}
@@ -0,0 +1,86 @@
+package ring
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyEncoded(t *testing.T) {
+	edwards, err := NewEd25519(Ed25519ImplEdwards25519)
+	require.NoError(t, err)
+
+	for _, curve := range []Curve{Secp256k1(), Ed25519(), edwards} {
+		id := byte(CurveIDOf(curve))
+		sig := createSigWithCurve(t, curve, 5, 2)
+		enc, err := sig.Serialize()
+		require.NoError(t, err)
+
+		ok, err := VerifyEncoded(VerifyVersion1, id, enc, testMsg)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = VerifyEncoded(VerifyVersion1, id, enc, [32]byte{1})
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		_, err = VerifyEncoded(0, id, enc, testMsg)
+		require.ErrorContains(t, err, "version")
+		_, err = VerifyEncoded(VerifyVersion1, byte(CurveUnknown), enc, testMsg)
+		require.Error(t, err)
+		_, err = VerifyEncoded(VerifyVersion1, id, enc[:len(enc)-1], testMsg)
+		require.Error(t, err)
+
+		uncompressed, err := sig.SerializeWith(WithPointEncoding(PointUncompressed))
+		require.NoError(t, err)
+		_, err = VerifyEncoded(VerifyVersion1, id, uncompressed, testMsg)
+		require.ErrorIs(t, err, ErrNonCanonical)
+	}
+}
+
+func TestVerifyEncoded_NonCanonicalScalar(t *testing.T) {
+	sig := createSigWithCurve(t, Secp256k1(), 3, 0)
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+
+	// a challenge above the group order, which isn't a canonical scalar
+	// encoding even if the backend reduces it
+	copy(enc[4:36], bytes.Repeat([]byte{0xff}, 32))
+	ok, err := VerifyEncoded(VerifyVersion1, byte(CurveSecp256k1), enc, testMsg)
+	require.Error(t, err)
+	require.False(t, ok)
+}
+
+func TestVerifyEncoded_RegisteredCurve(t *testing.T) {
+	registerTestCurve()
+	curve, err := CurveID(testCurveID).Curve()
+	require.NoError(t, err)
+	sig := createSigWithCurve(t, curve, 3, 1)
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+
+	_, err = VerifyEncoded(VerifyVersion1, byte(testCurveID), enc, testMsg)
+	require.EqualError(t, err, "unsupported curve ID 240")
+}
+
+func TestVerifyEncoded_Rules(t *testing.T) {
+	privKey := Ed25519().NewRandomScalar()
+	keyring, err := NewKeyRing(Ed25519(), 3, privKey, 1)
+	require.NoError(t, err)
+	now := time.Now()
+	sig, err := keyring.Sign(testMsg, privKey, WithValidity(now.Add(-time.Hour), now.Add(time.Hour)))
+	require.NoError(t, err)
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+	_, err = VerifyEncoded(VerifyVersion1, byte(CurveEd25519), enc, testMsg)
+	require.ErrorContains(t, err, "validity window")
+
+	torsioned, _, _ := signWithTorsion(t, 3, 1)
+	enc, err = torsioned.Serialize()
+	require.NoError(t, err)
+	ok, err := VerifyEncoded(VerifyVersion1, byte(CurveEd25519), enc, testMsg)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
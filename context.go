@@ -0,0 +1,339 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash"
+	"log/slog"
+	"time"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"golang.org/x/crypto/sha3"
+)
+
+const dstDomain = "ring-go/dst"
+
+// Metrics receives measurements of the operations of a Context.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveSign is called after each signing attempt, with its error.
+	ObserveSign(curve CurveID, size int, d time.Duration, err error)
+	// ObserveVerify is called after each verification, with its result.
+	ObserveVerify(curve CurveID, size int, d time.Duration, err error)
+}
+
+// Context bundles a curve with the options and policies used to create rings
+// and to sign, verify and decode signatures, so that they are configured once
+// rather than at every call site. Its methods supersede the package's free
+// functions for applications that need any of the options.
+//
+// A Context is immutable and safe for concurrent use.
+type Context struct {
+	curve       types.Curve
+	dst         []byte
+	newHash     func() hash.Hash
+	minRingSize int
+	strict      bool
+	cofactor    CofactorPolicy
+	signOpts    []SignOption
+	metrics     Metrics
+	logger      *slog.Logger
+}
+
+// ContextOption configures a Context.
+type ContextOption func(*Context)
+
+// WithDST sets a domain separation tag that is bound into every message signed
+// and verified with the context, so that signatures created for one
+// application or protocol can't be verified by another. It defaults to none,
+// in which case messages are signed as-is, as with the free functions.
+func WithDST(dst []byte) ContextOption {
+	return func(c *Context) {
+		c.dst = append([]byte{}, dst...)
+	}
+}
+
+// WithMessageHash sets the hash function used to hash messages in SignMessage
+// and VerifyMessage, and to bind the domain separation tag. It must have a
+// 32-byte output. It defaults to SHA3-256.
+func WithMessageHash(newHash func() hash.Hash) ContextOption {
+	return func(c *Context) {
+		c.newHash = newHash
+	}
+}
+
+// WithMinRingSize sets the smallest ring size accepted when creating rings,
+// signing and verifying. It defaults to 2.
+func WithMinRingSize(size int) ContextOption {
+	return func(c *Context) {
+		c.minRingSize = size
+	}
+}
+
+// WithStrict makes verification and ring creation reject rings containing
+// duplicate public keys, or public keys with a small-order component, in
+// addition to the checks always performed.
+func WithStrict(strict bool) ContextOption {
+	return func(c *Context) {
+		c.strict = strict
+	}
+}
+
+// WithCofactorPolicy sets how the context handles key images with a
+// small-order component. It defaults to RequireTorsionFree.
+func WithCofactorPolicy(p CofactorPolicy) ContextOption {
+	return func(c *Context) {
+		c.cofactor = p
+	}
+}
+
+// WithDefaultSignOptions sets options applied to every call to Sign, before
+// the options passed to it.
+func WithDefaultSignOptions(opts ...SignOption) ContextOption {
+	return func(c *Context) {
+		c.signOpts = append(c.signOpts, opts...)
+	}
+}
+
+// WithMetrics sets the Metrics receiving measurements of signing and
+// verification.
+func WithMetrics(m Metrics) ContextOption {
+	return func(c *Context) {
+		c.metrics = m
+	}
+}
+
+// WithLogger sets a logger to which failed operations are logged, at debug
+// level. It defaults to none.
+func WithLogger(logger *slog.Logger) ContextOption {
+	return func(c *Context) {
+		c.logger = logger
+	}
+}
+
+// NewContext returns a new Context for the given curve.
+func NewContext(curve types.Curve, opts ...ContextOption) (*Context, error) {
+	if curve == nil {
+		return nil, errors.New("nil curve")
+	}
+
+	c := &Context{
+		curve:       curve,
+		newHash:     sha3.New256,
+		minRingSize: 2,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.newHash == nil || c.newHash().Size() != 32 {
+		return nil, errors.New("message hash must have a 32-byte output")
+	}
+
+	if len(c.dst) > 0xffff {
+		return nil, errors.New("domain separation tag too long")
+	}
+
+	if c.minRingSize < 2 {
+		return nil, errors.New("minimum ring size must be at least 2")
+	}
+
+	if c.cofactor < RequireTorsionFree || c.cofactor > ClearOnLink {
+		return nil, fmt.Errorf("unknown cofactor policy %s", c.cofactor)
+	}
+
+	return c, nil
+}
+
+// Curve returns the context's curve.
+func (c *Context) Curve() types.Curve {
+	return c.curve
+}
+
+// NewRing returns a ring of the given public keys, in order, checking them
+// against the context's policies.
+func (c *Context) NewRing(pubkeys []types.Point) (*Ring, error) {
+	r, err := NewFixedKeyRingFromPublicKeys(c.curve, pubkeys)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkRing(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Sign signs the message with the given ring and private key, which must be
+// one of the ring's members, like Ring.Sign.
+func (c *Context) Sign(r *Ring, m [32]byte, privKey types.Scalar, opts ...SignOption) (*RingSig, error) {
+	start := time.Now()
+	sig, err := c.sign(r, m, privKey, opts)
+	size := 0
+	if r != nil {
+		size = r.Size()
+	}
+	if c.metrics != nil {
+		c.metrics.ObserveSign(CurveIDOf(c.curve), size, time.Since(start), err)
+	}
+	if err != nil {
+		c.log("ring signing failed", size, err)
+	}
+	return sig, err
+}
+
+func (c *Context) sign(r *Ring, m [32]byte, privKey types.Scalar, opts []SignOption) (*RingSig, error) {
+	if r == nil || r.curve == nil {
+		return nil, errors.New("ring is nil")
+	}
+
+	if !sameCurve(r.curve, c.curve) {
+		return nil, errors.New("ring is not on the context's curve")
+	}
+
+	if err := c.checkRing(r); err != nil {
+		return nil, err
+	}
+
+	allOpts := append(append([]SignOption{}, c.signOpts...), opts...)
+	return r.Sign(c.message(m), privKey, allOpts...)
+}
+
+// SignMessage hashes the message with the context's message hash and signs
+// the digest with Sign.
+func (c *Context) SignMessage(r *Ring, msg []byte, privKey types.Scalar, opts ...SignOption) (*RingSig, error) {
+	return c.Sign(r, c.hash(msg), privKey, opts...)
+}
+
+// Verify verifies the signature for the given message, like
+// RingSig.VerifyWithOpts with the context's CofactorPolicy, and checks the
+// signature's ring against the context's policies. It returns nil if the
+// signature is valid.
+func (c *Context) Verify(sig *RingSig, m [32]byte) error {
+	start := time.Now()
+	err := c.verify(sig, m)
+	size := 0
+	if sig != nil && sig.ring != nil {
+		size = sig.ring.Size()
+	}
+	if c.metrics != nil {
+		c.metrics.ObserveVerify(CurveIDOf(c.curve), size, time.Since(start), err)
+	}
+	if err != nil {
+		c.log("ring signature verification failed", size, err)
+	}
+	return err
+}
+
+func (c *Context) verify(sig *RingSig, m [32]byte) error {
+	if sig == nil || sig.ring == nil {
+		return ErrInvalidSignature
+	}
+
+	if !sameCurve(sig.ring.curve, c.curve) {
+		return errors.New("signature is not on the context's curve")
+	}
+
+	if err := c.checkRing(sig.ring); err != nil {
+		return err
+	}
+
+	return sig.VerifyWithOpts(c.message(m), &VerifyOpts{Cofactor: c.cofactor})
+}
+
+// KeyImage returns the key image of the signature under the context's
+// CofactorPolicy, for use as a key in key image registries.
+func (c *Context) KeyImage(sig *RingSig) types.Point {
+	return c.cofactor.KeyImage(sig)
+}
+
+// Link returns whether the two signatures were created by the same signer
+// under the context's CofactorPolicy.
+func (c *Context) Link(sigA, sigB *RingSig) bool {
+	return c.cofactor.Link(sigA, sigB)
+}
+
+// VerifyMessage hashes the message with the context's message hash and
+// verifies the signature for the digest with Verify.
+func (c *Context) VerifyMessage(sig *RingSig, msg []byte) error {
+	return c.Verify(sig, c.hash(msg))
+}
+
+// Deserialize decodes a signature on the context's curve, like
+// RingSig.Deserialize, and checks its ring against the context's policies.
+func (c *Context) Deserialize(in []byte) (*RingSig, error) {
+	sig := new(RingSig)
+	if err := sig.Deserialize(c.curve, in); err != nil {
+		return nil, err
+	}
+
+	if err := c.checkRing(sig.ring); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// checkRing checks the ring against the minimum size and, in strict mode,
+// checks that it contains no duplicate or torsioned public keys.
+func (c *Context) checkRing(r *Ring) error {
+	if r.Size() < c.minRingSize {
+		return fmt.Errorf("ring size %d is less than the minimum of %d", r.Size(), c.minRingSize)
+	}
+
+	if !c.strict {
+		return nil
+	}
+
+	seen := make(map[string]struct{}, r.Size())
+	for i, pk := range r.pubkeys {
+		enc := string(encodePoint(pk))
+		if _, ok := seen[enc]; ok {
+			return fmt.Errorf("duplicate public key at index %d", i)
+		}
+		seen[enc] = struct{}{}
+
+		if !isTorsionFree(pk) {
+			return fmt.Errorf("public key at index %d has a small-order component", i)
+		}
+	}
+	return nil
+}
+
+// message returns the message actually signed for m: m itself if the context
+// has no domain separation tag, or a hash of the tag and m otherwise.
+func (c *Context) message(m [32]byte) [32]byte {
+	if len(c.dst) == 0 {
+		return m
+	}
+
+	h := c.newHash()
+	_, _ = h.Write([]byte(dstDomain))
+	_, _ = h.Write([]byte{byte(len(c.dst) >> 8), byte(len(c.dst))})
+	_, _ = h.Write(c.dst)
+	_, _ = h.Write(m[:])
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+func (c *Context) hash(msg []byte) [32]byte {
+	h := c.newHash()
+	_, _ = h.Write(msg)
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+func (c *Context) log(msg string, size int, err error) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.LogAttrs(context.Background(), slog.LevelDebug, msg,
+		slog.String("curve", CurveIDOf(c.curve).String()),
+		slog.Int("size", size),
+		slog.String("error", err.Error()),
+	)
+}
@@ -0,0 +1,167 @@
+package ring
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testMetrics struct {
+	mu       sync.Mutex
+	signs    []error
+	verifies []error
+}
+
+func (m *testMetrics) ObserveSign(_ CurveID, _ int, _ time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.signs = append(m.signs, err)
+}
+
+func (m *testMetrics) ObserveVerify(_ CurveID, _ int, _ time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.verifies = append(m.verifies, err)
+}
+
+func TestContext(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		metrics := &testMetrics{}
+		ctx, err := NewContext(curve, WithDST([]byte("app-a")), WithMetrics(metrics))
+		require.NoError(t, err)
+
+		privKey := curve.NewRandomScalar()
+		keyring, err := ctx.NewRing(append(randomPubkeys(curve, 3), curve.ScalarBaseMul(privKey)))
+		require.NoError(t, err)
+
+		sig, err := ctx.Sign(keyring, testMsg, privKey)
+		require.NoError(t, err)
+		require.NoError(t, ctx.Verify(sig, testMsg))
+		require.ErrorIs(t, ctx.Verify(sig, [32]byte{}), ErrInvalidSignature)
+
+		// the DST is bound into the signature
+		require.False(t, sig.Verify(testMsg))
+		other, err := NewContext(curve, WithDST([]byte("app-b")))
+		require.NoError(t, err)
+		require.Error(t, other.Verify(sig, testMsg))
+
+		enc, err := sig.Serialize()
+		require.NoError(t, err)
+		res, err := ctx.Deserialize(enc)
+		require.NoError(t, err)
+		require.NoError(t, ctx.Verify(res, testMsg))
+
+		sig, err = ctx.SignMessage(keyring, []byte("hello"), privKey)
+		require.NoError(t, err)
+		require.NoError(t, ctx.VerifyMessage(sig, []byte("hello")))
+		require.Error(t, ctx.VerifyMessage(sig, []byte("hellO")))
+
+		require.Len(t, metrics.signs, 2)
+		require.Len(t, metrics.verifies, 5)
+	}
+}
+
+func TestContext_NilRing(t *testing.T) {
+	curve := Ed25519()
+	metrics := &testMetrics{}
+	var logs bytes.Buffer
+	ctx, err := NewContext(curve, WithMetrics(metrics), WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+	require.NoError(t, err)
+
+	for _, r := range []*Ring{nil, {}} {
+		_, err = ctx.Sign(r, testMsg, curve.NewRandomScalar())
+		require.EqualError(t, err, "ring is nil")
+	}
+	require.Len(t, metrics.signs, 2)
+	require.Contains(t, logs.String(), "ring signing failed")
+
+	require.ErrorIs(t, ctx.Verify(nil, testMsg), ErrInvalidSignature)
+}
+
+func TestContext_NoDST(t *testing.T) {
+	curve := Secp256k1()
+	ctx, err := NewContext(curve)
+	require.NoError(t, err)
+
+	// without a DST, signatures are interchangeable with the free functions
+	sig := createSig(t, 3, 1)
+	require.NoError(t, ctx.Verify(sig, testMsg))
+}
+
+func TestContext_Policies(t *testing.T) {
+	curve := Ed25519()
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ctx, err := NewContext(curve, WithMinRingSize(4), WithStrict(true), WithLogger(logger))
+	require.NoError(t, err)
+
+	privKey := curve.NewRandomScalar()
+	small, err := NewKeyRing(curve, 3, privKey, 0)
+	require.NoError(t, err)
+
+	_, err = ctx.Sign(small, testMsg, privKey)
+	require.EqualError(t, err, "ring size 3 is less than the minimum of 4")
+	require.Contains(t, logs.String(), "ring signing failed")
+
+	sig, err := small.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	require.Error(t, ctx.Verify(sig, testMsg))
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+	_, err = ctx.Deserialize(enc)
+	require.Error(t, err)
+
+	// strict mode rejects duplicate keys, even when they're distinct values
+	pubkeys := randomPubkeys(curve, 3)
+	pubkeys = append(pubkeys, curve.ScalarBaseMul(curve.ScalarFromInt(1)), curve.BasePoint())
+	_, err = ctx.NewRing(pubkeys)
+	require.EqualError(t, err, "duplicate public key at index 4")
+
+	_, err = ctx.Sign(small, testMsg, privKey)
+	require.Error(t, err)
+
+	other := Secp256k1()
+	otherRing, err := NewKeyRing(other, 4, other.NewRandomScalar(), 0)
+	require.NoError(t, err)
+	_, err = ctx.Sign(otherRing, testMsg, other.NewRandomScalar())
+	require.EqualError(t, err, "ring is not on the context's curve")
+}
+
+func TestNewContext_Invalid(t *testing.T) {
+	_, err := NewContext(nil)
+	require.Error(t, err)
+
+	_, err = NewContext(Ed25519(), WithMessageHash(sha512.New))
+	require.Error(t, err)
+
+	_, err = NewContext(Ed25519(), WithMessageHash(sha256.New))
+	require.NoError(t, err)
+
+	_, err = NewContext(Ed25519(), WithMinRingSize(1))
+	require.Error(t, err)
+}
+
+func TestContext_DefaultSignOptions(t *testing.T) {
+	curve := Secp256k1()
+	notAfter := time.Now().Add(-time.Hour)
+	ctx, err := NewContext(curve, WithDefaultSignOptions(WithValidity(time.Time{}, notAfter)))
+	require.NoError(t, err)
+
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 2, privKey, 1)
+	require.NoError(t, err)
+	sig, err := ctx.Sign(keyring, testMsg, privKey)
+	require.NoError(t, err)
+	require.ErrorIs(t, ctx.Verify(sig, testMsg), ErrNotValidAt)
+
+	// options passed to Sign are applied after the defaults
+	sig, err = ctx.Sign(keyring, testMsg, privKey, WithValidity(time.Time{}, time.Time{}))
+	require.NoError(t, err)
+	require.NoError(t, ctx.Verify(sig, testMsg))
+}
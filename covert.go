@@ -0,0 +1,284 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// ErrCovertChannel is returned by CovertReport.Err if a signature shows signs
+// of embedded data.
+var ErrCovertChannel = errors.New("signature may carry a covert channel")
+
+// AnomalyKind is the kind of an Anomaly found by RingSig.AnalyzeCovert.
+type AnomalyKind int
+
+const (
+	// AnomalyRepeatedValue is a response equal to another response or to the
+	// challenge.
+	AnomalyRepeatedValue AnomalyKind = iota + 1
+	// AnomalyRepeatedPattern is a run of bytes that occurs more than once in
+	// the responses and challenge, eg. data copied into several of them.
+	AnomalyRepeatedPattern
+	// AnomalySmallValue is a scalar far smaller than the group order, ie. with
+	// many leading zero bits.
+	AnomalySmallValue
+	// AnomalyPrintable is a long run of printable ASCII characters.
+	AnomalyPrintable
+	// AnomalyBitBias is a proportion of set bits across all responses that is
+	// too far from one half.
+	AnomalyBitBias
+	// AnomalyByteDistribution is a distribution of byte values across all
+	// responses that is too far from uniform.
+	AnomalyByteDistribution
+)
+
+// String returns a short description of the kind.
+func (k AnomalyKind) String() string {
+	switch k {
+	case AnomalyRepeatedValue:
+		return "repeated value"
+	case AnomalyRepeatedPattern:
+		return "repeated pattern"
+	case AnomalySmallValue:
+		return "small value"
+	case AnomalyPrintable:
+		return "printable text"
+	case AnomalyBitBias:
+		return "bit bias"
+	case AnomalyByteDistribution:
+		return "byte distribution"
+	default:
+		return fmt.Sprintf("AnomalyKind(%d)", int(k))
+	}
+}
+
+// Anomaly is a property of a signature's scalars that is very unlikely for
+// honestly generated ones.
+type Anomaly struct {
+	Kind AnomalyKind
+	// Index is the index of the ring member whose response is affected, or -1
+	// if it's the challenge or the responses as a whole.
+	Index int
+	// Detail describes the anomaly.
+	Detail string
+}
+
+func (a Anomaly) String() string {
+	if a.Index < 0 {
+		return fmt.Sprintf("%s: %s", a.Kind, a.Detail)
+	}
+	return fmt.Sprintf("%s at member %d: %s", a.Kind, a.Index, a.Detail)
+}
+
+// CovertReport is the result of RingSig.AnalyzeCovert.
+type CovertReport struct {
+	Anomalies []Anomaly
+}
+
+// Suspicious returns whether any anomaly was found.
+func (r *CovertReport) Suspicious() bool {
+	return len(r.Anomalies) > 0
+}
+
+// Err returns nil if no anomaly was found, or ErrCovertChannel wrapped with
+// the first anomaly otherwise.
+func (r *CovertReport) Err() error {
+	if !r.Suspicious() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrCovertChannel, r.Anomalies[0])
+}
+
+// Thresholds of the covert channel analysis. Each check is tuned so that an
+// honest signature triggers it with a probability below about 2^-30, even for
+// large rings.
+const (
+	// covertPatternLen is the length of the runs of bytes compared across
+	// scalars.
+	covertPatternLen = 8
+	// covertPrintableLen is the length of the shortest run of printable ASCII
+	// characters reported.
+	covertPrintableLen = 20
+	// covertSmallBits is how much shorter than the group order a scalar must
+	// be to be reported as small.
+	covertSmallBits = 40
+	// covertMinStatMembers is the ring size from which the statistical tests
+	// over all responses are run; they're meaningless on smaller samples.
+	covertMinStatMembers = 48
+	// covertZ is the number of standard deviations from the expected value at
+	// which the statistical tests report an anomaly.
+	covertZ = 7
+	// covertStatBytes is the number of low-order bytes of each response used
+	// in the statistical tests, which are uniform for all supported curves.
+	covertStatBytes = 31
+)
+
+// AnalyzeCovert inspects the signature's responses and challenge for patterns
+// that honestly generated scalars would almost never show, and that could
+// indicate data embedded by malicious signer software (a kleptographic
+// channel): repeated values or byte runs, scalars with many leading zero bits,
+// runs of printable text, and, for rings of 48 members or more, bias in the
+// bits and bytes of the responses. Gateways accepting signatures from
+// third-party signers can use it to reject or flag them, eg. with
+// CovertReport.Err.
+//
+// A clean report doesn't prove that a signature carries no data: a signer can
+// always encrypt what it embeds so that it looks random, or grind the
+// challenge. It only catches careless or low-effort channels. Signatures
+// created with WithAuditableDecoys can be checked exactly with AuditDecoys.
+//
+// It doesn't verify the signature.
+func (sig *RingSig) AnalyzeCovert() *CovertReport {
+	report := &CovertReport{}
+	add := func(kind AnomalyKind, idx int, format string, args ...any) {
+		report.Anomalies = append(report.Anomalies, Anomaly{Kind: kind, Index: idx, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	id := CurveIDOf(sig.ring.curve)
+	orderBits := scalarOrderBits(id)
+
+	// values holds the big-endian encodings of the challenge, at index 0, and
+	// of the responses
+	values := make([][]byte, 0, len(sig.s)+1)
+	values = append(values, scalarBigEndian(id, sig.c))
+	for _, s := range sig.s {
+		values = append(values, scalarBigEndian(id, s))
+	}
+	member := func(v int) int { return v - 1 }
+
+	seen := make(map[string]int, len(values))
+	patterns := make(map[string]int, len(values)*(len(values[0])-covertPatternLen+1))
+	for v, b := range values {
+		if prev, ok := seen[string(b)]; ok {
+			add(AnomalyRepeatedValue, member(v), "equal to %s", covertValueName(prev))
+			continue
+		}
+		seen[string(b)] = v
+
+		if orderBits > 0 && bitLen(b) <= orderBits-covertSmallBits {
+			add(AnomalySmallValue, member(v), "%d bits", bitLen(b))
+		}
+
+		if start, n := longestPrintable(b); n >= covertPrintableLen {
+			add(AnomalyPrintable, member(v), "%q", b[start:start+n])
+		}
+
+		for off := 0; off+covertPatternLen <= len(b); off++ {
+			window := string(b[off : off+covertPatternLen])
+			if prev, ok := patterns[window]; ok {
+				add(AnomalyRepeatedPattern, member(v), "%x also in %s", window, covertValueName(prev))
+				break
+			}
+			patterns[window] = v
+		}
+	}
+
+	if len(sig.s) >= covertMinStatMembers && orderBits > 0 {
+		analyzeDistribution(values[1:], add)
+	}
+
+	return report
+}
+
+// analyzeDistribution runs the statistical tests over the low-order bytes of
+// the responses.
+func analyzeDistribution(values [][]byte, add func(AnomalyKind, int, string, ...any)) {
+	var (
+		counts [256]int
+		ones   int
+	)
+	for _, b := range values {
+		for _, x := range b[len(b)-covertStatBytes:] {
+			counts[x]++
+			ones += bits.OnesCount8(x)
+		}
+	}
+
+	nbytes := float64(len(values) * covertStatBytes)
+	nbits := nbytes * 8
+	if z := (float64(ones) - nbits/2) / math.Sqrt(nbits/4); math.Abs(z) > covertZ {
+		add(AnomalyBitBias, -1, "%.4f of bits set", float64(ones)/nbits)
+	}
+
+	// Pearson's chi-squared test, with the Wilson-Hilferty approximation of
+	// the quantile, is only meaningful with enough samples per byte value
+	if nbytes < 5*256 {
+		return
+	}
+	expected := nbytes / 256
+	var chi2 float64
+	for _, n := range counts {
+		d := float64(n) - expected
+		chi2 += d * d / expected
+	}
+	const df = 255.0
+	k := 2 / (9 * df)
+	threshold := df * math.Pow(1-k+covertZ*math.Sqrt(k), 3)
+	if chi2 > threshold {
+		add(AnomalyByteDistribution, -1, "chi-squared %.1f over %.1f", chi2, threshold)
+	}
+}
+
+func covertValueName(v int) string {
+	if v == 0 {
+		return "the challenge"
+	}
+	return fmt.Sprintf("the response of member %d", v-1)
+}
+
+// scalarOrderBits returns the bit length of the order of the curve's group, or
+// 0 if it's unknown.
+func scalarOrderBits(id CurveID) int {
+	switch id {
+	case CurveSecp256k1:
+		return 256
+	case CurveEd25519:
+		return 253
+	default:
+		return 0
+	}
+}
+
+// scalarBigEndian returns the big-endian encoding of s. Scalars of curves
+// other than ed25519, which encodes them in little-endian order, are returned
+// as encoded.
+func scalarBigEndian(id CurveID, s types.Scalar) []byte {
+	b := s.Encode()
+	if id == CurveEd25519 {
+		for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+			b[i], b[j] = b[j], b[i]
+		}
+	}
+	return b
+}
+
+// bitLen returns the bit length of the big-endian integer b.
+func bitLen(b []byte) int {
+	for i, x := range b {
+		if x != 0 {
+			return (len(b)-i-1)*8 + bits.Len8(x)
+		}
+	}
+	return 0
+}
+
+// longestPrintable returns the start and length of the longest run of
+// printable ASCII characters in b.
+func longestPrintable(b []byte) (start, n int) {
+	cur := 0
+	for i, x := range b {
+		if x < 0x20 || x > 0x7e {
+			cur = 0
+			continue
+		}
+		cur++
+		if cur > n {
+			start, n = i-cur+1, cur
+		}
+	}
+	return start, n
+}
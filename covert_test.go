@@ -0,0 +1,84 @@
+package ring
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeCovert_Honest(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		for _, size := range []int{2, 64} {
+			privKey := curve.NewRandomScalar()
+			keyring, err := NewKeyRing(curve, size, privKey, 1)
+			require.NoError(t, err)
+			sig, err := keyring.Sign(testMsg, privKey)
+			require.NoError(t, err)
+
+			report := sig.AnalyzeCovert()
+			require.False(t, report.Suspicious(), "%v", report.Anomalies)
+			require.NoError(t, report.Err())
+		}
+	}
+}
+
+func TestAnalyzeCovert_Anomalies(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 64, privKey, 0)
+	require.NoError(t, err)
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+
+	scalar := func(b []byte) Scalar {
+		var enc [32]byte
+		copy(enc[32-len(b):], b)
+		s, err := curve.DecodeToScalar(enc[:])
+		require.NoError(t, err)
+		return s
+	}
+	kinds := func(s *RingSig) map[AnomalyKind]int {
+		out := make(map[AnomalyKind]int)
+		for _, a := range s.AnalyzeCovert().Anomalies {
+			out[a.Kind] = a.Index
+		}
+		return out
+	}
+
+	tampered := *sig
+	tampered.s = append([]Scalar(nil), sig.s...)
+	tampered.s[3] = sig.s[1]
+	tampered.s[5] = scalar([]byte{0xde, 0xad, 0xbe, 0xef})
+	tampered.s[7] = scalar(append([]byte{0x9c, 0x81, 0xf0, 0x03, 0xaa, 0x18, 0xc4, 0x90, 0x01}, "exfiltrated private key"...))
+	enc := sig.s[8].Encode()
+	copy(enc[20:], sig.s[9].Encode()[4:16])
+	tampered.s[9] = scalar(enc)
+	got := kinds(&tampered)
+	require.Equal(t, map[AnomalyKind]int{
+		AnomalyRepeatedValue:   3,
+		AnomalySmallValue:      5,
+		AnomalyPrintable:       7,
+		AnomalyRepeatedPattern: 9,
+	}, got)
+
+	err = tampered.AnalyzeCovert().Err()
+	require.ErrorIs(t, err, ErrCovertChannel)
+	require.Contains(t, err.Error(), "at member 3")
+
+	// responses with a quarter of their bits set
+	biased := *sig
+	biased.s = make([]Scalar, len(sig.s))
+	for i := range biased.s {
+		a, b := make([]byte, 32), make([]byte, 32)
+		_, _ = rand.Read(a)
+		_, _ = rand.Read(b)
+		for j := range a {
+			a[j] &= b[j]
+		}
+		biased.s[i] = scalar(a)
+	}
+	got = kinds(&biased)
+	require.Contains(t, got, AnomalyBitBias)
+	require.Contains(t, got, AnomalyByteDistribution)
+}
@@ -0,0 +1,252 @@
+// Package decoys builds rings from the outputs or accounts of a chain, so that
+// wallets can sign with rings of live keys rather than generated ones. The
+// chain data is fetched by the application, through a callback, eg. from a
+// node's RPC or from outputs matched with BIP-158 compact filters; the package
+// pages through it, caches it, validates the keys and selects the decoys.
+//
+//	pool := decoys.NewPool(ring.Secp256k1(), provider, decoys.WithTTL(time.Minute))
+//	r, err := pool.BuildRing(ctx, privKey, 16)
+//	sig, err := r.Sign(msgHash, privKey)
+//
+// Decoys are selected uniformly at random among the valid, distinct keys
+// returned by the provider, and the signer is placed at a random position.
+package decoys
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// DefaultMaxOutputs is the default maximum number of outputs fetched by a
+// Pool.
+const DefaultMaxOutputs = 1 << 20
+
+// ErrNotEnoughDecoys is returned by BuildRing when the provider doesn't return
+// enough valid keys for the requested ring size.
+var ErrNotEnoughDecoys = errors.New("not enough decoys")
+
+// Output is a candidate decoy from the chain.
+type Output struct {
+	// ID identifies the output for the provider, eg. "txid:vout" or an
+	// account address. It's only used in error messages.
+	ID string
+	// PublicKey is the compressed encoding of the output's public key.
+	PublicKey []byte
+	// Height is the height of the block the output was created in, or 0 if
+	// it's unknown.
+	Height uint64
+}
+
+// Page is a page of outputs returned by an OutputProvider.
+type Page struct {
+	Outputs []Output
+	// Next is the cursor of the next page, or empty if this is the last one.
+	Next string
+}
+
+// OutputProvider returns the page of outputs at the given cursor, the empty
+// cursor being that of the first page. It's called sequentially.
+type OutputProvider func(ctx context.Context, cursor string) (*Page, error)
+
+// Pool is a cache of the candidate decoys returned by an OutputProvider. It's
+// safe for concurrent use.
+type Pool struct {
+	curve      ring.Curve
+	provider   OutputProvider
+	maxOutputs int
+	ttl        time.Duration
+	filter     func(Output) bool
+	now        func() time.Time
+
+	mu        sync.Mutex
+	keys      []ring.Point
+	fetchedAt time.Time
+	fetched   bool
+}
+
+// Option configures a Pool.
+type Option func(*Pool)
+
+// WithMaxOutputs sets the maximum number of outputs fetched from the provider,
+// after which the remaining pages are ignored. It defaults to
+// DefaultMaxOutputs.
+func WithMaxOutputs(n int) Option {
+	return func(p *Pool) {
+		p.maxOutputs = n
+	}
+}
+
+// WithTTL sets how long the fetched outputs are used before being fetched
+// again. It defaults to 0, meaning until Invalidate is called.
+func WithTTL(ttl time.Duration) Option {
+	return func(p *Pool) {
+		p.ttl = ttl
+	}
+}
+
+// WithFilter sets a function selecting the outputs that may be used as decoys,
+// eg. by age or by type. Outputs for which it returns false are ignored.
+func WithFilter(filter func(Output) bool) Option {
+	return func(p *Pool) {
+		p.filter = filter
+	}
+}
+
+// NewPool returns a pool of the outputs returned by provider, whose keys must
+// be on the given curve. Nothing is fetched until the pool is first used.
+func NewPool(curve ring.Curve, provider OutputProvider, opts ...Option) *Pool {
+	p := &Pool{
+		curve:      curve,
+		provider:   provider,
+		maxOutputs: DefaultMaxOutputs,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Invalidate discards the cached outputs, so that they are fetched again on
+// the next use of the pool.
+func (p *Pool) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys, p.fetched = nil, false
+}
+
+// Keys returns the distinct valid public keys of the outputs returned by the
+// provider and accepted by the filter, in the order the provider returned
+// them. Outputs whose key doesn't decode to a point of the pool's curve, or is
+// the identity or has a small-order component, are ignored.
+//
+// The keys are fetched on the first call, and again once the TTL has expired.
+func (p *Pool) Keys(ctx context.Context) ([]ring.Point, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.fetched && (p.ttl == 0 || p.now().Sub(p.fetchedAt) < p.ttl) {
+		return p.keys, nil
+	}
+
+	keys, err := p.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.keys, p.fetchedAt, p.fetched = keys, p.now(), true
+	return keys, nil
+}
+
+func (p *Pool) fetch(ctx context.Context) ([]ring.Point, error) {
+	var (
+		keys   []ring.Point
+		seen   = make(map[string]bool)
+		cursor string
+		count  int
+	)
+	for count < p.maxOutputs {
+		page, err := p.provider(ctx, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("fetching outputs at cursor %q: %w", cursor, err)
+		}
+
+		for _, out := range page.Outputs {
+			if count == p.maxOutputs {
+				break
+			}
+			count++
+
+			if seen[string(out.PublicKey)] || (p.filter != nil && !p.filter(out)) {
+				continue
+			}
+			pk, ok := p.decode(out.PublicKey)
+			if !ok {
+				continue
+			}
+			seen[string(out.PublicKey)] = true
+			keys = append(keys, pk)
+		}
+
+		if page.Next == "" {
+			break
+		}
+		if page.Next == cursor {
+			return nil, fmt.Errorf("provider returned the same cursor %q twice", cursor)
+		}
+		cursor = page.Next
+	}
+	return keys, nil
+}
+
+// decode decodes a public key, and checks that it can be used as a decoy.
+func (p *Pool) decode(enc []byte) (ring.Point, bool) {
+	pk, err := p.curve.DecodeToPoint(enc)
+	if err != nil {
+		return nil, false
+	}
+	q := pk.Copy()
+	if q.Equals(q.Sub(q)) {
+		// the identity
+		return nil, false
+	}
+	if !ring.NormalizeKeyImage(pk).Equals(pk.Copy()) {
+		return nil, false
+	}
+	return pk, true
+}
+
+// BuildRing returns a ring of the given size, made of the public key of
+// privKey, at a random position, and size-1 decoys selected at random from the
+// pool. It returns ErrNotEnoughDecoys if the pool has fewer distinct keys
+// other than the signer's.
+func (p *Pool) BuildRing(ctx context.Context, privKey ring.Scalar, size int) (*ring.Ring, error) {
+	if size < 2 {
+		return nil, errors.New("size of ring less than two")
+	}
+	keys, err := p.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pubkey := p.curve.ScalarBaseMul(privKey)
+	candidates := make([]ring.Point, 0, len(keys))
+	for _, pk := range keys {
+		if !pk.Copy().Equals(pubkey.Copy()) {
+			candidates = append(candidates, pk)
+		}
+	}
+	if len(candidates) < size-1 {
+		return nil, fmt.Errorf("%w: %d available for a ring of %d", ErrNotEnoughDecoys, len(candidates), size)
+	}
+
+	// partial Fisher-Yates shuffle of the candidates
+	for i := 0; i < size-1; i++ {
+		j, err := randInt(len(candidates) - i)
+		if err != nil {
+			return nil, err
+		}
+		candidates[i], candidates[i+j] = candidates[i+j], candidates[i]
+	}
+
+	idx, err := randInt(size)
+	if err != nil {
+		return nil, err
+	}
+	return ring.NewKeyRingFromPublicKeys(p.curve, candidates[:size-1], privKey, idx)
+}
+
+// randInt returns a uniformly random integer in [0, n).
+func randInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
+}
@@ -0,0 +1,143 @@
+package decoys
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+var testMsg = [32]byte{1, 2, 3}
+
+// chain is a fake chain, returning its outputs in pages of pageSize.
+type chain struct {
+	outputs  []Output
+	pageSize int
+	calls    int
+}
+
+func newChain(curve ring.Curve, n int) *chain {
+	c := &chain{pageSize: 3}
+	for i := 0; i < n; i++ {
+		pk := curve.ScalarBaseMul(curve.NewRandomScalar())
+		c.outputs = append(c.outputs, Output{ID: strconv.Itoa(i), PublicKey: pk.Encode(), Height: uint64(i)})
+	}
+	return c
+}
+
+func (c *chain) provider(_ context.Context, cursor string) (*Page, error) {
+	c.calls++
+	start := 0
+	if cursor != "" {
+		var err error
+		if start, err = strconv.Atoi(cursor); err != nil {
+			return nil, err
+		}
+	}
+	end := min(start+c.pageSize, len(c.outputs))
+	page := &Page{Outputs: c.outputs[start:end]}
+	if end < len(c.outputs) {
+		page.Next = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+func TestBuildRing(t *testing.T) {
+	ctx := context.Background()
+	for _, curve := range []ring.Curve{ring.Secp256k1(), ring.Ed25519()} {
+		c := newChain(curve, 10)
+		pool := NewPool(curve, c.provider)
+
+		privKey := curve.NewRandomScalar()
+		r, err := pool.BuildRing(ctx, privKey, 8)
+		require.NoError(t, err)
+		require.Equal(t, 8, r.Size())
+		_, ok := r.IndexOf(curve.ScalarBaseMul(privKey))
+		require.True(t, ok)
+
+		sig, err := r.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		require.True(t, sig.Verify(testMsg))
+
+		// 4 pages, fetched once
+		_, err = pool.BuildRing(ctx, privKey, 8)
+		require.NoError(t, err)
+		require.Equal(t, 4, c.calls)
+
+		_, err = pool.BuildRing(ctx, privKey, 12)
+		require.ErrorIs(t, err, ErrNotEnoughDecoys)
+	}
+}
+
+func TestPool_Validation(t *testing.T) {
+	curve := ring.Ed25519()
+	c := newChain(curve, 4)
+	identity := make([]byte, 32)
+	identity[0] = 1
+	c.outputs = append(c.outputs,
+		Output{ID: "dup", PublicKey: c.outputs[0].PublicKey},
+		Output{ID: "garbage", PublicKey: []byte{1, 2, 3}},
+		Output{ID: "identity", PublicKey: identity},
+		Output{ID: "old", PublicKey: curve.ScalarBaseMul(curve.NewRandomScalar()).Encode()},
+	)
+
+	pool := NewPool(curve, c.provider, WithFilter(func(out Output) bool {
+		return out.ID != "old"
+	}))
+	keys, err := pool.Keys(context.Background())
+	require.NoError(t, err)
+	require.Len(t, keys, 4)
+	for i, pk := range keys {
+		require.Equal(t, c.outputs[i].PublicKey, pk.Encode())
+	}
+}
+
+func TestPool_Cache(t *testing.T) {
+	ctx := context.Background()
+	curve := ring.Secp256k1()
+	c := newChain(curve, 5)
+	now := time.Unix(0, 0)
+	pool := NewPool(curve, c.provider, WithTTL(time.Minute), WithMaxOutputs(4))
+	pool.now = func() time.Time { return now }
+
+	keys, err := pool.Keys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 4)
+	require.Equal(t, 2, c.calls)
+
+	now = now.Add(30 * time.Second)
+	_, err = pool.Keys(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, c.calls)
+
+	now = now.Add(time.Minute)
+	_, err = pool.Keys(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 4, c.calls)
+
+	pool.Invalidate()
+	_, err = pool.Keys(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 6, c.calls)
+}
+
+func TestPool_ProviderErrors(t *testing.T) {
+	ctx := context.Background()
+	errChain := errors.New("node unavailable")
+	pool := NewPool(ring.Ed25519(), func(context.Context, string) (*Page, error) {
+		return nil, errChain
+	})
+	_, err := pool.Keys(ctx)
+	require.ErrorIs(t, err, errChain)
+
+	loop := NewPool(ring.Ed25519(), func(context.Context, string) (*Page, error) {
+		return &Page{Next: "again"}, nil
+	})
+	_, err = loop.Keys(ctx)
+	require.Error(t, err)
+}
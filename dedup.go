@@ -0,0 +1,38 @@
+package ring
+
+import "golang.org/x/crypto/sha3"
+
+const dedupKeyDomain = "ring-go/dedup"
+
+// DedupKey returns a key identifying the signer's signatures over the message
+// m with the signature's ring, for mempools and gossip layers that drop
+// duplicate signatures, eg. a transaction rebroadcast or re-signed by the
+// same signer. Signatures by the same signer over the same ring and message
+// have the same key, even if they were signed with different nonces.
+//
+// Conflicting signatures, by the same signer over different messages, have
+// different keys, but the same normalized key image; layers dropping them
+// index signatures by NormalizeKeyImage(sig.KeyImage()) as well.
+//
+// m is the message passed to Verify; the signature doesn't carry it, unless it
+// was signed WithEmbeddedDigest (see SignedDigest). Other implementations
+// compute the key as
+//
+//	SHA3-256("ring-go/dedup" || I || ring hash || m)
+//
+// with I the compressed encoding of the normalized key image (see
+// NormalizeKeyImage) and the ring hash as returned by Ring.Hash. The key
+// doesn't depend on the signature's point encoding or extensions.
+func (r *RingSig) DedupKey(m [32]byte) [32]byte {
+	ringHash := r.ring.Hash()
+
+	h := sha3.New256()
+	_, _ = h.Write([]byte(dedupKeyDomain))
+	_, _ = h.Write(encodePoint(NormalizeKeyImage(r.image)))
+	_, _ = h.Write(ringHash[:])
+	_, _ = h.Write(m[:])
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
@@ -0,0 +1,70 @@
+package ring
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestDedupKey(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 5, privKey, 2)
+		require.NoError(t, err)
+
+		sig, err := keyring.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		key := sig.DedupKey(testMsg)
+
+		// re-signing the same message gives another signature, with the
+		// same key
+		again, err := keyring.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		require.False(t, again.Equal(sig))
+		require.Equal(t, key, again.DedupKey(testMsg))
+
+		// conflicting signature: same image, another message
+		other := [32]byte{9}
+		conflict, err := keyring.Sign(other, privKey)
+		require.NoError(t, err)
+		require.NotEqual(t, key, conflict.DedupKey(other))
+		require.True(t, Link(sig, conflict))
+
+		// another ring
+		otherRing, err := NewKeyRing(curve, 5, privKey, 2)
+		require.NoError(t, err)
+		sig2, err := otherRing.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		require.NotEqual(t, key, sig2.DedupKey(testMsg))
+
+		// another signer in the same ring
+		decoy := createSigWithCurve(t, curve, 5, 0)
+		require.NotEqual(t, key, decoy.DedupKey(testMsg))
+	}
+}
+
+func TestDedupKey_Torsion(t *testing.T) {
+	torsioned, regular, _ := signWithTorsion(t, 3, 1)
+	require.Equal(t, regular.DedupKey(testMsg), torsioned.DedupKey(testMsg))
+}
+
+func TestDedupKey_KnownAnswer(t *testing.T) {
+	curve := Secp256k1()
+	privKey, err := curve.HashToScalar([]byte("dedup key"))
+	require.NoError(t, err)
+	keyring, err := NewKeyRingDeterministic(curve, 3, privKey, 1, []byte("dedup decoys"))
+	require.NoError(t, err)
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+
+	key := sig.DedupKey(testMsg)
+	require.Equal(t, "cb4c531ba68492a63787b018cfe0658b4b4fc06ad88a1abcb4c564020e68c584", hex.EncodeToString(key[:]))
+
+	// the documented layout
+	ringHash := keyring.Hash()
+	in := append([]byte("ring-go/dedup"), sig.KeyImage().Encode()...)
+	in = append(append(in, ringHash[:]...), testMsg[:]...)
+	require.Equal(t, sha3.Sum256(in), key)
+}
@@ -0,0 +1,183 @@
+package ring
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"golang.org/x/crypto/cryptobyte"
+	cbasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// OIDSchemeLSAG is the object identifier of the signature scheme of this
+// package in DER-encoded signatures, under the UUID arc 2.25 (ITU-T X.667).
+const OIDSchemeLSAG = "2.25.69499814033911309131738404684811063255"
+
+var (
+	// oidSchemeLSAG is the content of the DER encoding of OIDSchemeLSAG.
+	// Its last arc doesn't fit in an int, so it can't be an
+	// asn1.ObjectIdentifier.
+	oidSchemeLSAG = []byte{
+		0x69, 0xe8, 0xc9, 0x96, 0xd8, 0xec, 0xa3, 0xe2, 0x90, 0xb1,
+		0x9b, 0xc5, 0x8c, 0xa1, 0x9c, 0x87, 0xc7, 0x9f, 0x57,
+	}
+	oidEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+// MarshalDER encodes the signature as the DER encoding of the ASN.1 structure
+//
+//	RingSignature ::= SEQUENCE {
+//	    scheme     OBJECT IDENTIFIER, -- OIDSchemeLSAG
+//	    curve      OBJECT IDENTIFIER, -- secp256k1 (1.3.132.0.10) or id-Ed25519 (1.3.101.112)
+//	    challenge  OCTET STRING,
+//	    keyImage   OCTET STRING,      -- compressed point
+//	    members    SEQUENCE OF Member,
+//	    extensions [0] IMPLICIT OCTET STRING OPTIONAL
+//	}
+//
+//	Member ::= SEQUENCE {
+//	    publicKey OCTET STRING,       -- compressed point
+//	    response  OCTET STRING
+//	}
+//
+// Scalars are encoded as in Serialize. The extensions, present if the
+// signature has any (eg. a validity window), are the flags of the header of
+// Serialize followed by the extension fields, as in Serialize.
+//
+// It's an alternative to Serialize for tooling that requires DER structures,
+// eg. HSMs and X.509-adjacent systems; ParseDER decodes it.
+func (sig *RingSig) MarshalDER() ([]byte, error) {
+	var curveOID asn1.ObjectIdentifier
+	switch CurveIDOf(sig.ring.curve) {
+	case CurveSecp256k1:
+		curveOID = oidSecp256k1
+	case CurveEd25519:
+		curveOID = oidEd25519
+	default:
+		return nil, errors.New("unsupported curve")
+	}
+
+	var b cryptobyte.Builder
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1(cbasn1.OBJECT_IDENTIFIER, func(b *cryptobyte.Builder) {
+			b.AddBytes(oidSchemeLSAG)
+		})
+		b.AddASN1ObjectIdentifier(curveOID)
+		b.AddASN1OctetString(sig.c.Encode())
+		b.AddASN1OctetString(encodePoint(sig.image))
+		b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			for i, pk := range sig.ring.pubkeys {
+				b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddASN1OctetString(encodePoint(pk))
+					b.AddASN1OctetString(sig.s[i].Encode())
+				})
+			}
+		})
+		if flags := sig.ext.flags(); flags != 0 {
+			b.AddASN1(cbasn1.Tag(0).ContextSpecific(), func(b *cryptobyte.Builder) {
+				b.AddUint8(flags)
+				b.AddBytes(sig.ext.encode())
+			})
+		}
+	})
+	return b.Bytes()
+}
+
+// ParseDER decodes a signature encoded with MarshalDER, over the curve given
+// by its OID.
+func ParseDER(der []byte) (*RingSig, error) {
+	input := cryptobyte.String(der)
+	var seq, scheme, members cryptobyte.String
+	var curveOID asn1.ObjectIdentifier
+	var c, image []byte
+	if !input.ReadASN1(&seq, cbasn1.SEQUENCE) || !input.Empty() ||
+		!seq.ReadASN1(&scheme, cbasn1.OBJECT_IDENTIFIER) ||
+		!seq.ReadASN1ObjectIdentifier(&curveOID) ||
+		!seq.ReadASN1Bytes(&c, cbasn1.OCTET_STRING) ||
+		!seq.ReadASN1Bytes(&image, cbasn1.OCTET_STRING) ||
+		!seq.ReadASN1(&members, cbasn1.SEQUENCE) {
+		return nil, errors.New("malformed DER signature")
+	}
+	if !bytes.Equal(scheme, oidSchemeLSAG) {
+		return nil, errors.New("unsupported signature scheme")
+	}
+
+	var curve types.Curve
+	switch {
+	case curveOID.Equal(oidSecp256k1):
+		curve = Secp256k1()
+	case curveOID.Equal(oidEd25519):
+		curve = Ed25519()
+	default:
+		return nil, fmt.Errorf("unsupported curve %s", curveOID)
+	}
+
+	sig := &RingSig{ring: &Ring{curve: curve}}
+	var err error
+	if sig.c, err = decodeScalar(curve, c); err != nil {
+		return nil, err
+	}
+	if sig.image, err = curve.DecodeToPoint(image); err != nil {
+		return nil, err
+	}
+
+	for i := 0; !members.Empty(); i++ {
+		if i == MaxRingSize {
+			return nil, errors.New("ring size exceeds MaxRingSize")
+		}
+		var member cryptobyte.String
+		var pkEnc, sEnc []byte
+		if !members.ReadASN1(&member, cbasn1.SEQUENCE) ||
+			!member.ReadASN1Bytes(&pkEnc, cbasn1.OCTET_STRING) ||
+			!member.ReadASN1Bytes(&sEnc, cbasn1.OCTET_STRING) || !member.Empty() {
+			return nil, fmt.Errorf("malformed ring member %d", i)
+		}
+		pk, err := curve.DecodeToPoint(pkEnc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key at index %d: %w", i, err)
+		}
+		s, err := decodeScalar(curve, sEnc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid response at index %d: %w", i, err)
+		}
+		sig.ring.pubkeys = append(sig.ring.pubkeys, pk)
+		sig.s = append(sig.s, s)
+	}
+	if len(sig.s) == 0 {
+		return nil, errors.New("empty ring")
+	}
+
+	var ext cryptobyte.String
+	var hasExt bool
+	if !seq.ReadOptionalASN1(&ext, &hasExt, cbasn1.Tag(0).ContextSpecific()) || !seq.Empty() {
+		return nil, errors.New("malformed DER signature")
+	}
+	if hasExt {
+		var header byte
+		if !ext.ReadUint8(&header) {
+			return nil, errors.New("malformed extensions")
+		}
+		enc, flags, err := splitFlags(header)
+		if err != nil {
+			return nil, err
+		}
+		if enc != PointCompressed || flags == 0 || len(ext) != extensionsLen(flags) {
+			return nil, errors.New("malformed extensions")
+		}
+		if sig.ext, err = decodeExtensions(flags, ext); err != nil {
+			return nil, err
+		}
+	}
+
+	return sig, nil
+}
+
+// decodeScalar decodes a scalar, checking its length first.
+func decodeScalar(curve types.Curve, in []byte) (types.Scalar, error) {
+	if len(in) != ScalarSize(curve) {
+		return nil, fmt.Errorf("scalar must be %d bytes, got %d", ScalarSize(curve), len(in))
+	}
+	return curve.DecodeToScalar(in)
+}
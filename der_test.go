@@ -0,0 +1,77 @@
+package ring
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalDER(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 4, privKey, 1)
+		require.NoError(t, err)
+
+		for _, opts := range [][]SignOption{nil, {WithChainID(1), WithNetwork("mainnet")}} {
+			sig, err := keyring.Sign(testMsg, privKey, opts...)
+			require.NoError(t, err)
+
+			der, err := sig.MarshalDER()
+			require.NoError(t, err)
+
+			// the encoding is valid DER, as parsed by encoding/asn1
+			var raw asn1.RawValue
+			rest, err := asn1.Unmarshal(der, &raw)
+			require.NoError(t, err)
+			require.Empty(t, rest)
+
+			decoded, err := ParseDER(der)
+			require.NoError(t, err)
+			require.True(t, decoded.Ring().Equals(keyring))
+			require.True(t, decoded.Verify(testMsg))
+			require.True(t, Link(sig, decoded))
+
+			enc, err := sig.Serialize()
+			require.NoError(t, err)
+			decodedEnc, err := decoded.Serialize()
+			require.NoError(t, err)
+			require.Equal(t, enc, decodedEnc)
+
+			_, err = ParseDER(der[:len(der)-1])
+			require.Error(t, err)
+			_, err = ParseDER(append(der, 0))
+			require.Error(t, err)
+		}
+	}
+}
+
+func TestOIDSchemeLSAG(t *testing.T) {
+	// decode the base-128 arcs of the encoded OID
+	var arcs []*big.Int
+	arc := new(big.Int)
+	for _, b := range oidSchemeLSAG {
+		arc.Lsh(arc, 7).Or(arc, big.NewInt(int64(b&0x7f)))
+		if b&0x80 == 0 {
+			arcs = append(arcs, arc)
+			arc = new(big.Int)
+		}
+	}
+	require.Len(t, arcs, 2)
+	require.Equal(t, int64(2*40+25), arcs[0].Int64())
+	require.Equal(t, OIDSchemeLSAG, "2.25."+arcs[1].String())
+}
+
+func TestParseDER_Scheme(t *testing.T) {
+	sig := createSigWithCurve(t, Ed25519(), 2, 0)
+	der, err := sig.MarshalDER()
+	require.NoError(t, err)
+
+	i := bytes.Index(der, oidSchemeLSAG)
+	require.Positive(t, i)
+	der[i+len(oidSchemeLSAG)-1] ^= 1
+	_, err = ParseDER(der)
+	require.ErrorContains(t, err, "unsupported signature scheme")
+}
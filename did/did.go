@@ -0,0 +1,167 @@
+// Package did identifies ring members by did:key decentralized identifiers,
+// for self-sovereign identity and verifiable credential ecosystems using ring
+// signatures for anonymous presentations.
+//
+// A did:key identifier encodes a public key as "did:key:z" followed by the
+// base58btc encoding of its multicodec-prefixed bytes: ed25519 keys use the
+// ed25519-pub codec (0xed), and secp256k1 keys the secp256k1-pub codec (0xe7)
+// with the compressed public key. Rings are built from lists of identifiers:
+//
+//	keyring, err := did.NewRing([]string{"did:key:z6Mk...", "did:key:z6Mk..."})
+//	sig, err := keyring.Sign(msgHash, privKey)
+//	...
+//	meta, err := did.NewMetadata(sig)
+package did
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	ring "github.com/pokt-network/ring-go"
+	"github.com/pokt-network/ring-go/internal/base58"
+)
+
+const prefix = "did:key:z"
+
+// Multicodec codes of the supported key types, as unsigned varints.
+var (
+	codecEd25519   = []byte{0xed, 0x01}
+	codecSecp256k1 = []byte{0xe7, 0x01}
+)
+
+// Encode returns the did:key identifier of a public key on one of the curves
+// supported by the ring package.
+func Encode(pk ring.Point) (string, error) {
+	var codec []byte
+	switch ring.CurveIDOfPoint(pk) {
+	case ring.CurveEd25519:
+		codec = codecEd25519
+	case ring.CurveSecp256k1:
+		codec = codecSecp256k1
+	default:
+		return "", fmt.Errorf("unsupported point type %T", pk)
+	}
+
+	b := append(append([]byte{}, codec...), pk.Encode()...)
+	return prefix + base58.Encode(b), nil
+}
+
+// Parse returns the public key identified by a did:key identifier.
+func Parse(did string) (ring.Point, error) {
+	// a DID URL may reference the key with a fragment
+	did, _, _ = strings.Cut(did, "#")
+
+	encoded, ok := strings.CutPrefix(did, prefix)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a base58btc did:key identifier", did)
+	}
+	b, err := base58.Decode(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid did:key identifier: %w", err)
+	}
+	if len(b) < 2 {
+		return nil, errors.New("invalid did:key identifier: too short")
+	}
+
+	var curve ring.Curve
+	switch codec := b[:2]; {
+	case string(codec) == string(codecEd25519):
+		curve = ring.Ed25519()
+	case string(codec) == string(codecSecp256k1):
+		curve = ring.Secp256k1()
+	default:
+		return nil, fmt.Errorf("unsupported did:key multicodec %#x", codec)
+	}
+
+	if len(b)-2 != curve.CompressedPointSize() {
+		return nil, errors.New("invalid did:key public key length")
+	}
+	return curve.DecodeToPoint(b[2:])
+}
+
+// NewRing builds a ring from did:key identifiers, in order. The keys must be
+// on the same curve, and distinct.
+func NewRing(dids []string) (*ring.Ring, error) {
+	if len(dids) == 0 {
+		return nil, errors.New("no identifiers")
+	}
+
+	pubkeys := make([]ring.Point, len(dids))
+	seen := make(map[string]bool, len(dids))
+	for i, did := range dids {
+		pk, err := Parse(did)
+		if err != nil {
+			return nil, fmt.Errorf("member %d: %w", i, err)
+		}
+		if i > 0 && ring.CurveIDOfPoint(pk) != ring.CurveIDOfPoint(pubkeys[0]) {
+			return nil, fmt.Errorf("member %d: all keys must be on the same curve", i)
+		}
+
+		enc := string(pk.Encode())
+		if seen[enc] {
+			return nil, fmt.Errorf("member %d: duplicate public key", i)
+		}
+		seen[enc] = true
+		pubkeys[i] = pk
+	}
+
+	curve, err := ring.CurveIDOfPoint(pubkeys[0]).Curve()
+	if err != nil {
+		return nil, err
+	}
+	return ring.NewFixedKeyRingFromPublicKeys(curve, pubkeys)
+}
+
+// Members returns the did:key identifiers of the members of a ring, in order.
+func Members(r *ring.Ring) ([]string, error) {
+	pubkeys := r.PublicKeys()
+	dids := make([]string, len(pubkeys))
+	for i, pk := range pubkeys {
+		var err error
+		if dids[i], err = Encode(pk); err != nil {
+			return nil, err
+		}
+	}
+	return dids, nil
+}
+
+// Metadata describes a ring signature by referencing the members of its ring
+// by DID, eg. for inclusion in a credential presentation. It doesn't include
+// the signature itself.
+type Metadata struct {
+	// Curve is the name of the ring's curve, eg. "ed25519".
+	Curve string `json:"curve"`
+	// Members are the did:key identifiers of the ring members, in order.
+	Members []string `json:"members"`
+	// KeyImage is the hex encoding of the signature's normalized key image,
+	// which is the same for all signatures by the same member.
+	KeyImage string `json:"keyImage"`
+}
+
+// NewMetadata returns the metadata of a ring signature.
+func NewMetadata(sig *ring.RingSig) (*Metadata, error) {
+	members, err := Members(sig.Ring())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Metadata{
+		Curve:    ring.CurveIDOf(sig.Ring().Curve()).String(),
+		Members:  members,
+		KeyImage: hex.EncodeToString(ring.NormalizeKeyImage(sig.KeyImage()).Encode()),
+	}, nil
+}
+
+// Ring returns the ring of the members referenced by the metadata.
+func (m *Metadata) Ring() (*ring.Ring, error) {
+	r, err := NewRing(m.Members)
+	if err != nil {
+		return nil, err
+	}
+	if id := ring.CurveIDOf(r.Curve()).String(); id != m.Curve {
+		return nil, fmt.Errorf("members are on %s, not %s", id, m.Curve)
+	}
+	return r, nil
+}
@@ -0,0 +1,99 @@
+package did
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+	"github.com/pokt-network/ring-go/internal/base58"
+)
+
+func TestEncodeAndParse(t *testing.T) {
+	for curve, prefix := range map[ring.Curve]string{
+		ring.Ed25519():   "did:key:z6Mk",
+		ring.Secp256k1(): "did:key:zQ3s",
+	} {
+		pk := curve.ScalarBaseMul(curve.NewRandomScalar())
+		did, err := Encode(pk)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(did, prefix), did)
+
+		parsed, err := Parse(did)
+		require.NoError(t, err)
+		require.True(t, parsed.Equals(pk))
+
+		// fragments of DID URLs are ignored
+		parsed, err = Parse(did + "#" + strings.TrimPrefix(did, "did:key:"))
+		require.NoError(t, err)
+		require.True(t, parsed.Equals(pk))
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, did := range []string{
+		"",
+		"did:web:example.com",
+		"did:key:f0123",
+		"did:key:z",
+		"did:key:z0",
+		"did:key:z" + base58.Encode([]byte{0x12, 0x00, 1, 2, 3}),
+		"did:key:z" + base58.Encode(append([]byte{0xed, 0x01}, make([]byte, 31)...)),
+	} {
+		_, err := Parse(did)
+		require.Error(t, err, did)
+	}
+}
+
+func TestNewRing(t *testing.T) {
+	curve := ring.Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := ring.NewKeyRing(curve, 4, privKey, 1)
+	require.NoError(t, err)
+
+	dids, err := Members(keyring)
+	require.NoError(t, err)
+	fromDIDs, err := NewRing(dids)
+	require.NoError(t, err)
+	require.True(t, fromDIDs.Equals(keyring))
+
+	sig, err := fromDIDs.Sign([32]byte{1}, privKey)
+	require.NoError(t, err)
+
+	meta, err := NewMetadata(sig)
+	require.NoError(t, err)
+	require.Equal(t, "ed25519", meta.Curve)
+	require.Equal(t, dids, meta.Members)
+
+	b, err := json.Marshal(meta)
+	require.NoError(t, err)
+	var decoded Metadata
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	r, err := decoded.Ring()
+	require.NoError(t, err)
+	require.True(t, r.Equals(keyring))
+
+	decoded.Curve = "secp256k1"
+	_, err = decoded.Ring()
+	require.Error(t, err)
+}
+
+func TestNewRing_Invalid(t *testing.T) {
+	ed := ring.Ed25519().ScalarBaseMul(ring.Ed25519().NewRandomScalar())
+	secp := ring.Secp256k1().ScalarBaseMul(ring.Secp256k1().NewRandomScalar())
+	edDID, err := Encode(ed)
+	require.NoError(t, err)
+	secpDID, err := Encode(secp)
+	require.NoError(t, err)
+
+	_, err = NewRing(nil)
+	require.Error(t, err)
+	_, err = NewRing([]string{edDID, secpDID})
+	require.EqualError(t, err, "member 1: all keys must be on the same curve")
+	_, err = NewRing([]string{edDID, edDID})
+	require.EqualError(t, err, "member 1: duplicate public key")
+	_, err = NewRing([]string{edDID, "did:key:x"})
+	require.Error(t, err)
+}
@@ -0,0 +1,103 @@
+package ring
+
+import (
+	"crypto"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+const digestDomain = "ring-go/digest"
+
+// maxDigestLen is the largest digest accepted by SignDigest.
+const maxDigestLen = 1<<16 - 1
+
+// Digest is a message digest of any length, along with the hash function that
+// produced it. It allows signing digests that aren't 32 bytes long, such as
+// SHA-512 digests, without truncating them.
+//
+// 32-byte digests are signed as is, so signing a SHA-256 Digest is the same as
+// signing its sum with Sign. Other digests are hashed to 32 bytes together
+// with their length and hash function, which binds them into the transcript:
+// a signature over a 64-byte digest doesn't verify for its truncation, or for
+// the same bytes produced by another hash function.
+type Digest struct {
+	// Hash is the hash function that produced the digest. It may be zero if
+	// it's unknown, in which case the length of Sum isn't checked.
+	Hash crypto.Hash
+	// Sum is the digest.
+	Sum []byte
+}
+
+// NewDigest returns the digest of msg computed with the given hash function,
+// which must be available.
+func NewDigest(hash crypto.Hash, msg []byte) (Digest, error) {
+	if !hash.Available() {
+		return Digest{}, fmt.Errorf("hash function %s is not available", hash)
+	}
+	h := hash.New()
+	_, _ = h.Write(msg)
+	return Digest{Hash: hash, Sum: h.Sum(nil)}, nil
+}
+
+// SHA512Digest returns the SHA-512 digest of msg.
+func SHA512Digest(msg []byte) Digest {
+	sum := sha512.Sum512(msg)
+	return Digest{Hash: crypto.SHA512, Sum: sum[:]}
+}
+
+// BLAKE2bDigest returns the 64-byte BLAKE2b-512 digest of msg.
+func BLAKE2bDigest(msg []byte) Digest {
+	sum := blake2b.Sum512(msg)
+	return Digest{Hash: crypto.BLAKE2b_512, Sum: sum[:]}
+}
+
+// message returns the 32-byte message signed for the digest.
+func (d Digest) message() ([32]byte, error) {
+	switch {
+	case len(d.Sum) == 0:
+		return [32]byte{}, errors.New("digest is empty")
+	case len(d.Sum) > maxDigestLen:
+		return [32]byte{}, fmt.Errorf("digest is longer than %d bytes", maxDigestLen)
+	case d.Hash != 0 && len(d.Sum) != d.Hash.Size():
+		return [32]byte{}, fmt.Errorf("digest length %d does not match hash function %s", len(d.Sum), d.Hash)
+	case len(d.Sum) == 32:
+		return [32]byte(d.Sum), nil
+	}
+
+	h := sha3.New256()
+	_, _ = h.Write([]byte(digestDomain))
+	_, _ = h.Write(binary.BigEndian.AppendUint32(nil, uint32(d.Hash)))
+	_, _ = h.Write(binary.BigEndian.AppendUint16(nil, uint16(len(d.Sum))))
+	_, _ = h.Write(d.Sum)
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out, nil
+}
+
+// SignDigest creates a ring signature on the given digest, like Sign.
+func (r *Ring) SignDigest(d Digest, privKey types.Scalar, opts ...SignOption) (*RingSig, error) {
+	m, err := d.message()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Sign(m, privKey, opts...)
+}
+
+// VerifyDigest verifies the ring signature for the given digest, like Verify.
+// It returns false if the digest is malformed.
+func (sig *RingSig) VerifyDigest(d Digest) bool {
+	m, err := d.message()
+	if err != nil {
+		return false
+	}
+
+	return sig.Verify(m)
+}
@@ -0,0 +1,65 @@
+package ring
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignDigest(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 4, privKey, 2)
+		require.NoError(t, err)
+
+		for _, d := range []Digest{
+			SHA512Digest([]byte("hello")),
+			BLAKE2bDigest([]byte("hello")),
+			{Sum: make([]byte, 48)},
+		} {
+			sig, err := keyring.SignDigest(d, privKey)
+			require.NoError(t, err)
+			require.True(t, sig.VerifyDigest(d))
+
+			// the length and hash function are bound
+			require.False(t, sig.VerifyDigest(Digest{Hash: d.Hash, Sum: d.Sum[:len(d.Sum)-1]}))
+			require.False(t, sig.VerifyDigest(Digest{Hash: crypto.SHA384, Sum: d.Sum}))
+			require.False(t, sig.Verify([32]byte(d.Sum)))
+		}
+	}
+}
+
+func TestSignDigest_32Bytes(t *testing.T) {
+	privKey := Secp256k1().NewRandomScalar()
+	keyring, err := NewKeyRing(Secp256k1(), 3, privKey, 0)
+	require.NoError(t, err)
+
+	d, err := NewDigest(crypto.SHA256, []byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, sha256.Sum256([]byte("hello")), [32]byte(d.Sum))
+
+	// 32-byte digests are signed as is
+	sig, err := keyring.SignDigest(d, privKey)
+	require.NoError(t, err)
+	require.True(t, sig.Verify([32]byte(d.Sum)))
+}
+
+func TestSignDigest_Invalid(t *testing.T) {
+	privKey := Ed25519().NewRandomScalar()
+	keyring, err := NewKeyRing(Ed25519(), 3, privKey, 0)
+	require.NoError(t, err)
+
+	for _, d := range []Digest{
+		{},
+		{Hash: crypto.SHA512, Sum: make([]byte, 32)},
+		{Sum: make([]byte, maxDigestLen+1)},
+	} {
+		_, err := keyring.SignDigest(d, privKey)
+		require.Error(t, err)
+	}
+
+	_, err = NewDigest(crypto.Hash(0), nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,46 @@
+package ring
+
+import "errors"
+
+const digestLen = 32
+
+// WithEmbeddedDigest embeds the signed message in the signature, so that it
+// can be re-verified later with VerifyEmbedded, eg. by an audit pipeline that
+// archives signatures without the messages they sign. The message is returned
+// by SignedDigest.
+//
+// The embedded message is part of the signed transcript. Signatures embedding
+// it set a flag in the encoding's header, and can't be decoded by versions of
+// this package that predate it.
+func WithEmbeddedDigest() SignOption {
+	return func(o *signOptions) {
+		o.embedDigest = true
+	}
+}
+
+// SignedDigest returns the message embedded in the signature with
+// WithEmbeddedDigest. ok is false if the signature doesn't embed it.
+//
+// The message is only known to be signed once the signature has been verified,
+// eg. with VerifyEmbedded.
+func (r *RingSig) SignedDigest() (m [32]byte, ok bool) {
+	if r.ext.digest == nil {
+		return [32]byte{}, false
+	}
+	return *r.ext.digest, true
+}
+
+// ErrNoEmbeddedDigest is returned by VerifyEmbedded when the signature doesn't
+// embed the signed message.
+var ErrNoEmbeddedDigest = errors.New("signature has no embedded digest")
+
+// VerifyEmbedded verifies the signature for the message embedded in it, like
+// VerifyWithPolicy with a nil policy. It returns ErrNoEmbeddedDigest if the
+// signature doesn't embed the message.
+func (sig *RingSig) VerifyEmbedded() error {
+	m, ok := sig.SignedDigest()
+	if !ok {
+		return ErrNoEmbeddedDigest
+	}
+	return sig.VerifyWithPolicy(m, nil)
+}
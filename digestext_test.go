@@ -0,0 +1,47 @@
+package ring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedDigest(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 4, privKey, 1)
+		require.NoError(t, err)
+
+		sig, err := keyring.Sign(testMsg, privKey, WithEmbeddedDigest(), WithValidity(time.Time{}, time.Now().Add(time.Hour)))
+		require.NoError(t, err)
+		m, ok := sig.SignedDigest()
+		require.True(t, ok)
+		require.Equal(t, testMsg, m)
+		require.NoError(t, sig.VerifyEmbedded())
+		require.True(t, sig.Verify(testMsg))
+
+		enc, err := sig.Serialize()
+		require.NoError(t, err)
+		require.Equal(t, flagValidity|flagDigest, enc[0])
+
+		res := new(RingSig)
+		require.NoError(t, res.Deserialize(curve, enc))
+		m, ok = res.SignedDigest()
+		require.True(t, ok)
+		require.Equal(t, testMsg, m)
+		require.NoError(t, res.VerifyEmbedded())
+
+		// the embedded digest is bound into the transcript
+		enc[len(enc)-keyring.Size()*(32+curve.CompressedPointSize())-1] ^= 1
+		require.NoError(t, res.Deserialize(curve, enc))
+		require.ErrorIs(t, res.VerifyEmbedded(), ErrInvalidSignature)
+	}
+}
+
+func TestEmbeddedDigest_Absent(t *testing.T) {
+	sig := createSig(t, 3, 0)
+	_, ok := sig.SignedDigest()
+	require.False(t, ok)
+	require.ErrorIs(t, sig.VerifyEmbedded(), ErrNoEmbeddedDigest)
+}
@@ -0,0 +1,289 @@
+// Package dual links ring signatures over a secp256k1 ring and an ed25519 ring
+// to one identity, eg. to bridge an identity across chains using different
+// curves. The signer's keys on both curves are derived from one secret with
+// Keys, and a cross-group discrete logarithm equality proof (go-dleq) shows
+// that both signatures were created with it:
+//
+//	secret, err := dual.GenerateSecret()
+//	secpKey, edKey, err := dual.Keys(secret)
+//	...
+//	sig, err := dual.Sign(msgHash, secpRing, edRing, secret)
+//	err = sig.Verify(msgHash)
+//
+// An IdentitySig is NOT anonymous: the proof's commitments are the signer's
+// public keys, so it reveals which member of each ring signed. Applications
+// that need the signer to stay hidden among the rings' members must not use
+// this package.
+package dual
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq"
+	"github.com/athanorlabs/go-dleq/types"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+const (
+	domain = "ring-go/dual"
+
+	// secretBits is the size of the secrets: the bit size of the smaller
+	// curve, ed25519.
+	secretBits = 252
+)
+
+// IdentitySig carries ring signatures of the same message over a secp256k1
+// ring and an ed25519 ring, with a proof that both were created with the same
+// secret.
+//
+// It is NOT anonymous: the proof reveals the signer's public key on both
+// curves (see PublicKeys), and so which member of each ring signed. Each
+// signature's key image is additionally proven to belong to the revealed
+// public key and bound to the message, so that the proofs can't be reused
+// with other signatures.
+type IdentitySig struct {
+	secp, ed         *ring.RingSig
+	proof            *dleq.Proof
+	secpLink, edLink *imageProof
+}
+
+// GenerateSecret returns a random secret for Keys. Secrets must be smaller
+// than both curves' orders, so they have 252 bits.
+func GenerateSecret() ([32]byte, error) {
+	return dleq.GenerateSecretForCurves(ring.Secp256k1(), ring.Ed25519())
+}
+
+// Keys returns the secp256k1 and ed25519 private keys for the secret,
+// which is little-endian and must be smaller than 2^252, as returned by
+// GenerateSecret.
+func Keys(secret [32]byte) (secp, ed types.Scalar, err error) {
+	if secret[31]&0xf0 != 0 {
+		return nil, nil, errors.New("dual secret must be smaller than 2^252")
+	}
+	secp = ring.Secp256k1().ScalarFromBytes(secret)
+	if secp.IsZero() {
+		return nil, nil, errors.New("dual secret is zero")
+	}
+	return secp, ring.Ed25519().ScalarFromBytes(secret), nil
+}
+
+// Sign signs the message with the keys derived from the secret over both
+// rings, whose curves must be secp256k1 and ed25519, and proves that the keys
+// share the secret. The options are passed to both calls to Ring.Sign.
+func Sign(m [32]byte, secpRing, edRing *ring.Ring, secret [32]byte, opts ...ring.SignOption) (*IdentitySig, error) {
+	if ring.CurveIDOf(secpRing.Curve()) != ring.CurveSecp256k1 || ring.CurveIDOf(edRing.Curve()) != ring.CurveEd25519 {
+		return nil, errors.New("rings must be over secp256k1 and ed25519")
+	}
+
+	secpKey, edKey, err := Keys(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	d := new(IdentitySig)
+	if d.secp, err = secpRing.Sign(m, secpKey, opts...); err != nil {
+		return nil, fmt.Errorf("failed to sign over the secp256k1 ring: %w", err)
+	}
+	if d.ed, err = edRing.Sign(m, edKey, opts...); err != nil {
+		return nil, fmt.Errorf("failed to sign over the ed25519 ring: %w", err)
+	}
+
+	if d.proof, err = dleq.NewProof(secpRing.Curve(), edRing.Curve(), secret); err != nil {
+		return nil, err
+	}
+
+	d.secpLink = proveImage(d.secp, m, secpKey)
+	d.edLink = proveImage(d.ed, m, edKey)
+	return d, nil
+}
+
+// Secp256k1Sig returns the signature over the secp256k1 ring.
+func (d *IdentitySig) Secp256k1Sig() *ring.RingSig {
+	return d.secp
+}
+
+// Ed25519Sig returns the signature over the ed25519 ring.
+func (d *IdentitySig) Ed25519Sig() *ring.RingSig {
+	return d.ed
+}
+
+// PublicKeys returns the signer's public keys on both curves, as revealed by
+// the proof.
+func (d *IdentitySig) PublicKeys() (secp, ed types.Point) {
+	return d.proof.CommitmentA.Copy(), d.proof.CommitmentB.Copy()
+}
+
+// Verify verifies both signatures for the message and the proof that they
+// were created with the same secret. It returns nil if the IdentitySig is
+// valid, ring.ErrNotValidAt if the signatures' validity window doesn't contain
+// the current time, and an error wrapping ring.ErrInvalidSignature otherwise.
+func (d *IdentitySig) Verify(m [32]byte) error {
+	if d.secp == nil || d.ed == nil || d.proof == nil || d.secpLink == nil || d.edLink == nil {
+		return fmt.Errorf("%w: incomplete dual signature", ring.ErrInvalidSignature)
+	}
+
+	if err := d.secp.VerifyWithPolicy(m, nil); err != nil {
+		return err
+	}
+	if err := d.ed.VerifyWithPolicy(m, nil); err != nil {
+		return err
+	}
+
+	secpCurve, edCurve := d.secp.Ring().Curve(), d.ed.Ring().Curve()
+	if err := d.proof.Verify(secpCurve, edCurve); err != nil {
+		return fmt.Errorf("%w: %w", ring.ErrInvalidSignature, err)
+	}
+
+	secpKey, edKey := d.proof.CommitmentA, d.proof.CommitmentB
+	if _, ok := d.secp.Ring().IndexOf(secpKey); !ok {
+		return fmt.Errorf("%w: secp256k1 key is not in the ring", ring.ErrInvalidSignature)
+	}
+	if _, ok := d.ed.Ring().IndexOf(edKey); !ok {
+		return fmt.Errorf("%w: ed25519 key is not in the ring", ring.ErrInvalidSignature)
+	}
+
+	if !d.secpLink.verify(d.secp, m, secpKey) || !d.edLink.verify(d.ed, m, edKey) {
+		return fmt.Errorf("%w: key image is not the signer's", ring.ErrInvalidSignature)
+	}
+	return nil
+}
+
+// Serialize encodes the IdentitySig as the secp256k1 signature, the ed25519
+// signature and the DLEQ proof, each prefixed with its 4-byte length,
+// followed by the key image proofs.
+func (d *IdentitySig) Serialize() ([]byte, error) {
+	secp, err := d.secp.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	ed, err := d.ed.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []byte
+	for _, part := range [][]byte{secp, ed, d.proof.Serialize()} {
+		out = binary.BigEndian.AppendUint32(out, uint32(len(part)))
+		out = append(out, part...)
+	}
+	out = d.secpLink.appendEncoding(out)
+	return d.edLink.appendEncoding(out), nil
+}
+
+// Deserialize decodes an IdentitySig encoded with Serialize.
+func (d *IdentitySig) Deserialize(in []byte) error {
+	r := bytes.NewBuffer(in)
+	var parts [3][]byte
+	for i := range parts {
+		if r.Len() < 4 {
+			return errors.New("input too short")
+		}
+		n := binary.BigEndian.Uint32(r.Next(4))
+		if uint64(r.Len()) < uint64(n) {
+			return errors.New("input too short")
+		}
+		parts[i] = r.Next(int(n))
+	}
+	secpCurve, edCurve := ring.Secp256k1(), ring.Ed25519()
+	secpLen, edLen := 2*ring.ScalarSize(secpCurve), 2*ring.ScalarSize(edCurve)
+	if r.Len() != secpLen+edLen {
+		return errors.New("invalid key image proofs length")
+	}
+
+	res := &IdentitySig{secp: new(ring.RingSig), ed: new(ring.RingSig), proof: new(dleq.Proof)}
+	if err := res.secp.Deserialize(secpCurve, parts[0]); err != nil {
+		return fmt.Errorf("invalid secp256k1 signature: %w", err)
+	}
+	if err := res.ed.Deserialize(edCurve, parts[1]); err != nil {
+		return fmt.Errorf("invalid ed25519 signature: %w", err)
+	}
+	// go-dleq's Verify expects one bit proof per bit of the secret, so check
+	// their number, which follows the commitments
+	if countAt := 33 + 32; len(parts[2]) <= countAt || parts[2][countAt] != secretBits {
+		return errors.New("invalid DLEQ proof length")
+	}
+	if err := res.proof.Deserialize(secpCurve, edCurve, parts[2]); err != nil {
+		return fmt.Errorf("invalid DLEQ proof: %w", err)
+	}
+	if !bytes.Equal(res.proof.Serialize(), parts[2]) {
+		return errors.New("invalid DLEQ proof encoding")
+	}
+
+	var err error
+	if res.secpLink, err = decodeImageProof(secpCurve, r.Next(secpLen)); err != nil {
+		return err
+	}
+	if res.edLink, err = decodeImageProof(edCurve, r.Next(edLen)); err != nil {
+		return err
+	}
+
+	*d = *res
+	return nil
+}
+
+// imageProof proves that a signature's key image I was created with the
+// private key x of the public key P, ie. that log_G(P) = log_{H_p(P)}(I), for
+// a given message. It's a Chaum-Pedersen proof (e, s) with
+// e = H(T1, T2), T1 = s*G + e*P and T2 = s*H_p(P) + e*I.
+type imageProof struct {
+	e, s types.Scalar
+}
+
+func proveImage(sig *ring.RingSig, m [32]byte, privKey types.Scalar) *imageProof {
+	curve := sig.Ring().Curve()
+	pubkey := curve.ScalarBaseMul(privKey)
+	k := curve.NewRandomScalar()
+
+	e := imageChallenge(sig, m, pubkey, curve.ScalarBaseMul(k), curve.ScalarMul(k, ring.HashToCurve(pubkey)))
+	return &imageProof{e: e, s: k.Sub(e.Mul(privKey))}
+}
+
+func (p *imageProof) verify(sig *ring.RingSig, m [32]byte, pubkey types.Point) bool {
+	curve := sig.Ring().Curve()
+	t1 := curve.ScalarMul(p.e, pubkey).Add(curve.ScalarBaseMul(p.s))
+	t2 := curve.ScalarMul(p.s, ring.HashToCurve(pubkey)).Add(curve.ScalarMul(p.e, sig.KeyImage()))
+	return imageChallenge(sig, m, pubkey, t1, t2).Eq(p.e)
+}
+
+// imageChallenge binds the proof to the signature and the message.
+func imageChallenge(sig *ring.RingSig, m [32]byte, pubkey, t1, t2 types.Point) types.Scalar {
+	h := sig.Hash()
+
+	var buf bytes.Buffer
+	buf.WriteString(domain)
+	buf.Write(h[:])
+	buf.Write(m[:])
+	buf.Write(pubkey.Copy().Encode())
+	buf.Write(t1.Copy().Encode())
+	buf.Write(t2.Copy().Encode())
+
+	e, err := sig.Ring().Curve().HashToScalar(buf.Bytes())
+	if err != nil {
+		// this should not happen
+		panic(err)
+	}
+	return e
+}
+
+func (p *imageProof) appendEncoding(out []byte) []byte {
+	out = append(out, p.e.Encode()...)
+	return append(out, p.s.Encode()...)
+}
+
+// decodeImageProof decodes a proof from in, which holds two scalars.
+func decodeImageProof(curve types.Curve, in []byte) (*imageProof, error) {
+	scalarLen := len(in) / 2
+	e, err := curve.DecodeToScalar(in[:scalarLen])
+	if err != nil {
+		return nil, err
+	}
+	s, err := curve.DecodeToScalar(in[scalarLen:])
+	if err != nil {
+		return nil, err
+	}
+	return &imageProof{e: e, s: s}, nil
+}
@@ -0,0 +1,123 @@
+package dual
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+var testMsg = [32]byte{1, 2, 3}
+
+func newDualRings(t *testing.T, secret [32]byte) (secpRing, edRing *ring.Ring) {
+	secpKey, edKey, err := Keys(secret)
+	require.NoError(t, err)
+	secpRing, err = ring.NewKeyRing(ring.Secp256k1(), 4, secpKey, 1)
+	require.NoError(t, err)
+	edRing, err = ring.NewKeyRing(ring.Ed25519(), 5, edKey, 3)
+	require.NoError(t, err)
+	return secpRing, edRing
+}
+
+func TestIdentitySig(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	secpRing, edRing := newDualRings(t, secret)
+
+	d, err := Sign(testMsg, secpRing, edRing, secret, ring.WithValidity(time.Time{}, time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	require.NoError(t, d.Verify(testMsg))
+	require.ErrorIs(t, d.Verify([32]byte{}), ring.ErrInvalidSignature)
+
+	secpKey, edKey := d.PublicKeys()
+	idx, ok := secpRing.IndexOf(secpKey)
+	require.True(t, ok)
+	require.Equal(t, 1, idx)
+	idx, ok = edRing.IndexOf(edKey)
+	require.True(t, ok)
+	require.Equal(t, 3, idx)
+	require.True(t, d.Secp256k1Sig().Ring().Equals(secpRing))
+	require.True(t, d.Ed25519Sig().Ring().Equals(edRing))
+
+	enc, err := d.Serialize()
+	require.NoError(t, err)
+	res := new(IdentitySig)
+	require.NoError(t, res.Deserialize(enc))
+	require.NoError(t, res.Verify(testMsg))
+
+	// another signature by the same secret links with the dual signature
+	sig, err := secpRing.Sign([32]byte{1}, mustDualKey(t, secret))
+	require.NoError(t, err)
+	require.True(t, ring.Link(sig, res.Secp256k1Sig()))
+
+	for _, n := range []int{0, 10, len(enc) - 1} {
+		require.Error(t, new(IdentitySig).Deserialize(enc[:n]))
+	}
+}
+
+func mustDualKey(t *testing.T, secret [32]byte) ring.Scalar {
+	secpKey, _, err := Keys(secret)
+	require.NoError(t, err)
+	return secpKey
+}
+
+func TestIdentitySig_Mismatch(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	other, err := GenerateSecret()
+	require.NoError(t, err)
+	secpRing, edRing := newDualRings(t, secret)
+	_, otherEdRing := newDualRings(t, other)
+
+	d, err := Sign(testMsg, secpRing, edRing, secret)
+	require.NoError(t, err)
+	e, err := Sign(testMsg, secpRing, mergeRings(t, edRing, otherEdRing), other)
+	require.Error(t, err) // other's secp key isn't in the ring
+	require.Nil(t, e)
+
+	// the ed25519 signature of another secret can't be swapped in
+	secpOther, otherEd := newDualRings(t, other)
+	e, err = Sign(testMsg, secpOther, otherEd, other)
+	require.NoError(t, err)
+	swapped := &IdentitySig{secp: d.secp, ed: e.ed, proof: d.proof, secpLink: d.secpLink, edLink: e.edLink}
+	require.ErrorIs(t, swapped.Verify(testMsg), ring.ErrInvalidSignature)
+
+	// nor the proofs reused for another message
+	d2, err := Sign([32]byte{1}, secpRing, edRing, secret)
+	require.NoError(t, err)
+	replayed := &IdentitySig{secp: d2.secp, ed: d2.ed, proof: d.proof, secpLink: d.secpLink, edLink: d.edLink}
+	require.ErrorIs(t, replayed.Verify([32]byte{1}), ring.ErrInvalidSignature)
+
+	require.ErrorIs(t, new(IdentitySig).Verify(testMsg), ring.ErrInvalidSignature)
+}
+
+func mergeRings(t *testing.T, a, b *ring.Ring) *ring.Ring {
+	r, err := ring.NewFixedKeyRingFromPublicKeys(a.Curve(), append(a.PublicKeys(), b.PublicKeys()...))
+	require.NoError(t, err)
+	return r
+}
+
+func TestKeys(t *testing.T) {
+	var secret [32]byte
+	_, _, err := Keys(secret)
+	require.Error(t, err)
+
+	secret[31] = 0x10
+	_, _, err = Keys(secret)
+	require.Error(t, err)
+
+	secret[31] = 0x0f
+	secpKey, edKey, err := Keys(secret)
+	require.NoError(t, err)
+	enc := secpKey.Encode()
+	for i, j := 0, len(enc)-1; i < j; i, j = i+1, j-1 {
+		enc[i], enc[j] = enc[j], enc[i]
+	}
+	require.Equal(t, enc, edKey.Encode())
+
+	secpRing, edRing := newDualRings(t, secret)
+	_, err = Sign(testMsg, edRing, secpRing, secret)
+	require.Error(t, err)
+}
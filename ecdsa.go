@@ -0,0 +1,75 @@
+package ring
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+
+	"github.com/athanorlabs/go-dleq/types"
+	dsecp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// ScalarFromECDSA converts a secp256k1 *ecdsa.PrivateKey, such as one used by
+// go-ethereum or decred, into a private key that can be used with the
+// Secp256k1 curve.
+//
+// It returns an error if the key is not on secp256k1, or if its value is not
+// in the range [1, N-1].
+func ScalarFromECDSA(priv *ecdsa.PrivateKey) (types.Scalar, error) {
+	if priv == nil || priv.D == nil {
+		return nil, errors.New("nil private key")
+	}
+
+	if !isSecp256k1(priv.Curve) {
+		return nil, errors.New("private key is not on secp256k1")
+	}
+
+	if priv.D.Sign() <= 0 || priv.D.Cmp(dsecp256k1.S256().Params().N) >= 0 {
+		return nil, errors.New("private key out of range")
+	}
+
+	var b [32]byte
+	priv.D.FillBytes(b[:])
+	return Secp256k1().DecodeToScalar(b[:])
+}
+
+// PointFromECDSAPub converts a secp256k1 *ecdsa.PublicKey into a public key
+// that can be used in a Secp256k1 ring.
+//
+// It returns an error if the key is not on secp256k1.
+func PointFromECDSAPub(pub *ecdsa.PublicKey) (types.Point, error) {
+	if pub == nil || pub.X == nil || pub.Y == nil {
+		return nil, errors.New("nil public key")
+	}
+
+	if !isSecp256k1(pub.Curve) {
+		return nil, errors.New("public key is not on secp256k1")
+	}
+
+	if pub.X.Sign() < 0 || pub.X.BitLen() > 256 || pub.Y.Sign() < 0 || pub.Y.BitLen() > 256 {
+		return nil, errors.New("public key coordinates out of range")
+	}
+
+	// decoding the uncompressed encoding checks that the point is on the curve
+	var uncompressed [65]byte
+	uncompressed[0] = 0x04
+	pub.X.FillBytes(uncompressed[1:33])
+	pub.Y.FillBytes(uncompressed[33:])
+	return Secp256k1().DecodeToPoint(uncompressed[:])
+}
+
+// isSecp256k1 returns true if the curve's parameters are those of secp256k1.
+// The parameters are compared rather than the curve itself, as libraries such
+// as go-ethereum provide their own secp256k1 elliptic.Curve implementations.
+func isSecp256k1(curve elliptic.Curve) bool {
+	if curve == nil {
+		return false
+	}
+
+	params, want := curve.Params(), dsecp256k1.S256().Params()
+	return params.P.Cmp(want.P) == 0 &&
+		params.N.Cmp(want.N) == 0 &&
+		params.B.Cmp(want.B) == 0 &&
+		params.Gx.Cmp(want.Gx) == 0 &&
+		params.Gy.Cmp(want.Gy) == 0
+}
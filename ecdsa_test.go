@@ -0,0 +1,69 @@
+package ring
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	dsecp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScalarFromECDSA(t *testing.T) {
+	priv, err := dsecp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	ecdsaPriv := priv.ToECDSA()
+
+	privKey, err := ScalarFromECDSA(ecdsaPriv)
+	require.NoError(t, err)
+
+	pubkey, err := PointFromECDSAPub(&ecdsaPriv.PublicKey)
+	require.NoError(t, err)
+
+	curve := Secp256k1()
+	require.True(t, curve.ScalarBaseMul(privKey).Equals(pubkey))
+	require.Equal(t, priv.PubKey().SerializeCompressed(), pubkey.Encode())
+
+	// the converted keys can be used to sign
+	keyring, err := NewKeyRingFromPublicKeys(curve, []Point{curve.ScalarBaseMul(curve.NewRandomScalar())}, privKey, 1)
+	require.NoError(t, err)
+	require.True(t, keyring.pubkeys[1].Equals(pubkey))
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	require.True(t, sig.Verify(testMsg))
+}
+
+func TestScalarFromECDSA_Invalid(t *testing.T) {
+	_, err := ScalarFromECDSA(nil)
+	require.Error(t, err)
+
+	p256Priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, err = ScalarFromECDSA(p256Priv)
+	require.EqualError(t, err, "private key is not on secp256k1")
+	_, err = PointFromECDSAPub(&p256Priv.PublicKey)
+	require.EqualError(t, err, "public key is not on secp256k1")
+
+	curve := dsecp256k1.S256()
+	for _, d := range []*big.Int{big.NewInt(0), big.NewInt(-1), curve.Params().N} {
+		_, err = ScalarFromECDSA(&ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{Curve: curve},
+			D:         d,
+		})
+		require.EqualError(t, err, "private key out of range")
+	}
+}
+
+func TestPointFromECDSAPub_NotOnCurve(t *testing.T) {
+	_, err := PointFromECDSAPub(&ecdsa.PublicKey{
+		Curve: dsecp256k1.S256(),
+		X:     big.NewInt(1),
+		Y:     big.NewInt(1),
+	})
+	require.Error(t, err)
+
+	_, err = PointFromECDSAPub(&ecdsa.PublicKey{Curve: dsecp256k1.S256()})
+	require.Error(t, err)
+}
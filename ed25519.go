@@ -0,0 +1,67 @@
+package ring
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"errors"
+
+	"filippo.io/edwards25519"
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// ScalarFromEd25519Seed converts a standard RFC 8032 ed25519 private key seed,
+// as returned by crypto/ed25519's PrivateKey.Seed, into a private key that can
+// be used with the Ed25519 curve.
+//
+// The seed is expanded with SHA-512 and the lower half is clamped, as in RFC
+// 8032 section 5.1.5, so the public key of the returned scalar is the same as
+// the RFC 8032 public key for the seed.
+func ScalarFromEd25519Seed(seed []byte) (types.Scalar, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, errors.New("invalid ed25519 seed length")
+	}
+
+	h := sha512.Sum512(seed)
+	s, err := new(edwards25519.Scalar).SetBytesWithClamping(h[:32])
+	if err != nil {
+		return nil, err
+	}
+
+	// the clamped value is reduced mod l, which doesn't change the public key
+	// as the base point has order l
+	return Ed25519().DecodeToScalar(s.Bytes())
+}
+
+// ScalarFromEd25519PrivateKey converts a standard crypto/ed25519 private key
+// into a private key that can be used with the Ed25519 curve.
+//
+// It returns an error if the public key embedded in the private key does not
+// match the public key derived from its seed.
+func ScalarFromEd25519PrivateKey(priv ed25519.PrivateKey) (types.Scalar, error) {
+	if len(priv) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid ed25519 private key length")
+	}
+
+	privKey, err := ScalarFromEd25519Seed(priv.Seed())
+	if err != nil {
+		return nil, err
+	}
+
+	pub := Ed25519().ScalarBaseMul(privKey).Encode()
+	if !bytes.Equal(pub, priv[ed25519.SeedSize:]) {
+		return nil, errors.New("derived public key does not match ed25519 public key")
+	}
+
+	return privKey, nil
+}
+
+// PointFromEd25519PublicKey converts a standard crypto/ed25519 public key into
+// a public key that can be used in an Ed25519 ring.
+func PointFromEd25519PublicKey(pub ed25519.PublicKey) (types.Point, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid ed25519 public key length")
+	}
+
+	return Ed25519().DecodeToPoint(pub)
+}
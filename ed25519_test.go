@@ -0,0 +1,55 @@
+package ring
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScalarFromEd25519Seed(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privKey, err := ScalarFromEd25519Seed(priv.Seed())
+	require.NoError(t, err)
+
+	pubkey, err := PointFromEd25519PublicKey(pub)
+	require.NoError(t, err)
+
+	curve := Ed25519()
+	require.True(t, curve.ScalarBaseMul(privKey).Equals(pubkey))
+
+	fromPriv, err := ScalarFromEd25519PrivateKey(priv)
+	require.NoError(t, err)
+	require.True(t, fromPriv.Eq(privKey))
+
+	// the converted keys can be used to sign
+	keyring, err := NewKeyRingFromPublicKeys(curve, []Point{curve.ScalarBaseMul(curve.NewRandomScalar())}, privKey, 0)
+	require.NoError(t, err)
+	require.True(t, keyring.pubkeys[0].Equals(pubkey))
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	require.True(t, sig.Verify(testMsg))
+}
+
+func TestScalarFromEd25519Seed_Invalid(t *testing.T) {
+	_, err := ScalarFromEd25519Seed(make([]byte, 31))
+	require.Error(t, err)
+
+	_, err = ScalarFromEd25519PrivateKey(make([]byte, 32))
+	require.Error(t, err)
+
+	_, err = PointFromEd25519PublicKey(make([]byte, 31))
+	require.Error(t, err)
+
+	// a private key with a mismatched public key is rejected
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	copy(priv[ed25519.SeedSize:], otherPub)
+	_, err = ScalarFromEd25519PrivateKey(priv)
+	require.EqualError(t, err, "derived public key does not match ed25519 public key")
+}
@@ -0,0 +1,369 @@
+package ring
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	"github.com/athanorlabs/go-dleq/ed25519"
+	"github.com/athanorlabs/go-dleq/types"
+	"golang.org/x/crypto/sha3"
+)
+
+// Ed25519Impl names an implementation of the ed25519 curve, so that it can be
+// selected at runtime, eg. from configuration.
+type Ed25519Impl string
+
+const (
+	// Ed25519ImplGoDLEQ is the go-dleq implementation returned by Ed25519.
+	Ed25519ImplGoDLEQ Ed25519Impl = "go-dleq"
+	// Ed25519ImplEdwards25519 is an implementation operating directly on
+	// filippo.io/edwards25519 points and scalars.
+	Ed25519ImplEdwards25519 Ed25519Impl = "edwards25519"
+)
+
+// NewEd25519 returns an ed25519 curve using the given implementation. Both
+// implementations produce the same encodings, so signatures created with one
+// can be deserialized and verified with the other.
+func NewEd25519(impl Ed25519Impl) (types.Curve, error) {
+	switch impl {
+	case Ed25519ImplGoDLEQ:
+		return Ed25519(), nil
+	case Ed25519ImplEdwards25519:
+		return newEdwards25519Backend(), nil
+	default:
+		return nil, fmt.Errorf("unknown ed25519 implementation %q", impl)
+	}
+}
+
+// edwards25519Backend is the ed25519 curve implemented directly on
+// filippo.io/edwards25519. Points are kept in extended coordinates, and base
+// point multiplications use the package's precomputed tables.
+//
+// Its points and scalars are separate types from go-dleq's. Points and scalars
+// of the go-dleq ed25519 curve (eg. from ScalarFromEd25519Seed) are accepted
+// as arguments and converted through their encoding, but go-dleq's methods
+// don't accept the values of this curve.
+type edwards25519Backend struct {
+	altBasePoint *edPoint
+}
+
+func newEdwards25519Backend() *edwards25519Backend {
+	// go-dleq's alternate base point, so that DLEQ proofs are compatible
+	alt, err := new(edwards25519.Point).SetBytes(ed25519.NewCurve().AltBasePoint().Encode())
+	if err != nil {
+		panic(err)
+	}
+
+	return &edwards25519Backend{
+		altBasePoint: &edPoint{inner: *alt},
+	}
+}
+
+func (*edwards25519Backend) ScalarSize() int {
+	return 32
+}
+
+func (*edwards25519Backend) Capabilities() Capabilities {
+	return CapConstantTime | CapEncodeInto | CapMultiScalarMul | CapDoubleBaseMul
+}
+
+func (*edwards25519Backend) EncodeInto(dst []byte, p types.Point) []byte {
+	return append(dst, toEdPoint(p).inner.Bytes()...)
+}
+
+// MultiScalarMul uses edwards25519's variable-time multi-scalar
+// multiplication, which shares the point doublings across the terms.
+func (*edwards25519Backend) MultiScalarMul(scalars []types.Scalar, points []types.Point) types.Point {
+	edScalars := make([]*edwards25519.Scalar, len(scalars))
+	edPoints := make([]*edwards25519.Point, len(points))
+	for i := range scalars {
+		edScalars[i] = &toEdScalar(scalars[i]).inner
+		edPoints[i] = &toEdPoint(points[i]).inner
+	}
+	out := new(edPoint)
+	out.inner.VarTimeMultiScalarMult(edScalars, edPoints)
+	return out
+}
+
+// DoubleScalarBaseMul computes a*P + b*G in a single pass, interleaving the
+// two multiplications so that they share their point doublings, with a
+// precomputed table for the base point. It runs in variable time.
+func (*edwards25519Backend) DoubleScalarBaseMul(a types.Scalar, p types.Point, b types.Scalar) types.Point {
+	out := new(edPoint)
+	out.inner.VarTimeDoubleScalarBaseMult(&toEdScalar(a).inner, &toEdPoint(p).inner, &toEdScalar(b).inner)
+	return out
+}
+
+func (*edwards25519Backend) BitSize() uint64 {
+	return 252
+}
+
+func (*edwards25519Backend) CompressedPointSize() int {
+	return 32
+}
+
+func (*edwards25519Backend) BasePoint() types.Point {
+	return &edPoint{inner: *edwards25519.NewGeneratorPoint()}
+}
+
+func (c *edwards25519Backend) AltBasePoint() types.Point {
+	return c.altBasePoint.Copy()
+}
+
+func (*edwards25519Backend) NewRandomScalar() types.Scalar {
+	var b [64]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+
+	s := new(edScalar)
+	if _, err := s.inner.SetUniformBytes(b[:]); err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+func (c *edwards25519Backend) ScalarFromInt(in uint32) types.Scalar {
+	var b [32]byte
+	binary.LittleEndian.PutUint32(b[:4], in)
+	return c.ScalarFromBytes(b)
+}
+
+func (*edwards25519Backend) ScalarFromBytes(b [32]byte) types.Scalar {
+	s := new(edScalar)
+	if _, err := s.inner.SetCanonicalBytes(b[:]); err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+func (*edwards25519Backend) HashToScalar(in []byte) (types.Scalar, error) {
+	h := sha3.Sum512(in)
+	s := new(edScalar)
+	if _, err := s.inner.SetUniformBytes(h[:]); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (*edwards25519Backend) ScalarBaseMul(s types.Scalar) types.Point {
+	p := new(edPoint)
+	p.inner.ScalarBaseMult(&toEdScalar(s).inner)
+	return p
+}
+
+func (*edwards25519Backend) ScalarMul(s types.Scalar, p types.Point) types.Point {
+	return toEdPoint(p).ScalarMul(s)
+}
+
+// signNoncePrefixDomain separates the nonce prefix of Sign from other hashes
+// of the private key.
+const signNoncePrefixDomain = "ring-go/edwards25519/sign-nonce-prefix"
+
+// Sign creates a Schnorr signature of the encoding of p, in the same format as
+// go-dleq's ed25519 curve. Unlike go-dleq's, whose nonce depends only on the
+// private key, so that two signatures of different points reveal the key, the
+// nonce is derived as in RFC 8032: r = H(prefix || A || p), with prefix a
+// secret derived from the private key, separately from it.
+func (*edwards25519Backend) Sign(s types.Scalar, p types.Point) ([]byte, error) {
+	x := &toEdScalar(s).inner
+	A := new(edwards25519.Point).ScalarBaseMult(x)
+
+	prefix := sha512.Sum512(append([]byte(signNoncePrefixDomain), x.Bytes()...))
+	nonce := sha512.New()
+	_, _ = nonce.Write(prefix[32:])
+	_, _ = nonce.Write(A.Bytes())
+	_, _ = nonce.Write(p.Encode())
+	r, err := edwards25519.NewScalar().SetUniformBytes(nonce.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	R := new(edwards25519.Point).ScalarBaseMult(r)
+
+	hram := sha512.Sum512(append(append(R.Bytes(), A.Bytes()...), p.Encode()...))
+	ch, err := edwards25519.NewScalar().SetUniformBytes(hram[:])
+	if err != nil {
+		return nil, err
+	}
+
+	sigS := edwards25519.NewScalar().MultiplyAdd(ch, x, r)
+	return append(R.Bytes(), sigS.Bytes()...), nil
+}
+
+// Verify verifies a signature created by Sign.
+func (*edwards25519Backend) Verify(pubkey, msgPoint types.Point, sig []byte) bool {
+	if len(sig) != 64 {
+		return false
+	}
+
+	A := &toEdPoint(pubkey).inner
+	hram := sha512.Sum512(append(append(sig[:32:32], A.Bytes()...), msgPoint.Encode()...))
+	ch, err := edwards25519.NewScalar().SetUniformBytes(hram[:])
+	if err != nil {
+		return false
+	}
+
+	R, err := new(edwards25519.Point).SetBytes(sig[:32])
+	if err != nil {
+		return false
+	}
+
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(sig[32:])
+	if err != nil {
+		return false
+	}
+
+	res := new(edwards25519.Point).VarTimeDoubleScalarBaseMult(edwards25519.NewScalar().Negate(ch), A, s)
+	return res.Equal(R) == 1
+}
+
+func (*edwards25519Backend) DecodeToPoint(in []byte) (types.Point, error) {
+	p := new(edPoint)
+	if _, err := p.inner.SetBytes(in); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (*edwards25519Backend) DecodeToScalar(in []byte) (types.Scalar, error) {
+	if len(in) != 32 {
+		return nil, errors.New("invalid scalar length")
+	}
+
+	s := new(edScalar)
+	if _, err := s.inner.SetCanonicalBytes(in); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// edScalar is a scalar of edwards25519Backend.
+type edScalar struct {
+	inner edwards25519.Scalar
+}
+
+// toEdScalar returns s as an *edScalar, converting go-dleq ed25519 scalars.
+func toEdScalar(s types.Scalar) *edScalar {
+	switch s := s.(type) {
+	case *edScalar:
+		return s
+	case *ed25519.ScalarImpl:
+		out := new(edScalar)
+		if _, err := out.inner.SetCanonicalBytes(s.Encode()); err != nil {
+			panic(err)
+		}
+		return out
+	default:
+		panic("invalid scalar; type is not an ed25519 scalar")
+	}
+}
+
+func (s *edScalar) Add(b types.Scalar) types.Scalar {
+	out := new(edScalar)
+	out.inner.Add(&s.inner, &toEdScalar(b).inner)
+	return out
+}
+
+func (s *edScalar) Sub(b types.Scalar) types.Scalar {
+	out := new(edScalar)
+	out.inner.Subtract(&s.inner, &toEdScalar(b).inner)
+	return out
+}
+
+func (s *edScalar) Negate() types.Scalar {
+	out := new(edScalar)
+	out.inner.Negate(&s.inner)
+	return out
+}
+
+func (s *edScalar) Mul(b types.Scalar) types.Scalar {
+	out := new(edScalar)
+	out.inner.Multiply(&s.inner, &toEdScalar(b).inner)
+	return out
+}
+
+func (s *edScalar) Inverse() types.Scalar {
+	out := new(edScalar)
+	out.inner.Invert(&s.inner)
+	return out
+}
+
+func (s *edScalar) Encode() []byte {
+	return s.inner.Bytes()
+}
+
+func (s *edScalar) Eq(b types.Scalar) bool {
+	return s.inner.Equal(&toEdScalar(b).inner) == 1
+}
+
+func (s *edScalar) IsZero() bool {
+	return s.inner.Equal(edwards25519.NewScalar()) == 1
+}
+
+// edPoint is a point of edwards25519Backend. Unlike go-dleq's ed25519 points,
+// IsZero reports whether the point is the identity.
+type edPoint struct {
+	inner edwards25519.Point
+}
+
+// toEdPoint returns p as an *edPoint, converting go-dleq ed25519 points.
+func toEdPoint(p types.Point) *edPoint {
+	switch p := p.(type) {
+	case *edPoint:
+		return p
+	case *ed25519.PointImpl:
+		out := new(edPoint)
+		if _, err := out.inner.SetBytes(encodePoint(p)); err != nil {
+			panic(err)
+		}
+		return out
+	default:
+		panic("invalid point; type is not an ed25519 point")
+	}
+}
+
+func (p *edPoint) Copy() types.Point {
+	out := new(edPoint)
+	out.inner.Set(&p.inner)
+	return out
+}
+
+func (p *edPoint) Add(b types.Point) types.Point {
+	out := new(edPoint)
+	out.inner.Add(&p.inner, &toEdPoint(b).inner)
+	return out
+}
+
+func (p *edPoint) Sub(b types.Point) types.Point {
+	out := new(edPoint)
+	out.inner.Subtract(&p.inner, &toEdPoint(b).inner)
+	return out
+}
+
+func (p *edPoint) ScalarMul(s types.Scalar) types.Point {
+	out := new(edPoint)
+	out.inner.ScalarMult(&toEdScalar(s).inner, &p.inner)
+	return out
+}
+
+func (p *edPoint) Encode() []byte {
+	return p.inner.Bytes()
+}
+
+func (p *edPoint) IsZero() bool {
+	return p.inner.Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+func (p *edPoint) Equals(other types.Point) bool {
+	return p.inner.Equal(&toEdPoint(other).inner) == 1
+}
@@ -0,0 +1,216 @@
+package ring
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newEdwards25519(t *testing.T) types.Curve {
+	curve, err := NewEd25519(Ed25519ImplEdwards25519)
+	require.NoError(t, err)
+	return curve
+}
+
+func TestNewEd25519(t *testing.T) {
+	curve, err := NewEd25519(Ed25519ImplGoDLEQ)
+	require.NoError(t, err)
+	require.IsType(t, &ed25519Backend{}, curve)
+
+	curve = newEdwards25519(t)
+	require.Equal(t, CurveEd25519, CurveIDOf(curve))
+	_, ok := curve.(CurveBackend)
+	require.True(t, ok)
+
+	_, err = NewEd25519("nope")
+	require.Error(t, err)
+}
+
+func TestEdwards25519_MultiScalarMul(t *testing.T) {
+	curve := newEdwards25519(t)
+	backend := curve.(MultiScalarMulBackend)
+	for _, n := range []int{1, 2, 5} {
+		scalars := make([]types.Scalar, n)
+		points := make([]types.Point, n)
+		expected := curve.ScalarBaseMul(curve.ScalarFromInt(0))
+		for i := range scalars {
+			scalars[i] = curve.NewRandomScalar()
+			points[i] = curve.ScalarBaseMul(curve.NewRandomScalar())
+			expected = expected.Add(curve.ScalarMul(scalars[i], points[i]))
+		}
+		require.True(t, backend.MultiScalarMul(scalars, points).Equals(expected))
+	}
+
+	p := curve.ScalarBaseMul(curve.NewRandomScalar())
+	require.Equal(t, append([]byte("prefix"), p.Encode()...), curve.(EncodeIntoBackend).EncodeInto([]byte("prefix"), p))
+}
+
+func TestEdwards25519_SignAndVerify(t *testing.T) {
+	curve := newEdwards25519(t)
+	for _, size := range []int{2, 3, 16} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, size, privKey, size/2)
+		require.NoError(t, err)
+
+		sig, err := keyring.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		require.True(t, sig.Verify(testMsg))
+		require.False(t, sig.Verify([32]byte{1}))
+	}
+}
+
+func TestEdwards25519_Interop(t *testing.T) {
+	dleq := Ed25519()
+	edwards := newEdwards25519(t)
+
+	// sign with one implementation, verify with the other
+	for _, pair := range [][2]types.Curve{{dleq, edwards}, {edwards, dleq}} {
+		signer, verifier := pair[0], pair[1]
+
+		privKey := signer.NewRandomScalar()
+		keyring, err := NewKeyRing(signer, 8, privKey, 3)
+		require.NoError(t, err)
+		sig, err := keyring.Sign(testMsg, privKey)
+		require.NoError(t, err)
+
+		enc, err := sig.Serialize()
+		require.NoError(t, err)
+		decoded := new(RingSig)
+		require.NoError(t, decoded.Deserialize(verifier, enc))
+		require.True(t, decoded.Verify(testMsg))
+
+		// the key image, and hence linkability, doesn't depend on the implementation
+		privKey2, err := verifier.DecodeToScalar(privKey.Encode())
+		require.NoError(t, err)
+		sig2, err := decoded.Ring().Sign(testMsg, privKey2)
+		require.NoError(t, err)
+		require.Equal(t, sig.KeyImage().Encode(), sig2.KeyImage().Encode())
+		require.True(t, Link(decoded, sig2))
+	}
+}
+
+func TestEdwards25519_AcceptsGoDLEQValues(t *testing.T) {
+	curve := newEdwards25519(t)
+	privKey := Ed25519().NewRandomScalar()
+	pubkey := Ed25519().ScalarBaseMul(privKey)
+
+	require.True(t, curve.ScalarBaseMul(privKey).Equals(pubkey))
+	require.True(t, curve.BasePoint().Add(pubkey).Sub(curve.BasePoint()).Equals(pubkey))
+
+	keyring, err := NewKeyRingFromPublicKeys(curve, []types.Point{
+		curve.ScalarBaseMul(curve.NewRandomScalar()),
+		curve.ScalarBaseMul(privKey),
+	}, privKey, 1)
+	require.NoError(t, err)
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	require.True(t, sig.Verify(testMsg))
+}
+
+func TestEdwards25519_Operations(t *testing.T) {
+	dleq := Ed25519()
+	edwards := newEdwards25519(t)
+
+	a, b := dleq.NewRandomScalar(), dleq.NewRandomScalar()
+	ea, err := edwards.DecodeToScalar(a.Encode())
+	require.NoError(t, err)
+	eb, err := edwards.DecodeToScalar(b.Encode())
+	require.NoError(t, err)
+
+	require.Equal(t, a.Add(b).Encode(), ea.Add(eb).Encode())
+	require.Equal(t, a.Sub(b).Encode(), ea.Sub(eb).Encode())
+	require.Equal(t, a.Mul(b).Encode(), ea.Mul(eb).Encode())
+	require.Equal(t, a.Negate().Encode(), ea.Negate().Encode())
+	require.Equal(t, a.Inverse().Encode(), ea.Inverse().Encode())
+	require.True(t, ea.Eq(a))
+	require.True(t, ea.Sub(eb.Sub(eb)).Eq(ea))
+	require.True(t, ea.Sub(ea).IsZero())
+	require.False(t, ea.IsZero())
+
+	require.Equal(t, dleq.ScalarFromInt(1234).Encode(), edwards.ScalarFromInt(1234).Encode())
+	h1, err := dleq.HashToScalar([]byte("hello"))
+	require.NoError(t, err)
+	h2, err := edwards.HashToScalar([]byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, h1.Encode(), h2.Encode())
+
+	require.Equal(t, dleq.BasePoint().Encode(), edwards.BasePoint().Encode())
+	require.Equal(t, dleq.AltBasePoint().Encode(), edwards.AltBasePoint().Encode())
+
+	p, q := dleq.ScalarBaseMul(a), dleq.ScalarBaseMul(b)
+	ep, eq := edwards.ScalarBaseMul(ea), edwards.ScalarBaseMul(eb)
+	require.Equal(t, p.Encode(), ep.Encode())
+	require.Equal(t, p.Add(q).Encode(), ep.Add(eq).Encode())
+	require.Equal(t, p.Sub(q).Encode(), ep.Sub(eq).Encode())
+	require.Equal(t, p.ScalarMul(b).Encode(), ep.ScalarMul(eb).Encode())
+	require.Equal(t, dleq.ScalarMul(b, p).Encode(), edwards.ScalarMul(eb, ep).Encode())
+	require.True(t, ep.Sub(ep).IsZero())
+	require.False(t, ep.IsZero())
+
+	decoded, err := edwards.DecodeToPoint(p.Encode())
+	require.NoError(t, err)
+	require.True(t, decoded.Equals(ep))
+	_, err = edwards.DecodeToScalar(make([]byte, 31))
+	require.Error(t, err)
+}
+
+func TestEdwards25519_SchnorrInterop(t *testing.T) {
+	dleq := Ed25519()
+	edwards := newEdwards25519(t)
+
+	priv := dleq.NewRandomScalar()
+	msgPoint := dleq.ScalarBaseMul(dleq.NewRandomScalar())
+	ePriv, err := edwards.DecodeToScalar(priv.Encode())
+	require.NoError(t, err)
+	eMsgPoint, err := edwards.DecodeToPoint(msgPoint.Encode())
+	require.NoError(t, err)
+
+	sig, err := dleq.Sign(priv, msgPoint)
+	require.NoError(t, err)
+	eSig, err := edwards.Sign(ePriv, eMsgPoint)
+	require.NoError(t, err)
+
+	// the nonces differ, but each implementation verifies the other's
+	// signatures
+	require.True(t, dleq.Verify(dleq.ScalarBaseMul(priv), msgPoint, eSig))
+	require.True(t, edwards.Verify(edwards.ScalarBaseMul(ePriv), eMsgPoint, sig))
+	require.True(t, edwards.Verify(edwards.ScalarBaseMul(ePriv), eMsgPoint, eSig))
+	require.False(t, edwards.Verify(edwards.ScalarBaseMul(ePriv), edwards.BasePoint(), sig))
+	require.False(t, edwards.Verify(edwards.ScalarBaseMul(ePriv), eMsgPoint, sig[:63]))
+}
+
+func TestEdwards25519_SignNonce(t *testing.T) {
+	edwards := newEdwards25519(t)
+	priv := edwards.NewRandomScalar()
+	pub := edwards.ScalarBaseMul(priv)
+	p1 := edwards.ScalarBaseMul(edwards.NewRandomScalar())
+	p2 := edwards.ScalarBaseMul(edwards.NewRandomScalar())
+
+	sig1, err := edwards.Sign(priv, p1)
+	require.NoError(t, err)
+	sig2, err := edwards.Sign(priv, p2)
+	require.NoError(t, err)
+	require.True(t, edwards.Verify(pub, p1, sig1))
+	require.True(t, edwards.Verify(pub, p2, sig2))
+
+	// reusing the nonce across points would reveal the private key
+	require.NotEqual(t, sig1[:32], sig2[:32])
+
+	// signing is deterministic
+	again, err := edwards.Sign(priv, p1)
+	require.NoError(t, err)
+	require.Equal(t, sig1, again)
+}
+
+func TestScalarMul_Edwards25519(t *testing.T) {
+	curve := newEdwards25519(t)
+	ref := newRefEd25519()
+	order, _ := new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	testScalarMulAgainstReference(t, curve, ref, order, func(s types.Scalar) *big.Int {
+		var be [32]byte
+		copy(be[:], scalarBytes(curve, [32]byte(s.Encode())))
+		return new(big.Int).SetBytes(be[:])
+	})
+}
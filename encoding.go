@@ -0,0 +1,180 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	"filippo.io/edwards25519/field"
+	"github.com/athanorlabs/go-dleq/ed25519"
+	"github.com/athanorlabs/go-dleq/secp256k1"
+	"github.com/athanorlabs/go-dleq/types"
+	dsecp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// PointEncoding is an encoding of the points (key image and public keys) of a
+// serialized signature. Uncompressed encodings make signatures larger, but
+// faster to decode, as decompressing a point requires a square root.
+type PointEncoding byte
+
+const (
+	// PointCompressed is the default encoding: 33-byte SEC1 compressed points
+	// on secp256k1, and 32-byte points on ed25519.
+	PointCompressed PointEncoding = iota
+	// PointUncompressed encodes points with both coordinates: 65-byte SEC1
+	// uncompressed points on secp256k1, and the 32-byte little-endian x and y
+	// coordinates on ed25519.
+	PointUncompressed
+	// PointHybrid encodes secp256k1 points in the 65-byte SEC1 hybrid format,
+	// which is the uncompressed format with the parity of y in the prefix. It's
+	// not supported on ed25519.
+	PointHybrid
+)
+
+// The point encoding is stored in the top two bits of the header's flags.
+// Unlike the extension flags, it's not part of the signed transcript, so a
+// signature can be re-encoded without invalidating it.
+const (
+	encodingShift = 6
+	encodingMask  = 0x3 << encodingShift
+)
+
+// splitFlags splits the top byte of a signature header into the point encoding
+// and the extension flags, and checks that both are supported.
+func splitFlags(b byte) (PointEncoding, byte, error) {
+	enc := PointEncoding(b >> encodingShift)
+	flags := b &^ encodingMask
+	if enc > PointHybrid {
+		return 0, 0, errors.New("unsupported point encoding")
+	}
+	if flags&^knownFlags != 0 {
+		return 0, 0, errors.New("unsupported format flags")
+	}
+	return enc, flags, nil
+}
+
+// String returns the encoding's name, eg. "compressed".
+func (e PointEncoding) String() string {
+	switch e {
+	case PointCompressed:
+		return "compressed"
+	case PointUncompressed:
+		return "uncompressed"
+	case PointHybrid:
+		return "hybrid"
+	default:
+		return fmt.Sprintf("PointEncoding(%d)", byte(e))
+	}
+}
+
+// pointLen returns the length of points on the curve in the encoding.
+func (e PointEncoding) pointLen(curve types.Curve) (int, error) {
+	if e == PointCompressed {
+		return curve.CompressedPointSize(), nil
+	}
+
+	switch CurveIDOf(curve) {
+	case CurveSecp256k1:
+		if e == PointUncompressed || e == PointHybrid {
+			return dsecp256k1.PubKeyBytesLenUncompressed, nil
+		}
+	case CurveEd25519:
+		if e == PointUncompressed {
+			return 64, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%s point encoding is not supported on this curve", e)
+}
+
+// appendPoint appends the encoding of p to out.
+func (e PointEncoding) appendPoint(out []byte, curve types.Curve, p types.Point) ([]byte, error) {
+	compressed := encodePoint(p)
+	if e == PointCompressed {
+		return append(out, compressed...), nil
+	}
+
+	switch CurveIDOf(curve) {
+	case CurveSecp256k1:
+		pk, err := dsecp256k1.ParsePubKey(compressed)
+		if err != nil {
+			return nil, err
+		}
+		enc := pk.SerializeUncompressed()
+		if e == PointHybrid {
+			enc[0] = dsecp256k1.PubKeyFormatHybridEven | compressed[0]&1
+		}
+		return append(out, enc...), nil
+	case CurveEd25519:
+		point, err := new(edwards25519.Point).SetBytes(compressed)
+		if err != nil {
+			return nil, err
+		}
+		X, Y, Z, _ := point.ExtendedCoordinates()
+		zInv := new(field.Element).Invert(Z)
+		out = append(out, new(field.Element).Multiply(X, zInv).Bytes()...)
+		return append(out, new(field.Element).Multiply(Y, zInv).Bytes()...), nil
+	}
+
+	return nil, fmt.Errorf("%s point encoding is not supported on this curve", e)
+}
+
+// decodePoint decodes a point in the encoding.
+func (e PointEncoding) decodePoint(curve types.Curve, in []byte) (types.Point, error) {
+	if e == PointCompressed {
+		return curve.DecodeToPoint(in)
+	}
+
+	switch CurveIDOf(curve) {
+	case CurveSecp256k1:
+		format := in[0]
+		if e == PointUncompressed && format != dsecp256k1.PubKeyFormatUncompressed ||
+			e == PointHybrid && format != dsecp256k1.PubKeyFormatHybridEven && format != dsecp256k1.PubKeyFormatHybridOdd {
+			return nil, fmt.Errorf("invalid %s point format %#x", e, format)
+		}
+
+		// ParsePubKey checks that the point is on the curve
+		pk, err := dsecp256k1.ParsePubKey(in)
+		if err != nil {
+			return nil, err
+		}
+		var j dsecp256k1.JacobianPoint
+		pk.AsJacobian(&j)
+		return secp256k1.NewPointFromCoordinates(j.X, j.Y), nil
+	case CurveEd25519:
+		x, err := decodeFieldElement(in[:32])
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeFieldElement(in[32:])
+		if err != nil {
+			return nil, err
+		}
+
+		// SetExtendedCoordinates checks that the point is on the curve
+		point, err := new(edwards25519.Point).SetExtendedCoordinates(x, y,
+			new(field.Element).One(), new(field.Element).Multiply(x, y))
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := curve.(*edwards25519Backend); ok {
+			return &edPoint{inner: *point}, nil
+		}
+		return ed25519.NewPoint(point), nil
+	}
+
+	return nil, fmt.Errorf("%s point encoding is not supported on this curve", e)
+}
+
+// decodeFieldElement decodes a canonical little-endian field element.
+func decodeFieldElement(in []byte) (*field.Element, error) {
+	fe, err := new(field.Element).SetBytes(in)
+	if err != nil {
+		return nil, err
+	}
+	if string(fe.Bytes()) != string(in) {
+		return nil, errors.New("non-canonical field element")
+	}
+	return fe, nil
+}
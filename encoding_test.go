@@ -0,0 +1,100 @@
+package ring
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerializeWith_PointEncodings(t *testing.T) {
+	ed, err := NewEd25519(Ed25519ImplEdwards25519)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		curve    Curve
+		encoding PointEncoding
+		pointLen int
+	}{
+		{"secp256k1 compressed", Secp256k1(), PointCompressed, 33},
+		{"secp256k1 uncompressed", Secp256k1(), PointUncompressed, 65},
+		{"secp256k1 hybrid", Secp256k1(), PointHybrid, 65},
+		{"ed25519 compressed", Ed25519(), PointCompressed, 32},
+		{"ed25519 uncompressed", Ed25519(), PointUncompressed, 64},
+		{"edwards25519 uncompressed", ed, PointUncompressed, 64},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			const size = 5
+			sig := createSigWithCurve(t, tc.curve, size, 2)
+			enc, err := sig.SerializeWith(WithPointEncoding(tc.encoding))
+			require.NoError(t, err)
+			require.Len(t, enc, 4+32+tc.pointLen+size*(32+tc.pointLen))
+			require.Equal(t, byte(tc.encoding), enc[0]>>encodingShift)
+
+			res := new(RingSig)
+			require.NoError(t, res.Deserialize(tc.curve, enc))
+			require.True(t, res.Equal(sig))
+			require.True(t, res.Verify(testMsg))
+			require.NoError(t, VerifyStream(tc.curve, testMsg, bytes.NewReader(enc), nil))
+
+			// re-encoding doesn't invalidate the signature
+			compressed, err := res.Serialize()
+			require.NoError(t, err)
+			expected, err := sig.Serialize()
+			require.NoError(t, err)
+			require.Equal(t, expected, compressed)
+		})
+	}
+}
+
+func TestSerializeWith_Validity(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 3, privKey, 1)
+	require.NoError(t, err)
+	sig, err := keyring.Sign(testMsg, privKey, WithValidity(time.Time{}, time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+
+	enc, err := sig.SerializeWith(WithPointEncoding(PointHybrid))
+	require.NoError(t, err)
+	require.Equal(t, byte(PointHybrid)<<encodingShift|flagValidity, enc[0])
+
+	res := new(RingSig)
+	require.NoError(t, res.Deserialize(curve, enc))
+	require.True(t, res.Verify(testMsg))
+}
+
+func TestSerializeWith_Unsupported(t *testing.T) {
+	sig := createSigWithCurve(t, Ed25519(), 2, 0)
+	_, err := sig.SerializeWith(WithPointEncoding(PointHybrid))
+	require.EqualError(t, err, "hybrid point encoding is not supported on this curve")
+
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+	enc[0] = 0xc0
+	require.EqualError(t, new(RingSig).Deserialize(Ed25519(), enc), "unsupported point encoding")
+}
+
+func TestDeserialize_InvalidPointEncoding(t *testing.T) {
+	curve := Secp256k1()
+	sig := createSigWithCurve(t, curve, 2, 0)
+
+	// the prefix must match the encoding in the header
+	enc, err := sig.SerializeWith(WithPointEncoding(PointUncompressed))
+	require.NoError(t, err)
+	enc[4+32] = 0x06
+	require.Error(t, new(RingSig).Deserialize(curve, enc))
+
+	// points must be on the curve
+	enc, err = sig.SerializeWith(WithPointEncoding(PointUncompressed))
+	require.NoError(t, err)
+	enc[4+32+64] ^= 1
+	require.Error(t, new(RingSig).Deserialize(curve, enc))
+
+	enc, err = createSigWithCurve(t, Ed25519(), 2, 0).SerializeWith(WithPointEncoding(PointUncompressed))
+	require.NoError(t, err)
+	enc[4+32+63] ^= 1
+	require.Error(t, new(RingSig).Deserialize(Ed25519(), enc))
+}
@@ -0,0 +1,63 @@
+package ring
+
+import (
+	"bytes"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	ringHashDomain    = "ring-go/ring"
+	ringSigHashDomain = "ring-go/ringsig"
+)
+
+// Equal returns true if both signatures have the same challenge, responses,
+// key image and ring, in the same order. The values are compared by their
+// encodings, so the result does not depend on the curve implementation.
+func (r *RingSig) Equal(other *RingSig) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+
+	if !sameCurve(r.ring.curve, other.ring.curve) {
+		return false
+	}
+
+	return bytes.Equal(r.encode(), other.encode())
+}
+
+// Hash returns a digest of the signature's canonical encoding, including its
+// curve, which makes it usable as a map key, eg. for deduplication. Equal
+// signatures have the same hash. The curve is identified by its base point
+// only, while Equal also compares the alternate base points, so signatures
+// over curves with the same base point but different alternate base points
+// have the same hash without being Equal. Over the curves of this package,
+// whose base points have a single alternate base point each, two signatures
+// have the same hash if and only if they are Equal.
+func (r *RingSig) Hash() [32]byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte(ringSigHashDomain))
+	_, _ = h.Write(encodePoint(r.ring.curve.BasePoint()))
+	_, _ = h.Write(r.encode())
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// Hash returns a digest of the ring's curve, identified by its base point, and
+// public keys. As with Equals, the order of the public keys matters. Like
+// RingSig.Hash, it doesn't tell apart curves with the same base point but
+// different alternate base points, which Equals does.
+func (r *Ring) Hash() [32]byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte(ringHashDomain))
+	_, _ = h.Write(encodePoint(r.curve.BasePoint()))
+	for _, pk := range r.pubkeys {
+		_, _ = h.Write(encodePoint(pk))
+	}
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
@@ -0,0 +1,88 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingSig_Equal(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		sig := createSigWithCurve(t, curve, 5, 2)
+		require.True(t, sig.Equal(sig))
+
+		enc, err := sig.Serialize()
+		require.NoError(t, err)
+		res := new(RingSig)
+		require.NoError(t, res.Deserialize(curve, enc))
+		require.True(t, sig.Equal(res))
+		require.True(t, res.Equal(sig))
+		require.Equal(t, sig.Hash(), res.Hash())
+
+		res.s[0] = curve.NewRandomScalar()
+		require.False(t, sig.Equal(res))
+		require.NotEqual(t, sig.Hash(), res.Hash())
+
+		require.False(t, sig.Equal(nil))
+	}
+}
+
+func TestRingSig_Equal_DifferentCurves(t *testing.T) {
+	sigA := createSigWithCurve(t, Secp256k1(), 2, 0)
+	sigB := createSigWithCurve(t, Ed25519(), 2, 0)
+	require.False(t, sigA.Equal(sigB))
+	require.NotEqual(t, sigA.Hash(), sigB.Hash())
+}
+
+func TestRingSig_Hash_MapKey(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 3)
+	require.NoError(t, err)
+
+	seen := make(map[[32]byte]struct{})
+	for i := 0; i < 3; i++ {
+		sig, err := keyring.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		seen[sig.Hash()] = struct{}{}
+		seen[sig.Hash()] = struct{}{}
+	}
+	require.Len(t, seen, 3)
+}
+
+func TestRing_Hash(t *testing.T) {
+	curve := Secp256k1()
+	keyring, err := NewKeyRing(curve, 4, curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+
+	same, err := NewFixedKeyRingFromPublicKeys(Secp256k1(), keyring.pubkeys)
+	require.NoError(t, err)
+	require.Equal(t, keyring.Hash(), same.Hash())
+
+	reordered := append(keyring.pubkeys[1:4:4], keyring.pubkeys[0])
+	other, err := NewFixedKeyRingFromPublicKeys(curve, reordered)
+	require.NoError(t, err)
+	require.NotEqual(t, keyring.Hash(), other.Hash())
+}
+
+// altCurve is a curve with the same base point as another, but a different
+// alternate base point.
+type altCurve struct {
+	Curve
+	alt Point
+}
+
+func (c *altCurve) AltBasePoint() Point { return c.alt }
+
+func TestRing_Hash_AltBasePoint(t *testing.T) {
+	// as documented, the hash identifies the curve by its base point only
+	curve := Ed25519()
+	alt := &altCurve{Curve: curve, alt: curve.ScalarBaseMul(curve.ScalarFromInt(2))}
+	keyring, err := NewKeyRing(curve, 3, curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+	other, err := NewFixedKeyRingFromPublicKeys(alt, keyring.PublicKeys())
+	require.NoError(t, err)
+
+	require.False(t, keyring.Equals(other))
+	require.Equal(t, keyring.Hash(), other.Hash())
+}
@@ -0,0 +1,187 @@
+// Package ethring builds secp256k1 rings from Ethereum addresses, the only
+// identifiers most dapps have for their users, and verifies ring signatures
+// against address lists:
+//
+//	keyring, err := ethring.NewRing(addrs, pubkeys)
+//	sig, err := keyring.Sign(msgHash, privKey)
+//	...
+//	err = ethring.Verify(sig, msgHash, addrs)
+//
+// Addresses can't be turned back into public keys, so the signer must know
+// the public key of every member, eg. recovered from one of their
+// transactions with RecoverPublicKey. The signature carries these public keys
+// in its ring, and Verify checks that each one hashes to the corresponding
+// address, so verifiers only need the address list.
+package ethring
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	dsecp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	decdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// ErrAddressMismatch is returned by Verify when a signature's ring doesn't
+// hold the public keys of the expected addresses.
+var ErrAddressMismatch = errors.New("ring does not match the addresses")
+
+// Address is a 20-byte Ethereum address.
+type Address [20]byte
+
+// ParseAddress parses a hex-encoded address, with or without the 0x prefix.
+// If the address has mixed case, its EIP-55 checksum is checked.
+func ParseAddress(s string) (Address, error) {
+	var a Address
+	hexAddr := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(hexAddr) != 2*len(a) {
+		return a, fmt.Errorf("invalid address length %d", len(hexAddr))
+	}
+	if _, err := hex.Decode(a[:], []byte(hexAddr)); err != nil {
+		return a, fmt.Errorf("invalid address: %w", err)
+	}
+
+	if hexAddr != strings.ToLower(hexAddr) && hexAddr != strings.ToUpper(hexAddr) &&
+		hexAddr != a.String()[2:] {
+		return a, errors.New("invalid address checksum")
+	}
+	return a, nil
+}
+
+// String returns the address in its EIP-55 checksummed hex encoding, with the
+// 0x prefix.
+func (a Address) String() string {
+	lower := hex.EncodeToString(a[:])
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write([]byte(lower))
+	digest := h.Sum(nil)
+
+	out := []byte(lower)
+	for i, c := range out {
+		// letters whose nibble in the digest is at least 8 are uppercase
+		nibble := digest[i/2] >> (4 * (1 - i%2)) & 0xf
+		if c >= 'a' && nibble >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+// AddressOf returns the address of a secp256k1 public key: the last 20 bytes
+// of the Keccak-256 digest of its uncompressed encoding, without the prefix.
+func AddressOf(pubkey ring.Point) (Address, error) {
+	if ring.CurveIDOfPoint(pubkey) != ring.CurveSecp256k1 {
+		return Address{}, errors.New("public key is not on secp256k1")
+	}
+
+	// the go-dleq encoding is compressed; parsing it checks the point
+	pk, err := dsecp256k1.ParsePubKey(pubkey.Copy().Encode())
+	if err != nil {
+		return Address{}, err
+	}
+
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write(pk.SerializeUncompressed()[1:])
+	return Address(h.Sum(nil)[12:]), nil
+}
+
+// RecoverPublicKey recovers the public key of the signer of a 32-byte
+// message hash from its 65-byte Ethereum signature r || s || v, where v is 0,
+// 1, 27 or 28. It also verifies the signature.
+func RecoverPublicKey(hash, sig []byte) (ring.Point, error) {
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("hash must be 32 bytes, got %d", len(hash))
+	}
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("signature must be 65 bytes, got %d", len(sig))
+	}
+
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return nil, fmt.Errorf("invalid recovery ID %d", sig[64])
+	}
+
+	// decred's compact format is the recovery code followed by r and s
+	compact := make([]byte, 65)
+	compact[0] = 27 + v
+	copy(compact[1:], sig[:64])
+
+	pk, _, err := decdsa.RecoverCompact(compact, hash)
+	if err != nil {
+		return nil, err
+	}
+	return ring.Secp256k1().DecodeToPoint(pk.SerializeCompressed())
+}
+
+// NewRing returns the ring of the given addresses, in order. pubkeys holds
+// the public keys of all the addresses, in any order; keys of other
+// addresses are ignored.
+func NewRing(addrs []Address, pubkeys []ring.Point) (*ring.Ring, error) {
+	byAddress := make(map[Address]ring.Point, len(pubkeys))
+	for _, pk := range pubkeys {
+		addr, err := AddressOf(pk)
+		if err != nil {
+			return nil, err
+		}
+		byAddress[addr] = pk
+	}
+
+	members := make([]ring.Point, len(addrs))
+	for i, addr := range addrs {
+		pk, ok := byAddress[addr]
+		if !ok {
+			return nil, fmt.Errorf("no public key for address %s", addr)
+		}
+		members[i] = pk
+	}
+
+	return ring.NewFixedKeyRingFromPublicKeys(ring.Secp256k1(), members)
+}
+
+// Addresses returns the addresses of the members of a secp256k1 ring, in
+// order.
+func Addresses(r *ring.Ring) ([]Address, error) {
+	if ring.CurveIDOf(r.Curve()) != ring.CurveSecp256k1 {
+		return nil, errors.New("ring is not over secp256k1")
+	}
+
+	pubkeys := r.PublicKeys()
+	addrs := make([]Address, len(pubkeys))
+	for i, pk := range pubkeys {
+		var err error
+		if addrs[i], err = AddressOf(pk); err != nil {
+			return nil, err
+		}
+	}
+	return addrs, nil
+}
+
+// Verify verifies the signature for the message, and checks that its ring
+// holds the public keys of the given addresses, in order. It returns
+// ErrAddressMismatch if it doesn't, and otherwise the result of
+// RingSig.VerifyWithPolicy.
+func Verify(sig *ring.RingSig, m [32]byte, addrs []Address) error {
+	if sig.Ring().Size() != len(addrs) {
+		return ErrAddressMismatch
+	}
+
+	got, err := Addresses(sig.Ring())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAddressMismatch, err)
+	}
+	for i := range addrs {
+		if got[i] != addrs[i] {
+			return fmt.Errorf("%w: member %d is %s, want %s", ErrAddressMismatch, i, got[i], addrs[i])
+		}
+	}
+
+	return sig.VerifyWithPolicy(m, nil)
+}
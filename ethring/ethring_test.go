@@ -0,0 +1,128 @@
+package ethring
+
+import (
+	"encoding/hex"
+	"testing"
+
+	dsecp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	decdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+func TestAddress_EIP55(t *testing.T) {
+	for _, s := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		a, err := ParseAddress(s)
+		require.NoError(t, err)
+		require.Equal(t, s, a.String())
+
+		// all-lowercase addresses have no checksum
+		_, err = ParseAddress("0x" + hex.EncodeToString(a[:]))
+		require.NoError(t, err)
+	}
+
+	_, err := ParseAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD")
+	require.Error(t, err)
+	_, err = ParseAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA")
+	require.Error(t, err)
+	_, err = ParseAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg")
+	require.Error(t, err)
+}
+
+func TestAddressOf(t *testing.T) {
+	// from the web3.js documentation
+	priv, err := ring.Secp256k1().DecodeToScalar(mustHex(t, "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"))
+	require.NoError(t, err)
+	addr, err := AddressOf(ring.Secp256k1().ScalarBaseMul(priv))
+	require.NoError(t, err)
+	require.Equal(t, "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23", addr.String())
+
+	_, err = AddressOf(ring.Ed25519().ScalarBaseMul(ring.Ed25519().NewRandomScalar()))
+	require.Error(t, err)
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+// ethSign signs the hash like an Ethereum wallet, returning r || s || v.
+func ethSign(t *testing.T, priv ring.Scalar, hash []byte) []byte {
+	compact := decdsa.SignCompact(dsecp256k1.PrivKeyFromBytes(priv.Encode()), hash, false)
+	return append(compact[1:], compact[0]-27)
+}
+
+func TestRecoverPublicKey(t *testing.T) {
+	curve := ring.Secp256k1()
+	priv := curve.NewRandomScalar()
+	hash := sha3.Sum256([]byte("transaction"))
+
+	sig := ethSign(t, priv, hash[:])
+	pk, err := RecoverPublicKey(hash[:], sig)
+	require.NoError(t, err)
+	require.True(t, pk.Equals(curve.ScalarBaseMul(priv)))
+
+	sig[64] += 27
+	pk, err = RecoverPublicKey(hash[:], sig)
+	require.NoError(t, err)
+	require.True(t, pk.Equals(curve.ScalarBaseMul(priv)))
+
+	sig[64] = 2
+	_, err = RecoverPublicKey(hash[:], sig)
+	require.Error(t, err)
+	_, err = RecoverPublicKey(hash[:31], sig)
+	require.Error(t, err)
+	_, err = RecoverPublicKey(hash[:], sig[:64])
+	require.Error(t, err)
+}
+
+func TestRing(t *testing.T) {
+	curve := ring.Secp256k1()
+	privKeys := make([]ring.Scalar, 4)
+	pubkeys := make([]ring.Point, len(privKeys))
+	addrs := make([]Address, len(privKeys))
+	hash := sha3.Sum256([]byte("transaction"))
+	for i := range privKeys {
+		privKeys[i] = curve.NewRandomScalar()
+		// the public keys are recovered from transaction signatures
+		var err error
+		pubkeys[i], err = RecoverPublicKey(hash[:], ethSign(t, privKeys[i], hash[:]))
+		require.NoError(t, err)
+		addrs[i], err = AddressOf(pubkeys[i])
+		require.NoError(t, err)
+	}
+
+	// the keys can be given in any order
+	keyring, err := NewRing(addrs, []ring.Point{pubkeys[3], pubkeys[1], pubkeys[0], pubkeys[2]})
+	require.NoError(t, err)
+	got, err := Addresses(keyring)
+	require.NoError(t, err)
+	require.Equal(t, addrs, got)
+
+	msg := sha3.Sum256([]byte("signed by one of four accounts"))
+	sig, err := keyring.Sign(msg, privKeys[2])
+	require.NoError(t, err)
+
+	// verifiers only need the addresses
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+	res := new(ring.RingSig)
+	require.NoError(t, res.Deserialize(curve, enc))
+	require.NoError(t, Verify(res, msg, addrs))
+	require.ErrorIs(t, Verify(res, [32]byte{}, addrs), ring.ErrInvalidSignature)
+
+	swapped := []Address{addrs[1], addrs[0], addrs[2], addrs[3]}
+	require.ErrorIs(t, Verify(res, msg, swapped), ErrAddressMismatch)
+	require.ErrorIs(t, Verify(res, msg, addrs[:3]), ErrAddressMismatch)
+
+	_, err = NewRing(addrs, pubkeys[:3])
+	require.Error(t, err)
+}
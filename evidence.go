@@ -0,0 +1,150 @@
+package ring
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+const (
+	// evidenceVersion is the version of the encoding of Evidence.
+	evidenceVersion = 1
+
+	// MaxEvidenceMetadata is the maximum length of Evidence.Metadata.
+	MaxEvidenceMetadata = 0xffff
+)
+
+// ErrInvalidEvidence is returned by VerifyEvidence for evidence that doesn't
+// prove double-signing.
+var ErrInvalidEvidence = errors.New("invalid double-signing evidence")
+
+// Evidence is a Conflict submitted to a consensus layer, which adjudicates it
+// with VerifyEvidence, eg. to slash the signer's stake.
+type Evidence struct {
+	Conflict
+
+	// Height is the application-defined height at which the conflict was
+	// detected, eg. a block height.
+	Height uint64
+	// Metadata is opaque application data, eg. the reporter's address, of at
+	// most MaxEvidenceMetadata bytes. Like Height, it isn't checked by
+	// VerifyEvidence.
+	Metadata []byte
+}
+
+// NewEvidence returns the evidence of the conflict, detected at the given
+// height.
+func NewEvidence(c Conflict, height uint64, metadata []byte) *Evidence {
+	return &Evidence{
+		Conflict: c,
+		Height:   height,
+		Metadata: bytes.Clone(metadata),
+	}
+}
+
+// KeyImage returns the normalized key image of the double-signer (see
+// NormalizeKeyImage), eg. to look up its stake. The evidence must have been
+// verified with VerifyEvidence.
+func (ev *Evidence) KeyImage() types.Point {
+	return NormalizeKeyImage(ev.A.image)
+}
+
+// VerifyEvidence checks that the evidence proves double-signing on the curve:
+// that both signatures are over the curve and valid for their messages, that
+// the messages differ, and that the signatures link, ie. have the same
+// normalized key image. It returns nil if the evidence is valid, and an error
+// wrapping ErrInvalidEvidence otherwise.
+//
+// Like VerifyEncoded, it depends only on its arguments, so that all the nodes
+// of a consensus layer reach the same result: the signatures' validity
+// windows aren't checked, as signing outside of them is double-signing all
+// the same, and key images with a small-order component are rejected, as by
+// Verify.
+func VerifyEvidence(curve types.Curve, ev *Evidence) (err error) {
+	defer recoverInternal(&err)
+
+	switch {
+	case ev == nil || ev.A == nil || ev.B == nil || ev.A.ring == nil || ev.B.ring == nil:
+		return fmt.Errorf("%w: missing signature", ErrInvalidEvidence)
+	case !sameCurve(curve, ev.A.ring.curve) || !sameCurve(curve, ev.B.ring.curve):
+		return fmt.Errorf("%w: signature over another curve", ErrInvalidEvidence)
+	case ev.MsgA == ev.MsgB:
+		return fmt.Errorf("%w: same message", ErrInvalidEvidence)
+	case len(ev.Metadata) > MaxEvidenceMetadata:
+		return fmt.Errorf("%w: metadata too long", ErrInvalidEvidence)
+	}
+
+	for i, sig := range []*RingSig{ev.A, ev.B} {
+		m := ev.MsgA
+		if i == 1 {
+			m = ev.MsgB
+		}
+		if err := sig.verifyTranscript(m, nil, nil, RequireTorsionFree); err != nil {
+			return fmt.Errorf("%w: signature %c: %w", ErrInvalidEvidence, 'A'+i, err)
+		}
+	}
+
+	if !Link(ev.A, ev.B) {
+		return fmt.Errorf("%w: signatures don't link", ErrInvalidEvidence)
+	}
+	return nil
+}
+
+// Serialize encodes the evidence as a version byte, the curve ID (see
+// CurveIDOf), the height as a big-endian uint64, the length of the metadata
+// as a big-endian uint16, the metadata, and the serialized conflict.
+func (ev *Evidence) Serialize() ([]byte, error) {
+	if ev.A == nil || ev.A.ring == nil {
+		return nil, errors.New("conflict signature is nil")
+	}
+	id := CurveIDOf(ev.A.ring.curve)
+	if id == CurveUnknown {
+		return nil, errors.New("unsupported curve")
+	}
+	if len(ev.Metadata) > MaxEvidenceMetadata {
+		return nil, errors.New("metadata too long")
+	}
+
+	out := []byte{evidenceVersion, byte(id)}
+	out = binary.BigEndian.AppendUint64(out, ev.Height)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(ev.Metadata)))
+	out = append(out, ev.Metadata...)
+
+	conflict, err := ev.Conflict.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return append(out, conflict...), nil
+}
+
+// Deserialize decodes evidence encoded with Serialize. It doesn't verify it;
+// see VerifyEvidence.
+func (ev *Evidence) Deserialize(in []byte) error {
+	r := bytes.NewBuffer(in)
+	if r.Len() < 2+8+2 {
+		return errors.New("input too short")
+	}
+	if v := r.Next(1)[0]; v != evidenceVersion {
+		return fmt.Errorf("unsupported evidence version %d", v)
+	}
+	curve, err := CurveID(r.Next(1)[0]).Curve()
+	if err != nil {
+		return err
+	}
+
+	res := Evidence{Height: binary.BigEndian.Uint64(r.Next(8))}
+	n := int(binary.BigEndian.Uint16(r.Next(2)))
+	if r.Len() < n {
+		return errors.New("input too short")
+	}
+	res.Metadata = bytes.Clone(r.Next(n))
+	if err := res.Conflict.Deserialize(curve, r.Bytes()); err != nil {
+		return err
+	}
+
+	*ev = res
+	return nil
+}
@@ -0,0 +1,109 @@
+package ring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testEvidence returns evidence of double-signing over the curve.
+func testEvidence(t *testing.T, curve Curve) *Evidence {
+	t.Helper()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 5, privKey, 2)
+	require.NoError(t, err)
+	a, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	b, err := keyring.Sign([32]byte{2}, privKey)
+	require.NoError(t, err)
+
+	conflicts, err := DetectConflicts([]*RingSig{a, b}, [][32]byte{testMsg, {2}})
+	require.NoError(t, err)
+	require.Len(t, conflicts, 1)
+	return NewEvidence(conflicts[0], 42, []byte("reporter"))
+}
+
+func TestVerifyEvidence(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		ev := testEvidence(t, curve)
+		require.NoError(t, VerifyEvidence(curve, ev))
+		require.True(t, ev.KeyImage().Equals(NormalizeKeyImage(ev.B.KeyImage())))
+
+		enc, err := ev.Serialize()
+		require.NoError(t, err)
+		decoded := new(Evidence)
+		require.NoError(t, decoded.Deserialize(enc))
+		require.Equal(t, uint64(42), decoded.Height)
+		require.Equal(t, []byte("reporter"), decoded.Metadata)
+		require.NoError(t, VerifyEvidence(curve, decoded))
+
+		reenc, err := decoded.Serialize()
+		require.NoError(t, err)
+		require.Equal(t, enc, reenc)
+	}
+}
+
+func TestVerifyEvidence_Invalid(t *testing.T) {
+	curve := Secp256k1()
+	ev := testEvidence(t, curve)
+
+	// a signature by another signer, over testMsg
+	other := createSigWithCurve(t, curve, 5, 1)
+
+	for name, tc := range map[string]struct {
+		ev  *Evidence
+		err string
+	}{
+		"nil":           {nil, "missing signature"},
+		"missing":       {&Evidence{Conflict: Conflict{A: ev.A}}, "missing signature"},
+		"same message":  {&Evidence{Conflict: Conflict{A: ev.A, B: ev.B, MsgA: ev.MsgA, MsgB: ev.MsgA}}, "same message"},
+		"wrong message": {&Evidence{Conflict: Conflict{A: ev.A, B: ev.B, MsgA: ev.MsgA, MsgB: [32]byte{3}}}, "signature B: invalid ring signature"},
+		"swapped":       {&Evidence{Conflict: Conflict{A: ev.B, B: ev.A, MsgA: ev.MsgA, MsgB: ev.MsgB}}, "signature A: invalid ring signature"},
+		"unlinked":      {&Evidence{Conflict: Conflict{A: other, B: ev.B, MsgA: testMsg, MsgB: ev.MsgB}}, "signatures don't link"},
+		"long metadata": {&Evidence{Conflict: ev.Conflict, Metadata: make([]byte, MaxEvidenceMetadata+1)}, "metadata too long"},
+		"another curve": {testEvidence(t, Ed25519()), "signature over another curve"},
+		"mixed curves":  {&Evidence{Conflict: Conflict{A: ev.A, B: testEvidence(t, Ed25519()).B, MsgA: ev.MsgA, MsgB: ev.MsgB}}, "signature over another curve"},
+	} {
+		err := VerifyEvidence(curve, tc.ev)
+		require.ErrorIs(t, err, ErrInvalidEvidence, name)
+		require.ErrorContains(t, err, tc.err, name)
+	}
+}
+
+func TestVerifyEvidence_Validity(t *testing.T) {
+	// signatures whose validity window has passed are still evidence
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 3, privKey, 0)
+	require.NoError(t, err)
+	expired := time.Now().Add(-time.Hour)
+	a, err := keyring.Sign(testMsg, privKey, WithValidity(time.Time{}, expired))
+	require.NoError(t, err)
+	b, err := keyring.Sign([32]byte{2}, privKey)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, a.VerifyWithPolicy(testMsg, nil), ErrNotValidAt)
+	ev := NewEvidence(Conflict{A: a, B: b, MsgA: testMsg, MsgB: [32]byte{2}}, 0, nil)
+	require.NoError(t, VerifyEvidence(curve, ev))
+}
+
+func TestEvidence_Deserialize_Invalid(t *testing.T) {
+	ev := testEvidence(t, Ed25519())
+	enc, err := ev.Serialize()
+	require.NoError(t, err)
+
+	for i := 0; i < len(enc); i++ {
+		require.Error(t, new(Evidence).Deserialize(enc[:i]))
+	}
+	require.Error(t, new(Evidence).Deserialize(append(enc, 0)))
+
+	bad := append([]byte{}, enc...)
+	bad[0] = 2
+	require.EqualError(t, new(Evidence).Deserialize(bad), "unsupported evidence version 2")
+	bad[0], bad[1] = 1, 0x7f
+	require.Error(t, new(Evidence).Deserialize(bad))
+
+	_, err = (&Evidence{Conflict: ev.Conflict, Metadata: make([]byte, MaxEvidenceMetadata+1)}).Serialize()
+	require.EqualError(t, err, "metadata too long")
+}
@@ -0,0 +1,123 @@
+package ring_test
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+func ExampleRing_Sign() {
+	curve := ring.Secp256k1()
+	privKey := curve.NewRandomScalar()
+	msgHash := sha3.Sum256([]byte("helloworld"))
+
+	// create a ring of 16 public keys, with ours at index 7
+	keyring, err := ring.NewKeyRing(curve, 16, privKey, 7)
+	if err != nil {
+		panic(err)
+	}
+
+	sig, err := keyring.Sign(msgHash, privKey)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(sig.Verify(msgHash))
+	// Output: true
+}
+
+func ExampleLink() {
+	curve := ring.Ed25519()
+	privKey := curve.NewRandomScalar()
+
+	sign := func(msg string) *ring.RingSig {
+		keyring, err := ring.NewKeyRing(curve, 4, privKey, 1)
+		if err != nil {
+			panic(err)
+		}
+
+		sig, err := keyring.Sign(sha3.Sum256([]byte(msg)), privKey)
+		if err != nil {
+			panic(err)
+		}
+		return sig
+	}
+
+	fmt.Println(ring.Link(sign("hello"), sign("world")))
+	// Output: true
+}
+
+func ExampleRingSig_Serialize() {
+	curve := ring.Ed25519()
+	privKey := curve.NewRandomScalar()
+	msgHash := sha3.Sum256([]byte("helloworld"))
+
+	keyring, err := ring.NewKeyRing(curve, 4, privKey, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	sig, err := keyring.Sign(msgHash, privKey)
+	if err != nil {
+		panic(err)
+	}
+
+	enc, err := sig.Serialize()
+	if err != nil {
+		panic(err)
+	}
+
+	res := new(ring.RingSig)
+	if err := res.Deserialize(curve, enc); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(len(enc), res.Verify(msgHash))
+	// Output: 324 true
+}
+
+func ExampleNewKeyRingFromPublicKeys() {
+	curve := ring.Secp256k1()
+	privKey := curve.NewRandomScalar()
+
+	// public keys, eg. decoded from their compressed or uncompressed encodings
+	// with curve.DecodeToPoint
+	pubkeys := make([]ring.Point, 3)
+	for i := range pubkeys {
+		pubkeys[i] = curve.ScalarBaseMul(curve.NewRandomScalar())
+	}
+
+	keyring, err := ring.NewKeyRingFromPublicKeys(curve, pubkeys, privKey, 2)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(keyring.Size())
+	// Output: 4
+}
+
+func ExampleBatchVerify() {
+	curve := ring.Ed25519()
+	privKey := curve.NewRandomScalar()
+
+	keyring, err := ring.NewKeyRing(curve, 64, privKey, 10)
+	if err != nil {
+		panic(err)
+	}
+	keyring.Precompute()
+
+	sigs := make([]*ring.RingSig, 4)
+	msgs := make([][32]byte, 4)
+	for i := range sigs {
+		msgs[i] = sha3.Sum256([]byte{byte(i)})
+		sigs[i], err = keyring.Sign(msgs[i], privKey)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	fmt.Println(ring.BatchVerify(sigs, msgs))
+	// Output: <nil>
+}
@@ -1,3 +1,5 @@
+// Command basic signs a message with a ring of randomly generated public keys
+// and verifies the resulting signature, on both supported curves.
 package main
 
 import (
@@ -0,0 +1,65 @@
+// Command ethereum builds a secp256k1 ring from Ethereum-style keys: a
+// hex-encoded private key, as exported by wallets, and uncompressed public
+// keys of other accounts.
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+const privKeyHex = "ae6ae8e5ccbfb04590405997ee2d52d2b330726137b875053c36d94e974d162f"
+
+var otherPubKeysHex = []string{
+	"04785a891f323acd6cef0fc509bb14304410595914267c50467e51c87142acbb5e12ab6cc10390ee6e31985e52e7d5701bed4c265dfc899cac07bc9c608ab02a74",
+	"04396c2c8a22ec28dbe02613027edea9a3b0c314294985e09c2f389818b29fee066a8384bb217f7fc35134d0e778ad8681b2d922ed630f86bea161d27f97536a3e",
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func main() {
+	curve := ring.Secp256k1()
+
+	// secp256k1 private keys are decoded as 32-byte big-endian integers
+	privKey, err := curve.DecodeToScalar(mustDecodeHex(privKeyHex))
+	if err != nil {
+		panic(err)
+	}
+
+	// public keys can be decoded from either their compressed (33 bytes) or
+	// uncompressed (65 bytes) encodings
+	pubkeys := make([]ring.Point, len(otherPubKeysHex))
+	for i, s := range otherPubKeysHex {
+		pubkeys[i], err = curve.DecodeToPoint(mustDecodeHex(s))
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	// place our public key at index 1 of the ring
+	keyring, err := ring.NewKeyRingFromPublicKeys(curve, pubkeys, privKey, 1)
+	if err != nil {
+		panic(err)
+	}
+
+	msgHash := sha3.Sum256([]byte("signed by one of three ethereum accounts"))
+	sig, err := keyring.Sign(msgHash, privKey)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("verified signature:", sig.Verify(msgHash))
+	for i, pk := range keyring.PublicKeys() {
+		fmt.Printf("ring member %d: %x\n", i, pk.Encode())
+	}
+}
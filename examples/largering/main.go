@@ -0,0 +1,52 @@
+// Command largering signs many messages with one large ring and verifies the
+// signatures as a batch.
+//
+// Hash-to-curve values for the ring's public keys are computed once and shared
+// by every signature over the same *Ring, so batching amortizes their cost.
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/sha3"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+func main() {
+	const (
+		size  = 1024
+		count = 8
+	)
+
+	curve := ring.Ed25519()
+	privKey := curve.NewRandomScalar()
+
+	keyring, err := ring.NewKeyRing(curve, size, privKey, size/3)
+	if err != nil {
+		panic(err)
+	}
+
+	start := time.Now()
+	keyring.Precompute()
+	fmt.Printf("precomputed %d-member ring in %s\n", size, time.Since(start))
+
+	sigs := make([]*ring.RingSig, count)
+	msgs := make([][32]byte, count)
+	start = time.Now()
+	for i := range sigs {
+		msgs[i] = sha3.Sum256([]byte(fmt.Sprintf("message %d", i)))
+		sigs[i], err = keyring.Sign(msgs[i], privKey)
+		if err != nil {
+			panic(err)
+		}
+	}
+	fmt.Printf("signed %d messages in %s\n", count, time.Since(start))
+
+	start = time.Now()
+	if err := ring.BatchVerify(sigs, msgs); err != nil {
+		panic(err)
+	}
+	fmt.Printf("verified %d signatures in %s\n", count, time.Since(start))
+}
@@ -0,0 +1,42 @@
+// Command linkability shows how Link detects two signatures created by the same
+// private key, even over different rings and messages.
+package main
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+func main() {
+	curve := ring.Ed25519()
+	alice := curve.NewRandomScalar()
+	bob := curve.NewRandomScalar()
+
+	sign := func(privKey ring.Scalar, size, idx int, msg string) *ring.RingSig {
+		keyring, err := ring.NewKeyRing(curve, size, privKey, idx)
+		if err != nil {
+			panic(err)
+		}
+
+		sig, err := keyring.Sign(sha3.Sum256([]byte(msg)), privKey)
+		if err != nil {
+			panic(err)
+		}
+		return sig
+	}
+
+	aliceSig1 := sign(alice, 8, 3, "first message")
+	aliceSig2 := sign(alice, 16, 11, "second message")
+	bobSig := sign(bob, 8, 0, "first message")
+
+	fmt.Println("alice's signatures linked:", ring.Link(aliceSig1, aliceSig2))
+	fmt.Println("alice's and bob's signatures linked:", ring.Link(aliceSig1, bobSig))
+
+	// the key image is what links signatures; registries tracking used key
+	// images should store their normalized encoding
+	image := ring.NormalizeKeyImage(aliceSig1.KeyImage())
+	fmt.Printf("alice's key image: %x\n", image.Encode())
+}
@@ -0,0 +1,44 @@
+// Command serialization serializes a ring signature to bytes, deserializes it
+// and verifies the result.
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+func main() {
+	curve := ring.Secp256k1()
+	privKey := curve.NewRandomScalar()
+	msgHash := sha3.Sum256([]byte("helloworld"))
+
+	keyring, err := ring.NewKeyRing(curve, 4, privKey, 2)
+	if err != nil {
+		panic(err)
+	}
+
+	sig, err := keyring.Sign(msgHash, privKey)
+	if err != nil {
+		panic(err)
+	}
+
+	enc, err := sig.Serialize()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("serialized signature (%d bytes): %s\n", len(enc), hex.EncodeToString(enc))
+
+	// the encoding doesn't include the curve, so it must be known by the
+	// receiver
+	res := new(ring.RingSig)
+	if err := res.Deserialize(curve, enc); err != nil {
+		panic(err)
+	}
+
+	fmt.Println("deserialized signature equal to original:", res.Equal(sig))
+	fmt.Println("deserialized signature verified:", res.Verify(msgHash))
+}
@@ -0,0 +1,148 @@
+package ring
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/athanorlabs/go-dleq/types"
+
+	"github.com/pokt-network/ring-go/internal/reedsolomon"
+)
+
+const (
+	fecVersion = 1
+
+	// fecHeaderLen is the length of the header: the version, the numbers of
+	// data and parity shards, and the length of the signature encoding.
+	fecHeaderLen = 1 + 1 + 1 + 4
+	// fecHeaderCopies is the number of copies of the header, which is
+	// recovered by a majority vote on each byte.
+	fecHeaderCopies = 3
+	// fecShardSize is the target size of the shards, as small shards make
+	// the corruption of a few bytes cost less parity.
+	fecShardSize   = 64
+	fecChecksumLen = crc32.Size
+)
+
+// ErrFECUnrecoverable is returned by DeserializeWithFEC when too much of the
+// input is corrupted to recover the signature.
+var ErrFECUnrecoverable = errors.New("too many corrupted shards to recover the signature")
+
+// SerializeWithFEC encodes the signature like Serialize, with Reed-Solomon
+// redundancy for storage or transmission over lossy media, eg. QR codes or
+// radio, where partial corruption shouldn't invalidate a large signature.
+//
+// The encoding is split into data shards of about 64 bytes (at most 255 shards
+// in total), extended with the given number of parity shards, and each shard
+// is followed by its CRC-32 checksum. DeserializeWithFEC recovers the
+// signature as long as at most parityShards shards are corrupted or missing
+// at the end of the input. The input must keep its length: corruption is
+// recovered, insertions and deletions aren't.
+func (sig *RingSig) SerializeWithFEC(parityShards int) ([]byte, error) {
+	if parityShards < 1 || parityShards >= reedsolomon.MaxShards-1 {
+		return nil, fmt.Errorf("invalid number of parity shards %d", parityShards)
+	}
+	enc, err := sig.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	dataShards := min((len(enc)+fecShardSize-1)/fecShardSize, reedsolomon.MaxShards-1-parityShards)
+	shardSize := (len(enc) + dataShards - 1) / dataShards
+	code, err := reedsolomon.New(dataShards, parityShards)
+	if err != nil {
+		return nil, err
+	}
+
+	padded := make([]byte, (dataShards+parityShards)*shardSize)
+	copy(padded, enc)
+	shards := make([][]byte, dataShards+parityShards)
+	for i := range shards {
+		shards[i] = padded[i*shardSize : (i+1)*shardSize]
+	}
+	if err := code.Encode(shards); err != nil {
+		return nil, err
+	}
+
+	header := []byte{fecVersion, byte(dataShards), byte(parityShards)}
+	header = binary.BigEndian.AppendUint32(header, uint32(len(enc)))
+	out := make([]byte, 0, fecHeaderCopies*fecHeaderLen+len(shards)*(shardSize+fecChecksumLen))
+	for i := 0; i < fecHeaderCopies; i++ {
+		out = append(out, header...)
+	}
+	for i, shard := range shards {
+		out = append(out, shard...)
+		out = binary.BigEndian.AppendUint32(out, fecChecksum(i, shard))
+	}
+	return out, nil
+}
+
+// DeserializeWithFEC decodes a signature over the given curve encoded with
+// SerializeWithFEC, recovering the shards whose checksum doesn't match. It
+// returns an error wrapping ErrFECUnrecoverable if too many shards are
+// corrupted.
+func (sig *RingSig) DeserializeWithFEC(curve types.Curve, in []byte) error {
+	if len(in) < fecHeaderCopies*fecHeaderLen {
+		return errors.New("input too short")
+	}
+
+	// majority vote on each byte of the header copies
+	header := make([]byte, fecHeaderLen)
+	for i := range header {
+		a, b, c := in[i], in[fecHeaderLen+i], in[2*fecHeaderLen+i]
+		switch {
+		case a == b || a == c:
+			header[i] = a
+		case b == c:
+			header[i] = b
+		default:
+			return fmt.Errorf("%w: corrupted header", ErrFECUnrecoverable)
+		}
+	}
+	if header[0] != fecVersion {
+		return fmt.Errorf("unsupported FEC version %d", header[0])
+	}
+	dataShards, parityShards := int(header[1]), int(header[2])
+	length := int(binary.BigEndian.Uint32(header[3:]))
+	code, err := reedsolomon.New(dataShards, parityShards)
+	if err != nil {
+		return err
+	}
+	shardSize := (length + dataShards - 1) / dataShards
+
+	body := in[fecHeaderCopies*fecHeaderLen:]
+	if len(body) > (dataShards+parityShards)*(shardSize+fecChecksumLen) {
+		return errors.New("input too long")
+	}
+	shards := make([][]byte, dataShards+parityShards)
+	for i := range shards {
+		start := i * (shardSize + fecChecksumLen)
+		if start+shardSize+fecChecksumLen > len(body) {
+			break
+		}
+		shard := body[start : start+shardSize]
+		if binary.BigEndian.Uint32(body[start+shardSize:]) == fecChecksum(i, shard) {
+			shards[i] = shard
+		}
+	}
+	if err := code.Reconstruct(shards); err != nil {
+		if errors.Is(err, reedsolomon.ErrTooFewShards) {
+			return fmt.Errorf("%w: %w", ErrFECUnrecoverable, err)
+		}
+		return err
+	}
+
+	enc := make([]byte, 0, dataShards*shardSize)
+	for _, shard := range shards[:dataShards] {
+		enc = append(enc, shard...)
+	}
+	return sig.Deserialize(curve, enc[:length])
+}
+
+// fecChecksum returns the checksum of the shard with the given index, which
+// is covered so that shards can't be swapped.
+func fecChecksum(index int, shard []byte) uint32 {
+	return crc32.Update(crc32.ChecksumIEEE([]byte{byte(index)}), crc32.IEEETable, shard)
+}
@@ -0,0 +1,64 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerializeWithFEC(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		sig := createSigWithCurve(t, curve, 16, 3)
+		full, err := sig.Serialize()
+		require.NoError(t, err)
+		const parity = 4
+		enc, err := sig.SerializeWithFEC(parity)
+		require.NoError(t, err)
+
+		decoded := new(RingSig)
+		require.NoError(t, decoded.DeserializeWithFEC(curve, enc))
+		require.True(t, decoded.Verify(testMsg))
+
+		// corrupt one of the header copies and a byte in as many shards as
+		// there are parity shards
+		corrupted := append([]byte(nil), enc...)
+		corrupted[1] ^= 0xff
+		shardLen := (len(enc) - fecHeaderCopies*fecHeaderLen) / (int(enc[1]) + parity)
+		for i := 0; i < parity; i++ {
+			corrupted[fecHeaderCopies*fecHeaderLen+i*3*shardLen+i] ^= 0x5a
+		}
+		decoded = new(RingSig)
+		require.NoError(t, decoded.DeserializeWithFEC(curve, corrupted))
+		got, err := decoded.Serialize()
+		require.NoError(t, err)
+		require.Equal(t, full, got)
+
+		// shards cut off the end are missing
+		decoded = new(RingSig)
+		require.NoError(t, decoded.DeserializeWithFEC(curve, enc[:len(enc)-parity*shardLen]))
+		require.True(t, decoded.Verify(testMsg))
+
+		corrupted[len(corrupted)-1] ^= 0x01
+		err = new(RingSig).DeserializeWithFEC(curve, corrupted)
+		require.ErrorIs(t, err, ErrFECUnrecoverable)
+	}
+}
+
+func TestSerializeWithFEC_Invalid(t *testing.T) {
+	sig := createSigWithCurve(t, Ed25519(), 4, 1)
+	for _, parity := range []int{-1, 0, 255} {
+		_, err := sig.SerializeWithFEC(parity)
+		require.Error(t, err)
+	}
+
+	enc, err := sig.SerializeWithFEC(2)
+	require.NoError(t, err)
+	require.Error(t, new(RingSig).DeserializeWithFEC(Ed25519(), enc[:fecHeaderCopies*fecHeaderLen-1]))
+	require.Error(t, new(RingSig).DeserializeWithFEC(Ed25519(), append(enc, 0)))
+
+	// all the header copies disagree
+	corrupted := append([]byte(nil), enc...)
+	corrupted[fecHeaderLen+1] ^= 0x01
+	corrupted[2*fecHeaderLen+1] ^= 0x02
+	require.ErrorIs(t, new(RingSig).DeserializeWithFEC(Ed25519(), corrupted), ErrFECUnrecoverable)
+}
@@ -0,0 +1,164 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"golang.org/x/crypto/sha3"
+)
+
+// The fuzz targets in this file are native Go fuzz targets, so they can be run
+// locally with `go test -fuzz=FuzzSignVerify` and are picked up as-is by
+// OSS-Fuzz's compile_native_go_fuzzer.
+
+const fuzzMaxRingSize = 16
+
+func fuzzCurve(useEd25519 bool) types.Curve {
+	if useEd25519 {
+		return Ed25519()
+	}
+	return Secp256k1()
+}
+
+// fuzzScalar deterministically derives a private key from the fuzzer input.
+func fuzzScalar(t *testing.T, curve types.Curve, seed []byte) types.Scalar {
+	priv, err := curve.HashToScalar(append([]byte("ring-go fuzz"), seed...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if priv.IsZero() {
+		t.Skip("zero private key")
+	}
+	return priv
+}
+
+func fuzzSig(t *testing.T, curve types.Curve, seed []byte, msg [32]byte, size, idx int) *RingSig {
+	priv := fuzzScalar(t, curve, seed)
+	keyring, err := NewKeyRing(curve, size, priv, idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := keyring.Sign(msg, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func FuzzSignVerify(f *testing.F) {
+	f.Add(false, []byte("seed"), []byte("helloworld"), uint8(2), uint8(0), uint16(0))
+	f.Add(true, []byte("seed"), []byte("helloworld"), uint8(5), uint8(3), uint16(77))
+	f.Add(false, []byte{}, []byte{}, uint8(15), uint8(200), uint16(1000))
+
+	f.Fuzz(func(t *testing.T, useEd25519 bool, seed, msg []byte, size, idx uint8, flip uint16) {
+		curve := fuzzCurve(useEd25519)
+		ringSize := 2 + int(size)%(fuzzMaxRingSize-1)
+		m := sha3.Sum256(msg)
+
+		sig := fuzzSig(t, curve, seed, m, ringSize, int(idx)%ringSize)
+		if !sig.Verify(m) {
+			t.Fatal("valid signature failed to verify")
+		}
+
+		// a signature must not verify for any other message
+		other := m
+		other[int(flip)%len(other)] ^= 0x01
+		if sig.Verify(other) {
+			t.Fatal("signature verified for a different message")
+		}
+
+		// flipping any single bit of the encoded signature must either make it
+		// undecodable or invalid
+		enc, err := sig.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mutated := make([]byte, len(enc))
+		copy(mutated, enc)
+		bit := int(flip) % (len(mutated) * 8)
+		mutated[bit/8] ^= 1 << (bit % 8)
+
+		res := new(RingSig)
+		if err := res.Deserialize(curve, mutated); err == nil && res.Verify(m) {
+			t.Fatalf("mutated signature verified (bit %d)", bit)
+		}
+	})
+}
+
+func FuzzDeserializeVerify(f *testing.F) {
+	for _, useEd25519 := range []bool{false, true} {
+		curve := fuzzCurve(useEd25519)
+		priv := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 3, priv, 1)
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		sig, err := keyring.Sign(testMsg, priv)
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		enc, err := sig.Serialize()
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		f.Add(useEd25519, enc)
+	}
+	f.Add(false, []byte{})
+	f.Add(true, []byte{0, 0, 0, 0})
+
+	f.Fuzz(func(t *testing.T, useEd25519 bool, in []byte) {
+		curve := fuzzCurve(useEd25519)
+
+		// arbitrary input must never panic, only fail to decode or verify
+		sig := new(RingSig)
+		if err := sig.Deserialize(curve, in); err != nil {
+			return
+		}
+		ok := sig.Verify(testMsg)
+
+		// whatever was decoded must survive a round-trip unchanged
+		enc, err := sig.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res := new(RingSig)
+		if err := res.Deserialize(curve, enc); err != nil {
+			t.Fatalf("failed to deserialize re-serialized signature: %s", err)
+		}
+		if res.Verify(testMsg) != ok {
+			t.Fatal("re-serialized signature verification result changed")
+		}
+	})
+}
+
+func FuzzLink(f *testing.F) {
+	f.Add(false, []byte("a"), []byte("b"), []byte("msg1"), []byte("msg2"))
+	f.Add(true, []byte("a"), []byte("a"), []byte("msg1"), []byte("msg2"))
+	f.Add(true, []byte("a"), []byte("b"), []byte{}, []byte{})
+
+	f.Fuzz(func(t *testing.T, useEd25519 bool, seedA, seedB, msgA, msgB []byte) {
+		curve := fuzzCurve(useEd25519)
+		sigA := fuzzSig(t, curve, seedA, sha3.Sum256(msgA), 2, 0)
+		sigB := fuzzSig(t, curve, seedB, sha3.Sum256(msgB), 3, 2)
+
+		if !Link(sigA, sigA) || !Link(sigB, sigB) {
+			t.Fatal("Link is not reflexive")
+		}
+
+		ab, ba := Link(sigA, sigB), Link(sigB, sigA)
+		if ab != ba {
+			t.Fatal("Link is not symmetric")
+		}
+
+		sameSigner := fuzzScalar(t, curve, seedA).Eq(fuzzScalar(t, curve, seedB))
+		if ab != sameSigner {
+			t.Fatalf("Link returned %v for signers that are equal: %v", ab, sameSigner)
+		}
+	})
+}
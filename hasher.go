@@ -0,0 +1,76 @@
+package ring
+
+import (
+	"crypto"
+	"encoding/binary"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const messageHasherDomain = "ring-go/message-hasher"
+
+// MessageHasher computes the message to sign for a payload written to it
+// incrementally, so that large payloads (files, batched request bodies) can
+// be signed without buffering them in memory:
+//
+//	h := signer.NewMessageHasher()
+//	if _, err := io.Copy(h, file); err != nil { ... }
+//	m := h.Sum()
+//	sig, err := keyring.Sign(m, privKey)
+//	...
+//	ok := sig.Verify(m)
+//
+// The message is a hash of the payload's digest together with the hash
+// function, so the hash function is bound into the signature: a signature
+// doesn't verify for the same payload hashed with another function, nor for
+// a payload whose digest under another function collides with this one.
+// Verifiers compute the message with a MessageHasher of the same function.
+type MessageHasher struct {
+	hash crypto.Hash
+	h    hash.Hash
+}
+
+// NewMessageHasher returns a MessageHasher using the given hash function,
+// which must be available.
+func NewMessageHasher(hash crypto.Hash) (*MessageHasher, error) {
+	if !hash.Available() {
+		return nil, fmt.Errorf("hash function %s is not available", hash)
+	}
+	return &MessageHasher{hash: hash, h: hash.New()}, nil
+}
+
+// NewMessageHasher returns a MessageHasher using SHA3-256, for messages to
+// sign with the signer.
+func (s *Signer) NewMessageHasher() *MessageHasher {
+	return &MessageHasher{hash: crypto.SHA3_256, h: sha3.New256()}
+}
+
+// Write adds p to the payload. It never returns an error.
+func (m *MessageHasher) Write(p []byte) (int, error) {
+	return m.h.Write(p)
+}
+
+// Hash returns the hash function used for the payload.
+func (m *MessageHasher) Hash() crypto.Hash {
+	return m.hash
+}
+
+// Sum returns the message to sign for the payload written so far. It doesn't
+// change the hasher's state, so more data can be written afterwards.
+func (m *MessageHasher) Sum() [32]byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte(messageHasherDomain))
+	_, _ = h.Write(binary.BigEndian.AppendUint32(nil, uint32(m.hash)))
+	_, _ = h.Write(m.h.Sum(nil))
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// Reset discards the payload written so far.
+func (m *MessageHasher) Reset() {
+	m.h.Reset()
+}
@@ -0,0 +1,66 @@
+package ring
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageHasher(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 1)
+	require.NoError(t, err)
+	signer, err := NewSigner(keyring, privKey)
+	require.NoError(t, err)
+
+	payload := make([]byte, 3<<20)
+	_, err = rand.Read(payload)
+	require.NoError(t, err)
+
+	h := signer.NewMessageHasher()
+	require.Equal(t, crypto.SHA3_256, h.Hash())
+	_, err = io.Copy(h, bytes.NewReader(payload))
+	require.NoError(t, err)
+	m := h.Sum()
+	require.Equal(t, m, h.Sum())
+
+	enc, err := signer.Sign(nil, m[:], nil)
+	require.NoError(t, err)
+	sig := new(RingSig)
+	require.NoError(t, sig.Deserialize(curve, enc))
+
+	// the verifier hashes the payload in chunks of another size
+	v, err := NewMessageHasher(crypto.SHA3_256)
+	require.NoError(t, err)
+	for chunk := payload; len(chunk) > 0; {
+		n := min(len(chunk), 1000)
+		_, _ = v.Write(chunk[:n])
+		chunk = chunk[n:]
+	}
+	require.True(t, sig.Verify(v.Sum()))
+
+	// the hash function is bound into the message
+	other, err := NewMessageHasher(crypto.SHA256)
+	require.NoError(t, err)
+	_, _ = other.Write(payload)
+	require.False(t, sig.Verify(other.Sum()))
+
+	// the message isn't the plain digest either
+	digest, err := NewDigest(crypto.SHA3_256, payload)
+	require.NoError(t, err)
+	require.False(t, sig.VerifyDigest(digest))
+
+	v.Reset()
+	_, _ = v.Write(payload[1:])
+	require.False(t, sig.Verify(v.Sum()))
+}
+
+func TestNewMessageHasher_Unavailable(t *testing.T) {
+	_, err := NewMessageHasher(crypto.MD4)
+	require.Error(t, err)
+}
@@ -12,15 +12,55 @@ import (
 	"golang.org/x/crypto/sha3"
 )
 
+// encodePoint returns the encoding of p without modifying p.
+//
+// The secp256k1 point implementation normalizes its internal representation
+// in place when a point is encoded or compared, which is a data race when the
+// point is shared between goroutines. Points that may be shared, such as ring
+// public keys, key images and curve base points, must therefore only be
+// encoded or compared through encodePoint and equalPoints, which operate on
+// copies. Scalar multiplication and addition only read their inputs.
+func encodePoint(p types.Point) []byte {
+	return p.Copy().Encode()
+}
+
+// equalPoints returns true if a and b are equal, without modifying either.
+// See encodePoint.
+func equalPoints(a, b types.Point) bool {
+	return a.Copy().Equals(b.Copy())
+}
+
+// isIdentity returns true if p is the identity element, without modifying p.
+// Point.IsZero can't be used for this, as the ed25519 implementation compares
+// against the point with an all-zero encoding rather than the identity.
+func isIdentity(p types.Point) bool {
+	q := p.Copy()
+	return q.Equals(q.Sub(q))
+}
+
+// HashToCurve returns H_p(P), the hash of the public key P to a point of its
+// curve, which is used to compute key images (I = x*H_p(P)). Rings compute and
+// cache these values themselves; this is for storing them alongside public
+// keys, eg. for VerifyStream. Unlike the package's other functions, it panics
+// if pk isn't a point of one of the package's curves, or of a curve registered
+// with RegisterCurve that implements HPProvider.
+func HashToCurve(pk types.Point) types.Point {
+	return hashToCurve(pk)
+}
+
 func hashToCurve(pk types.Point) types.Point {
 	switch k := pk.(type) {
 	case *ed25519.PointImpl:
 		return hashToCurveEd25519(k)
+	case *edPoint:
+		return &edPoint{inner: *hashToEdwards25519(k.Encode())}
 	case *secp256k1.PointImpl:
 		return hashToCurveSecp256k1(k)
-	default:
-		panic("unsupported point type")
 	}
+	if hp, ok := registeredHashToCurve(pk); ok {
+		return hp
+	}
+	panic("unsupported point type")
 }
 
 // hashToCurveEd25519 hashes a point and attempts to set the hash to a point.
@@ -28,16 +68,18 @@ func hashToCurve(pk types.Point) types.Point {
 // is the y-coordinate with the highest bit set for whether x is positive/negative.
 // It repeatedly hashes the hash until it finds a valid point.
 func hashToCurveEd25519(pk *ed25519.PointImpl) *ed25519.PointImpl {
+	return ed25519.NewPoint(hashToEdwards25519(encodePoint(pk)))
+}
+
+// hashToEdwards25519 implements hashToCurveEd25519 on an encoded point.
+func hashToEdwards25519(compressedKey []byte) *edwards25519.Point {
 	const safety = 128
-	compressedKey := pk.Encode()
 	hash := sha3.Sum256(compressedKey)
 
 	for i := 0; i < safety; i++ {
 		point, err := new(edwards25519.Point).SetBytes(hash[:])
 		if err == nil {
-			return ed25519.NewPoint(
-				new(edwards25519.Point).MultByCofactor(point),
-			)
+			return point.MultByCofactor(point)
 		}
 
 		hash = sha3.Sum256(hash[:])
@@ -116,7 +158,7 @@ func decompressYEd25519(x *field.Element) (*ed25519.PointImpl, error) { //nolint
 // based off https://github.com/particl/particl-core/blob/master/src/secp256k1/src/modules/mlsag/main_impl.h#L139
 func hashToCurveSecp256k1(pk *secp256k1.PointImpl) *secp256k1.PointImpl {
 	const safety = 128
-	compressedKey := pk.Encode()
+	compressedKey := encodePoint(pk)
 	hash := sha3.Sum256(compressedKey)
 	fe := &dsecp256k1.FieldVal{}
 	fe.SetBytes(&hash)
@@ -0,0 +1,251 @@
+package ring
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+const (
+	// hpChunkSize is the number of H_p(P_i) values computed at once when
+	// they are not cached on the ring.
+	hpChunkSize = 256
+
+	// hpMinPerWorker is the minimum number of H_p(P_i) values computed by a
+	// single goroutine, below which parallelizing isn't worth it.
+	hpMinPerWorker = 16
+)
+
+// hpCacheMaxSize is the size of the largest ring for which H_p(P_i) is cached
+// on the ring. For larger rings, the values are computed one chunk at a time
+// during signing and verification, so that memory usage stays bounded.
+// It's a variable so that tests can exercise the uncached path.
+var hpCacheMaxSize = 1 << 16
+
+// Precompute computes and caches H_p(P_i) for each public key in the ring,
+// which is otherwise done lazily by the first call to Sign or Verify.
+// It does nothing for rings larger than the cache limit (65536 members),
+// which compute the values on the fly instead.
+func (r *Ring) Precompute() {
+	r.ensureHP()
+}
+
+// PrecomputeAsync computes and caches H_p(P_i) for each public key in the ring
+// in the background, like Precompute, eg. for large rings loaded at service
+// boot, so that the first signatures don't pay for it. Sign, Verify and
+// Precompute don't compute the values again while they're pending; they wait
+// for them instead.
+//
+// The work is spread over up to half of GOMAXPROCS goroutines, and all the
+// background computations of the process share a limit of GOMAXPROCS
+// goroutines, so that loading many rings doesn't starve the rest of the
+// service. The returned channel receives nil once the values are cached, or
+// the context's error if it's canceled first, in which case the values are
+// computed by the next call that needs them.
+func (r *Ring) PrecomputeAsync(ctx context.Context) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- r.precomputeAsync(ctx)
+	}()
+	return done
+}
+
+func (r *Ring) precomputeAsync(ctx context.Context) error {
+	task := &hpTask{done: make(chan struct{})}
+	for {
+		if len(r.pubkeys) > hpCacheMaxSize || r.hpReady.Load() {
+			return nil
+		}
+		if r.hpPending.CompareAndSwap(nil, task) {
+			break
+		}
+
+		// wait for the pending computation; if it was canceled, start
+		// another one
+		if pending := r.hpPending.Load(); pending != nil {
+			select {
+			case <-pending.done:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	defer func() {
+		// waiters find no pending computation once they wake up
+		r.hpPending.Store(nil)
+		close(task.done)
+	}()
+
+	hp := make([]types.Point, len(r.pubkeys))
+	if err := computeHPAsync(ctx, r.pubkeys, hp); err != nil {
+		return err
+	}
+	r.setHP(hp)
+	return nil
+}
+
+// hpTask is a background computation of H_p(P_i) started by PrecomputeAsync.
+type hpTask struct {
+	// done is closed when the computation is over, whether it succeeded or
+	// was canceled.
+	done chan struct{}
+}
+
+// hpAsyncWorkers limits the number of goroutines of all the PrecomputeAsync
+// computations of the process.
+var hpAsyncWorkers = make(chan struct{}, runtime.GOMAXPROCS(0))
+
+// computeHPAsync sets out[i] = H_p(pubkeys[i]) like computeHP, with up to half
+// of GOMAXPROCS goroutines, each holding a slot of hpAsyncWorkers. It returns
+// the context's error if it's canceled before all the values are computed.
+func computeHPAsync(ctx context.Context, pubkeys, out []types.Point) error {
+	workers := max(min(runtime.GOMAXPROCS(0)/2, len(pubkeys)/hpMinPerWorker), 1)
+
+	var (
+		wg       sync.WaitGroup
+		canceled atomic.Bool
+	)
+	for w := 0; w < workers; w++ {
+		select {
+		case hpAsyncWorkers <- struct{}{}:
+		case <-ctx.Done():
+			canceled.Store(true)
+		}
+		if canceled.Load() {
+			break
+		}
+
+		wg.Add(1)
+		go func(w int) {
+			defer func() {
+				<-hpAsyncWorkers
+				wg.Done()
+			}()
+			for n, i := 0, w; i < len(pubkeys); n, i = n+1, i+workers {
+				if n%hpMinPerWorker == 0 && ctx.Err() != nil {
+					canceled.Store(true)
+					return
+				}
+				out[i] = hashToCurve(pubkeys[i])
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if canceled.Load() {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// setHP caches the H_p(P_i) values of the ring, unless they already are.
+func (r *Ring) setHP(hp []types.Point) {
+	r.hpOnce.Do(func() {
+		r.hp = hp
+		r.hpReady.Store(true)
+	})
+}
+
+// ensureHP computes H_p(P_i) for each public key in the ring and caches them,
+// if the ring is small enough to be cached, waiting for PrecomputeAsync if
+// it's computing them. It's safe for concurrent use.
+func (r *Ring) ensureHP() {
+	if len(r.pubkeys) > hpCacheMaxSize {
+		return
+	}
+	if task := r.hpPending.Load(); task != nil {
+		<-task.done
+	}
+
+	r.hpOnce.Do(func() {
+		hp := make([]types.Point, len(r.pubkeys))
+		computeHP(r.pubkeys, hp)
+		r.hp = hp
+		r.hpReady.Store(true)
+	})
+}
+
+// cachedHP returns the cached H_p(P_i) values, or nil if they aren't cached.
+// Rings too large for ensureHP only have them if they were supplied with
+// SetHashPoints, possibly concurrently, so hpReady guards them.
+func (r *Ring) cachedHP() []types.Point {
+	if !r.hpReady.Load() {
+		return nil
+	}
+	return r.hp
+}
+
+// forEachHP calls fn with i and H_p(P_i) for each i in [start, end), in order,
+// stopping at the first error. ensureHP must have been called beforehand.
+// If the values aren't cached, they're computed in chunks of hpChunkSize.
+func (r *Ring) forEachHP(start, end int, fn func(i int, hp types.Point) error) error {
+	var buf []types.Point
+	cached := r.cachedHP()
+	for chunkStart := start; chunkStart < end; chunkStart += hpChunkSize {
+		chunkEnd := min(chunkStart+hpChunkSize, end)
+
+		var hp []types.Point
+		if cached != nil {
+			hp = cached[chunkStart:chunkEnd]
+		} else {
+			if buf == nil {
+				buf = make([]types.Point, min(hpChunkSize, end-start))
+			}
+			hp = buf[:chunkEnd-chunkStart]
+			computeHP(r.pubkeys[chunkStart:chunkEnd], hp)
+		}
+
+		for i, h := range hp {
+			if err := fn(chunkStart+i, h); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// computeHP sets out[i] = H_p(pubkeys[i]), spreading the work over up to
+// GOMAXPROCS goroutines.
+func computeHP(pubkeys, out []types.Point) {
+	workers := min(runtime.GOMAXPROCS(0), len(pubkeys)/hpMinPerWorker)
+	if workers <= 1 {
+		for i, pk := range pubkeys {
+			out[i] = hashToCurve(pk)
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(pubkeys); i += workers {
+				out[i] = hashToCurve(pubkeys[i])
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// forEachHPFrom calls fn with i and H_p(P_i) for each member of the ring, in
+// order, stopping at the first error. The values are taken from hps if it's
+// not nil, without caching them on the ring, and from the ring (see
+// forEachHP) otherwise.
+func (r *Ring) forEachHPFrom(hps HPProvider, fn func(i int, hp types.Point) error) error {
+	if hps == nil {
+		r.ensureHP()
+		return r.forEachHP(0, len(r.pubkeys), fn)
+	}
+
+	for i, pk := range r.pubkeys {
+		if err := fn(i, hps.HashToCurve(pk)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
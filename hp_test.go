@@ -0,0 +1,119 @@
+package ring
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureHP(t *testing.T) {
+	curve := Ed25519()
+	keyring, err := NewKeyRing(curve, 40, curve.NewRandomScalar(), 3)
+	require.NoError(t, err)
+	require.Nil(t, keyring.hp)
+
+	keyring.Precompute()
+	require.Len(t, keyring.hp, keyring.Size())
+	for i, pk := range keyring.pubkeys {
+		require.True(t, hashToCurve(pk).Equals(keyring.hp[i]))
+	}
+}
+
+func TestPrecomputeAsync(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 300, privKey, 7)
+	require.NoError(t, err)
+
+	// signers racing with the background computation wait for it
+	var wg sync.WaitGroup
+	done := keyring.PrecomputeAsync(context.Background())
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sig, err := keyring.Sign(testMsg, privKey)
+			if err != nil || !sig.Verify(testMsg) {
+				t.Error("signing during precomputation failed", err)
+			}
+		}()
+	}
+	require.NoError(t, <-done)
+	wg.Wait()
+
+	require.True(t, keyring.hpReady.Load())
+	require.Nil(t, keyring.hpPending.Load())
+	for i, pk := range keyring.pubkeys {
+		require.True(t, hashToCurve(pk).Equals(keyring.hp[i]))
+	}
+
+	// already cached
+	require.NoError(t, <-keyring.PrecomputeAsync(context.Background()))
+}
+
+func TestPrecomputeAsync_Canceled(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 100, privKey, 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, <-keyring.PrecomputeAsync(ctx), context.Canceled)
+	require.False(t, keyring.hpReady.Load())
+	require.Nil(t, keyring.hpPending.Load())
+
+	// the next call that needs the values computes them
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	require.True(t, sig.Verify(testMsg))
+	require.True(t, keyring.hpReady.Load())
+}
+
+func TestSignAndVerify_UncachedHP(t *testing.T) {
+	// force the chunked, uncached path with a ring spanning several chunks
+	defaultMax := hpCacheMaxSize
+	hpCacheMaxSize = 4
+	t.Cleanup(func() { hpCacheMaxSize = defaultMax })
+
+	curve := Ed25519()
+	size := 2*hpChunkSize + 17
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, size, privKey, hpChunkSize+1)
+	require.NoError(t, err)
+
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	require.Nil(t, keyring.hp)
+	require.True(t, sig.Verify(testMsg))
+
+	// the signature must also verify with the cached path
+	hpCacheMaxSize = defaultMax
+	cached, err := NewFixedKeyRingFromPublicKeys(curve, keyring.pubkeys)
+	require.NoError(t, err)
+	sig.ring = cached
+	require.True(t, sig.Verify(testMsg))
+	require.NotNil(t, cached.hp)
+}
+
+func TestForEachHP_Order(t *testing.T) {
+	curve := Secp256k1()
+	keyring, err := NewKeyRing(curve, 20, curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+	keyring.ensureHP()
+
+	var seen []int
+	err = keyring.forEachHP(5, 20, func(i int, hp types.Point) error {
+		seen = append(seen, i)
+		require.True(t, hp.Equals(hashToCurve(keyring.pubkeys[i])))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, seen, 15)
+	for j, i := range seen {
+		require.Equal(t, j+5, i)
+	}
+}
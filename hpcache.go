@@ -0,0 +1,138 @@
+package ring
+
+import (
+	"hash/maphash"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// HPProvider provides the hash-to-curve values H_p(P) of public keys during
+// verification, eg. from a cache shared by all the rings of a verifier.
+// Implementations must be safe for concurrent use, and must not modify the
+// points they return afterwards.
+type HPProvider interface {
+	// HashToCurve returns H_p(pubkey), as computed by HashToCurve.
+	HashToCurve(pubkey types.Point) types.Point
+}
+
+// VerifyOpts are the options of VerifyWithOpts.
+type VerifyOpts struct {
+	// HPCache provides H_p(P_i) for the ring members. If nil, the values are
+	// computed and cached on the ring, as by Verify.
+	HPCache HPProvider
+
+	// Time is the time the signature's validity window is checked against.
+	// If zero, the current time is used.
+	Time time.Time
+
+	// Policy is called with each ring member, as by VerifyWithPolicy.
+	Policy func(i int, pub types.Point) error
+
+	// Cofactor selects whether key images with a small-order component are
+	// accepted. It defaults to RequireTorsionFree, as for Verify.
+	Cofactor CofactorPolicy
+}
+
+// VerifyWithOpts verifies the ring signature for the given message like
+// VerifyWithPolicy, with the given options. With an HPCache, verification
+// doesn't write to the ring, so stateless verifiers that decode a new ring
+// for each signature can share the H_p(P_i) values across them instead.
+func (sig *RingSig) VerifyWithOpts(m [32]byte, opts *VerifyOpts) error {
+	if opts == nil {
+		opts = &VerifyOpts{}
+	}
+
+	at := opts.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if sig.ext.validity != nil && !sig.ext.validity.contains(at) {
+		return ErrNotValidAt
+	}
+
+	return sig.verifyTranscript(m, opts.Policy, opts.HPCache, opts.Cofactor)
+}
+
+// hpCacheShards is the number of shards of an HPCache, so that concurrent
+// verifications rarely contend for the same lock.
+const hpCacheShards = 64
+
+// HPCache is an HPProvider caching the H_p values it computes in a sharded
+// map keyed by the public keys' compressed encodings and implementations. It's
+// safe for concurrent use, and can be shared by rings over different curves,
+// or over different implementations of a curve, eg. the ed25519 curves
+// returned by Ed25519 and NewEd25519, whose public keys have the same
+// encodings: each gets H_p values of its own point type.
+type HPCache struct {
+	seed     maphash.Seed
+	perShard int
+	shards   [hpCacheShards]hpShard
+}
+
+type hpShard struct {
+	mu sync.RWMutex
+	hp map[hpKey]types.Point
+}
+
+// hpKey identifies a public key by its implementation, which determines its
+// curve (see CurveIDOfPoint), and its compressed encoding.
+type hpKey struct {
+	pointType reflect.Type
+	pubkey    string
+}
+
+var _ HPProvider = (*HPCache)(nil)
+
+// NewHPCache returns an empty cache holding up to about maxEntries values.
+// When a shard is full, arbitrary entries of it are evicted. maxEntries is
+// rounded up to a multiple of the number of shards, 64.
+func NewHPCache(maxEntries int) *HPCache {
+	c := &HPCache{
+		seed:     maphash.MakeSeed(),
+		perShard: max((maxEntries+hpCacheShards-1)/hpCacheShards, 1),
+	}
+	for i := range c.shards {
+		c.shards[i].hp = make(map[hpKey]types.Point)
+	}
+	return c
+}
+
+// HashToCurve implements HPProvider.
+func (c *HPCache) HashToCurve(pubkey types.Point) types.Point {
+	key := hpKey{pointType: reflect.TypeOf(pubkey), pubkey: string(encodePoint(pubkey))}
+	shard := &c.shards[maphash.String(c.seed, key.pubkey)%hpCacheShards]
+
+	shard.mu.RLock()
+	hp, ok := shard.hp[key]
+	shard.mu.RUnlock()
+	if ok {
+		return hp
+	}
+
+	hp = hashToCurve(pubkey)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	if len(shard.hp) >= c.perShard {
+		for k := range shard.hp {
+			delete(shard.hp, k)
+			break
+		}
+	}
+	shard.hp[key] = hp
+	return hp
+}
+
+// Len returns the number of values in the cache.
+func (c *HPCache) Len() int {
+	n := 0
+	for i := range c.shards {
+		c.shards[i].mu.RLock()
+		n += len(c.shards[i].hp)
+		c.shards[i].mu.RUnlock()
+	}
+	return n
+}
@@ -0,0 +1,98 @@
+package ring
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyWithOpts_HPCache(t *testing.T) {
+	cache := NewHPCache(1024)
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 8, privKey, 3)
+		require.NoError(t, err)
+		sig, err := keyring.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		enc, err := sig.Serialize()
+		require.NoError(t, err)
+
+		// each verification decodes a new ring, as a stateless verifier would
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				res := new(RingSig)
+				require.NoError(t, res.Deserialize(curve, enc))
+				require.NoError(t, res.VerifyWithOpts(testMsg, &VerifyOpts{HPCache: cache}))
+				require.ErrorIs(t, res.VerifyWithOpts([32]byte{}, &VerifyOpts{HPCache: cache}), ErrInvalidSignature)
+				// the ring isn't written to
+				require.Nil(t, res.ring.hp)
+			}()
+		}
+		wg.Wait()
+	}
+	require.Equal(t, 16, cache.Len())
+}
+
+func TestHPCache_Implementations(t *testing.T) {
+	// the ed25519 implementations encode public keys the same way, but each
+	// gets H_p values of its own point type
+	edwards, err := NewEd25519(Ed25519ImplEdwards25519)
+	require.NoError(t, err)
+	privKey := Ed25519().NewRandomScalar()
+	keyring, err := NewKeyRing(Ed25519(), 4, privKey, 1)
+	require.NoError(t, err)
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+
+	cache := NewHPCache(1024)
+	for _, curve := range []Curve{Ed25519(), edwards, Ed25519()} {
+		res := new(RingSig)
+		require.NoError(t, res.Deserialize(curve, enc))
+		require.NoError(t, res.VerifyWithOpts(testMsg, &VerifyOpts{HPCache: cache}))
+
+		pk := res.Ring().PublicKeys()[0]
+		require.IsType(t, pk, cache.HashToCurve(pk))
+	}
+	require.Equal(t, 8, cache.Len())
+}
+
+func TestVerifyWithOpts(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 0)
+	require.NoError(t, err)
+
+	notAfter := time.Unix(1700000000, 0)
+	sig, err := keyring.Sign(testMsg, privKey, WithValidity(time.Time{}, notAfter))
+	require.NoError(t, err)
+
+	require.ErrorIs(t, sig.VerifyWithOpts(testMsg, nil), ErrNotValidAt)
+	require.NoError(t, sig.VerifyWithOpts(testMsg, &VerifyOpts{Time: notAfter}))
+
+	calls := 0
+	require.NoError(t, sig.VerifyWithOpts(testMsg, &VerifyOpts{
+		Time: notAfter,
+		Policy: func(int, Point) error {
+			calls++
+			return nil
+		},
+	}))
+	require.Equal(t, 4, calls)
+}
+
+func TestHPCache_Eviction(t *testing.T) {
+	curve := Secp256k1()
+	cache := NewHPCache(1)
+	for i := 0; i < 500; i++ {
+		pk := curve.ScalarBaseMul(curve.NewRandomScalar())
+		require.True(t, cache.HashToCurve(pk).Equals(HashToCurve(pk)))
+	}
+	require.LessOrEqual(t, cache.Len(), hpCacheShards)
+}
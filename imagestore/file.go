@@ -0,0 +1,251 @@
+package imagestore
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+)
+
+// fileMagic is the header of image log files.
+const fileMagic = "RINGIMG\x01"
+
+// FileStore is a Store backed by an append-only log file, for embedded use.
+// Images are also kept in memory, so the store must fit in memory.
+//
+// Each image is written as a record with a checksum, and the file is synced
+// before Put and PutBatch return. A record left incomplete by a crash, which
+// can only be the last one, is discarded when the file is reopened; as it was
+// never acknowledged, no accepted image is lost. A corrupt record followed by
+// others can't have been left by a crash, so OpenFile refuses the file rather
+// than discard the images after it.
+type FileStore struct {
+	mu     sync.Mutex
+	f      *os.File
+	images map[string]struct{}
+}
+
+var _ BatchStore = (*FileStore)(nil)
+
+// OpenFile opens the image log at path, creating it if it doesn't exist.
+func OpenFile(path string) (*FileStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileStore{f: f, images: make(map[string]struct{})}
+	if err := s.load(); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// load reads the records of the file, and truncates it after the last
+// complete one if the last record is incomplete or corrupt. It returns an
+// error for a corrupt record followed by more data.
+func (s *FileStore) load() error {
+	info, err := s.f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		if _, err := s.f.Write([]byte(fileMagic)); err != nil {
+			return err
+		}
+		return s.f.Sync()
+	}
+
+	r := bufio.NewReader(s.f)
+	magic := make([]byte, len(fileMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != fileMagic {
+		return errors.New("not an image log file")
+	}
+
+	offset := int64(len(fileMagic))
+	for {
+		image, n, err := readRecord(r)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			// the end of the file, possibly after a record cut short by a
+			// crash during a write
+			break
+		}
+		if errors.Is(err, errCorruptRecord) {
+			if offset+int64(n) == info.Size() {
+				// the last record, left corrupt by a crash during a write
+				break
+			}
+			return fmt.Errorf("corrupt record at offset %d followed by more data", offset)
+		}
+		if err != nil {
+			return err
+		}
+		s.images[string(image)] = struct{}{}
+		offset += int64(n)
+	}
+
+	if offset < info.Size() {
+		if err := s.f.Truncate(offset); err != nil {
+			return err
+		}
+		if err := s.f.Sync(); err != nil {
+			return err
+		}
+	}
+	_, err = s.f.Seek(offset, io.SeekStart)
+	return err
+}
+
+// A record is the image length (1 byte), the image, and the CRC-32 of both.
+func appendRecord(out, image []byte) []byte {
+	start := len(out)
+	out = append(out, byte(len(image)))
+	out = append(out, image...)
+	return binary.BigEndian.AppendUint32(out, crc32.ChecksumIEEE(out[start:]))
+}
+
+// errCorruptRecord is returned by readRecord for a complete record whose
+// checksum doesn't match.
+var errCorruptRecord = errors.New("corrupt record")
+
+// readRecord reads a record. It returns io.EOF at the end of the file,
+// io.ErrUnexpectedEOF for an incomplete record, and errCorruptRecord, with the
+// record's length, for a corrupt one.
+func readRecord(r *bufio.Reader) (image []byte, n int, err error) {
+	l, err := r.ReadByte()
+	if err != nil {
+		return nil, 0, err
+	}
+	rec := make([]byte, 1+int(l)+4)
+	rec[0] = l
+	if _, err := io.ReadFull(r, rec[1:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, 0, err
+	}
+	if crc32.ChecksumIEEE(rec[:1+l]) != binary.BigEndian.Uint32(rec[1+l:]) {
+		return nil, len(rec), errCorruptRecord
+	}
+	return rec[1 : 1+l], len(rec), nil
+}
+
+func checkImage(image []byte) error {
+	if len(image) == 0 || len(image) > 255 {
+		return errors.New("invalid key image length")
+	}
+	return nil
+}
+
+// Put implements Store.
+func (s *FileStore) Put(ctx context.Context, image []byte) (bool, error) {
+	added, err := s.PutBatch(ctx, [][]byte{image})
+	if err != nil {
+		return false, err
+	}
+	return added[0], nil
+}
+
+// PutBatch implements BatchStore. The new images are written with a single
+// write and sync.
+func (s *FileStore) PutBatch(_ context.Context, images [][]byte) ([]bool, error) {
+	for _, image := range images {
+		if err := checkImage(image); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return nil, os.ErrClosed
+	}
+
+	added := make([]bool, len(images))
+	batch := make(map[string]bool, len(images))
+	var buf []byte
+	for i, image := range images {
+		if _, ok := s.images[string(image)]; ok || batch[string(image)] {
+			continue
+		}
+		batch[string(image)] = true
+		added[i] = true
+		buf = appendRecord(buf, image)
+	}
+	if len(buf) == 0 {
+		return added, nil
+	}
+
+	if err := s.write(buf); err != nil {
+		return nil, err
+	}
+	for image := range batch {
+		s.images[image] = struct{}{}
+	}
+	return added, nil
+}
+
+// write appends buf to the file and syncs it. On failure, the file is
+// truncated back to its previous size, so that no image is left half-written.
+func (s *FileStore) write(buf []byte) error {
+	offset, err := s.f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.f.Write(buf)
+	if err == nil {
+		err = s.f.Sync()
+	}
+	if err != nil {
+		_ = s.f.Truncate(offset)
+		_, _ = s.f.Seek(offset, io.SeekStart)
+		return err
+	}
+	return nil
+}
+
+// Has implements Store.
+func (s *FileStore) Has(_ context.Context, image []byte) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.images[string(image)]
+	return ok, nil
+}
+
+// Iterate implements Store.
+func (s *FileStore) Iterate(ctx context.Context, fn func(image []byte) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for image := range s.images {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn([]byte(image)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements Store.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
@@ -0,0 +1,139 @@
+package imagestore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	s, err := OpenFile(filepath.Join(t.TempDir(), "images.log"))
+	require.NoError(t, err)
+	defer s.Close()
+	testStore(t, s)
+}
+
+func TestFileStore_Reopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "images.log")
+
+	s, err := OpenFile(path)
+	require.NoError(t, err)
+	_, err = PutBatch(ctx, s, [][]byte{[]byte("a"), []byte("b")})
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	_, err = s.Put(ctx, []byte("c"))
+	require.ErrorIs(t, err, os.ErrClosed)
+
+	s, err = OpenFile(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, collect(t, s))
+	added, err := s.Put(ctx, []byte("a"))
+	require.NoError(t, err)
+	require.False(t, added)
+	added, err = s.Put(ctx, []byte("c"))
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NoError(t, s.Close())
+
+	s, err = OpenFile(path)
+	require.NoError(t, err)
+	defer s.Close()
+	require.Equal(t, []string{"a", "b", "c"}, collect(t, s))
+}
+
+func TestFileStore_TornWrite(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "images.log")
+
+	s, err := OpenFile(path)
+	require.NoError(t, err)
+	_, err = s.Put(ctx, []byte("a"))
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	size := info.Size()
+
+	// a record cut short by a crash
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.Write(appendRecord(nil, []byte("b"))[:3])
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	s, err = OpenFile(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, collect(t, s))
+	info, err = os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, size, info.Size())
+
+	// the log is usable after recovery
+	added, err := s.Put(ctx, []byte("b"))
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NoError(t, s.Close())
+
+	s, err = OpenFile(path)
+	require.NoError(t, err)
+	defer s.Close()
+	require.Equal(t, []string{"a", "b"}, collect(t, s))
+}
+
+func TestFileStore_CorruptRecord(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "images.log")
+
+	s, err := OpenFile(path)
+	require.NoError(t, err)
+	for _, image := range []string{"a", "b", "c"} {
+		_, err = s.Put(ctx, []byte(image))
+		require.NoError(t, err)
+	}
+	require.NoError(t, s.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	recLen := len(appendRecord(nil, []byte("a")))
+
+	// a bit flip in the middle record can't be a torn write: the file is
+	// refused, rather than truncated, which would forget "c"
+	corrupt := append([]byte{}, data...)
+	corrupt[len(fileMagic)+recLen+1] ^= 1
+	require.NoError(t, os.WriteFile(path, corrupt, 0o600))
+	_, err = OpenFile(path)
+	require.ErrorContains(t, err, "corrupt record")
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, corrupt, after)
+
+	// a corrupt last record is discarded, like an incomplete one
+	corrupt = append([]byte{}, data...)
+	corrupt[len(fileMagic)+2*recLen+1] ^= 1
+	require.NoError(t, os.WriteFile(path, corrupt, 0o600))
+	s, err = OpenFile(path)
+	require.NoError(t, err)
+	defer s.Close()
+	require.Equal(t, []string{"a", "b"}, collect(t, s))
+}
+
+func TestFileStore_Errors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "images.log")
+	require.NoError(t, os.WriteFile(path, []byte("not a log"), 0o600))
+	_, err := OpenFile(path)
+	require.Error(t, err)
+
+	s, err := OpenFile(filepath.Join(t.TempDir(), "images.log"))
+	require.NoError(t, err)
+	defer s.Close()
+	_, err = s.Put(context.Background(), nil)
+	require.Error(t, err)
+	_, err = s.Put(context.Background(), make([]byte, 256))
+	require.Error(t, err)
+}
@@ -0,0 +1,245 @@
+// Package imagestore records the key images of accepted signatures in
+// persistent storage, so that double-signing (eg. double-spend) protection
+// survives restarts.
+//
+// A Registry checks and records the key image of each signature it accepts,
+// and rejects signatures whose key image was seen before:
+//
+//	store, err := imagestore.OpenFile("images.log")
+//	reg := imagestore.NewRegistry(store)
+//	if err := reg.Register(ctx, sig); errors.Is(err, imagestore.ErrSeen) {
+//		// the signer already signed
+//	}
+//
+// Images are stored through the Store interface, which has three
+// implementations: MemoryStore, FileStore, an append-only log file, and
+// SQLStore, a table in a SQL database.
+package imagestore
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+const rotationMarkerDomain = "ring-go/imagestore/rotation"
+
+// ErrSeen is returned by Registry.Register when a signature's key image was
+// seen before.
+var ErrSeen = errors.New("key image was already seen")
+
+// Store is a persistent set of key images. Implementations must be safe for
+// concurrent use, and durable: once Put returns, the image must survive a
+// crash.
+type Store interface {
+	// Put adds the image to the store, and returns whether it was added,
+	// ie. it wasn't present. The check and the insertion are atomic.
+	Put(ctx context.Context, image []byte) (added bool, err error)
+	// Has returns whether the image is in the store.
+	Has(ctx context.Context, image []byte) (bool, error)
+	// Iterate calls fn with each image in the store, in no particular order,
+	// stopping at the first error, which it returns. fn must not call the
+	// store's other methods, and image is only valid until it returns.
+	Iterate(ctx context.Context, fn func(image []byte) error) error
+	// Close releases the store's resources.
+	Close() error
+}
+
+// BatchStore is a Store that can add several images at once, atomically
+// and more efficiently than adding them one by one.
+type BatchStore interface {
+	Store
+	// PutBatch adds the images to the store, and returns whether each one
+	// was added. Either all the images are added, or none if it fails.
+	PutBatch(ctx context.Context, images [][]byte) (added []bool, err error)
+}
+
+// PutBatch adds the images to the store with its PutBatch method if it has
+// one, and otherwise one at a time, in which case they're not added
+// atomically.
+func PutBatch(ctx context.Context, s Store, images [][]byte) ([]bool, error) {
+	if bs, ok := s.(BatchStore); ok {
+		return bs.PutBatch(ctx, images)
+	}
+
+	added := make([]bool, len(images))
+	for i, image := range images {
+		var err error
+		if added[i], err = s.Put(ctx, image); err != nil {
+			return nil, err
+		}
+	}
+	return added, nil
+}
+
+// Registry records the key images of accepted signatures in a store.
+type Registry struct {
+	store Store
+}
+
+// NewRegistry returns a registry that records images in the store.
+func NewRegistry(store Store) *Registry {
+	return &Registry{store: store}
+}
+
+// Image returns the key image of a signature as stored by a Registry: the
+// encoding of its normalized key image.
+func Image(sig *ring.RingSig) []byte {
+	return ring.NormalizeKeyImage(sig.KeyImage()).Encode()
+}
+
+// Register records the signature's key image, and returns ErrSeen if it was
+// recorded before. The signature must have been verified beforehand.
+func (r *Registry) Register(ctx context.Context, sig *ring.RingSig) error {
+	added, err := r.store.Put(ctx, Image(sig))
+	if err != nil {
+		return err
+	}
+	if !added {
+		return ErrSeen
+	}
+	return nil
+}
+
+// RegisterBatch records the key images of several signatures, and returns
+// whether each one was new. If the store is a BatchStore, the images are
+// recorded atomically.
+func (r *Registry) RegisterBatch(ctx context.Context, sigs []*ring.RingSig) ([]bool, error) {
+	images := make([][]byte, len(sigs))
+	for i, sig := range sigs {
+		images[i] = Image(sig)
+	}
+	return PutBatch(ctx, r.store, images)
+}
+
+// Seen returns whether the signature's key image was recorded.
+func (r *Registry) Seen(ctx context.Context, sig *ring.RingSig) (bool, error) {
+	return r.store.Has(ctx, Image(sig))
+}
+
+// RegisterRotation verifies a rotation proof linking a member of oldRing to a
+// member of newRing (see ring.ProveRotation), and transfers the old key's
+// status to the new one: if the old key image was seen, the new one is
+// recorded as seen too, so that the rotated key can't sign again. The old key
+// image is recorded in any case, retiring the old key. A hash of both images is
+// also stored, which marks the rotation as registered, so that registering it
+// again has no effect.
+//
+// It returns ErrSeen if both key images were seen before, ie. the party
+// already signed with both keys. If it fails after recording the old key
+// image, eg. because of a crash, registering the rotation again records the
+// new key image as seen, failing closed.
+func (r *Registry) RegisterRotation(ctx context.Context, proof *ring.RotationProof, oldRing, newRing *ring.Ring) error {
+	if err := proof.Verify(oldRing, newRing); err != nil {
+		return err
+	}
+
+	oldImage := ring.NormalizeKeyImage(proof.OldKeyImage()).Encode()
+	newImage := ring.NormalizeKeyImage(proof.NewKeyImage()).Encode()
+	marker := rotationMarker(oldImage, newImage)
+	if done, err := r.store.Has(ctx, marker); err != nil || done {
+		return err
+	}
+
+	oldAdded, err := r.store.Put(ctx, oldImage)
+	if err != nil {
+		return err
+	}
+	if !oldAdded {
+		newAdded, err := r.store.Put(ctx, newImage)
+		if err != nil {
+			return err
+		}
+		if !newAdded {
+			return ErrSeen
+		}
+	}
+
+	_, err = r.store.Put(ctx, marker)
+	return err
+}
+
+// rotationMarker returns the entry recording that a rotation was registered,
+// which is a hash so that it can't be mistaken for a key image.
+func rotationMarker(oldImage, newImage []byte) []byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte(rotationMarkerDomain))
+	_, _ = h.Write(oldImage)
+	_, _ = h.Write(newImage)
+	return h.Sum(nil)
+}
+
+// MemoryStore is a Store that keeps images in memory. It's not persistent,
+// and meant for tests and ephemeral deployments.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	images map[string]struct{}
+}
+
+var _ BatchStore = (*MemoryStore)(nil)
+
+// NewMemoryStore returns an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{images: make(map[string]struct{})}
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(_ context.Context, image []byte) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.images[string(image)]; ok {
+		return false, nil
+	}
+	s.images[string(image)] = struct{}{}
+	return true, nil
+}
+
+// PutBatch implements BatchStore.
+func (s *MemoryStore) PutBatch(_ context.Context, images [][]byte) ([]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	added := make([]bool, len(images))
+	for i, image := range images {
+		if _, ok := s.images[string(image)]; !ok {
+			s.images[string(image)] = struct{}{}
+			added[i] = true
+		}
+	}
+	return added, nil
+}
+
+// Has implements Store.
+func (s *MemoryStore) Has(_ context.Context, image []byte) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.images[string(image)]
+	return ok, nil
+}
+
+// Iterate implements Store.
+func (s *MemoryStore) Iterate(ctx context.Context, fn func(image []byte) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for image := range s.images {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn([]byte(image)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements Store.
+func (*MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,126 @@
+package imagestore
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// testStore checks the behaviour common to all stores.
+func testStore(t *testing.T, s Store) {
+	ctx := context.Background()
+	a, b, c := []byte("image-a"), []byte("image-b"), []byte("image-c")
+
+	added, err := s.Put(ctx, a)
+	require.NoError(t, err)
+	require.True(t, added)
+	added, err = s.Put(ctx, a)
+	require.NoError(t, err)
+	require.False(t, added)
+
+	has, err := s.Has(ctx, a)
+	require.NoError(t, err)
+	require.True(t, has)
+	has, err = s.Has(ctx, b)
+	require.NoError(t, err)
+	require.False(t, has)
+
+	batch, err := PutBatch(ctx, s, [][]byte{a, b, c})
+	require.NoError(t, err)
+	require.Equal(t, []bool{false, true, true}, batch)
+
+	require.Equal(t, []string{"image-a", "image-b", "image-c"}, collect(t, s))
+}
+
+func collect(t *testing.T, s Store) []string {
+	var images []string
+	require.NoError(t, s.Iterate(context.Background(), func(image []byte) error {
+		images = append(images, string(image))
+		return nil
+	}))
+	sort.Strings(images)
+	return images
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	curve := ring.Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := ring.NewKeyRing(curve, 4, privKey, 2)
+	require.NoError(t, err)
+
+	sig1, err := keyring.Sign([32]byte{1}, privKey)
+	require.NoError(t, err)
+	sig2, err := keyring.Sign([32]byte{2}, privKey)
+	require.NoError(t, err)
+	otherKey := curve.NewRandomScalar()
+	other, err := ring.NewKeyRing(curve, 4, otherKey, 0)
+	require.NoError(t, err)
+	sig3, err := other.Sign([32]byte{1}, otherKey)
+	require.NoError(t, err)
+
+	reg := NewRegistry(NewMemoryStore())
+	seen, err := reg.Seen(ctx, sig1)
+	require.NoError(t, err)
+	require.False(t, seen)
+
+	require.NoError(t, reg.Register(ctx, sig1))
+	// the same signer, over another message
+	require.ErrorIs(t, reg.Register(ctx, sig2), ErrSeen)
+	seen, err = reg.Seen(ctx, sig2)
+	require.NoError(t, err)
+	require.True(t, seen)
+
+	added, err := reg.RegisterBatch(ctx, []*ring.RingSig{sig2, sig3})
+	require.NoError(t, err)
+	require.Equal(t, []bool{false, true}, added)
+}
+
+func TestRegistry_RegisterRotation(t *testing.T) {
+	ctx := context.Background()
+	curve := ring.Ed25519()
+	oldPriv, newPriv := curve.NewRandomScalar(), curve.NewRandomScalar()
+	oldRing, err := ring.NewKeyRing(curve, 3, oldPriv, 0)
+	require.NoError(t, err)
+	newRing, err := ring.NewKeyRing(curve, 3, newPriv, 1)
+	require.NoError(t, err)
+	proof, err := ring.ProveRotation(oldRing, oldPriv, newRing, newPriv)
+	require.NoError(t, err)
+
+	oldSig, err := oldRing.Sign([32]byte{1}, oldPriv)
+	require.NoError(t, err)
+	newSig, err := newRing.Sign([32]byte{2}, newPriv)
+	require.NoError(t, err)
+
+	// the old key signed before rotating: the new key inherits it
+	reg := NewRegistry(NewMemoryStore())
+	require.NoError(t, reg.Register(ctx, oldSig))
+	require.NoError(t, reg.RegisterRotation(ctx, proof, oldRing, newRing))
+	require.ErrorIs(t, reg.Register(ctx, newSig), ErrSeen)
+	require.NoError(t, reg.RegisterRotation(ctx, proof, oldRing, newRing))
+
+	// the old key hadn't signed: it's retired, and the new one can sign once
+	reg = NewRegistry(NewMemoryStore())
+	require.NoError(t, reg.RegisterRotation(ctx, proof, oldRing, newRing))
+	require.NoError(t, reg.RegisterRotation(ctx, proof, oldRing, newRing))
+	require.ErrorIs(t, reg.Register(ctx, oldSig), ErrSeen)
+	require.NoError(t, reg.Register(ctx, newSig))
+	require.ErrorIs(t, reg.Register(ctx, newSig), ErrSeen)
+
+	// both keys signed
+	reg = NewRegistry(NewMemoryStore())
+	require.NoError(t, reg.Register(ctx, oldSig))
+	require.NoError(t, reg.Register(ctx, newSig))
+	require.ErrorIs(t, reg.RegisterRotation(ctx, proof, oldRing, newRing), ErrSeen)
+
+	// invalid proofs are rejected
+	require.ErrorIs(t, reg.RegisterRotation(ctx, proof, newRing, oldRing), ring.ErrInvalidRotation)
+}
@@ -0,0 +1,172 @@
+package imagestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+)
+
+// DefaultTable is the default name of the table used by SQLStore.
+const DefaultTable = "ring_key_images"
+
+var identifierRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLStore is a Store backed by a table of a SQL database, eg. PostgreSQL or
+// SQLite, which is safe to share between processes. It uses $n placeholders
+// and INSERT ... ON CONFLICT DO NOTHING, so the database must support both.
+//
+// The table has a single column, image, which is its primary key; the
+// database's uniqueness constraint makes Put atomic across processes.
+type SQLStore struct {
+	db *sql.DB
+
+	insertQuery, hasQuery, iterateQuery string
+}
+
+var _ BatchStore = (*SQLStore)(nil)
+
+// SQLOption is an option for NewSQLStore.
+type SQLOption func(*sqlOptions)
+
+type sqlOptions struct {
+	table       string
+	createTable bool
+}
+
+// WithTable sets the name of the table. It must be a plain SQL identifier.
+// The default is DefaultTable.
+func WithTable(name string) SQLOption {
+	return func(o *sqlOptions) {
+		o.table = name
+	}
+}
+
+// WithoutCreateTable disables creating the table if it doesn't exist, eg.
+// when the schema is managed by migrations.
+func WithoutCreateTable() SQLOption {
+	return func(o *sqlOptions) {
+		o.createTable = false
+	}
+}
+
+// NewSQLStore returns a store backed by a table of the database, which it
+// creates if it doesn't exist. The caller keeps ownership of db: closing the
+// store doesn't close it.
+func NewSQLStore(ctx context.Context, db *sql.DB, opts ...SQLOption) (*SQLStore, error) {
+	o := &sqlOptions{table: DefaultTable, createTable: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if !identifierRe.MatchString(o.table) {
+		return nil, errors.New("invalid table name")
+	}
+
+	if o.createTable {
+		if _, err := db.ExecContext(ctx,
+			"CREATE TABLE IF NOT EXISTS "+o.table+" (image BYTEA PRIMARY KEY)"); err != nil {
+			return nil, err
+		}
+	}
+
+	return &SQLStore{
+		db:           db,
+		insertQuery:  "INSERT INTO " + o.table + " (image) VALUES ($1) ON CONFLICT DO NOTHING",
+		hasQuery:     "SELECT 1 FROM " + o.table + " WHERE image = $1",
+		iterateQuery: "SELECT image FROM " + o.table,
+	}, nil
+}
+
+// Put implements Store.
+func (s *SQLStore) Put(ctx context.Context, image []byte) (bool, error) {
+	if err := checkImage(image); err != nil {
+		return false, err
+	}
+	return insert(ctx, s.db, s.insertQuery, image)
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func insert(ctx context.Context, db execer, query string, image []byte) (bool, error) {
+	res, err := db.ExecContext(ctx, query, image)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// PutBatch implements BatchStore. The images are inserted in a single
+// transaction.
+func (s *SQLStore) PutBatch(ctx context.Context, images [][]byte) (added []bool, err error) {
+	for _, image := range images {
+		if err := checkImage(image); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	added = make([]bool, len(images))
+	for i, image := range images {
+		if added[i], err = insert(ctx, tx, s.insertQuery, image); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// Has implements Store.
+func (s *SQLStore) Has(ctx context.Context, image []byte) (bool, error) {
+	var one int
+	err := s.db.QueryRowContext(ctx, s.hasQuery, image).Scan(&one)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+// Iterate implements Store.
+func (s *SQLStore) Iterate(ctx context.Context, fn func(image []byte) error) error {
+	rows, err := s.db.QueryContext(ctx, s.iterateQuery)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var image []byte
+		if err := rows.Scan(&image); err != nil {
+			return err
+		}
+		if err := fn(image); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Close implements Store. It doesn't close the database.
+func (*SQLStore) Close() error {
+	return nil
+}
@@ -0,0 +1,184 @@
+package imagestore
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver is a database/sql driver understanding only the queries of
+// SQLStore, over a single in-memory table.
+type fakeDriver struct {
+	mu      sync.Mutex
+	created bool
+	images  map[string]bool
+	// failOn makes inserting this image fail.
+	failOn string
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{d: d}, nil
+}
+
+type fakeConn struct {
+	d    *fakeDriver
+	undo []string // images inserted by the current transaction
+	inTx bool
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{c: c, query: query}, nil
+}
+
+func (*fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.inTx, c.undo = true, nil
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.inTx, c.undo = false, nil
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	for _, image := range c.undo {
+		delete(c.d.images, image)
+	}
+	c.inTx, c.undo = false, nil
+	return nil
+}
+
+type fakeStmt struct {
+	c     *fakeConn
+	query string
+}
+
+func (*fakeStmt) Close() error  { return nil }
+func (*fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.c.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS ring_key_images "):
+		d.created = true
+		return driver.RowsAffected(0), nil
+	case strings.HasPrefix(s.query, "INSERT INTO ring_key_images ") && d.created:
+		image := string(args[0].([]byte))
+		if image == d.failOn {
+			return nil, errors.New("insert failed")
+		}
+		if d.images[image] {
+			return driver.RowsAffected(0), nil
+		}
+		d.images[image] = true
+		if s.c.inTx {
+			s.c.undo = append(s.c.undo, image)
+		}
+		return driver.RowsAffected(1), nil
+	}
+	return nil, errors.New("unexpected query: " + s.query)
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	d := s.c.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	rows := &fakeRows{}
+	switch {
+	case strings.HasPrefix(s.query, "SELECT 1 FROM ring_key_images WHERE image = $1") && d.created:
+		if d.images[string(args[0].([]byte))] {
+			rows.values = append(rows.values, int64(1))
+		}
+	case s.query == "SELECT image FROM ring_key_images" && d.created:
+		for image := range d.images {
+			rows.values = append(rows.values, []byte(image))
+		}
+	default:
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	values []driver.Value
+}
+
+func (*fakeRows) Columns() []string { return []string{"c"} }
+func (*fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+func newFakeDB(t *testing.T) (*sql.DB, *fakeDriver) {
+	d := &fakeDriver{images: make(map[string]bool)}
+	name := "imagestore-fake-" + t.Name()
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db, d
+}
+
+func TestSQLStore(t *testing.T) {
+	db, _ := newFakeDB(t)
+	s, err := NewSQLStore(context.Background(), db)
+	require.NoError(t, err)
+	defer s.Close()
+	testStore(t, s)
+}
+
+func TestSQLStore_BatchRollback(t *testing.T) {
+	ctx := context.Background()
+	db, d := newFakeDB(t)
+	s, err := NewSQLStore(ctx, db)
+	require.NoError(t, err)
+
+	d.failOn = "b"
+	_, err = s.PutBatch(ctx, [][]byte{[]byte("a"), []byte("b")})
+	require.Error(t, err)
+	require.Empty(t, collect(t, s))
+
+	d.failOn = ""
+	added, err := s.PutBatch(ctx, [][]byte{[]byte("a"), []byte("b")})
+	require.NoError(t, err)
+	require.Equal(t, []bool{true, true}, added)
+}
+
+func TestSQLStore_Options(t *testing.T) {
+	ctx := context.Background()
+	db, d := newFakeDB(t)
+
+	_, err := NewSQLStore(ctx, db, WithTable("images; DROP TABLE users"))
+	require.Error(t, err)
+
+	// the table isn't created, so the fake rejects queries
+	s, err := NewSQLStore(ctx, db, WithoutCreateTable())
+	require.NoError(t, err)
+	require.False(t, d.created)
+	_, err = s.Put(ctx, []byte("a"))
+	require.Error(t, err)
+
+	s, err = NewSQLStore(ctx, db, WithTable("other"))
+	require.Error(t, err)
+	require.Nil(t, s)
+}
@@ -0,0 +1,48 @@
+package ring
+
+import (
+	"errors"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// ErrSignerNotInRing is returned by Sign when the private key's public key is
+// not a member of the ring.
+var ErrSignerNotInRing = errors.New("failed to find given key in public key set")
+
+// IndexOf returns the index of the given public key in the ring, and whether
+// it's a member of the ring.
+//
+// The first call builds an index of the ring's public keys, so that later
+// calls take constant time. Rings larger than the H_p cache limit aren't
+// indexed, and are searched linearly instead.
+func (r *Ring) IndexOf(pubkey types.Point) (int, bool) {
+	if len(r.pubkeys) > hpCacheMaxSize {
+		for i, pk := range r.pubkeys {
+			if pk != nil && equalPoints(pk, pubkey) {
+				return i, true
+			}
+		}
+		return -1, false
+	}
+
+	r.indexOnce.Do(func() {
+		index := make(map[string]int, len(r.pubkeys))
+		for i, pk := range r.pubkeys {
+			if pk == nil {
+				continue
+			}
+			// keep the first index of duplicate keys
+			if _, ok := index[string(encodePoint(pk))]; !ok {
+				index[string(encodePoint(pk))] = i
+			}
+		}
+		r.index = index
+	})
+
+	i, ok := r.index[string(encodePoint(pubkey))]
+	if !ok {
+		return -1, false
+	}
+	return i, true
+}
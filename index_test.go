@@ -0,0 +1,61 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexOf(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 8, privKey, 6)
+		require.NoError(t, err)
+
+		for i, pk := range keyring.PublicKeys() {
+			idx, ok := keyring.IndexOf(pk)
+			require.True(t, ok)
+			require.Equal(t, i, idx)
+		}
+
+		idx, ok := keyring.IndexOf(curve.ScalarBaseMul(curve.NewRandomScalar()))
+		require.False(t, ok)
+		require.Equal(t, -1, idx)
+	}
+}
+
+func TestIndexOf_Unindexed(t *testing.T) {
+	defer func(size int) { hpCacheMaxSize = size }(hpCacheMaxSize)
+	hpCacheMaxSize = 2
+
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 3)
+	require.NoError(t, err)
+
+	idx, ok := keyring.IndexOf(curve.ScalarBaseMul(privKey))
+	require.True(t, ok)
+	require.Equal(t, 3, idx)
+	require.Nil(t, keyring.index)
+}
+
+func TestSign_SignerIndex(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 2)
+	require.NoError(t, err)
+
+	sig, err := keyring.Sign(testMsg, privKey, WithSignerIndex(2))
+	require.NoError(t, err)
+	require.True(t, sig.Verify(testMsg))
+
+	_, err = keyring.Sign(testMsg, privKey, WithSignerIndex(1))
+	require.EqualError(t, err, "secret index in ring is not signer")
+	_, err = keyring.Sign(testMsg, privKey, WithSignerIndex(4))
+	require.EqualError(t, err, "secret index out of range of ring size")
+	_, err = Sign(testMsg, keyring, privKey, -1)
+	require.EqualError(t, err, "secret index out of range of ring size")
+
+	_, err = keyring.Sign(testMsg, curve.NewRandomScalar())
+	require.ErrorIs(t, err, ErrSignerNotInRing)
+}
@@ -0,0 +1,68 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RingSigner creates serialized ring signatures over 32-byte messages. It's
+// implemented by *Signer, and by the fake in package ringtest, so that code
+// depending on it can be tested without doing curve arithmetic.
+type RingSigner interface {
+	SignMessage(m [32]byte, opts ...SignOption) ([]byte, error)
+}
+
+// RingVerifier verifies serialized ring signatures over 32-byte messages, and
+// returns the signer's key image, eg. to detect double-signing. It's
+// implemented by *Verifier, and by the fake in package ringtest.
+type RingVerifier interface {
+	VerifyMessage(m [32]byte, sig []byte) (keyImage []byte, err error)
+}
+
+var (
+	_ RingSigner   = (*Signer)(nil)
+	_ RingVerifier = (*Verifier)(nil)
+)
+
+// SignMessage signs the message with the signer's ring, and returns the
+// serialized signature.
+func (s *Signer) SignMessage(m [32]byte, opts ...SignOption) ([]byte, error) {
+	sig, err := s.ring.Sign(m, s.privKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Serialize()
+}
+
+// Verifier verifies serialized signatures against a trusted ring.
+type Verifier struct {
+	ring *Ring
+	opts *VerifyOpts
+}
+
+// NewVerifier returns a Verifier accepting signatures over the given ring,
+// verified with the given options, which may be nil.
+func NewVerifier(ring *Ring, opts *VerifyOpts) (*Verifier, error) {
+	if ring == nil {
+		return nil, errors.New("ring is nil")
+	}
+	return &Verifier{ring: ring, opts: opts}, nil
+}
+
+// VerifyMessage decodes the signature, checks that it's over the verifier's
+// ring, and verifies it for the message with VerifyWithOpts. It returns the
+// encoding of the normalized key image (see NormalizeKeyImage) if the
+// signature is valid.
+func (v *Verifier) VerifyMessage(m [32]byte, enc []byte) ([]byte, error) {
+	sig := new(RingSig)
+	if err := sig.Deserialize(v.ring.curve, enc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if !sig.ring.Equals(v.ring) {
+		return nil, fmt.Errorf("%w: signature is over a different ring", ErrInvalidSignature)
+	}
+	if err := sig.VerifyWithOpts(m, v.opts); err != nil {
+		return nil, err
+	}
+	return encodePoint(NormalizeKeyImage(sig.image)), nil
+}
@@ -0,0 +1,68 @@
+package ring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignerAndVerifier(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 4, privKey, 1)
+		require.NoError(t, err)
+
+		s, err := NewSigner(keyring, privKey)
+		require.NoError(t, err)
+		var signer RingSigner = s
+		v, err := NewVerifier(keyring, nil)
+		require.NoError(t, err)
+		var verifier RingVerifier = v
+
+		enc, err := signer.SignMessage(testMsg, WithChainID(2))
+		require.NoError(t, err)
+		image, err := verifier.VerifyMessage(testMsg, enc)
+		require.NoError(t, err)
+
+		sig := new(RingSig)
+		require.NoError(t, sig.Deserialize(curve, enc))
+		require.Equal(t, NormalizeKeyImage(sig.KeyImage()).Encode(), image)
+
+		_, err = verifier.VerifyMessage([32]byte{1}, enc)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+		_, err = verifier.VerifyMessage(testMsg, enc[1:])
+		require.ErrorIs(t, err, ErrInvalidSignature)
+
+		// signatures over other rings are rejected
+		other, err := NewKeyRing(curve, 4, privKey, 2)
+		require.NoError(t, err)
+		otherSig, err := other.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		otherEnc, err := otherSig.Serialize()
+		require.NoError(t, err)
+		_, err = verifier.VerifyMessage(testMsg, otherEnc)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+	}
+}
+
+func TestVerifier_Opts(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 3, privKey, 0)
+	require.NoError(t, err)
+	signer, err := NewSigner(keyring, privKey)
+	require.NoError(t, err)
+
+	notAfter := time.Now().Add(time.Hour)
+	enc, err := signer.SignMessage(testMsg, WithValidity(time.Time{}, notAfter))
+	require.NoError(t, err)
+
+	v, err := NewVerifier(keyring, &VerifyOpts{Time: notAfter.Add(time.Hour)})
+	require.NoError(t, err)
+	_, err = v.VerifyMessage(testMsg, enc)
+	require.ErrorIs(t, err, ErrNotValidAt)
+
+	_, err = NewVerifier(nil, nil)
+	require.Error(t, err)
+}
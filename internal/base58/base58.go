@@ -0,0 +1,69 @@
+// Package base58 implements the base58btc encoding used by Bitcoin, multibase
+// ("z" prefix) and libp2p peer IDs.
+package base58
+
+import (
+	"errors"
+	"math/big"
+)
+
+// alphabet is the Bitcoin base58 alphabet.
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var index = func() [256]int8 {
+	var index [256]int8
+	for i := range index {
+		index[i] = -1
+	}
+	for i, c := range alphabet {
+		index[c] = int8(i)
+	}
+	return index
+}()
+
+var bigRadix = big.NewInt(58)
+
+// Encode encodes b in base58btc. Leading zero bytes are encoded as
+// leading '1's.
+func Encode(b []byte) string {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+
+	n := new(big.Int).SetBytes(b)
+	var out []byte
+	mod := new(big.Int)
+	for n.Sign() > 0 {
+		n.DivMod(n, bigRadix, mod)
+		out = append(out, alphabet[mod.Int64()])
+	}
+	for i := 0; i < zeros; i++ {
+		out = append(out, alphabet[0])
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// Decode decodes a base58btc string.
+func Decode(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	n := new(big.Int)
+	for i := 0; i < len(s); i++ {
+		v := index[s[i]]
+		if v < 0 {
+			return nil, errors.New("invalid base58 character")
+		}
+		n.Mul(n, bigRadix)
+		n.Add(n, big.NewInt(int64(v)))
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
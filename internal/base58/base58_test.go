@@ -0,0 +1,27 @@
+package base58
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase58(t *testing.T) {
+	for _, tc := range []struct {
+		in  string
+		out string
+	}{
+		{"", ""},
+		{"hello world", "StV1DL6CwTryKyV"},
+		{"\x00\x00\x01", "112"},
+		{"\x00", "1"},
+	} {
+		require.Equal(t, tc.out, Encode([]byte(tc.in)))
+		b, err := Decode(tc.out)
+		require.NoError(t, err)
+		require.Equal(t, tc.in, string(b))
+	}
+
+	_, err := Decode("0OIl")
+	require.Error(t, err)
+}
@@ -0,0 +1,227 @@
+// Package reedsolomon implements systematic Reed-Solomon erasure codes over
+// GF(2^8): data split into data shards is extended with parity shards, such
+// that the data can be recovered from any data-shard-count of the shards.
+//
+// The parity rows of the encoding matrix form a Cauchy matrix, so that every
+// square submatrix of the encoding matrix is invertible.
+package reedsolomon
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxShards is the maximum total number of shards, the size of the field.
+const MaxShards = 256
+
+// ErrTooFewShards is returned by Reconstruct when fewer shards than data
+// shards are present.
+var ErrTooFewShards = errors.New("too few shards to reconstruct the data")
+
+// Code is a Reed-Solomon code with a fixed number of data and parity shards.
+type Code struct {
+	data, parity int
+	// parityRows[i][j] is the coefficient of data shard j in parity shard i
+	parityRows [][]byte
+}
+
+// New returns a code with the given numbers of data and parity shards.
+func New(dataShards, parityShards int) (*Code, error) {
+	if dataShards <= 0 || parityShards < 0 || dataShards+parityShards > MaxShards {
+		return nil, fmt.Errorf("invalid shard counts %d+%d", dataShards, parityShards)
+	}
+
+	c := &Code{data: dataShards, parity: parityShards, parityRows: make([][]byte, parityShards)}
+	for i := range c.parityRows {
+		row := make([]byte, dataShards)
+		for j := range row {
+			// x_i = data+i and y_j = j are distinct, so x_i + y_j != 0
+			row[j] = inv(byte(dataShards+i) ^ byte(j))
+		}
+		c.parityRows[i] = row
+	}
+	return c, nil
+}
+
+// Encode computes the parity shards from the data shards. shards holds the
+// data shards followed by the parity shards, which must all have the same
+// length.
+func (c *Code) Encode(shards [][]byte) error {
+	if err := c.checkShards(shards, false); err != nil {
+		return err
+	}
+	for i, row := range c.parityRows {
+		out := shards[c.data+i]
+		clear(out)
+		for j, coef := range row {
+			mulAdd(out, shards[j], coef)
+		}
+	}
+	return nil
+}
+
+// Reconstruct recomputes the missing shards, which are nil, from the present
+// ones. At least as many shards as data shards must be present.
+func (c *Code) Reconstruct(shards [][]byte) error {
+	if err := c.checkShards(shards, true); err != nil {
+		return err
+	}
+
+	// pick the first data-shard-count present shards, and the rows of the
+	// encoding matrix producing them
+	var (
+		rows    = make([][]byte, 0, c.data)
+		present = make([][]byte, 0, c.data)
+		size    int
+	)
+	for i, shard := range shards {
+		if shard == nil || len(rows) == c.data {
+			continue
+		}
+		size = len(shard)
+		present = append(present, shard)
+		if i < c.data {
+			row := make([]byte, c.data)
+			row[i] = 1
+			rows = append(rows, row)
+		} else {
+			rows = append(rows, c.parityRows[i-c.data])
+		}
+	}
+	if len(rows) < c.data {
+		return ErrTooFewShards
+	}
+
+	decode, err := invert(rows)
+	if err != nil {
+		return err
+	}
+	for j := 0; j < c.data; j++ {
+		if shards[j] != nil {
+			continue
+		}
+		out := make([]byte, size)
+		for r, coef := range decode[j] {
+			mulAdd(out, present[r], coef)
+		}
+		shards[j] = out
+	}
+
+	for i, row := range c.parityRows {
+		if shards[c.data+i] != nil {
+			continue
+		}
+		out := make([]byte, size)
+		for j, coef := range row {
+			mulAdd(out, shards[j], coef)
+		}
+		shards[c.data+i] = out
+	}
+	return nil
+}
+
+// checkShards checks the number of shards and that the present ones have the
+// same length. Missing shards are allowed if allowNil is true.
+func (c *Code) checkShards(shards [][]byte, allowNil bool) error {
+	if len(shards) != c.data+c.parity {
+		return fmt.Errorf("expected %d shards, got %d", c.data+c.parity, len(shards))
+	}
+	size := -1
+	for _, shard := range shards {
+		if shard == nil {
+			if !allowNil {
+				return errors.New("missing shard")
+			}
+			continue
+		}
+		if size >= 0 && len(shard) != size {
+			return errors.New("shards have different lengths")
+		}
+		size = len(shard)
+	}
+	return nil
+}
+
+// mulAdd sets out[i] += coef * in[i].
+func mulAdd(out, in []byte, coef byte) {
+	if coef == 0 {
+		return
+	}
+	logCoef := int(logTable[coef])
+	for i, b := range in {
+		if b != 0 {
+			out[i] ^= expTable[int(logTable[b])+logCoef]
+		}
+	}
+}
+
+// invert returns the inverse of the square matrix m, by Gauss-Jordan
+// elimination.
+func invert(m [][]byte) ([][]byte, error) {
+	n := len(m)
+	a := make([][]byte, n)
+	out := make([][]byte, n)
+	for i := range m {
+		a[i] = append([]byte(nil), m[i]...)
+		out[i] = make([]byte, n)
+		out[i][i] = 1
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for pivot < n && a[pivot][col] == 0 {
+			pivot++
+		}
+		if pivot == n {
+			return nil, errors.New("singular matrix")
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		out[col], out[pivot] = out[pivot], out[col]
+
+		scale := inv(a[col][col])
+		for j := 0; j < n; j++ {
+			a[col][j] = mul(a[col][j], scale)
+			out[col][j] = mul(out[col][j], scale)
+		}
+		for row := 0; row < n; row++ {
+			if row == col || a[row][col] == 0 {
+				continue
+			}
+			f := a[row][col]
+			mulAdd(a[row], a[col], f)
+			mulAdd(out[row], out[col], f)
+		}
+	}
+	return out, nil
+}
+
+// GF(2^8) with the polynomial x^8 + x^4 + x^3 + x^2 + 1 (0x11d) and the
+// generator 2. expTable is doubled so that products of logs don't need to be
+// reduced.
+var expTable, logTable = func() ([510]byte, [256]byte) {
+	var exp [510]byte
+	var log [256]byte
+	x := 1
+	for i := 0; i < 255; i++ {
+		exp[i] = byte(x)
+		exp[i+255] = byte(x)
+		log[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+	return exp, log
+}()
+
+func mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+int(logTable[b])]
+}
+
+// inv returns the multiplicative inverse of a, which must be nonzero.
+func inv(a byte) byte {
+	return expTable[255-int(logTable[a])]
+}
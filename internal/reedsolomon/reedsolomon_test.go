@@ -0,0 +1,62 @@
+package reedsolomon
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestField(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		require.Equal(t, byte(1), mul(byte(a), inv(byte(a))), a)
+		require.Equal(t, byte(0), mul(byte(a), 0))
+	}
+	// 2 * 0xc7 = 0x18e, reduced by 0x11d
+	require.Equal(t, byte(0x93), mul(2, 0xc7))
+}
+
+func TestReconstruct(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, tc := range []struct{ data, parity int }{{1, 1}, {4, 2}, {10, 4}, {200, 56}, {3, 0}} {
+		code, err := New(tc.data, tc.parity)
+		require.NoError(t, err)
+
+		shards := make([][]byte, tc.data+tc.parity)
+		for i := range shards {
+			shards[i] = make([]byte, 37)
+			if i < tc.data {
+				rng.Read(shards[i])
+			}
+		}
+		require.NoError(t, code.Encode(shards))
+		original := make([][]byte, len(shards))
+		for i := range shards {
+			original[i] = bytes.Clone(shards[i])
+		}
+
+		// drop as many random shards as there are parity shards
+		for _, i := range rng.Perm(len(shards))[:tc.parity] {
+			shards[i] = nil
+		}
+		require.NoError(t, code.Reconstruct(shards))
+		require.Equal(t, original, shards)
+
+		if tc.parity > 0 {
+			for _, i := range rng.Perm(len(shards))[:tc.parity+1] {
+				shards[i] = nil
+			}
+			require.ErrorIs(t, code.Reconstruct(shards), ErrTooFewShards)
+		}
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	_, err := New(0, 2)
+	require.Error(t, err)
+	_, err = New(200, 57)
+	require.Error(t, err)
+	_, err = New(2, -1)
+	require.Error(t, err)
+}
@@ -0,0 +1,187 @@
+// Package kat runs known-answer tests of ring signatures from request and
+// response files in the style of the NIST CAVP .req/.rsp files, for
+// certification-style validation runs, and so that regressions show up as
+// diffs of text files rather than in test logs.
+//
+// A file is made of header comments, sections and records:
+//
+//	# ring-go sign KAT
+//
+//	[Curve = ed25519]
+//
+//	Count = 0
+//	Ring = 5866...
+//	Priv = 1a2b...
+//	Msg = 0000...
+//
+// Sections start with one or more "[Name = Value]" parameter lines, and
+// records are "Name = Value" lines separated by blank lines. Lines starting
+// with "#" are comments; those before the first section are kept as the
+// header. Names are case-insensitive.
+//
+// Sign and Verify compute the response file of a request file, by adding the
+// output fields to each record, and Check recomputes a response file and
+// reports the records whose outputs differ.
+package kat
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Field is a "Name = Value" line.
+type Field struct {
+	Name, Value string
+}
+
+// Record is a list of fields, in order.
+type Record []Field
+
+// Get returns the value of the field with the given name.
+func (r Record) Get(name string) (string, bool) {
+	for _, f := range r {
+		if strings.EqualFold(f.Name, name) {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// Set sets the value of the field with the given name, adding it at the end if
+// it's not present.
+func (r *Record) Set(name, value string) {
+	for i, f := range *r {
+		if strings.EqualFold(f.Name, name) {
+			(*r)[i].Value = value
+			return
+		}
+	}
+	*r = append(*r, Field{Name: name, Value: value})
+}
+
+// Section is a set of records sharing parameters, such as the curve.
+type Section struct {
+	Params  Record
+	Records []Record
+}
+
+// File is a request or response file.
+type File struct {
+	// Header holds the comments at the top of the file, without their "#".
+	Header   []string
+	Sections []*Section
+}
+
+// Parse parses a request or response file.
+func Parse(r io.Reader) (*File, error) {
+	var (
+		f       = new(File)
+		section *Section
+		record  Record
+	)
+	endRecord := func() {
+		if len(record) > 0 {
+			section.Records = append(section.Records, record)
+			record = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			if section != nil {
+				endRecord()
+			}
+		case strings.HasPrefix(line, "#"):
+			if section == nil {
+				f.Header = append(f.Header, strings.TrimSpace(strings.TrimPrefix(line, "#")))
+			}
+		case strings.HasPrefix(line, "["):
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section parameter", n)
+			}
+			field, err := parseField(line[1 : len(line)-1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			if section != nil {
+				endRecord()
+			}
+			if section == nil || len(section.Records) > 0 {
+				section = new(Section)
+				f.Sections = append(f.Sections, section)
+			}
+			section.Params = append(section.Params, field)
+		default:
+			field, err := parseField(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			if section == nil {
+				section = new(Section)
+				f.Sections = append(f.Sections, section)
+			}
+			record = append(record, field)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if section != nil {
+		endRecord()
+	}
+	return f, nil
+}
+
+func parseField(line string) (Field, error) {
+	name, value, ok := strings.Cut(line, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return Field{}, fmt.Errorf("expected \"Name = Value\", got %q", line)
+	}
+	return Field{Name: name, Value: strings.TrimSpace(value)}, nil
+}
+
+// WriteTo writes the file in the format read by Parse.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	for _, line := range f.Header {
+		fmt.Fprintf(&buf, "# %s\n", line)
+	}
+	if len(f.Header) > 0 {
+		buf.WriteByte('\n')
+	}
+	for _, section := range f.Sections {
+		for _, p := range section.Params {
+			fmt.Fprintf(&buf, "[%s = %s]\n", p.Name, p.Value)
+		}
+		if len(section.Params) > 0 {
+			buf.WriteByte('\n')
+		}
+		for _, record := range section.Records {
+			for _, field := range record {
+				fmt.Fprintf(&buf, "%s = %s\n", field.Name, field.Value)
+			}
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.WriteTo(w)
+}
+
+// clone returns a deep copy of the file.
+func (f *File) clone() *File {
+	out := &File{Header: append([]string(nil), f.Header...)}
+	for _, s := range f.Sections {
+		section := &Section{Params: append(Record(nil), s.Params...)}
+		for _, r := range s.Records {
+			section.Records = append(section.Records, append(Record(nil), r...))
+		}
+		out.Sections = append(out.Sections, section)
+	}
+	return out
+}
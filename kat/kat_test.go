@@ -0,0 +1,62 @@
+package kat
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	in := `# header
+#   second line
+
+[Curve = ed25519]
+[Size = 2]
+
+count = 0
+Msg = 00
+
+# comment
+Count = 1
+Msg = 01
+[Curve = secp256k1]
+Count = 2
+`
+	f, err := Parse(strings.NewReader(in))
+	require.NoError(t, err)
+	require.Equal(t, []string{"header", "second line"}, f.Header)
+	require.Len(t, f.Sections, 2)
+	require.Equal(t, Record{{"Curve", "ed25519"}, {"Size", "2"}}, f.Sections[0].Params)
+	require.Len(t, f.Sections[0].Records, 2)
+	count, ok := f.Sections[0].Records[0].Get("Count")
+	require.True(t, ok)
+	require.Equal(t, "0", count)
+	require.Equal(t, Record{{"Count", "2"}}, f.Sections[1].Records[0])
+
+	f.Sections[0].Records[1].Set("msg", "02")
+	f.Sections[0].Records[1].Set("Result", "P")
+	require.Equal(t, Record{{"Count", "1"}, {"Msg", "02"}, {"Result", "P"}}, f.Sections[0].Records[1])
+
+	_, err = Parse(strings.NewReader("[Curve = ed25519\n"))
+	require.ErrorContains(t, err, "line 1")
+	_, err = Parse(strings.NewReader("Count = 0\nMsg\n"))
+	require.ErrorContains(t, err, "line 2")
+}
+
+func TestWriteTo(t *testing.T) {
+	for _, name := range []string{"sign.req", "sign.rsp", "verify.req", "verify.rsp"} {
+		want, err := os.ReadFile("testdata/" + name)
+		require.NoError(t, err)
+		f, err := Parse(bytes.NewReader(want))
+		require.NoError(t, err)
+
+		var got bytes.Buffer
+		n, err := f.WriteTo(&got)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(want)), n)
+		require.Equal(t, string(want), got.String(), name)
+	}
+}
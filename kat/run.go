@@ -0,0 +1,186 @@
+package kat
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// Results of verification records.
+const (
+	Pass = "P"
+	Fail = "F"
+)
+
+// Operation computes the response file of a request file.
+type Operation func(req *File) (*File, error)
+
+var (
+	_ Operation = Sign
+	_ Operation = Verify
+)
+
+// Sign computes the response file of a sign request file. Each section has a
+// Curve parameter ("secp256k1" or "ed25519"), and each record the hex-encoded
+// fields Ring (as returned by ring.Ring.Bytes), Priv (the private key's scalar
+// encoding) and Msg (32 bytes). The response adds the field Sig, the
+// serialized signature created with ring.WithDeterministicNonces.
+//
+// Records that already have a Sig field, eg. in a response file, have it
+// replaced.
+func Sign(req *File) (*File, error) {
+	rsp := req.clone()
+	for _, section := range rsp.Sections {
+		curveID, err := sectionCurve(section)
+		if err != nil {
+			return nil, err
+		}
+		curve, err := curveID.Curve()
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range section.Records {
+			record := &section.Records[i]
+			sig, err := signRecord(curve, curveID, *record)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", recordName(*record, i), err)
+			}
+			record.Set("Sig", hex.EncodeToString(sig))
+		}
+	}
+	return rsp, nil
+}
+
+func signRecord(curve ring.Curve, curveID ring.CurveID, record Record) ([]byte, error) {
+	ringBytes, err := hexField(record, "Ring", 0)
+	if err != nil {
+		return nil, err
+	}
+	privBytes, err := hexField(record, "Priv", 0)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := hexField(record, "Msg", 32)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := ring.RingFromBytes(curveID, ringBytes)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := curve.DecodeToScalar(privBytes)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := r.Sign([32]byte(msg), priv, ring.WithDeterministicNonces())
+	if err != nil {
+		return nil, err
+	}
+	return sig.Serialize()
+}
+
+// Verify computes the response file of a verify request file. Each section has
+// a Curve parameter, and each record the hex-encoded fields Msg (32 bytes) and
+// Sig (a serialized signature). The response adds the field Result, which is
+// Pass if the signature decodes and is valid for the message, and Fail
+// otherwise.
+//
+// Records that already have a Result field, eg. in a response file, have it
+// replaced.
+func Verify(req *File) (*File, error) {
+	rsp := req.clone()
+	for _, section := range rsp.Sections {
+		curveID, err := sectionCurve(section)
+		if err != nil {
+			return nil, err
+		}
+		curve, err := curveID.Curve()
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range section.Records {
+			record := &section.Records[i]
+			msg, err := hexField(*record, "Msg", 32)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", recordName(*record, i), err)
+			}
+			sigBytes, err := hexField(*record, "Sig", 0)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", recordName(*record, i), err)
+			}
+
+			result := Fail
+			sig := new(ring.RingSig)
+			if sig.Deserialize(curve, sigBytes) == nil && sig.Verify([32]byte(msg)) {
+				result = Pass
+			}
+			record.Set("Result", result)
+		}
+	}
+	return rsp, nil
+}
+
+// Check recomputes the response file with the operation from its inputs, and
+// returns an error listing the records whose outputs differ from those in the
+// file, if any.
+func Check(rsp *File, op Operation) error {
+	got, err := op(rsp)
+	if err != nil {
+		return err
+	}
+
+	var mismatches []string
+	for s, section := range rsp.Sections {
+		for i, want := range section.Records {
+			for _, field := range got.Sections[s].Records[i] {
+				if value, _ := want.Get(field.Name); value != field.Value {
+					mismatches = append(mismatches, fmt.Sprintf("section %d, %s: %s = %s, want %s",
+						s, recordName(want, i), field.Name, field.Value, value))
+				}
+			}
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%d mismatches:\n%s", len(mismatches), strings.Join(mismatches, "\n"))
+	}
+	return nil
+}
+
+func sectionCurve(section *Section) (ring.CurveID, error) {
+	name, ok := section.Params.Get("Curve")
+	if !ok {
+		return ring.CurveUnknown, errors.New("section has no Curve parameter")
+	}
+	return ring.ParseCurveID(name)
+}
+
+// hexField returns the decoded value of a hex-encoded field, which must have
+// the given length unless it's 0.
+func hexField(record Record, name string, length int) ([]byte, error) {
+	value, ok := record.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("missing field %s", name)
+	}
+	b, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %w", name, err)
+	}
+	if length != 0 && len(b) != length {
+		return nil, fmt.Errorf("field %s must be %d bytes, got %d", name, length, len(b))
+	}
+	return b, nil
+}
+
+// recordName identifies a record in errors, by its Count field if it has one.
+func recordName(record Record, i int) string {
+	if count, ok := record.Get("Count"); ok {
+		return "Count = " + count
+	}
+	return fmt.Sprintf("record %d", i)
+}
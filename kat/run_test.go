@@ -0,0 +1,74 @@
+package kat
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readFile(t *testing.T, name string) *File {
+	in, err := os.Open("testdata/" + name)
+	require.NoError(t, err)
+	defer in.Close()
+	f, err := Parse(in)
+	require.NoError(t, err)
+	return f
+}
+
+// TestResponses regenerates the response files from the request files; if
+// the signatures change, the diff shows which records regressed.
+func TestResponses(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		op   Operation
+	}{
+		{"sign", Sign},
+		{"verify", Verify},
+	} {
+		rsp, err := tc.op(readFile(t, tc.name+".req"))
+		require.NoError(t, err)
+
+		var got bytes.Buffer
+		_, err = rsp.WriteTo(&got)
+		require.NoError(t, err)
+		want, err := os.ReadFile("testdata/" + tc.name + ".rsp")
+		require.NoError(t, err)
+		require.Equal(t, string(want), got.String(), tc.name)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	require.NoError(t, Check(readFile(t, "sign.rsp"), Sign))
+	require.NoError(t, Check(readFile(t, "verify.rsp"), Verify))
+	require.NoError(t, Check(readFile(t, "legacy.rsp"), Verify))
+
+	// the signed responses verify
+	verified, err := Verify(readFile(t, "sign.rsp"))
+	require.NoError(t, err)
+	for _, s := range verified.Sections {
+		for _, r := range s.Records {
+			result, _ := r.Get("Result")
+			require.Equal(t, Pass, result)
+		}
+	}
+
+	rsp := readFile(t, "verify.rsp")
+	rsp.Sections[1].Records[0].Set("Result", Fail)
+	err = Check(rsp, Verify)
+	require.ErrorContains(t, err, "1 mismatches")
+	require.ErrorContains(t, err, "Count = 4: Result = P, want F")
+}
+
+func TestSign_Errors(t *testing.T) {
+	req := readFile(t, "sign.req")
+	req.Sections[0].Records[1].Set("Msg", "00")
+	_, err := Sign(req)
+	require.ErrorContains(t, err, "Count = 1: field Msg must be 32 bytes")
+
+	req = readFile(t, "sign.req")
+	req.Sections[0].Params = nil
+	_, err = Sign(req)
+	require.ErrorContains(t, err, "Curve")
+}
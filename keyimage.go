@@ -0,0 +1,110 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+const keyImageProofDomain = "ring-go/key-image-proof"
+
+// ErrInvalidKeyImage is returned by SignWithKeyImage when the key image or its
+// proof doesn't match the private key.
+var ErrInvalidKeyImage = errors.New("invalid key image")
+
+// KeyImageProof proves that a key image I was created with the private key x
+// of a public key P = x*G, ie. that I = x*H_p(P), without revealing x. It's a
+// Chaum-Pedersen proof of equality of the discrete logarithms of P and I.
+//
+// It lets the key image be computed separately from signing, eg. inside an HSM
+// or in an MPC ceremony, and then used by SignWithKeyImage.
+type KeyImageProof struct {
+	e, s types.Scalar
+}
+
+// ProveKeyImage computes the key image of the given private key, and a proof
+// that it's correct.
+func ProveKeyImage(curve types.Curve, privKey types.Scalar) (image types.Point, proof *KeyImageProof, err error) {
+	defer recoverInternal(&err)
+
+	if privKey.IsZero() {
+		return nil, nil, errors.New("private key is zero")
+	}
+
+	pubkey := curve.ScalarBaseMul(privKey)
+	hp := hashToCurve(pubkey)
+	image = curve.ScalarMul(privKey, hp)
+
+	k := curve.NewRandomScalar()
+	e := keyImageChallenge(curve, pubkey, image, curve.ScalarBaseMul(k), curve.ScalarMul(k, hp))
+	return image, &KeyImageProof{e: e, s: k.Sub(e.Mul(privKey))}, nil
+}
+
+// Verify returns whether the proof shows that image is the key image of the
+// private key of pubkey.
+func (p *KeyImageProof) Verify(curve types.Curve, pubkey, image types.Point) (ok bool) {
+	defer recoverFalse(&ok)
+
+	if CurveIDOfPoint(image) != CurveIDOf(curve) || CurveIDOfPoint(pubkey) != CurveIDOf(curve) {
+		return false
+	}
+	if isIdentity(pubkey) || isIdentity(image) || !isTorsionFree(image) {
+		return false
+	}
+	t1 := doubleScalarBaseMul(curve, p.e, pubkey, p.s)
+	t2 := curve.ScalarMul(p.s, hashToCurve(pubkey)).Add(curve.ScalarMul(p.e, image))
+	return keyImageChallenge(curve, pubkey, image, t1, t2).Eq(p.e)
+}
+
+// Bytes returns the encoding of the proof: its two scalars, concatenated.
+func (p *KeyImageProof) Bytes() []byte {
+	return append(p.e.Encode(), p.s.Encode()...)
+}
+
+// DecodeKeyImageProof decodes a proof encoded by KeyImageProof.Bytes.
+func DecodeKeyImageProof(curve types.Curve, in []byte) (*KeyImageProof, error) {
+	scalarLen := ScalarSize(curve)
+	if len(in) != 2*scalarLen {
+		return nil, fmt.Errorf("key image proof must be %d bytes, got %d", 2*scalarLen, len(in))
+	}
+	e, err := curve.DecodeToScalar(in[:scalarLen])
+	if err != nil {
+		return nil, err
+	}
+	s, err := curve.DecodeToScalar(in[scalarLen:])
+	if err != nil {
+		return nil, err
+	}
+	return &KeyImageProof{e: e, s: s}, nil
+}
+
+func keyImageChallenge(curve types.Curve, pubkey, image, t1, t2 types.Point) types.Scalar {
+	buf := []byte(keyImageProofDomain)
+	buf = append(buf, encodePoint(pubkey)...)
+	buf = append(buf, encodePoint(image)...)
+	buf = append(buf, encodePoint(t1)...)
+	buf = append(buf, encodePoint(t2)...)
+
+	e, err := curve.HashToScalar(buf)
+	if err != nil {
+		// this should not happen
+		panic(err)
+	}
+	return e
+}
+
+// SignWithKeyImage creates a ring signature like Sign, with a key image that
+// was computed beforehand, eg. inside an HSM or in a prior session, instead of
+// computing it from the private key. The proof must show that the image is the
+// signer's (see ProveKeyImage); it's checked before signing, and
+// ErrInvalidKeyImage is returned if it doesn't.
+func (r *Ring) SignWithKeyImage(m [32]byte, privKey types.Scalar, image types.Point, proof *KeyImageProof, opts ...SignOption) (*RingSig, error) {
+	if image == nil || proof == nil {
+		return nil, fmt.Errorf("%w: missing image or proof", ErrInvalidKeyImage)
+	}
+	return r.Sign(m, privKey, append(opts[:len(opts):len(opts)], func(o *signOptions) {
+		o.keyImage = image
+		o.keyImageProof = proof
+	})...)
+}
@@ -0,0 +1,61 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWithKeyImage(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 5, privKey, 3)
+		require.NoError(t, err)
+
+		// eg. computed by an HSM
+		image, proof, err := ProveKeyImage(curve, privKey)
+		require.NoError(t, err)
+		pubkey := curve.ScalarBaseMul(privKey)
+		require.True(t, proof.Verify(curve, pubkey, image))
+
+		decoded, err := DecodeKeyImageProof(curve, proof.Bytes())
+		require.NoError(t, err)
+		require.True(t, decoded.Verify(curve, pubkey, image))
+
+		sig, err := keyring.SignWithKeyImage(testMsg, privKey, image, decoded, WithChainID(1))
+		require.NoError(t, err)
+		require.True(t, sig.Verify(testMsg))
+		require.True(t, sig.KeyImage().Equals(image))
+
+		plain, err := keyring.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		require.True(t, Link(sig, plain))
+	}
+}
+
+func TestSignWithKeyImage_Rejects(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 3, privKey, 0)
+	require.NoError(t, err)
+	pubkey := curve.ScalarBaseMul(privKey)
+
+	image, proof, err := ProveKeyImage(curve, privKey)
+	require.NoError(t, err)
+	otherImage, otherProof, err := ProveKeyImage(curve, curve.NewRandomScalar())
+	require.NoError(t, err)
+
+	require.False(t, otherProof.Verify(curve, pubkey, otherImage))
+	require.False(t, proof.Verify(curve, pubkey, otherImage))
+	require.False(t, proof.Verify(Ed25519(), pubkey, image))
+
+	_, err = keyring.SignWithKeyImage(testMsg, privKey, otherImage, otherProof)
+	require.ErrorIs(t, err, ErrInvalidKeyImage)
+	_, err = keyring.SignWithKeyImage(testMsg, privKey, otherImage, proof)
+	require.ErrorIs(t, err, ErrInvalidKeyImage)
+	_, err = keyring.SignWithKeyImage(testMsg, privKey, image, nil)
+	require.ErrorIs(t, err, ErrInvalidKeyImage)
+
+	_, err = DecodeKeyImageProof(curve, proof.Bytes()[1:])
+	require.Error(t, err)
+}
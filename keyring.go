@@ -0,0 +1,168 @@
+package ring
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// keyringFileHeader is the first line written by WriteKeyring.
+const keyringFileHeader = "# ring-go keyring v1"
+
+// ReadKeyring reads a ring from a keyring file, a text format listing one
+// public key per line as a curve name followed by the hex-encoded compressed
+// public key:
+//
+//	# ring-go keyring v1
+//	ed25519 d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a  # alice
+//	ed25519 3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c  # bob
+//
+// Text after a '#' is a comment, and blank lines are ignored. The keys must all
+// be on the same curve, and distinct. The ring has the keys in the order of
+// the file. It also returns the comment on the line of each key, if any.
+func ReadKeyring(r io.Reader) (*Ring, []string, error) {
+	var (
+		curve    types.Curve
+		curveID  CurveID
+		pubkeys  []types.Point
+		comments []string
+		seen     = make(map[string]bool)
+	)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text, comment, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, nil, fmt.Errorf("line %d: expected a curve and a public key", line)
+		}
+
+		id, err := ParseCurveID(fields[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if curve == nil {
+			curveID = id
+			if curve, err = id.Curve(); err != nil {
+				return nil, nil, err
+			}
+		} else if id != curveID {
+			return nil, nil, fmt.Errorf("line %d: all keys must be on the same curve", line)
+		}
+
+		b, err := hex.DecodeString(fields[1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		pk, err := curve.DecodeToPoint(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: invalid public key: %w", line, err)
+		}
+
+		enc := string(pk.Encode())
+		if seen[enc] {
+			return nil, nil, fmt.Errorf("line %d: duplicate public key", line)
+		}
+		seen[enc] = true
+
+		pubkeys = append(pubkeys, pk)
+		comments = append(comments, strings.TrimSpace(comment))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if curve == nil {
+		return nil, nil, errors.New("keyring is empty")
+	}
+
+	ring, err := NewFixedKeyRingFromPublicKeys(curve, pubkeys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ring, comments, nil
+}
+
+// WriteKeyring writes the ring in the keyring file format read by ReadKeyring.
+// comments, if not nil, has a single-line comment for each member of the ring,
+// which is omitted if empty.
+func WriteKeyring(w io.Writer, r *Ring, comments []string) error {
+	if comments != nil && len(comments) != len(r.pubkeys) {
+		return errors.New("number of comments does not match the ring size")
+	}
+
+	id := CurveIDOf(r.curve)
+	if id == CurveUnknown {
+		return errors.New("unsupported curve")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(keyringFileHeader + "\n")
+	for i, pk := range r.pubkeys {
+		fmt.Fprintf(&buf, "%s %x", id, encodePoint(pk))
+		if comments != nil && comments[i] != "" {
+			if strings.ContainsAny(comments[i], "\r\n") {
+				return fmt.Errorf("comment %d must be a single line", i)
+			}
+			fmt.Fprintf(&buf, "  # %s", comments[i])
+		}
+		buf.WriteByte('\n')
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// LoadKeyringFile reads a ring from the keyring file at path. See ReadKeyring.
+func LoadKeyringFile(path string) (*Ring, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ring, _, err := ReadKeyring(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return ring, nil
+}
+
+// SaveKeyringFile writes the ring to a keyring file at path, with optional
+// comments as in WriteKeyring. The file is replaced atomically, so readers
+// never see a partially written keyring.
+func SaveKeyringFile(path string, r *Ring, comments []string) error {
+	var buf bytes.Buffer
+	if err := WriteKeyring(&buf, r, comments); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,106 @@
+package ring
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadKeyring(t *testing.T) {
+	curve := Secp256k1()
+	a := curve.ScalarBaseMul(curve.NewRandomScalar())
+	b := curve.ScalarBaseMul(curve.NewRandomScalar())
+
+	keyring, comments, err := ReadKeyring(strings.NewReader(fmt.Sprintf(
+		"# comment\n\nsecp256k1 %x # alice\n  secp256k1   %x\n", a.Encode(), b.Encode())))
+	require.NoError(t, err)
+	require.Equal(t, 2, keyring.Size())
+	require.Equal(t, CurveSecp256k1, CurveIDOf(keyring.Curve()))
+	require.True(t, keyring.PublicKeys()[0].Equals(a))
+	require.True(t, keyring.PublicKeys()[1].Equals(b))
+	require.Equal(t, []string{"alice", ""}, comments)
+}
+
+func TestReadKeyring_RFC8032Keys(t *testing.T) {
+	keyring, comments, err := ReadKeyring(strings.NewReader(`# ring-go keyring v1
+ed25519 d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a  # alice
+ed25519 3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c  # bob
+`))
+	require.NoError(t, err)
+	require.Equal(t, 2, keyring.Size())
+	require.Equal(t, []string{"alice", "bob"}, comments)
+}
+
+func TestReadKeyring_Errors(t *testing.T) {
+	curve := Ed25519()
+	pk := fmt.Sprintf("%x", curve.ScalarBaseMul(curve.NewRandomScalar()).Encode())
+	secpPK := fmt.Sprintf("%x", Secp256k1().BasePoint().Encode())
+
+	for _, input := range []string{
+		"",
+		"# only comments\n",
+		"ed25519\n",
+		"ed25519 " + pk + " extra\n",
+		"p256 " + pk + "\n",
+		"ed25519 zz\n",
+		"ed25519 abcd\n",
+		"ed25519 " + pk + "\nsecp256k1 " + secpPK + "\n",
+		"ed25519 " + pk + "\ned25519 " + pk + "\n",
+	} {
+		_, _, err := ReadKeyring(strings.NewReader(input))
+		require.Error(t, err, input)
+	}
+}
+
+func TestWriteKeyring(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		keyring, err := NewKeyRing(curve, 3, curve.NewRandomScalar(), 0)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteKeyring(&buf, keyring, []string{"alice", "", "carol"}))
+		require.True(t, strings.HasPrefix(buf.String(), keyringFileHeader+"\n"))
+
+		read, comments, err := ReadKeyring(&buf)
+		require.NoError(t, err)
+		require.True(t, read.Equals(keyring))
+		require.Equal(t, []string{"alice", "", "carol"}, comments)
+
+		buf.Reset()
+		require.NoError(t, WriteKeyring(&buf, keyring, nil))
+		require.Error(t, WriteKeyring(&buf, keyring, []string{"alice"}))
+		require.Error(t, WriteKeyring(&buf, keyring, []string{"a\nb", "", ""}))
+	}
+}
+
+func TestKeyringFile(t *testing.T) {
+	curve := Ed25519()
+	keyring, err := NewKeyRing(curve, 4, curve.NewRandomScalar(), 1)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "maintainers.txt")
+	require.NoError(t, SaveKeyringFile(path, keyring, nil))
+	loaded, err := LoadKeyringFile(path)
+	require.NoError(t, err)
+	require.True(t, loaded.Equals(keyring))
+
+	// saving replaces the file
+	other, err := NewKeyRing(curve, 2, curve.NewRandomScalar(), 1)
+	require.NoError(t, err)
+	require.NoError(t, SaveKeyringFile(path, other, []string{"x", "y"}))
+	loaded, err = LoadKeyringFile(path)
+	require.NoError(t, err)
+	require.True(t, loaded.Equals(other))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	_, err = LoadKeyringFile(filepath.Join(t.TempDir(), "missing.txt"))
+	require.Error(t, err)
+}
@@ -0,0 +1,265 @@
+// Package libp2pring converts libp2p peer keys into ring members and signs
+// pubsub message payloads with ring signatures, so that peer-to-peer networks
+// can implement anonymous gossip authorship ("one of the validators said
+// this").
+//
+// It reads and writes the libp2p key formats directly, without depending on
+// go-libp2p: keys marshaled with crypto.MarshalPublicKey and
+// crypto.MarshalPrivateKey, and peer IDs of ed25519 and secp256k1 keys, which
+// embed the public key:
+//
+//	validators, err := libp2pring.NewRing(peerIDs)
+//	sig, err := libp2pring.SignMessage(validators, privKey, topic, payload)
+//	...
+//	rs, err := libp2pring.VerifyMessage(validators, topic, payload, sig)
+package libp2pring
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+
+	ring "github.com/pokt-network/ring-go"
+	"github.com/pokt-network/ring-go/internal/base58"
+)
+
+// Key types of the libp2p key protobuf messages.
+const (
+	keyTypeEd25519   = 1
+	keyTypeSecp256k1 = 2
+)
+
+// Multihash codes used by peer IDs.
+const (
+	multihashIdentity = 0x00
+	multihashSHA256   = 0x12
+)
+
+const messageDomain = "ring-go/libp2p/pubsub"
+
+// ErrWrongRing is returned by VerifyMessage when a message is signed with a
+// ring other than the expected one.
+var ErrWrongRing = errors.New("message is signed by a different ring")
+
+// UnmarshalPublicKey returns the ring member for a libp2p public key, as
+// marshaled by crypto.MarshalPublicKey. Only ed25519 and secp256k1 keys are
+// supported.
+func UnmarshalPublicKey(b []byte) (ring.Point, error) {
+	keyType, data, err := unmarshalKey(b)
+	if err != nil {
+		return nil, err
+	}
+
+	switch keyType {
+	case keyTypeEd25519:
+		return ring.PointFromEd25519PublicKey(data)
+	case keyTypeSecp256k1:
+		return ring.Secp256k1().DecodeToPoint(data)
+	default:
+		return nil, fmt.Errorf("unsupported key type %d", keyType)
+	}
+}
+
+// MarshalPublicKey returns the libp2p encoding of a ring member's public key,
+// as read by crypto.UnmarshalPublicKey.
+func MarshalPublicKey(pk ring.Point) ([]byte, error) {
+	switch ring.CurveIDOfPoint(pk) {
+	case ring.CurveEd25519:
+		return marshalKey(keyTypeEd25519, pk.Encode()), nil
+	case ring.CurveSecp256k1:
+		return marshalKey(keyTypeSecp256k1, pk.Encode()), nil
+	default:
+		return nil, fmt.Errorf("unsupported point type %T", pk)
+	}
+}
+
+// UnmarshalPrivateKey returns the private key for a libp2p private key, as
+// marshaled by crypto.MarshalPrivateKey. Only ed25519 and secp256k1 keys are
+// supported.
+func UnmarshalPrivateKey(b []byte) (ring.Scalar, error) {
+	keyType, data, err := unmarshalKey(b)
+	if err != nil {
+		return nil, err
+	}
+
+	switch keyType {
+	case keyTypeEd25519:
+		// libp2p ed25519 private keys are the seed followed by the public key
+		return ring.ScalarFromEd25519PrivateKey(data)
+	case keyTypeSecp256k1:
+		if len(data) != 32 {
+			return nil, errors.New("invalid secp256k1 private key length")
+		}
+		priv, err := ring.Secp256k1().DecodeToScalar(data)
+		if err != nil {
+			return nil, err
+		}
+		if priv.IsZero() {
+			return nil, errors.New("private key is zero")
+		}
+		return priv, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %d", keyType)
+	}
+}
+
+// PeerID returns the base58-encoded peer ID of a ring member: the identity
+// multihash of its marshaled public key.
+func PeerID(pk ring.Point) (string, error) {
+	b, err := MarshalPublicKey(pk)
+	if err != nil {
+		return "", err
+	}
+
+	mh := append([]byte{multihashIdentity, byte(len(b))}, b...)
+	return base58.Encode(mh), nil
+}
+
+// PublicKeyFromPeerID returns the ring member for a base58-encoded peer ID.
+// The peer ID must embed the public key, which is the case for ed25519 and
+// secp256k1 keys; peer IDs that are hashes of the key can't be converted.
+func PublicKeyFromPeerID(id string) (ring.Point, error) {
+	mh, err := base58.Decode(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid peer ID: %w", err)
+	}
+
+	code, n := binary.Uvarint(mh)
+	if n <= 0 {
+		return nil, errors.New("invalid peer ID multihash")
+	}
+	length, m := binary.Uvarint(mh[n:])
+	if m <= 0 || uint64(len(mh)-n-m) != length {
+		return nil, errors.New("invalid peer ID multihash")
+	}
+
+	switch code {
+	case multihashIdentity:
+		return UnmarshalPublicKey(mh[n+m:])
+	case multihashSHA256:
+		return nil, errors.New("peer ID is a hash of the public key, which is needed")
+	default:
+		return nil, fmt.Errorf("unsupported peer ID multihash %#x", code)
+	}
+}
+
+// NewRing builds a ring from peer IDs, in order. The keys must be on the same
+// curve, and distinct.
+func NewRing(peerIDs []string) (*ring.Ring, error) {
+	if len(peerIDs) == 0 {
+		return nil, errors.New("no peer IDs")
+	}
+
+	pubkeys := make([]ring.Point, len(peerIDs))
+	seen := make(map[string]bool, len(peerIDs))
+	for i, id := range peerIDs {
+		pk, err := PublicKeyFromPeerID(id)
+		if err != nil {
+			return nil, fmt.Errorf("peer %d: %w", i, err)
+		}
+		if i > 0 && ring.CurveIDOfPoint(pk) != ring.CurveIDOfPoint(pubkeys[0]) {
+			return nil, fmt.Errorf("peer %d: all keys must be on the same curve", i)
+		}
+		if seen[string(pk.Encode())] {
+			return nil, fmt.Errorf("peer %d: duplicate public key", i)
+		}
+		seen[string(pk.Encode())] = true
+		pubkeys[i] = pk
+	}
+
+	curve, err := ring.CurveIDOfPoint(pubkeys[0]).Curve()
+	if err != nil {
+		return nil, err
+	}
+	return ring.NewFixedKeyRingFromPublicKeys(curve, pubkeys)
+}
+
+// Message returns the message signed for a pubsub payload published on the
+// topic.
+func Message(topic string, data []byte) [32]byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte(messageDomain))
+	_, _ = h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(topic))))
+	_, _ = h.Write([]byte(topic))
+	_, _ = h.Write(data)
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// SignMessage ring-signs a pubsub payload published on the topic with the
+// private key of one of the members of the ring, and returns the serialized
+// signature, eg. to be sent along with the payload.
+func SignMessage(r *ring.Ring, privKey ring.Scalar, topic string, data []byte, opts ...ring.SignOption) ([]byte, error) {
+	sig, err := r.Sign(Message(topic, data), privKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Serialize()
+}
+
+// VerifyMessage verifies a serialized ring signature of a pubsub payload
+// published on the topic, which must be signed with the expected ring. It
+// returns the decoded signature, whose key image links messages by the same
+// peer.
+func VerifyMessage(expected *ring.Ring, topic string, data, sig []byte) (*ring.RingSig, error) {
+	rs := new(ring.RingSig)
+	if err := rs.Deserialize(expected.Curve(), sig); err != nil {
+		return nil, err
+	}
+	if !rs.Ring().Equals(expected) {
+		return nil, ErrWrongRing
+	}
+	if err := rs.VerifyWithPolicy(Message(topic, data), nil); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// marshalKey returns the protobuf encoding of a libp2p key message:
+//
+//	message PublicKey { required KeyType Type = 1; required bytes Data = 2; }
+func marshalKey(keyType uint64, data []byte) []byte {
+	b := []byte{0x08}
+	b = binary.AppendUvarint(b, keyType)
+	b = append(b, 0x12)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// unmarshalKey decodes the protobuf encoding of a libp2p key message.
+func unmarshalKey(b []byte) (keyType uint64, data []byte, err error) {
+	var hasType, hasData bool
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return 0, nil, errors.New("malformed key")
+		}
+		b = b[n:]
+
+		switch tag {
+		case 0x08: // field 1, varint
+			if keyType, n = binary.Uvarint(b); n <= 0 {
+				return 0, nil, errors.New("malformed key type")
+			}
+			b, hasType = b[n:], true
+		case 0x12: // field 2, length-delimited
+			length, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				return 0, nil, errors.New("malformed key data")
+			}
+			data, hasData = b[n:n+int(length)], true
+			b = b[n+int(length):]
+		default:
+			return 0, nil, fmt.Errorf("unexpected key field %#x", tag)
+		}
+	}
+
+	if !hasType || !hasData {
+		return 0, nil, errors.New("incomplete key")
+	}
+	return keyType, data, nil
+}
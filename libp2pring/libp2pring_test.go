@@ -0,0 +1,121 @@
+package libp2pring
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+	"github.com/pokt-network/ring-go/internal/base58"
+)
+
+func TestPeerID(t *testing.T) {
+	for curve, prefix := range map[ring.Curve]string{
+		ring.Ed25519():   "12D3KooW",
+		ring.Secp256k1(): "16Uiu2HA",
+	} {
+		pk := curve.ScalarBaseMul(curve.NewRandomScalar())
+		id, err := PeerID(pk)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(id, prefix), id)
+
+		parsed, err := PublicKeyFromPeerID(id)
+		require.NoError(t, err)
+		require.True(t, parsed.Equals(pk))
+	}
+}
+
+func TestPublicKeyFromPeerID_Invalid(t *testing.T) {
+	// RSA peer IDs are hashes of the key
+	hashed := base58.Encode(append([]byte{0x12, 0x20}, make([]byte, 32)...))
+	for _, id := range []string{"", "0", hashed, base58.Encode([]byte{0x00, 0x05, 1})} {
+		_, err := PublicKeyFromPeerID(id)
+		require.Error(t, err, id)
+	}
+}
+
+func TestUnmarshalKeys(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	pk, err := UnmarshalPublicKey(marshalKey(keyTypeEd25519, pub))
+	require.NoError(t, err)
+	require.Equal(t, []byte(pub), pk.Encode())
+
+	privKey, err := UnmarshalPrivateKey(marshalKey(keyTypeEd25519, priv))
+	require.NoError(t, err)
+	require.True(t, ring.Ed25519().ScalarBaseMul(privKey).Equals(pk))
+
+	secpPriv := ring.Secp256k1().NewRandomScalar()
+	privKey, err = UnmarshalPrivateKey(marshalKey(keyTypeSecp256k1, secpPriv.Encode()))
+	require.NoError(t, err)
+	require.True(t, privKey.Eq(secpPriv))
+
+	// fields can be in any order
+	b := append([]byte{0x12, 32}, pub...)
+	pk, err = UnmarshalPublicKey(append(b, 0x08, keyTypeEd25519))
+	require.NoError(t, err)
+	require.Equal(t, []byte(pub), pk.Encode())
+
+	for _, b := range [][]byte{
+		nil,
+		{0x08, 0x00, 0x12, 0x01, 0x00},           // RSA
+		{0x08, keyTypeEd25519},                   // no data
+		{0x08, keyTypeEd25519, 0x12, 0x20, 0x00}, // short data
+		{0x1a, 0x00},
+	} {
+		_, err := UnmarshalPublicKey(b)
+		require.Error(t, err)
+	}
+}
+
+func TestSignAndVerifyMessage(t *testing.T) {
+	for _, curve := range []ring.Curve{ring.Ed25519(), ring.Secp256k1()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := ring.NewKeyRing(curve, 5, privKey, 3)
+		require.NoError(t, err)
+
+		ids := make([]string, keyring.Size())
+		for i, pk := range keyring.PublicKeys() {
+			ids[i], err = PeerID(pk)
+			require.NoError(t, err)
+		}
+		validators, err := NewRing(ids)
+		require.NoError(t, err)
+		require.True(t, validators.Equals(keyring))
+
+		sig, err := SignMessage(validators, privKey, "blocks", []byte("payload"))
+		require.NoError(t, err)
+
+		rs, err := VerifyMessage(validators, "blocks", []byte("payload"), sig)
+		require.NoError(t, err)
+		require.True(t, rs.Ring().Equals(validators))
+
+		_, err = VerifyMessage(validators, "other", []byte("payload"), sig)
+		require.ErrorIs(t, err, ring.ErrInvalidSignature)
+		_, err = VerifyMessage(validators, "blocks", []byte("payload!"), sig)
+		require.ErrorIs(t, err, ring.ErrInvalidSignature)
+
+		other, err := ring.NewKeyRing(curve, 5, privKey, 3)
+		require.NoError(t, err)
+		_, err = VerifyMessage(other, "blocks", []byte("payload"), sig)
+		require.ErrorIs(t, err, ErrWrongRing)
+	}
+}
+
+func TestNewRing_Invalid(t *testing.T) {
+	edID, err := PeerID(ring.Ed25519().BasePoint())
+	require.NoError(t, err)
+	secpID, err := PeerID(ring.Secp256k1().BasePoint())
+	require.NoError(t, err)
+
+	_, err = NewRing(nil)
+	require.Error(t, err)
+	_, err = NewRing([]string{edID, secpID})
+	require.Error(t, err)
+	_, err = NewRing([]string{edID, edID})
+	require.Error(t, err)
+}
@@ -0,0 +1,307 @@
+// Package manifest implements signed ring manifests, which let a group
+// operator distribute rings to signers and verifiers.
+//
+// A Manifest lists the members of a ring for a given epoch, along with the
+// ring's curve and hash, and is signed by the operator with a plain ed25519 or
+// ECDSA key. Recipients load it with Load, which checks the operator's
+// signature and the epoch, and returns a ring that is ready to use:
+//
+//	m, err := manifest.New(keyring, epoch)
+//	signed, err := m.Sign(operatorKey)
+//	data, err := signed.Marshal()
+//	...
+//	keyring, err := manifest.Load(data, operatorPubKey, epoch)
+//
+// A Precompute lists the hash-to-curve values of a ring's members, so that the
+// operator of a large ring computes them once for all its verifiers, which
+// load them with LoadPrecompute.
+package manifest
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+const (
+	formatVersion = 1
+
+	// signingDomain is prepended to the encoded manifest when signing it.
+	signingDomain = "ring-go/manifest"
+)
+
+// Manifest describes a ring.
+type Manifest struct {
+	// CurveID is the curve of the ring's public keys.
+	CurveID ring.CurveID
+	// Epoch is the epoch the ring is valid for.
+	Epoch uint64
+	// RingHash is the hash of the ring, as returned by ring.Ring.Hash.
+	RingHash [32]byte
+	// Members are the encoded public keys of the ring, in order.
+	Members [][]byte
+}
+
+// SignedManifest is a Manifest signed by an operator.
+type SignedManifest struct {
+	Manifest
+	// Signature is the operator's signature over the encoded manifest; see
+	// Manifest.Sign.
+	Signature []byte
+}
+
+// New returns a manifest for the given ring and epoch.
+func New(keyring *ring.Ring, epoch uint64) (*Manifest, error) {
+	id := ring.CurveIDOf(keyring.Curve())
+	if id == ring.CurveUnknown {
+		return nil, errors.New("unsupported curve")
+	}
+
+	pubkeys := keyring.PublicKeys()
+	members := make([][]byte, len(pubkeys))
+	for i, pk := range pubkeys {
+		members[i] = pk.Encode()
+	}
+
+	return &Manifest{
+		CurveID:  id,
+		Epoch:    epoch,
+		RingHash: keyring.Hash(),
+		Members:  members,
+	}, nil
+}
+
+// Ring decodes the manifest's members into a ring, checks that it matches the
+// manifest's ring hash, and precomputes it, so it's ready for signing and
+// verification.
+func (m *Manifest) Ring() (*ring.Ring, error) {
+	curve, err := m.CurveID.Curve()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(m.Members))
+	pubkeys := make([]ring.Point, len(m.Members))
+	for i, member := range m.Members {
+		if _, ok := seen[string(member)]; ok {
+			return nil, fmt.Errorf("duplicate member at index %d", i)
+		}
+		seen[string(member)] = struct{}{}
+
+		pubkeys[i], err = curve.DecodeToPoint(member)
+		if err != nil {
+			return nil, fmt.Errorf("invalid member at index %d: %w", i, err)
+		}
+	}
+
+	keyring, err := ring.NewFixedKeyRingFromPublicKeys(curve, pubkeys)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyring.Hash() != m.RingHash {
+		return nil, errors.New("ring hash mismatch")
+	}
+
+	keyring.Precompute()
+	return keyring, nil
+}
+
+// encode returns the canonical encoding of the manifest: the format version,
+// the curve ID, the epoch as a big-endian uint64, the ring hash, the number of
+// members as a big-endian uint32, and the members.
+func (m *Manifest) encode() ([]byte, error) {
+	curve, err := m.CurveID.Curve()
+	if err != nil {
+		return nil, err
+	}
+	pointLen := curve.CompressedPointSize()
+
+	out := []byte{formatVersion, byte(m.CurveID)}
+	out = binary.BigEndian.AppendUint64(out, m.Epoch)
+	out = append(out, m.RingHash[:]...)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(m.Members)))
+	for i, member := range m.Members {
+		if len(member) != pointLen {
+			return nil, fmt.Errorf("invalid member length at index %d", i)
+		}
+		out = append(out, member...)
+	}
+	return out, nil
+}
+
+// Sign signs the manifest with the operator's key, which must be an
+// ed25519.PrivateKey or an *ecdsa.PrivateKey. ECDSA signatures are ASN.1
+// encoded, over the SHA-256 digest of the manifest.
+func (m *Manifest) Sign(operator crypto.Signer) (*SignedManifest, error) {
+	encoded, err := m.encode()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signPayload(operator, signingDomain, encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedManifest{
+		Manifest:  *m,
+		Signature: sig,
+	}, nil
+}
+
+// VerifyManifest checks that the manifest was signed by the operator, whose
+// key must be an ed25519.PublicKey or an *ecdsa.PublicKey.
+func VerifyManifest(m *SignedManifest, operator crypto.PublicKey) error {
+	encoded, err := m.encode()
+	if err != nil {
+		return err
+	}
+	return verifyPayload(operator, signingDomain, encoded, m.Signature)
+}
+
+// signPayload signs the domain followed by the encoded document with the
+// operator's key; see Manifest.Sign.
+func signPayload(operator crypto.Signer, domain string, encoded []byte) ([]byte, error) {
+	payload := append([]byte(domain), encoded...)
+	switch operator.Public().(type) {
+	case ed25519.PublicKey:
+		return operator.Sign(rand.Reader, payload, crypto.Hash(0))
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		return operator.Sign(rand.Reader, digest[:], crypto.SHA256)
+	default:
+		return nil, fmt.Errorf("unsupported operator key type %T", operator.Public())
+	}
+}
+
+// verifyPayload checks a signature created by signPayload.
+func verifyPayload(operator crypto.PublicKey, domain string, encoded, sig []byte) error {
+	payload := append([]byte(domain), encoded...)
+
+	var ok bool
+	switch pub := operator.(type) {
+	case ed25519.PublicKey:
+		ok = len(pub) == ed25519.PublicKeySize && ed25519.Verify(pub, payload, sig)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		ok = ecdsa.VerifyASN1(pub, digest[:], sig)
+	default:
+		return fmt.Errorf("unsupported operator key type %T", operator)
+	}
+
+	if !ok {
+		return errors.New("invalid manifest signature")
+	}
+	return nil
+}
+
+// appendSignature appends the length of the signature as a big-endian uint16
+// and the signature.
+func appendSignature(out, sig []byte) ([]byte, error) {
+	if len(sig) > 0xffff {
+		return nil, errors.New("signature too long")
+	}
+	out = binary.BigEndian.AppendUint16(out, uint16(len(sig)))
+	return append(out, sig...), nil
+}
+
+// readSignature reads a signature appended by appendSignature, which must end
+// the input.
+func readSignature(r *bytes.Reader) ([]byte, error) {
+	var sigLen uint16
+	if err := binary.Read(r, binary.BigEndian, &sigLen); err != nil {
+		return nil, errors.New("input too short")
+	}
+	if r.Len() != int(sigLen) {
+		return nil, errors.New("invalid signature length")
+	}
+	sig := make([]byte, sigLen)
+	_, _ = r.Read(sig)
+	return sig, nil
+}
+
+// Marshal encodes the signed manifest as the encoded manifest, followed by the
+// length of the signature as a big-endian uint16 and the signature.
+func (m *SignedManifest) Marshal() ([]byte, error) {
+	out, err := m.encode()
+	if err != nil {
+		return nil, err
+	}
+	return appendSignature(out, m.Signature)
+}
+
+// Unmarshal decodes a signed manifest encoded with Marshal. It doesn't verify
+// the signature; see VerifyManifest and Load.
+func Unmarshal(data []byte) (*SignedManifest, error) {
+	r := bytes.NewReader(data)
+
+	var header struct {
+		Version  byte
+		CurveID  ring.CurveID
+		Epoch    uint64
+		RingHash [32]byte
+		Size     uint32
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, errors.New("input too short")
+	}
+
+	if header.Version != formatVersion {
+		return nil, fmt.Errorf("unsupported format version %d", header.Version)
+	}
+
+	curve, err := header.CurveID.Curve()
+	if err != nil {
+		return nil, err
+	}
+	pointLen := curve.CompressedPointSize()
+
+	if uint64(header.Size) > uint64(r.Len())/uint64(pointLen) {
+		return nil, errors.New("input too short")
+	}
+
+	m := &SignedManifest{
+		Manifest: Manifest{
+			CurveID:  header.CurveID,
+			Epoch:    header.Epoch,
+			RingHash: header.RingHash,
+			Members:  make([][]byte, header.Size),
+		},
+	}
+	for i := range m.Members {
+		m.Members[i] = make([]byte, pointLen)
+		_, _ = r.Read(m.Members[i])
+	}
+
+	if m.Signature, err = readSignature(r); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Load decodes a signed manifest, checks that it was signed by the operator
+// and is for the given epoch, and returns its precomputed ring.
+func Load(data []byte, operator crypto.PublicKey, epoch uint64) (*ring.Ring, error) {
+	m, err := Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := VerifyManifest(m, operator); err != nil {
+		return nil, err
+	}
+
+	if m.Epoch != epoch {
+		return nil, fmt.Errorf("manifest is for epoch %d, not %d", m.Epoch, epoch)
+	}
+
+	return m.Ring()
+}
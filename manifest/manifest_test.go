@@ -0,0 +1,128 @@
+package manifest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+var testMsg = [32]byte{1, 2, 3}
+
+func operatorKeys(t *testing.T) map[string]crypto.Signer {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return map[string]crypto.Signer{"ed25519": edKey, "ecdsa": ecKey}
+}
+
+func TestLoad(t *testing.T) {
+	for name, operator := range operatorKeys(t) {
+		for _, curve := range []ring.Curve{ring.Secp256k1(), ring.Ed25519()} {
+			privKey := curve.NewRandomScalar()
+			keyring, err := ring.NewKeyRing(curve, 5, privKey, 3)
+			require.NoError(t, err)
+
+			m, err := New(keyring, 42)
+			require.NoError(t, err)
+			signed, err := m.Sign(operator)
+			require.NoError(t, err, name)
+			require.NoError(t, VerifyManifest(signed, operator.Public()))
+
+			data, err := signed.Marshal()
+			require.NoError(t, err)
+
+			loaded, err := Load(data, operator.Public(), 42)
+			require.NoError(t, err)
+			require.True(t, loaded.Equals(keyring))
+
+			sig, err := loaded.Sign(testMsg, privKey)
+			require.NoError(t, err)
+			require.True(t, sig.Verify(testMsg))
+
+			_, err = Load(data, operator.Public(), 43)
+			require.EqualError(t, err, "manifest is for epoch 42, not 43")
+		}
+	}
+}
+
+func TestVerifyManifest_Invalid(t *testing.T) {
+	operators := operatorKeys(t)
+	curve := ring.Ed25519()
+	keyring, err := ring.NewKeyRing(curve, 3, curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+
+	m, err := New(keyring, 1)
+	require.NoError(t, err)
+	signed, err := m.Sign(operators["ed25519"])
+	require.NoError(t, err)
+
+	// wrong operator
+	require.Error(t, VerifyManifest(signed, operators["ecdsa"].Public()))
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	require.EqualError(t, VerifyManifest(signed, otherPub), "invalid manifest signature")
+
+	// tampered manifest
+	signed.Epoch++
+	require.EqualError(t, VerifyManifest(signed, operators["ed25519"].Public()), "invalid manifest signature")
+	signed.Epoch--
+
+	data, err := signed.Marshal()
+	require.NoError(t, err)
+	data[len(data)-len(signed.Signature)-3] ^= 1
+	_, err = Load(data, operators["ed25519"].Public(), 1)
+	require.Error(t, err)
+}
+
+func TestRing_Invalid(t *testing.T) {
+	curve := ring.Secp256k1()
+	keyring, err := ring.NewKeyRing(curve, 3, curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+
+	m, err := New(keyring, 1)
+	require.NoError(t, err)
+
+	m.RingHash[0] ^= 1
+	_, err = m.Ring()
+	require.EqualError(t, err, "ring hash mismatch")
+	m.RingHash[0] ^= 1
+
+	m.Members[2] = m.Members[0]
+	_, err = m.Ring()
+	require.EqualError(t, err, "duplicate member at index 2")
+}
+
+func TestUnmarshal_Invalid(t *testing.T) {
+	curve := ring.Secp256k1()
+	keyring, err := ring.NewKeyRing(curve, 2, curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+	m, err := New(keyring, 7)
+	require.NoError(t, err)
+	signed, err := m.Sign(operatorKeys(t)["ed25519"])
+	require.NoError(t, err)
+	data, err := signed.Marshal()
+	require.NoError(t, err)
+
+	res, err := Unmarshal(data)
+	require.NoError(t, err)
+	require.Equal(t, signed, res)
+
+	for i := 0; i < len(data); i++ {
+		_, err = Unmarshal(data[:i])
+		require.Error(t, err)
+	}
+	_, err = Unmarshal(append(data, 0))
+	require.Error(t, err)
+
+	data[0] = 2
+	_, err = Unmarshal(data)
+	require.EqualError(t, err, "unsupported format version 2")
+}
@@ -0,0 +1,200 @@
+package manifest
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// precomputeSigningDomain is prepended to the encoded precompute manifest when
+// signing it, so that its signatures can't be mistaken for those of manifests.
+const precomputeSigningDomain = "ring-go/manifest/precompute"
+
+// Precompute lists the hash-to-curve values H_p(P_i) of a ring's members (see
+// ring.Ring.HashPoints), so that an operator computes them once for all the
+// verifiers of a large ring, which load them with LoadPrecompute instead of
+// computing them.
+type Precompute struct {
+	// CurveID is the curve of the ring.
+	CurveID ring.CurveID
+	// RingHash is the hash of the ring, as returned by ring.Ring.Hash.
+	RingHash [32]byte
+	// HashPoints are the encoded H_p(P_i) values, in the order of the ring's
+	// members.
+	HashPoints [][]byte
+}
+
+// SignedPrecompute is a Precompute signed by an operator.
+type SignedPrecompute struct {
+	Precompute
+	// Signature is the operator's signature over the encoded precompute
+	// manifest, as for Manifest.Sign.
+	Signature []byte
+}
+
+// NewPrecompute returns the precompute manifest of the ring, computing its
+// H_p(P_i) values if they aren't cached.
+func NewPrecompute(keyring *ring.Ring) (*Precompute, error) {
+	id := ring.CurveIDOf(keyring.Curve())
+	if id == ring.CurveUnknown {
+		return nil, errors.New("unsupported curve")
+	}
+
+	hps := keyring.HashPoints()
+	p := &Precompute{
+		CurveID:    id,
+		RingHash:   keyring.Hash(),
+		HashPoints: make([][]byte, len(hps)),
+	}
+	for i, hp := range hps {
+		p.HashPoints[i] = hp.Encode()
+	}
+	return p, nil
+}
+
+// encode returns the canonical encoding of the precompute manifest: the format
+// version, the curve ID, the ring hash, the number of values as a big-endian
+// uint32, and the values.
+func (p *Precompute) encode() ([]byte, error) {
+	curve, err := p.CurveID.Curve()
+	if err != nil {
+		return nil, err
+	}
+	pointLen := curve.CompressedPointSize()
+
+	out := []byte{formatVersion, byte(p.CurveID)}
+	out = append(out, p.RingHash[:]...)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(p.HashPoints)))
+	for i, hp := range p.HashPoints {
+		if len(hp) != pointLen {
+			return nil, fmt.Errorf("invalid hash point length at index %d", i)
+		}
+		out = append(out, hp...)
+	}
+	return out, nil
+}
+
+// Sign signs the precompute manifest with the operator's key, like
+// Manifest.Sign.
+func (p *Precompute) Sign(operator crypto.Signer) (*SignedPrecompute, error) {
+	encoded, err := p.encode()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signPayload(operator, precomputeSigningDomain, encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedPrecompute{
+		Precompute: *p,
+		Signature:  sig,
+	}, nil
+}
+
+// VerifyPrecompute checks that the precompute manifest was signed by the
+// operator, like VerifyManifest.
+func VerifyPrecompute(p *SignedPrecompute, operator crypto.PublicKey) error {
+	encoded, err := p.encode()
+	if err != nil {
+		return err
+	}
+	return verifyPayload(operator, precomputeSigningDomain, encoded, p.Signature)
+}
+
+// Marshal encodes the signed precompute manifest as the encoded precompute
+// manifest, followed by the length of the signature as a big-endian uint16 and
+// the signature.
+func (p *SignedPrecompute) Marshal() ([]byte, error) {
+	out, err := p.encode()
+	if err != nil {
+		return nil, err
+	}
+	return appendSignature(out, p.Signature)
+}
+
+// UnmarshalPrecompute decodes a signed precompute manifest encoded with
+// Marshal. It doesn't verify the signature; see VerifyPrecompute and
+// LoadPrecompute.
+func UnmarshalPrecompute(data []byte) (*SignedPrecompute, error) {
+	r := bytes.NewReader(data)
+
+	var header struct {
+		Version  byte
+		CurveID  ring.CurveID
+		RingHash [32]byte
+		Size     uint32
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, errors.New("input too short")
+	}
+
+	if header.Version != formatVersion {
+		return nil, fmt.Errorf("unsupported format version %d", header.Version)
+	}
+
+	curve, err := header.CurveID.Curve()
+	if err != nil {
+		return nil, err
+	}
+	pointLen := curve.CompressedPointSize()
+
+	if uint64(header.Size) > uint64(r.Len())/uint64(pointLen) {
+		return nil, errors.New("input too short")
+	}
+
+	p := &SignedPrecompute{
+		Precompute: Precompute{
+			CurveID:    header.CurveID,
+			RingHash:   header.RingHash,
+			HashPoints: make([][]byte, header.Size),
+		},
+	}
+	for i := range p.HashPoints {
+		p.HashPoints[i] = make([]byte, pointLen)
+		_, _ = r.Read(p.HashPoints[i])
+	}
+
+	if p.Signature, err = readSignature(r); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// LoadPrecompute decodes a signed precompute manifest, checks that it was
+// signed by the operator and is for the given ring, and caches its H_p(P_i)
+// values on the ring with ring.Ring.SetHashPoints. The values are checked
+// with check, which defaults to ring.TrustHashPoints, as the operator's
+// signature authenticates them; verifiers that don't fully trust the operator
+// pass eg. ring.SampleHashPoints(64).
+func LoadPrecompute(data []byte, operator crypto.PublicKey, keyring *ring.Ring, check ring.HashPointCheck) error {
+	p, err := UnmarshalPrecompute(data)
+	if err != nil {
+		return err
+	}
+
+	if err := VerifyPrecompute(p, operator); err != nil {
+		return err
+	}
+
+	if p.CurveID != ring.CurveIDOf(keyring.Curve()) || p.RingHash != keyring.Hash() {
+		return errors.New("precompute manifest is for another ring")
+	}
+
+	curve := keyring.Curve()
+	hps := make([]ring.Point, len(p.HashPoints))
+	for i, enc := range p.HashPoints {
+		if hps[i], err = curve.DecodeToPoint(enc); err != nil {
+			return fmt.Errorf("invalid hash point at index %d: %w", i, err)
+		}
+	}
+
+	if check == nil {
+		check = ring.TrustHashPoints
+	}
+	return keyring.SetHashPoints(hps, check)
+}
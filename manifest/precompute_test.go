@@ -0,0 +1,108 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+func TestLoadPrecompute(t *testing.T) {
+	for name, operator := range operatorKeys(t) {
+		for _, curve := range []ring.Curve{ring.Secp256k1(), ring.Ed25519()} {
+			privKey := curve.NewRandomScalar()
+			keyring, err := ring.NewKeyRing(curve, 6, privKey, 4)
+			require.NoError(t, err)
+			sig, err := keyring.Sign(testMsg, privKey)
+			require.NoError(t, err)
+
+			p, err := NewPrecompute(keyring)
+			require.NoError(t, err)
+			signed, err := p.Sign(operator)
+			require.NoError(t, err, name)
+			require.NoError(t, VerifyPrecompute(signed, operator.Public()))
+			data, err := signed.Marshal()
+			require.NoError(t, err)
+
+			// a verifier decoding the signature loads the values
+			enc, err := sig.Serialize()
+			require.NoError(t, err)
+			decoded := new(ring.RingSig)
+			require.NoError(t, decoded.Deserialize(curve, enc))
+			require.NoError(t, LoadPrecompute(data, operator.Public(), decoded.Ring(), nil))
+			require.True(t, decoded.Verify(testMsg))
+
+			decoded = new(ring.RingSig)
+			require.NoError(t, decoded.Deserialize(curve, enc))
+			require.NoError(t, LoadPrecompute(data, operator.Public(), decoded.Ring(), ring.SampleHashPoints(6)))
+			require.True(t, decoded.Verify(testMsg))
+		}
+	}
+}
+
+func TestLoadPrecompute_Invalid(t *testing.T) {
+	operators := operatorKeys(t)
+	operator := operators["ed25519"]
+	curve := ring.Ed25519()
+	keyring, err := ring.NewKeyRing(curve, 3, curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+	other, err := ring.NewKeyRing(curve, 3, curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+
+	p, err := NewPrecompute(keyring)
+	require.NoError(t, err)
+	signed, err := p.Sign(operator)
+	require.NoError(t, err)
+	data, err := signed.Marshal()
+	require.NoError(t, err)
+
+	err = LoadPrecompute(data, operators["ecdsa"].Public(), keyring, nil)
+	require.Error(t, err)
+	err = LoadPrecompute(data, operator.Public(), other, nil)
+	require.EqualError(t, err, "precompute manifest is for another ring")
+
+	// precompute manifests and manifests have different signing domains
+	m, err := New(keyring, 0)
+	require.NoError(t, err)
+	signedManifest, err := m.Sign(operator)
+	require.NoError(t, err)
+	signed.Signature = signedManifest.Signature
+	require.EqualError(t, VerifyPrecompute(signed, operator.Public()), "invalid manifest signature")
+
+	// wrong values signed by the operator are rejected by sampling
+	p.HashPoints[1], p.HashPoints[2] = p.HashPoints[2], p.HashPoints[1]
+	signed, err = p.Sign(operator)
+	require.NoError(t, err)
+	data, err = signed.Marshal()
+	require.NoError(t, err)
+	err = LoadPrecompute(data, operator.Public(), keyring, ring.SampleHashPoints(3))
+	require.ErrorIs(t, err, ring.ErrHashPointMismatch)
+}
+
+func TestUnmarshalPrecompute_Invalid(t *testing.T) {
+	curve := ring.Secp256k1()
+	keyring, err := ring.NewKeyRing(curve, 2, curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+	p, err := NewPrecompute(keyring)
+	require.NoError(t, err)
+	signed, err := p.Sign(operatorKeys(t)["ecdsa"])
+	require.NoError(t, err)
+	data, err := signed.Marshal()
+	require.NoError(t, err)
+
+	res, err := UnmarshalPrecompute(data)
+	require.NoError(t, err)
+	require.Equal(t, signed, res)
+
+	for i := 0; i < len(data); i++ {
+		_, err = UnmarshalPrecompute(data[:i])
+		require.Error(t, err)
+	}
+	_, err = UnmarshalPrecompute(append(data, 0))
+	require.Error(t, err)
+
+	data[0] = 2
+	_, err = UnmarshalPrecompute(data)
+	require.EqualError(t, err, "unsupported format version 2")
+}
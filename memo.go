@@ -0,0 +1,184 @@
+package ring
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	memoDomain  = "ring-go/memo"
+	memoKeyInfo = "ring-go/memo/key"
+)
+
+// ErrInvalidMemo is returned by MemoSig.OpenMemo when the memo can't be
+// decrypted with the given key.
+var ErrInvalidMemo = errors.New("invalid memo or wrong verifier key")
+
+// MemoSig is a ring signature with a memo encrypted to a verifier, eg. a
+// return address or a nonce hint in a relay protocol, so that anonymous
+// messages can be routed back without revealing the route to anyone else.
+//
+// The memo is encrypted with ChaCha20-Poly1305 under a key derived from a
+// Diffie-Hellman exchange between an ephemeral key and the verifier's public
+// key, on the ring's curve. The ciphertext is bound into the signed
+// transcript, so it can't be swapped for another one without invalidating
+// the signature; the embedded RingSig is only valid for the message derived
+// from the message and the ciphertext, not for the message itself.
+type MemoSig struct {
+	sig  *RingSig
+	memo []byte
+}
+
+// SignWithMemo signs the message like Sign, with the memo encrypted to the
+// verifier's public key, which must be a point of the ring's curve.
+func (r *Ring) SignWithMemo(m [32]byte, privKey types.Scalar, verifier types.Point, memo []byte, opts ...SignOption) (_ *MemoSig, err error) {
+	defer recoverInternal(&err)
+
+	// decode the key on the ring's curve, so that keys of another
+	// implementation of the curve are accepted and other curves aren't
+	verifier, err = r.curve.DecodeToPoint(encodePoint(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("verifier key is not on the ring's curve: %w", err)
+	}
+	if isIdentity(verifier) {
+		return nil, errors.New("verifier key is the identity")
+	}
+
+	ephemeral := r.curve.NewRandomScalar()
+	ephemeralPub := encodePoint(r.curve.ScalarBaseMul(ephemeral))
+	aead, err := memoAEAD(encodePoint(r.curve.ScalarMul(ephemeral, verifier)), ephemeralPub, encodePoint(verifier))
+	if err != nil {
+		return nil, err
+	}
+
+	// each key is only used once, so a zero nonce is safe
+	var nonce [chacha20poly1305.NonceSize]byte
+	ciphertext := aead.Seal(ephemeralPub, nonce[:], memo, m[:])
+
+	sig, err := r.Sign(memoMessage(m, ciphertext), privKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &MemoSig{sig: sig, memo: ciphertext}, nil
+}
+
+// Verify returns whether the signature is valid for the message and the
+// encrypted memo. It doesn't decrypt the memo.
+func (s *MemoSig) Verify(m [32]byte) bool {
+	return s.sig.Verify(memoMessage(m, s.memo))
+}
+
+// Signature returns the ring signature, eg. for its key image. It's valid for
+// the message derived from the message and the encrypted memo.
+func (s *MemoSig) Signature() *RingSig {
+	return s.sig
+}
+
+// Memo returns a copy of the encrypted memo.
+func (s *MemoSig) Memo() []byte {
+	return bytes.Clone(s.memo)
+}
+
+// OpenMemo decrypts the memo with the verifier's private key. The memo is
+// authenticated along with the message, so it must be the message the
+// signature was created for; OpenMemo doesn't verify the signature itself.
+// It returns ErrInvalidMemo if the memo wasn't encrypted to the key.
+func (s *MemoSig) OpenMemo(m [32]byte, verifierKey types.Scalar) (_ []byte, err error) {
+	defer recoverInternal(&err)
+
+	curve := s.sig.ring.curve
+	pointLen := curve.CompressedPointSize()
+	if len(s.memo) < pointLen+chacha20poly1305.Overhead {
+		return nil, ErrInvalidMemo
+	}
+
+	ephemeralPub := s.memo[:pointLen]
+	ephemeral, err := curve.DecodeToPoint(ephemeralPub)
+	// like key images, the ephemeral key must not be the identity or have a
+	// small-order component, which would leak the verifier's key modulo the
+	// cofactor
+	if err != nil || isIdentity(ephemeral) || !isTorsionFree(ephemeral) {
+		return nil, ErrInvalidMemo
+	}
+	aead, err := memoAEAD(encodePoint(curve.ScalarMul(verifierKey, ephemeral)), ephemeralPub, encodePoint(curve.ScalarBaseMul(verifierKey)))
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [chacha20poly1305.NonceSize]byte
+	memo, err := aead.Open(nil, nonce[:], s.memo[pointLen:], m[:])
+	if err != nil {
+		return nil, ErrInvalidMemo
+	}
+	return memo, nil
+}
+
+// Serialize encodes the signature as the length of the serialized RingSig, as
+// a big-endian uint32, the serialized RingSig, and the encrypted memo.
+func (s *MemoSig) Serialize() ([]byte, error) {
+	sig, err := s.sig.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	out := binary.BigEndian.AppendUint32(nil, uint32(len(sig)))
+	out = append(out, sig...)
+	return append(out, s.memo...), nil
+}
+
+// Deserialize decodes a signature over the given curve encoded with
+// Serialize.
+func (s *MemoSig) Deserialize(curve types.Curve, in []byte) error {
+	if len(in) < 4 {
+		return errors.New("input too short")
+	}
+	n := binary.BigEndian.Uint32(in)
+	rest := in[4:]
+	if uint64(len(rest)) < uint64(n) {
+		return errors.New("input too short")
+	}
+
+	sig := new(RingSig)
+	if err := sig.Deserialize(curve, rest[:n]); err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	if len(rest[n:]) < curve.CompressedPointSize()+chacha20poly1305.Overhead {
+		return errors.New("memo too short")
+	}
+
+	*s = MemoSig{sig: sig, memo: bytes.Clone(rest[n:])}
+	return nil
+}
+
+// memoMessage returns the message signed in place of m to bind the encrypted
+// memo.
+func memoMessage(m [32]byte, memo []byte) [32]byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte(memoDomain))
+	_, _ = h.Write(m[:])
+	_, _ = h.Write(memo)
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// memoAEAD returns the AEAD keyed from the Diffie-Hellman shared point between
+// the ephemeral key and the verifier's key.
+func memoAEAD(shared, ephemeralPub, verifierPub []byte) (cipher.AEAD, error) {
+	salt := append(bytes.Clone(ephemeralPub), verifierPub...)
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(memoKeyInfo)), key); err != nil {
+		return nil, err
+	}
+	return chacha20poly1305.New(key)
+}
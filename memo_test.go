@@ -0,0 +1,105 @@
+package ring
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWithMemo(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 4, privKey, 3)
+		require.NoError(t, err)
+		verifierKey := curve.NewRandomScalar()
+		verifier := curve.ScalarBaseMul(verifierKey)
+
+		memo := []byte("reply to relay 7, nonce 42")
+		sig, err := keyring.SignWithMemo(testMsg, privKey, verifier, memo, WithChainID(1))
+		require.NoError(t, err)
+		require.True(t, sig.Verify(testMsg))
+		require.False(t, sig.Verify([32]byte{1}))
+		require.NotContains(t, string(sig.Memo()), "relay")
+
+		// the RingSig alone is only valid for the derived message
+		require.False(t, sig.Signature().Verify(testMsg))
+
+		opened, err := sig.OpenMemo(testMsg, verifierKey)
+		require.NoError(t, err)
+		require.Equal(t, memo, opened)
+		_, err = sig.OpenMemo(testMsg, curve.NewRandomScalar())
+		require.ErrorIs(t, err, ErrInvalidMemo)
+		_, err = sig.OpenMemo([32]byte{1}, verifierKey)
+		require.ErrorIs(t, err, ErrInvalidMemo)
+
+		enc, err := sig.Serialize()
+		require.NoError(t, err)
+		decoded := new(MemoSig)
+		require.NoError(t, decoded.Deserialize(curve, enc))
+		require.True(t, decoded.Verify(testMsg))
+		opened, err = decoded.OpenMemo(testMsg, verifierKey)
+		require.NoError(t, err)
+		require.Equal(t, memo, opened)
+		require.True(t, Link(sig.Signature(), decoded.Signature()))
+
+		// the memo can't be swapped
+		other, err := keyring.SignWithMemo(testMsg, privKey, verifier, []byte("other"))
+		require.NoError(t, err)
+		swapped := &MemoSig{sig: sig.sig, memo: other.Memo()}
+		require.False(t, swapped.Verify(testMsg))
+
+		enc[len(enc)-1] ^= 1
+		require.NoError(t, decoded.Deserialize(curve, enc))
+		require.False(t, decoded.Verify(testMsg))
+		require.Error(t, decoded.Deserialize(curve, enc[:10]))
+	}
+}
+
+func TestSignWithMemo_VerifierCurve(t *testing.T) {
+	privKey := Ed25519().NewRandomScalar()
+	keyring, err := NewKeyRing(Ed25519(), 2, privKey, 0)
+	require.NoError(t, err)
+	verifier := Secp256k1().ScalarBaseMul(Secp256k1().NewRandomScalar())
+	_, err = keyring.SignWithMemo(testMsg, privKey, verifier, nil)
+	require.ErrorContains(t, err, "curve")
+}
+
+func TestOpenMemo_SmallOrderEphemeral(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 3, privKey, 1)
+	require.NoError(t, err)
+	verifierKey := curve.NewRandomScalar()
+	verifierPub := encodePoint(curve.ScalarBaseMul(verifierKey))
+	sig, err := keyring.SignWithMemo(testMsg, privKey, curve.ScalarBaseMul(verifierKey), []byte("memo"))
+	require.NoError(t, err)
+
+	// (0, -1), of order 2
+	order2Bytes, err := hex.DecodeString("ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	require.NoError(t, err)
+	order2, err := curve.DecodeToPoint(order2Bytes)
+	require.NoError(t, err)
+	identity := curve.ScalarBaseMul(curve.ScalarFromInt(0))
+
+	// with an order-2 ephemeral key, the shared point is the identity or the
+	// ephemeral key itself, depending on the parity of the verifier's key, so
+	// one of these memos would decrypt and reveal that parity
+	for _, shared := range []Point{identity, order2} {
+		aead, err := memoAEAD(encodePoint(shared), order2Bytes, verifierPub)
+		require.NoError(t, err)
+		var nonce [12]byte
+		memo := aead.Seal(bytes.Clone(order2Bytes), nonce[:], []byte("probe"), testMsg[:])
+		_, err = (&MemoSig{sig: sig.sig, memo: memo}).OpenMemo(testMsg, verifierKey)
+		require.ErrorIs(t, err, ErrInvalidMemo)
+	}
+
+	// likewise for the identity
+	aead, err := memoAEAD(encodePoint(identity), encodePoint(identity), verifierPub)
+	require.NoError(t, err)
+	var nonce [12]byte
+	memo := aead.Seal(encodePoint(identity), nonce[:], []byte("probe"), testMsg[:])
+	_, err = (&MemoSig{sig: sig.sig, memo: memo}).OpenMemo(testMsg, verifierKey)
+	require.ErrorIs(t, err, ErrInvalidMemo)
+}
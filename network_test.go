@@ -0,0 +1,84 @@
+package ring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithNetwork(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 4, privKey, 2)
+		require.NoError(t, err)
+
+		sig, err := keyring.Sign(testMsg, privKey, WithChainID(1), WithNetwork("mainnet"),
+			WithValidity(time.Time{}, time.Now().Add(time.Hour)), WithEmbeddedDigest())
+		require.NoError(t, err)
+
+		enc, err := sig.Serialize()
+		require.NoError(t, err)
+		res := new(RingSig)
+		require.NoError(t, res.Deserialize(curve, enc))
+		require.True(t, res.Equal(sig))
+
+		id, ok := res.ChainID()
+		require.True(t, ok)
+		require.Equal(t, uint64(1), id)
+		require.True(t, res.BoundToNetwork("mainnet"))
+		require.False(t, res.BoundToNetwork("testnet"))
+		require.NoError(t, res.VerifyEmbedded())
+
+		require.NoError(t, res.VerifyForNetwork(testMsg, 1, "mainnet"))
+		require.ErrorIs(t, res.VerifyForNetwork(testMsg, 5, "mainnet"), ErrWrongNetwork)
+		require.ErrorIs(t, res.VerifyForNetwork(testMsg, 1, "testnet"), ErrWrongNetwork)
+		require.ErrorIs(t, res.VerifyForNetwork(testMsg, 0, "mainnet"), ErrWrongNetwork)
+		require.ErrorIs(t, res.VerifyForNetwork(testMsg, 1, ""), ErrWrongNetwork)
+		require.ErrorIs(t, res.VerifyForNetwork([32]byte{}, 1, "mainnet"), ErrInvalidSignature)
+	}
+}
+
+func TestWithNetwork_Transcript(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 3, privKey, 0)
+	require.NoError(t, err)
+
+	// unbound signatures are only accepted by verifiers not expecting a binding
+	plain, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	require.NoError(t, plain.VerifyForNetwork(testMsg, 0, ""))
+	require.ErrorIs(t, plain.VerifyForNetwork(testMsg, 1, ""), ErrWrongNetwork)
+	_, ok := plain.ChainID()
+	require.False(t, ok)
+
+	// the bindings are part of the transcript
+	sig, err := keyring.Sign(testMsg, privKey, WithChainID(5))
+	require.NoError(t, err)
+	require.NoError(t, sig.VerifyForNetwork(testMsg, 5, ""))
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+
+	// the chain ID is the last 8 bytes of the header fields
+	offset := 4 + 32 + 33
+	enc[offset+7] = 1
+	res := new(RingSig)
+	require.NoError(t, res.Deserialize(curve, enc))
+	require.ErrorIs(t, res.VerifyForNetwork(testMsg, 1, ""), ErrInvalidSignature)
+
+	enc[offset+7] = 0
+	require.Error(t, res.Deserialize(curve, enc))
+}
+
+func TestWithNetwork_Errors(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 3, privKey, 0)
+	require.NoError(t, err)
+
+	_, err = keyring.Sign(testMsg, privKey, WithChainID(0))
+	require.Error(t, err)
+	_, err = keyring.Sign(testMsg, privKey, WithNetwork(""))
+	require.Error(t, err)
+}
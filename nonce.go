@@ -0,0 +1,124 @@
+package ring
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+const deterministicNonceDomain = "ring-go/deterministic-nonce"
+
+var (
+	// ErrRepeatedScalar is returned by Sign if the random number generator
+	// returns a zero scalar, or the same scalar twice, while signing. This
+	// should never happen with a working generator, and indicates that it is
+	// broken; the signature is not returned, as it could leak the private key.
+	ErrRepeatedScalar = errors.New("random number generator returned a zero or repeated scalar")
+
+	// ErrRepeatedCommitment is returned by Sign if the signer's commitment
+	// u*G was already seen by the CommitmentMonitor passed with
+	// WithCommitmentMonitor. Two signatures with the same commitment reveal
+	// the signer's private key, so this indicates a catastrophic failure of
+	// the random number generator.
+	ErrRepeatedCommitment = errors.New("repeated signing commitment, random number generator failure")
+)
+
+// scalarGuard detects zero or repeated random scalars within a signing call.
+type scalarGuard map[string]struct{}
+
+func (g scalarGuard) check(s types.Scalar) error {
+	if s.IsZero() {
+		return ErrRepeatedScalar
+	}
+
+	enc := string(s.Encode())
+	if _, ok := g[enc]; ok {
+		return ErrRepeatedScalar
+	}
+	g[enc] = struct{}{}
+	return nil
+}
+
+// CommitmentMonitor remembers the signer commitments (u*G) of the most recent
+// signatures created with it, across signing calls, and makes Sign fail if a
+// commitment is repeated. It's intended to be shared by all signers of a
+// process as a last line of defence against a broken random number generator.
+//
+// A CommitmentMonitor is safe for concurrent use.
+type CommitmentMonitor struct {
+	mu     sync.Mutex
+	seen   map[string]struct{}
+	recent []string // ring buffer of the most recent commitments
+	next   int
+}
+
+// NewCommitmentMonitor returns a CommitmentMonitor remembering the commitments
+// of the last window signatures.
+func NewCommitmentMonitor(window int) *CommitmentMonitor {
+	window = max(window, 1)
+	return &CommitmentMonitor{
+		seen:   make(map[string]struct{}, window),
+		recent: make([]string, 0, window),
+	}
+}
+
+// WithCommitmentMonitor makes Sign record the signer's commitment in m, and
+// fail with ErrRepeatedCommitment if it was already recorded.
+func WithCommitmentMonitor(m *CommitmentMonitor) SignOption {
+	return func(o *signOptions) {
+		o.monitor = m
+	}
+}
+
+// WithDeterministicNonces derives the signer's nonce and the decoy responses
+// from the private key, the ring and the signed message, like the nonces of
+// RFC 6979 and EdDSA, instead of picking them at random. Signing the same
+// message with the same key, ring and options then always produces the same
+// signature, eg. for known-answer tests, and signing doesn't depend on the
+// quality of the random number generator.
+//
+// The signatures are indistinguishable from randomized ones. With
+// WithAuditableDecoys, the decoy responses are still derived from the audit
+// seed.
+func WithDeterministicNonces() SignOption {
+	return func(o *signOptions) {
+		o.deterministic = true
+	}
+}
+
+// deterministicNonce derives the nonce with the given label, 0 for the
+// signer's nonce and i+1 for the response of ring member i, for signing the
+// transcript message m over the ring with the given hash with privKey.
+func deterministicNonce(curve types.Curve, privKey types.Scalar, ringHash, m [32]byte, label int) (types.Scalar, error) {
+	buf := []byte(deterministicNonceDomain)
+	buf = append(buf, privKey.Encode()...)
+	buf = append(buf, ringHash[:]...)
+	buf = append(buf, m[:]...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(label))
+	return curve.HashToScalar(buf)
+}
+
+// observe records the commitment, returning ErrRepeatedCommitment if it is
+// already in the window.
+func (m *CommitmentMonitor) observe(commitment types.Point) error {
+	enc := string(encodePoint(commitment))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.seen[enc]; ok {
+		return ErrRepeatedCommitment
+	}
+
+	if len(m.recent) < cap(m.recent) {
+		m.recent = append(m.recent, enc)
+	} else {
+		delete(m.seen, m.recent[m.next])
+		m.recent[m.next] = enc
+		m.next = (m.next + 1) % len(m.recent)
+	}
+	m.seen[enc] = struct{}{}
+	return nil
+}
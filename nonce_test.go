@@ -0,0 +1,81 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScalarGuard(t *testing.T) {
+	curve := Secp256k1()
+	guard := make(scalarGuard)
+
+	a, b := curve.NewRandomScalar(), curve.NewRandomScalar()
+	require.NoError(t, guard.check(a))
+	require.NoError(t, guard.check(b))
+	require.ErrorIs(t, guard.check(a.Add(curve.ScalarFromInt(0))), ErrRepeatedScalar)
+	require.ErrorIs(t, guard.check(curve.ScalarFromInt(0)), ErrRepeatedScalar)
+}
+
+func TestCommitmentMonitor(t *testing.T) {
+	curve := Ed25519()
+	m := NewCommitmentMonitor(2)
+
+	p := make([]Point, 3)
+	for i := range p {
+		p[i] = curve.ScalarBaseMul(curve.NewRandomScalar())
+	}
+
+	require.NoError(t, m.observe(p[0]))
+	require.ErrorIs(t, m.observe(p[0].Copy()), ErrRepeatedCommitment)
+	require.NoError(t, m.observe(p[1]))
+
+	// p[0] falls out of the window
+	require.NoError(t, m.observe(p[2]))
+	require.NoError(t, m.observe(p[0]))
+	require.ErrorIs(t, m.observe(p[2]), ErrRepeatedCommitment)
+}
+
+func TestSign_WithCommitmentMonitor(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 2)
+	require.NoError(t, err)
+
+	m := NewCommitmentMonitor(16)
+	for i := 0; i < 32; i++ {
+		sig, err := keyring.Sign(testMsg, privKey, WithCommitmentMonitor(m))
+		require.NoError(t, err)
+		require.True(t, sig.Verify(testMsg))
+	}
+	require.Len(t, m.seen, 16)
+}
+
+func TestSign_WithDeterministicNonces(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 5, privKey, 2)
+		require.NoError(t, err)
+
+		sig1, err := keyring.Sign(testMsg, privKey, WithDeterministicNonces())
+		require.NoError(t, err)
+		sig2, err := keyring.Sign(testMsg, privKey, WithDeterministicNonces())
+		require.NoError(t, err)
+		require.True(t, sig1.Verify(testMsg))
+		require.True(t, sig1.Equal(sig2))
+
+		// the nonces depend on the transcript, not only the message
+		sig3, err := keyring.Sign(testMsg, privKey, WithDeterministicNonces(), WithChainID(1))
+		require.NoError(t, err)
+		require.True(t, sig3.Verify(testMsg))
+		require.False(t, sig1.c.Eq(sig3.c))
+
+		sig4, err := keyring.Sign([32]byte{9}, privKey, WithDeterministicNonces())
+		require.NoError(t, err)
+		require.False(t, sig1.s[0].Eq(sig4.s[0]))
+
+		random, err := keyring.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		require.False(t, sig1.Equal(random))
+	}
+}
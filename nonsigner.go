@@ -0,0 +1,145 @@
+package ring
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+const nonSignerDomain = "ring-go/nonsigner"
+
+// NonSignerProof proves that the owner of a public key did not create a given
+// ring signature, ie. that their key image differs from the signature's,
+// without revealing their key image. It can be used for dispute resolution
+// when a ring member is accused of having created a signature.
+//
+// The proof is a proof of knowledge of (a, b) such that
+// C = a*H_p(P) + b*I and a*G + b*P = 0, for a nonzero C; for C = r*(x*H_p(P) - I)
+// with a random r, a = r*x and b = -r. C is nonzero if and only if the prover's
+// key image x*H_p(P) differs from I, and is otherwise random, so the prover's
+// own key image is not revealed.
+type NonSignerProof struct {
+	commitment types.Point  // C
+	e          types.Scalar // challenge
+	za, zb     types.Scalar // responses
+}
+
+// ProveNonSigner creates a proof that the owner of privKey, whose public key
+// need not be in the signature's ring, did not create the signature. It
+// returns an error if privKey is the signer's private key.
+func (sig *RingSig) ProveNonSigner(privKey types.Scalar) (_ *NonSignerProof, err error) {
+	defer recoverInternal(&err)
+
+	if privKey.IsZero() {
+		return nil, errors.New("private key is zero")
+	}
+
+	curve := sig.ring.curve
+	pubkey := curve.ScalarBaseMul(privKey)
+	hp := hashToCurve(pubkey)
+	image := NormalizeKeyImage(sig.image)
+
+	r := curve.NewRandomScalar()
+	a := r.Mul(privKey)
+	b := curve.ScalarFromInt(0).Sub(r)
+
+	// C = a*H_p(P) + b*I = r*(x*H_p(P) - I)
+	commitment := curve.ScalarMul(a, hp).Add(curve.ScalarMul(b, image))
+	if isIdentity(commitment) {
+		return nil, errors.New("private key belongs to the signer")
+	}
+
+	ka, kb := curve.NewRandomScalar(), curve.NewRandomScalar()
+	t1 := curve.ScalarMul(ka, hp).Add(curve.ScalarMul(kb, image))
+	t2 := curve.ScalarBaseMul(ka).Add(curve.ScalarMul(kb, pubkey))
+
+	e := sig.nonSignerChallenge(pubkey, commitment, t1, t2)
+	return &NonSignerProof{
+		commitment: commitment,
+		e:          e,
+		za:         ka.Add(e.Mul(a)),
+		zb:         kb.Add(e.Mul(b)),
+	}, nil
+}
+
+// VerifyNonSigner returns true if the proof shows that the owner of pubkey did
+// not create the signature.
+func (sig *RingSig) VerifyNonSigner(pubkey types.Point, proof *NonSignerProof) (ok bool) {
+	defer recoverFalse(&ok)
+
+	if proof == nil || proof.commitment == nil || isIdentity(proof.commitment) {
+		return false
+	}
+
+	curve := sig.ring.curve
+	hp := hashToCurve(pubkey)
+	image := NormalizeKeyImage(sig.image)
+
+	// T1 = za*H_p(P) + zb*I - e*C
+	t1 := curve.ScalarMul(proof.za, hp).
+		Add(curve.ScalarMul(proof.zb, image)).
+		Sub(curve.ScalarMul(proof.e, proof.commitment))
+
+	// T2 = za*G + zb*P - e*0
+	t2 := curve.ScalarBaseMul(proof.za).Add(curve.ScalarMul(proof.zb, pubkey))
+
+	return sig.nonSignerChallenge(pubkey, proof.commitment, t1, t2).Eq(proof.e)
+}
+
+// nonSignerChallenge binds the proof to the signature and the prover's public
+// key.
+func (sig *RingSig) nonSignerChallenge(pubkey, commitment, t1, t2 types.Point) types.Scalar {
+	h := sig.Hash()
+
+	var buf bytes.Buffer
+	buf.WriteString(nonSignerDomain)
+	buf.Write(h[:])
+	buf.Write(encodePoint(pubkey))
+	buf.Write(encodePoint(commitment))
+	buf.Write(encodePoint(t1))
+	buf.Write(encodePoint(t2))
+
+	e, err := sig.ring.curve.HashToScalar(buf.Bytes())
+	if err != nil {
+		// this should not happen
+		panic(err)
+	}
+	return e
+}
+
+// Serialize encodes the proof as the commitment followed by the challenge and
+// the two responses.
+func (p *NonSignerProof) Serialize() []byte {
+	out := encodePoint(p.commitment)
+	out = append(out, p.e.Encode()...)
+	out = append(out, p.za.Encode()...)
+	return append(out, p.zb.Encode()...)
+}
+
+// Deserialize decodes a proof encoded with Serialize.
+func (p *NonSignerProof) Deserialize(curve types.Curve, in []byte) error {
+	scalarLen := ScalarSize(curve)
+	pointLen := curve.CompressedPointSize()
+	if len(in) != pointLen+3*scalarLen {
+		return errors.New("invalid proof length")
+	}
+
+	commitment, err := curve.DecodeToPoint(in[:pointLen])
+	if err != nil {
+		return err
+	}
+
+	var scalars [3]types.Scalar
+	for i := range scalars {
+		start := pointLen + i*scalarLen
+		scalars[i], err = curve.DecodeToScalar(in[start : start+scalarLen])
+		if err != nil {
+			return err
+		}
+	}
+
+	p.commitment = commitment
+	p.e, p.za, p.zb = scalars[0], scalars[1], scalars[2]
+	return nil
+}
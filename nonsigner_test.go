@@ -0,0 +1,61 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonSignerProof(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		signerKey, memberKey := curve.NewRandomScalar(), curve.NewRandomScalar()
+		memberPub := curve.ScalarBaseMul(memberKey)
+		keyring, err := NewKeyRingFromPublicKeys(curve, []Point{memberPub}, signerKey, 1)
+		require.NoError(t, err)
+		sig, err := keyring.Sign(testMsg, signerKey)
+		require.NoError(t, err)
+
+		proof, err := sig.ProveNonSigner(memberKey)
+		require.NoError(t, err)
+		require.True(t, sig.VerifyNonSigner(memberPub, proof))
+
+		// the proof is bound to the prover's public key and the signature
+		signerPub := curve.ScalarBaseMul(signerKey)
+		require.False(t, sig.VerifyNonSigner(signerPub, proof))
+		other, err := keyring.Sign(testMsg, signerKey)
+		require.NoError(t, err)
+		require.False(t, other.VerifyNonSigner(memberPub, proof))
+
+		// the signer can't prove they're not the signer
+		_, err = sig.ProveNonSigner(signerKey)
+		require.EqualError(t, err, "private key belongs to the signer")
+
+		// round-trip
+		res := new(NonSignerProof)
+		require.NoError(t, res.Deserialize(curve, proof.Serialize()))
+		require.True(t, sig.VerifyNonSigner(memberPub, res))
+
+		enc := proof.Serialize()
+		require.Error(t, res.Deserialize(curve, enc[1:]))
+		enc[len(enc)-1] ^= 1
+		if res.Deserialize(curve, enc) == nil {
+			require.False(t, sig.VerifyNonSigner(memberPub, res))
+		}
+	}
+}
+
+func TestNonSignerProof_IdentityCommitment(t *testing.T) {
+	curve := Ed25519()
+	signerKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 2, signerKey, 0)
+	require.NoError(t, err)
+	sig, err := keyring.Sign(testMsg, signerKey)
+	require.NoError(t, err)
+
+	// a proof with C = 0 would be satisfied by the signer, so it is rejected
+	zero := curve.ScalarFromInt(0)
+	g := curve.BasePoint()
+	proof := &NonSignerProof{commitment: g.Sub(g), e: zero, za: zero, zb: zero}
+	require.False(t, sig.VerifyNonSigner(curve.ScalarBaseMul(signerKey), proof))
+	require.False(t, sig.VerifyNonSigner(g, nil))
+}
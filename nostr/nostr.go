@@ -0,0 +1,297 @@
+// Package nostr signs Nostr events with ring signatures, so that relays and
+// clients can verify that an event was posted by "one of these N authors"
+// without learning which one.
+//
+// Ring members are Nostr public keys, ie. x-only secp256k1 keys as in BIP-340,
+// which are lifted to the point with an even y coordinate. Private keys must be
+// normalized accordingly, which ParseNsec and NormalizePrivateKey do.
+//
+// The ring signature is carried in a tag of the event:
+//
+//	["ringsig", "<base64 serialized ring signature>"]
+//
+// It signs the event's ID computed without any "ringsig" tag, so it binds the
+// event's pubkey, creation time, kind, other tags and content. Events are
+// typically published under an ephemeral key, which must be set before ring
+// signing; the event's ID and regular signature are computed afterwards, as
+// usual:
+//
+//	ev.PubKey = ephemeralPubKeyHex
+//	err := nostr.SignEvent(ev, authors, privKey)
+//	ev.ID = hex.EncodeToString(ev.ComputeID()[:]) // then sign with the ephemeral key
+//	...
+//	sig, err := nostr.VerifyEvent(ev, authors)
+package nostr
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// TagRingSig is the name of the tag holding the ring signature.
+const TagRingSig = "ringsig"
+
+// ErrNoRingSig is returned by VerifyEvent when the event has no "ringsig" tag.
+var ErrNoRingSig = errors.New("event has no ring signature")
+
+// Event is a Nostr event, as specified in NIP-01.
+type Event struct {
+	ID        string     `json:"id"`
+	PubKey    string     `json:"pubkey"`
+	CreatedAt int64      `json:"created_at"`
+	Kind      int        `json:"kind"`
+	Tags      [][]string `json:"tags"`
+	Content   string     `json:"content"`
+	Sig       string     `json:"sig"`
+}
+
+// Serialize returns the NIP-01 serialization of the event, whose SHA-256 hash
+// is the event's ID:
+//
+//	[0,<pubkey>,<created_at>,<kind>,<tags>,<content>]
+func (e *Event) Serialize() []byte {
+	return e.serialize(e.Tags)
+}
+
+// ComputeID returns the event's ID, the SHA-256 hash of its serialization.
+func (e *Event) ComputeID() [32]byte {
+	return sha256.Sum256(e.Serialize())
+}
+
+// RingDigest returns the digest signed by the event's ring signature: its ID
+// computed without any "ringsig" tag.
+func (e *Event) RingDigest() [32]byte {
+	tags := make([][]string, 0, len(e.Tags))
+	for _, tag := range e.Tags {
+		if !isRingSigTag(tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return sha256.Sum256(e.serialize(tags))
+}
+
+func (e *Event) serialize(tags [][]string) []byte {
+	out := []byte("[0,")
+	out = appendString(out, e.PubKey)
+	out = append(out, ',')
+	out = strconv.AppendInt(out, e.CreatedAt, 10)
+	out = append(out, ',')
+	out = strconv.AppendInt(out, int64(e.Kind), 10)
+	out = append(out, ",["...)
+	for i, tag := range tags {
+		if i > 0 {
+			out = append(out, ',')
+		}
+		out = append(out, '[')
+		for j, v := range tag {
+			if j > 0 {
+				out = append(out, ',')
+			}
+			out = appendString(out, v)
+		}
+		out = append(out, ']')
+	}
+	out = append(out, "],"...)
+	out = appendString(out, e.Content)
+	return append(out, ']')
+}
+
+// appendString appends the NIP-01 encoding of a JSON string, which only
+// escapes quotes, backslashes, and the line break, carriage return, tab,
+// backspace and form feed characters.
+func appendString(out []byte, s string) []byte {
+	out = append(out, '"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			out = append(out, '\\', c)
+		case '\n':
+			out = append(out, '\\', 'n')
+		case '\r':
+			out = append(out, '\\', 'r')
+		case '\t':
+			out = append(out, '\\', 't')
+		case '\b':
+			out = append(out, '\\', 'b')
+		case '\f':
+			out = append(out, '\\', 'f')
+		default:
+			out = append(out, c)
+		}
+	}
+	return append(out, '"')
+}
+
+func isRingSigTag(tag []string) bool {
+	return len(tag) > 0 && tag[0] == TagRingSig
+}
+
+// SignEvent ring-signs the event with the private key of one of the members of
+// the authors' ring, which must be over secp256k1, and adds the "ringsig" tag
+// to the event, replacing any existing one. The event's ID and Sig are not
+// updated.
+func SignEvent(e *Event, authors *ring.Ring, privKey ring.Scalar, opts ...ring.SignOption) error {
+	if ring.CurveIDOf(authors.Curve()) != ring.CurveSecp256k1 {
+		return errors.New("ring is not over secp256k1")
+	}
+
+	sig, err := authors.Sign(e.RingDigest(), privKey, opts...)
+	if err != nil {
+		return err
+	}
+	enc, err := sig.Serialize()
+	if err != nil {
+		return err
+	}
+
+	tags := make([][]string, 0, len(e.Tags)+1)
+	for _, tag := range e.Tags {
+		if !isRingSigTag(tag) {
+			tags = append(tags, tag)
+		}
+	}
+	e.Tags = append(tags, []string{TagRingSig, base64.StdEncoding.EncodeToString(enc)})
+	return nil
+}
+
+// VerifyEvent verifies the ring signature of the event and returns it. If
+// authors is not nil, the signature's ring must be equal to it; otherwise,
+// callers must check the signature's ring themselves, eg. by displaying the
+// npubs of its members.
+//
+// It returns ErrNoRingSig if the event has no "ringsig" tag. The event's ID and
+// regular signature are not checked.
+func VerifyEvent(e *Event, authors *ring.Ring) (*ring.RingSig, error) {
+	var value string
+	found := false
+	for _, tag := range e.Tags {
+		if !isRingSigTag(tag) {
+			continue
+		}
+		if found {
+			return nil, errors.New("event has several ring signatures")
+		}
+		if len(tag) != 2 {
+			return nil, errors.New("malformed ringsig tag")
+		}
+		value, found = tag[1], true
+	}
+	if !found {
+		return nil, ErrNoRingSig
+	}
+
+	enc, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("malformed ringsig tag: %w", err)
+	}
+	sig := new(ring.RingSig)
+	if err := sig.Deserialize(ring.Secp256k1(), enc); err != nil {
+		return nil, fmt.Errorf("malformed ringsig tag: %w", err)
+	}
+
+	if authors != nil && !sig.Ring().Equals(authors) {
+		return nil, errors.New("event is signed by a different ring")
+	}
+	if err := sig.VerifyWithPolicy(e.RingDigest(), nil); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// PublicKeyFromHex returns the ring member for a hex-encoded x-only public key,
+// as found in the pubkey field of events.
+func PublicKeyFromHex(s string) (ring.Point, error) {
+	x, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return liftX(x)
+}
+
+// ParseNpub returns the ring member for a NIP-19 npub.
+func ParseNpub(npub string) (ring.Point, error) {
+	hrp, x, err := decodeBech32(npub)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != "npub" {
+		return nil, fmt.Errorf("not an npub: %q", hrp)
+	}
+	return liftX(x)
+}
+
+// Npub returns the NIP-19 npub of a ring member, ie. the bech32 encoding of its
+// x coordinate.
+func Npub(pk ring.Point) (string, error) {
+	if ring.CurveIDOfPoint(pk) != ring.CurveSecp256k1 {
+		return "", errors.New("public key is not on secp256k1")
+	}
+	return encodeBech32("npub", pk.Encode()[1:])
+}
+
+// NewRing builds a ring from npubs, in order. The keys must be distinct.
+func NewRing(npubs []string) (*ring.Ring, error) {
+	pubkeys := make([]ring.Point, len(npubs))
+	seen := make(map[string]bool, len(npubs))
+	for i, npub := range npubs {
+		pk, err := ParseNpub(npub)
+		if err != nil {
+			return nil, fmt.Errorf("member %d: %w", i, err)
+		}
+		if seen[string(pk.Encode())] {
+			return nil, fmt.Errorf("member %d: duplicate public key", i)
+		}
+		seen[string(pk.Encode())] = true
+		pubkeys[i] = pk
+	}
+	return ring.NewFixedKeyRingFromPublicKeys(ring.Secp256k1(), pubkeys)
+}
+
+// ParseNsec returns the private key for a NIP-19 nsec, normalized with
+// NormalizePrivateKey.
+func ParseNsec(nsec string) (ring.Scalar, error) {
+	hrp, b, err := decodeBech32(nsec)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != "nsec" {
+		return nil, fmt.Errorf("not an nsec: %q", hrp)
+	}
+	if len(b) != 32 {
+		return nil, errors.New("invalid nsec length")
+	}
+
+	priv, err := ring.Secp256k1().DecodeToScalar(b)
+	if err != nil {
+		return nil, err
+	}
+	if priv.IsZero() {
+		return nil, errors.New("private key is zero")
+	}
+	return NormalizePrivateKey(priv), nil
+}
+
+// NormalizePrivateKey returns the private key whose public key is the
+// even-y point with the same x coordinate as that of priv, ie. priv or its
+// negation. Nostr private keys must be normalized to sign with rings of
+// npubs.
+func NormalizePrivateKey(priv ring.Scalar) ring.Scalar {
+	if ring.Secp256k1().ScalarBaseMul(priv).Encode()[0] == 0x03 {
+		return priv.Negate()
+	}
+	return priv
+}
+
+// liftX returns the point with the given x coordinate and an even y.
+func liftX(x []byte) (ring.Point, error) {
+	if len(x) != 32 {
+		return nil, errors.New("invalid x-only public key length")
+	}
+	return ring.Secp256k1().DecodeToPoint(append([]byte{0x02}, x...))
+}
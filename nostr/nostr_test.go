@@ -0,0 +1,133 @@
+package nostr
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// test vectors from NIP-19
+const (
+	testNpub    = "npub10elfcs4fr0l0r8af98jlmgdh9c8tcxjvz9qkw038js35mp4dma8qzvjptg"
+	testNpubHex = "7e7e9c42a91bfef19fa929e5fda1b72e0ebc1a4c1141673e2794234d86addf4e"
+	testNsec    = "nsec1vl029mgpspedva04g90vltkh6fvh240zqtv9k0t9af8935ke9laqsnlfe5"
+	testNsecHex = "67dea2ed018072d675f5415ecfaed7d2597555e202d85b3d65ea4e58d2d92ffa"
+)
+
+func TestNpub(t *testing.T) {
+	pk, err := ParseNpub(testNpub)
+	require.NoError(t, err)
+	require.Equal(t, "02"+testNpubHex, hex.EncodeToString(pk.Encode()))
+
+	fromHex, err := PublicKeyFromHex(testNpubHex)
+	require.NoError(t, err)
+	require.True(t, fromHex.Equals(pk))
+
+	npub, err := Npub(pk)
+	require.NoError(t, err)
+	require.Equal(t, testNpub, npub)
+
+	_, err = ParseNpub(testNsec)
+	require.Error(t, err)
+	_, err = ParseNpub(testNpub[:len(testNpub)-1] + "q")
+	require.Error(t, err)
+	_, err = Npub(ring.Ed25519().BasePoint())
+	require.Error(t, err)
+}
+
+func TestParseNsec(t *testing.T) {
+	priv, err := ParseNsec(testNsec)
+	require.NoError(t, err)
+
+	// the public key of the normalized key has an even y
+	pub := ring.Secp256k1().ScalarBaseMul(priv).Encode()
+	require.Equal(t, byte(0x02), pub[0])
+
+	raw, err := hex.DecodeString(testNsecHex)
+	require.NoError(t, err)
+	rawPriv, err := ring.Secp256k1().DecodeToScalar(raw)
+	require.NoError(t, err)
+	require.Equal(t, pub[1:], ring.Secp256k1().ScalarBaseMul(rawPriv).Encode()[1:])
+}
+
+func TestSerialize(t *testing.T) {
+	ev := &Event{
+		PubKey:    testNpubHex,
+		CreatedAt: 1700000000,
+		Kind:      1,
+		Tags:      [][]string{{"e", "abc"}, {"p", "def", "wss://relay"}},
+		Content:   "line\nbreak \"quoted\" \\ tab\t <html> é  ",
+	}
+	require.Equal(t,
+		`[0,"`+testNpubHex+`",1700000000,1,[["e","abc"],["p","def","wss://relay"]],"line\nbreak \"quoted\" \\ tab\t <html> é `+" "+`"]`,
+		string(ev.Serialize()))
+}
+
+func newAuthors(t *testing.T) (*ring.Ring, ring.Scalar) {
+	curve := ring.Secp256k1()
+	npubs := make([]string, 4)
+	var privKey ring.Scalar
+	for i := range npubs {
+		priv := NormalizePrivateKey(curve.NewRandomScalar())
+		if i == 2 {
+			privKey = priv
+		}
+		var err error
+		npubs[i], err = Npub(curve.ScalarBaseMul(priv))
+		require.NoError(t, err)
+	}
+
+	authors, err := NewRing(npubs)
+	require.NoError(t, err)
+	return authors, privKey
+}
+
+func TestSignAndVerifyEvent(t *testing.T) {
+	authors, privKey := newAuthors(t)
+	ev := &Event{
+		PubKey:    testNpubHex,
+		CreatedAt: 1700000000,
+		Kind:      1,
+		Tags:      [][]string{{"t", "anonymous"}},
+		Content:   "one of us said this",
+	}
+
+	require.NoError(t, SignEvent(ev, authors, privKey))
+	require.Len(t, ev.Tags, 2)
+	require.Equal(t, TagRingSig, ev.Tags[1][0])
+
+	sig, err := VerifyEvent(ev, authors)
+	require.NoError(t, err)
+	require.True(t, sig.Ring().Equals(authors))
+	_, err = VerifyEvent(ev, nil)
+	require.NoError(t, err)
+
+	// re-signing replaces the tag
+	require.NoError(t, SignEvent(ev, authors, privKey))
+	require.Len(t, ev.Tags, 2)
+
+	// the content, tags and pubkey are signed
+	ev.Content = "something else"
+	_, err = VerifyEvent(ev, authors)
+	require.ErrorIs(t, err, ring.ErrInvalidSignature)
+
+	other, _ := newAuthors(t)
+	ev.Content = "one of us said this"
+	_, err = VerifyEvent(ev, other)
+	require.Error(t, err)
+
+	ev.Tags = ev.Tags[:1]
+	_, err = VerifyEvent(ev, authors)
+	require.ErrorIs(t, err, ErrNoRingSig)
+
+	require.Error(t, SignEvent(ev, mustEd25519Ring(t), privKey))
+}
+
+func mustEd25519Ring(t *testing.T) *ring.Ring {
+	r, err := ring.NewKeyRing(ring.Ed25519(), 2, ring.Ed25519().NewRandomScalar(), 0)
+	require.NoError(t, err)
+	return r
+}
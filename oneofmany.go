@@ -0,0 +1,370 @@
+package ring
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	oneOfManyDomain       = "ring-go/one-of-many"
+	oneOfManyOffsetDomain = "ring-go/one-of-many/offset"
+)
+
+// OneOfManySig is a ring signature by a member of a ring over one message out
+// of a published set, which hides both the signer and the message, eg. for
+// private ballots over a fixed list of choices. See Ring.SignOneOf.
+//
+// The signature carries a Pedersen commitment C = rho*G + h_choice*G' to the
+// chosen message, with h_t = H(m_t) and G' the curve's alternate base point.
+// It's an MLSAG signature over the virtual ring of the n*k pairs (P_i, m_t) of
+// a ring member and a message, each with two keys: P_i, with key image I, and
+// D_t = C - h_t*G'. The signer only knows the private keys of both, x and
+// rho, for the pair of its public key and its choice, so the signature shows
+// that a member signed the message C commits to, without revealing which one.
+// The commitment is binding, so the signer can later reveal its choice with
+// the opening returned by SignOneOf, eg. in the reveal phase of a
+// commit-reveal ballot, and can't claim another one (see VerifyChoice).
+//
+// The key image I = x*H_p(P) is that of ordinary signatures, so signatures by
+// the same member are linkable (eg. to allow a single ballot per member),
+// including with RingSigs.
+type OneOfManySig struct {
+	ring       *Ring
+	k          int
+	c          types.Scalar
+	image      types.Point
+	commitment types.Point
+	// s holds two responses per pair, for its keys P_i and D_t
+	s []types.Scalar
+}
+
+// SignOneOf signs the message msgs[choice] as a member of the ring, hiding the
+// signer among the ring members and the message among msgs. The messages must
+// be distinct, and the signature is verified against the same list, in the
+// same order. The signature has two responses per message and ring member, so
+// its size and the cost of signing and verifying grow with both.
+//
+// It also returns the opening of the signature's commitment to the choice,
+// which the signer keeps secret until it reveals its choice; see
+// VerifyChoice.
+func (r *Ring) SignOneOf(msgs [][32]byte, choice int, privKey types.Scalar) (_ *OneOfManySig, opening types.Scalar, err error) {
+	defer recoverInternal(&err)
+
+	size := len(r.pubkeys)
+	if size < 2 {
+		return nil, nil, errors.New("size of ring less than two")
+	}
+	if err := checkOneOfManyMessages(size, msgs); err != nil {
+		return nil, nil, err
+	}
+	if choice < 0 || choice >= len(msgs) {
+		return nil, nil, errors.New("choice out of range of messages")
+	}
+	if privKey.IsZero() {
+		return nil, nil, errors.New("private key is zero")
+	}
+
+	curve := r.curve
+	pubkey := curve.ScalarBaseMul(privKey)
+	ourIdx, ok := r.IndexOf(pubkey)
+	if !ok {
+		return nil, nil, ErrSignerNotInRing
+	}
+
+	k := len(msgs)
+	total := size * k
+	offsets, err := oneOfManyOffsets(curve, msgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	hps := r.hashPoints()
+
+	opening = curve.NewRandomScalar()
+	sig := &OneOfManySig{
+		ring:       r,
+		k:          k,
+		image:      curve.ScalarMul(privKey, hps[ourIdx]),
+		commitment: curve.ScalarBaseMul(opening).Add(curve.ScalarMul(offsets[choice], curve.AltBasePoint())),
+		s:          make([]types.Scalar, 2*total),
+	}
+	if !isTorsionFree(sig.image) {
+		// this should not happen
+		return nil, nil, errors.New("key image is not in the prime-order subgroup")
+	}
+
+	guard := make(scalarGuard, 2*total)
+	u, v := curve.NewRandomScalar(), curve.NewRandomScalar()
+	for _, n := range []types.Scalar{opening, u, v} {
+		if err := guard.check(n); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// the signer's pair is (ourIdx, choice); go around the virtual ring from
+	// the next pair
+	ch := newChallenger(curve, oneOfManyTranscript(msgs, sig.commitment))
+	keys := sig.commitmentKeys(offsets)
+	ourPair := ourIdx*k + choice
+	c := ch.challenge3(curve.ScalarBaseMul(u), curve.ScalarMul(u, hps[ourIdx]), curve.ScalarBaseMul(v))
+	for n := 1; n < total; n++ {
+		pair := (ourPair + n) % total
+		if pair == 0 {
+			sig.c = c
+		}
+
+		sig.s[2*pair], sig.s[2*pair+1] = curve.NewRandomScalar(), curve.NewRandomScalar()
+		for _, s := range sig.s[2*pair : 2*pair+2] {
+			if err := guard.check(s); err != nil {
+				return nil, nil, err
+			}
+		}
+		c = sig.step(ch, pair, c, keys, hps)
+	}
+	if ourPair == 0 {
+		sig.c = c
+	}
+
+	// close the ring with the private keys x and rho of the signer's pair
+	sig.s[2*ourPair] = u.Sub(c.Mul(privKey))
+	sig.s[2*ourPair+1] = v.Sub(c.Mul(opening))
+
+	if !sig.verify(msgs, keys, hps) {
+		// this should not happen
+		return nil, nil, errors.New("failed to close ring")
+	}
+	return sig, opening, nil
+}
+
+// commitmentKeys returns the keys D_t = C - h_t*G' of the messages.
+func (sig *OneOfManySig) commitmentKeys(offsets []types.Scalar) []types.Point {
+	curve := sig.ring.curve
+	keys := make([]types.Point, len(offsets))
+	for t, h := range offsets {
+		keys[t] = sig.commitment.Sub(curve.ScalarMul(h, curve.AltBasePoint()))
+	}
+	return keys
+}
+
+// step computes the challenge following the given pair, with challenge c:
+// H(m, L, R, L') with L = s*G + c*P_i, R = s*H_p(P_i) + c*I and L' = s'*G +
+// c*D_t.
+func (sig *OneOfManySig) step(ch *challenger, pair int, c types.Scalar, keys []types.Point, hps []types.Point) types.Scalar {
+	i, t := pair/sig.k, pair%sig.k
+	s, s2 := sig.s[2*pair], sig.s[2*pair+1]
+	l := ch.ops.doubleScalarBaseMul(c, sig.ring.pubkeys[i], s)
+	r := ch.ops.twoScalarMul(s, hps[i], c, sig.image)
+	l2 := ch.ops.doubleScalarBaseMul(c, keys[t], s2)
+	return ch.challenge3(l, r, l2)
+}
+
+// challenge3 computes H(m, L, R, L'), the challenge of a pair of a
+// OneOfManySig.
+func (ch *challenger) challenge3(l, r, l2 types.Point) types.Scalar {
+	ch.buf = append(ch.buf[:0], ch.m[:]...)
+	ch.buf = ch.ops.appendPoint(ch.buf, l)
+	ch.buf = ch.ops.appendPoint(ch.buf, r)
+	ch.buf = ch.ops.appendPoint(ch.buf, l2)
+	return ch.hash()
+}
+
+// Verify returns whether the signature is valid for the given list of
+// messages, which must be the list it was signed over, in the same order.
+func (sig *OneOfManySig) Verify(msgs [][32]byte) (ok bool) {
+	defer recoverFalse(&ok)
+
+	size := len(sig.ring.pubkeys)
+	if size == 0 || len(msgs) != sig.k || len(sig.s) != 2*size*sig.k {
+		return false
+	}
+	if checkOneOfManyMessages(size, msgs) != nil || !isTorsionFree(sig.image) || !isTorsionFree(sig.commitment) {
+		return false
+	}
+
+	offsets, err := oneOfManyOffsets(sig.ring.curve, msgs)
+	if err != nil {
+		return false
+	}
+	return sig.verify(msgs, sig.commitmentKeys(offsets), sig.ring.hashPoints())
+}
+
+func (sig *OneOfManySig) verify(msgs [][32]byte, keys []types.Point, hps []types.Point) bool {
+	ch := newChallenger(sig.ring.curve, oneOfManyTranscript(msgs, sig.commitment))
+	c := sig.c
+	for pair := 0; pair < len(sig.s)/2; pair++ {
+		c = sig.step(ch, pair, c, keys, hps)
+	}
+	return sig.c.Eq(c)
+}
+
+// VerifyChoice returns whether the opening, as returned by SignOneOf, opens the
+// signature's commitment to msgs[choice], ie. whether the signer chose that
+// message. It doesn't verify the signature itself; see Verify.
+func (sig *OneOfManySig) VerifyChoice(msgs [][32]byte, choice int, opening types.Scalar) (ok bool) {
+	defer recoverFalse(&ok)
+
+	if len(msgs) != sig.k || choice < 0 || choice >= len(msgs) {
+		return false
+	}
+	curve := sig.ring.curve
+	offsets, err := oneOfManyOffsets(curve, msgs[choice:choice+1])
+	if err != nil {
+		return false
+	}
+	expected := curve.ScalarBaseMul(opening).Add(curve.ScalarMul(offsets[0], curve.AltBasePoint()))
+	return equalPoints(expected, sig.commitment)
+}
+
+// Commitment returns the signature's Pedersen commitment to the chosen
+// message, C = rho*G + H(m)*G', with rho the opening and G' the curve's
+// alternate base point.
+func (sig *OneOfManySig) Commitment() types.Point {
+	return sig.commitment.Copy()
+}
+
+// Ring returns the ring the signature was created with.
+func (sig *OneOfManySig) Ring() *Ring {
+	return sig.ring
+}
+
+// KeyImage returns the signer's key image, which is the same as that of the
+// signer's RingSigs.
+func (sig *OneOfManySig) KeyImage() types.Point {
+	return sig.image.Copy()
+}
+
+// Serialize encodes the signature as the ring size and the number of messages
+// (each 4 bytes, big-endian), the challenge, the key image, the commitment,
+// the public keys, and the two responses of each pair, member by member and
+// message by message.
+func (sig *OneOfManySig) Serialize() ([]byte, error) {
+	size := len(sig.ring.pubkeys)
+	if size*sig.k > MaxRingSize {
+		return nil, errors.New("signature has too many responses")
+	}
+
+	out := binary.BigEndian.AppendUint32(nil, uint32(size))
+	out = binary.BigEndian.AppendUint32(out, uint32(sig.k))
+	out = append(out, sig.c.Encode()...)
+	out = append(out, encodePoint(sig.image)...)
+	out = append(out, encodePoint(sig.commitment)...)
+	for _, pk := range sig.ring.pubkeys {
+		out = append(out, encodePoint(pk)...)
+	}
+	for _, s := range sig.s {
+		out = append(out, s.Encode()...)
+	}
+	return out, nil
+}
+
+// Deserialize decodes a signature over the given curve encoded with Serialize.
+func (sig *OneOfManySig) Deserialize(curve types.Curve, in []byte) error {
+	if len(in) < 8 {
+		return errors.New("input too short")
+	}
+	r := bytes.NewBuffer(in)
+	size := binary.BigEndian.Uint32(r.Next(4))
+	k := binary.BigEndian.Uint32(r.Next(4))
+	if size == 0 || k == 0 || uint64(size)*uint64(k) > MaxRingSize {
+		return errors.New("invalid ring size or number of messages")
+	}
+
+	scalarLen, pointLen := ScalarSize(curve), curve.CompressedPointSize()
+	expected := scalarLen + 2*pointLen + int(size)*pointLen + 2*int(size*k)*scalarLen
+	if r.Len() != expected {
+		return fmt.Errorf("invalid length %d, expected %d", len(in), 8+expected)
+	}
+
+	res := &OneOfManySig{
+		k: int(k),
+		s: make([]types.Scalar, 2*size*k),
+	}
+	var err error
+	if res.c, err = curve.DecodeToScalar(r.Next(scalarLen)); err != nil {
+		return err
+	}
+	if res.image, err = curve.DecodeToPoint(r.Next(pointLen)); err != nil {
+		return err
+	}
+	if res.commitment, err = curve.DecodeToPoint(r.Next(pointLen)); err != nil {
+		return err
+	}
+	pubkeys := make([]types.Point, size)
+	for i := range pubkeys {
+		if pubkeys[i], err = curve.DecodeToPoint(r.Next(pointLen)); err != nil {
+			return fmt.Errorf("invalid public key at index %d: %w", i, err)
+		}
+	}
+	for i := range res.s {
+		if res.s[i], err = curve.DecodeToScalar(r.Next(scalarLen)); err != nil {
+			return err
+		}
+	}
+	res.ring = &Ring{pubkeys: pubkeys, curve: curve}
+
+	*sig = *res
+	return nil
+}
+
+// checkOneOfManyMessages checks that the messages are distinct, and that the
+// virtual ring isn't too large to be encoded.
+func checkOneOfManyMessages(size int, msgs [][32]byte) error {
+	if len(msgs) == 0 {
+		return errors.New("no messages")
+	}
+	if uint64(size)*uint64(len(msgs)) > MaxRingSize {
+		return errors.New("ring size times number of messages exceeds MaxRingSize")
+	}
+	seen := make(map[[32]byte]struct{}, len(msgs))
+	for _, m := range msgs {
+		if _, ok := seen[m]; ok {
+			return errors.New("duplicate message")
+		}
+		seen[m] = struct{}{}
+	}
+	return nil
+}
+
+// oneOfManyTranscript returns the message the challenges are computed over,
+// which binds the list of messages and the commitment.
+func oneOfManyTranscript(msgs [][32]byte, commitment types.Point) [32]byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte(oneOfManyDomain))
+	_, _ = h.Write(binary.BigEndian.AppendUint32(nil, uint32(len(msgs))))
+	for _, m := range msgs {
+		_, _ = h.Write(m[:])
+	}
+	_, _ = h.Write(encodePoint(commitment))
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// oneOfManyOffsets returns h_t = H(m_t) for each message.
+func oneOfManyOffsets(curve types.Curve, msgs [][32]byte) ([]types.Scalar, error) {
+	offsets := make([]types.Scalar, len(msgs))
+	for t, m := range msgs {
+		var err error
+		offsets[t], err = curve.HashToScalar(append([]byte(oneOfManyOffsetDomain), m[:]...))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return offsets, nil
+}
+
+// hashPoints returns H_p(P_i) for each public key of the ring.
+func (r *Ring) hashPoints() []types.Point {
+	r.ensureHP()
+	if cached := r.cachedHP(); cached != nil {
+		return cached
+	}
+	hps := make([]types.Point, len(r.pubkeys))
+	computeHP(r.pubkeys, hps)
+	return hps
+}
@@ -0,0 +1,101 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignOneOf(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 4, privKey, 2)
+		require.NoError(t, err)
+		choices := [][32]byte{{'y', 'e', 's'}, {'n', 'o'}, {'a', 'b', 's'}}
+
+		for choice := range choices {
+			sig, opening, err := keyring.SignOneOf(choices, choice, privKey)
+			require.NoError(t, err)
+			require.True(t, sig.Verify(choices))
+
+			// the opening reveals the choice, and only that one
+			for other := range choices {
+				require.Equal(t, other == choice, sig.VerifyChoice(choices, other, opening))
+			}
+			require.False(t, sig.VerifyChoice(choices, choice, curve.NewRandomScalar()))
+			require.False(t, sig.VerifyChoice(choices[:2], choice, opening))
+
+			// the list is bound, including its order
+			require.False(t, sig.Verify(choices[:2]))
+			require.False(t, sig.Verify([][32]byte{choices[1], choices[0], choices[2]}))
+			require.False(t, sig.Verify([][32]byte{choices[0], choices[1], {'m', 'a', 'y', 'b', 'e'}}))
+
+			enc, err := sig.Serialize()
+			require.NoError(t, err)
+			res := new(OneOfManySig)
+			require.NoError(t, res.Deserialize(curve, enc))
+			require.True(t, res.Verify(choices))
+			require.True(t, res.Ring().Equals(keyring))
+			require.True(t, res.VerifyChoice(choices, choice, opening))
+
+			// linkable with ordinary signatures
+			plain, err := keyring.Sign(testMsg, privKey)
+			require.NoError(t, err)
+			require.True(t, res.KeyImage().Equals(plain.KeyImage()))
+		}
+	}
+}
+
+func TestSignOneOf_Errors(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 3, privKey, 0)
+	require.NoError(t, err)
+	choices := [][32]byte{{1}, {2}}
+
+	_, _, err = keyring.SignOneOf(nil, 0, privKey)
+	require.Error(t, err)
+	_, _, err = keyring.SignOneOf(choices, 2, privKey)
+	require.Error(t, err)
+	_, _, err = keyring.SignOneOf([][32]byte{{1}, {1}}, 0, privKey)
+	require.Error(t, err)
+	_, _, err = keyring.SignOneOf(choices, 0, curve.NewRandomScalar())
+	require.ErrorIs(t, err, ErrSignerNotInRing)
+
+	sig, _, err := keyring.SignOneOf(choices, 1, privKey)
+	require.NoError(t, err)
+	sig.s[1] = sig.s[1].Add(curve.ScalarFromInt(1))
+	require.False(t, sig.Verify(choices))
+
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+	res := new(OneOfManySig)
+	require.Error(t, res.Deserialize(curve, enc[:len(enc)-1]))
+	require.Error(t, res.Deserialize(curve, enc[:7]))
+	require.Error(t, res.Deserialize(Ed25519(), enc))
+}
+
+func TestSignOneOf_Commitment(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 3, privKey, 1)
+	require.NoError(t, err)
+	choices := [][32]byte{{1}, {2}}
+
+	// the commitment hides the choice: it's different for each signature,
+	// even over the same choice
+	a, openA, err := keyring.SignOneOf(choices, 0, privKey)
+	require.NoError(t, err)
+	b, _, err := keyring.SignOneOf(choices, 0, privKey)
+	require.NoError(t, err)
+	require.False(t, a.Commitment().Equals(b.Commitment()))
+
+	// the signature is bound to its commitment, so it can't be swapped for a
+	// commitment to another choice
+	c, openC, err := keyring.SignOneOf(choices, 1, privKey)
+	require.NoError(t, err)
+	swapped := &OneOfManySig{ring: a.ring, k: a.k, c: a.c, image: a.image, commitment: c.commitment, s: a.s}
+	require.False(t, swapped.Verify(choices))
+	require.True(t, swapped.VerifyChoice(choices, 1, openC))
+	require.False(t, swapped.VerifyChoice(choices, 0, openA))
+}
@@ -0,0 +1,123 @@
+package ring
+
+import "github.com/athanorlabs/go-dleq/types"
+
+// SignOption configures optional behaviour of Sign.
+type SignOption func(*signOptions)
+
+type signOptions struct {
+	ext     extensions
+	monitor *CommitmentMonitor
+
+	// policy is checked before signing, counting the signature in scope; see
+	// WithSignerPolicy.
+	policy *SignerPolicy
+	scope  string
+
+	// minWeight, if set, is the minimum total weight of the ring; see
+	// WithMinTotalWeight.
+	minWeight *uint64
+
+	// auditSeed, if set, is the seed the decoy responses are derived from; see
+	// WithAuditableDecoys.
+	auditSeed *AuditSeed
+
+	// deterministic is true if the nonces are derived from the private key
+	// and the message; see WithDeterministicNonces.
+	deterministic bool
+
+	// keyImage, if set, is the signer's key image, computed beforehand and
+	// proven correct by keyImageProof; see SignWithKeyImage.
+	keyImage      types.Point
+	keyImageProof *KeyImageProof
+
+	// embedDigest is true if the message is embedded in the signature; see
+	// WithEmbeddedDigest.
+	embedDigest bool
+
+	// signerIdx is the signer's index in the ring, if signerIdxSet is true.
+	signerIdx    int
+	signerIdxSet bool
+
+	// allowSingleton is true if rings of a single member are accepted; see
+	// AllowSingletonRing.
+	allowSingleton bool
+
+	// selfCheckSet is true if WithSelfCheck was used, in which case
+	// selfCheckEnabled overrides the default.
+	selfCheckSet, selfCheckEnabled bool
+
+	// progress, if set, is called with the number of ring members processed
+	// after each of them, and aborts signing if it returns an error; see
+	// SignAsync.
+	progress func(done int) error
+
+	// err is the first error from an option, returned by Sign.
+	err error
+}
+
+func newSignOptions(opts []SignOption) *signOptions {
+	o := &signOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// setErr records err if no error was recorded yet.
+func (o *signOptions) setErr(err error) {
+	if o.err == nil {
+		o.err = err
+	}
+}
+
+// WithSignerIndex gives the index of the signer's public key in the ring, so
+// that Sign doesn't have to look it up. Sign returns an error if the key at
+// the index isn't the signer's.
+func WithSignerIndex(idx int) SignOption {
+	return func(o *signOptions) {
+		o.signerIdx = idx
+		o.signerIdxSet = true
+	}
+}
+
+// AllowSingletonRing makes Sign accept rings of a single member, the signer,
+// eg. for protocols that degrade to a single participant while bootstrapping.
+// Without it, Sign returns ErrSingletonRing for them.
+//
+// A signature over a singleton ring is an ordinary signature, encoded like
+// any other with a ring size of 1, and Verify accepts it. It proves that the
+// signer holds the private key of the ring's only member, and its key image
+// links it with the signer's other signatures, but it provides no anonymity:
+// verifiers that require some check the size of the signature's ring.
+func AllowSingletonRing() SignOption {
+	return func(o *signOptions) {
+		o.allowSingleton = true
+	}
+}
+
+// selfCheckMaxDefaultSize is the size of the largest ring for which Sign
+// verifies the signature it created by default. Verification costs about as
+// much as signing, so for larger rings it must be enabled with WithSelfCheck.
+const selfCheckMaxDefaultSize = 256
+
+// WithSelfCheck sets whether Sign fully verifies the signature it created
+// before returning it, which protects against faults (eg. memory corruption
+// or induced glitches) producing invalid signatures that could leak
+// information about the private key, at the cost of about doubling the
+// signing time. It's enabled by default for rings of up to 256 members.
+func WithSelfCheck(enabled bool) SignOption {
+	return func(o *signOptions) {
+		o.selfCheckSet = true
+		o.selfCheckEnabled = enabled
+	}
+}
+
+// selfCheck returns whether a signature over a ring of the given size must be
+// verified after signing.
+func (o *signOptions) selfCheck(size int) bool {
+	if o.selfCheckSet {
+		return o.selfCheckEnabled
+	}
+	return size <= selfCheckMaxDefaultSize
+}
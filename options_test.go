@@ -0,0 +1,30 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSelfCheck(t *testing.T) {
+	o := newSignOptions(nil)
+	require.True(t, o.selfCheck(2))
+	require.True(t, o.selfCheck(selfCheckMaxDefaultSize))
+	require.False(t, o.selfCheck(selfCheckMaxDefaultSize+1))
+
+	o = newSignOptions([]SignOption{WithSelfCheck(true)})
+	require.True(t, o.selfCheck(selfCheckMaxDefaultSize+1))
+
+	o = newSignOptions([]SignOption{WithSelfCheck(false)})
+	require.False(t, o.selfCheck(2))
+
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 1)
+	require.NoError(t, err)
+	for _, enabled := range []bool{false, true} {
+		sig, err := keyring.Sign(testMsg, privKey, WithSelfCheck(enabled))
+		require.NoError(t, err)
+		require.True(t, sig.Verify(testMsg))
+	}
+}
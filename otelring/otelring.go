@@ -0,0 +1,154 @@
+// Package otelring provides OpenTelemetry instrumentation for ring signature
+// operations.
+//
+// A Tracer wraps Sign, Verify and Deserialize in spans carrying the curve, the
+// ring size, the result and the duration of the operation, so that their cost
+// shows up in distributed traces without instrumenting every call site:
+//
+//	tracer := otelring.New()
+//	sig, err := tracer.Sign(ctx, keyring, msg, privKey)
+//	...
+//	ok := tracer.Verify(ctx, sig, msg)
+package otelring
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// ScopeName is the instrumentation scope name of the tracer.
+const ScopeName = "github.com/pokt-network/ring-go/otelring"
+
+// Span attribute keys.
+const (
+	// CurveKey is the name of the curve, eg. "secp256k1".
+	CurveKey = attribute.Key("ring.curve")
+	// SizeKey is the number of public keys in the ring.
+	SizeKey = attribute.Key("ring.size")
+	// ResultKey is the result of the operation: "ok", "invalid" or "error".
+	ResultKey = attribute.Key("ring.result")
+	// DurationKey is the duration of the operation in nanoseconds.
+	DurationKey = attribute.Key("ring.duration_ns")
+)
+
+// Results recorded under ResultKey.
+const (
+	ResultOK      = "ok"
+	ResultInvalid = "invalid"
+	ResultError   = "error"
+)
+
+// Tracer wraps ring signature operations in spans. It's safe for concurrent
+// use.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// Option configures a Tracer.
+type Option func(*config)
+
+type config struct {
+	provider trace.TracerProvider
+}
+
+// WithTracerProvider sets the TracerProvider used to create spans. It
+// defaults to the global provider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// New returns a new Tracer.
+func New(opts ...Option) *Tracer {
+	cfg := config{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.provider == nil {
+		cfg.provider = otel.GetTracerProvider()
+	}
+
+	return &Tracer{
+		tracer: cfg.provider.Tracer(ScopeName),
+	}
+}
+
+// Sign calls keyring.Sign in a "ring.Sign" span.
+func (t *Tracer) Sign(ctx context.Context, keyring *ring.Ring, m [32]byte, privKey ring.Scalar) (*ring.RingSig, error) {
+	_, span := t.tracer.Start(ctx, "ring.Sign", trace.WithAttributes(ringAttributes(keyring)...))
+	defer span.End()
+
+	start := time.Now()
+	sig, err := keyring.Sign(m, privKey)
+	end(span, start, err, true)
+	return sig, err
+}
+
+// Verify calls sig.Verify in a "ring.Verify" span.
+func (t *Tracer) Verify(ctx context.Context, sig *ring.RingSig, m [32]byte) bool {
+	_, span := t.tracer.Start(ctx, "ring.Verify")
+	defer span.End()
+
+	if sig.Ring() != nil {
+		span.SetAttributes(ringAttributes(sig.Ring())...)
+	}
+
+	start := time.Now()
+	ok := sig.Verify(m)
+	end(span, start, nil, ok)
+	return ok
+}
+
+// Deserialize decodes a ring signature, like RingSig.Deserialize, in a
+// "ring.Deserialize" span.
+func (t *Tracer) Deserialize(ctx context.Context, curve ring.Curve, in []byte) (*ring.RingSig, error) {
+	_, span := t.tracer.Start(ctx, "ring.Deserialize", trace.WithAttributes(
+		CurveKey.String(ring.CurveIDOf(curve).String()),
+		attribute.Int("ring.encoded_len", len(in)),
+	))
+	defer span.End()
+
+	start := time.Now()
+	sig := new(ring.RingSig)
+	err := sig.Deserialize(curve, in)
+	if err == nil {
+		span.SetAttributes(SizeKey.Int(sig.Ring().Size()))
+	} else {
+		sig = nil
+	}
+
+	end(span, start, err, true)
+	return sig, err
+}
+
+func ringAttributes(keyring *ring.Ring) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		CurveKey.String(ring.CurveIDOf(keyring.Curve()).String()),
+		SizeKey.Int(keyring.Size()),
+	}
+}
+
+// end records the result and duration of an operation on the span.
+func end(span trace.Span, start time.Time, err error, ok bool) {
+	span.SetAttributes(DurationKey.Int64(time.Since(start).Nanoseconds()))
+
+	switch {
+	case err != nil:
+		span.SetAttributes(ResultKey.String(ResultError))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case !ok:
+		span.SetAttributes(ResultKey.String(ResultInvalid))
+	default:
+		span.SetAttributes(ResultKey.String(ResultOK))
+	}
+}
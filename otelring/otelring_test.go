@@ -0,0 +1,97 @@
+package otelring
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+var testMsg = [32]byte{1, 2, 3}
+
+func newTestTracer() (*Tracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return New(WithTracerProvider(provider)), recorder
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	ret := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		ret[kv.Key] = kv.Value
+	}
+	return ret
+}
+
+func TestTracer(t *testing.T) {
+	tracer, recorder := newTestTracer()
+	ctx := context.Background()
+
+	curve := ring.Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := ring.NewKeyRing(curve, 4, privKey, 2)
+	require.NoError(t, err)
+
+	sig, err := tracer.Sign(ctx, keyring, testMsg, privKey)
+	require.NoError(t, err)
+	require.True(t, tracer.Verify(ctx, sig, testMsg))
+	require.False(t, tracer.Verify(ctx, sig, [32]byte{}))
+
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+	res, err := tracer.Deserialize(ctx, curve, enc)
+	require.NoError(t, err)
+	require.True(t, res.Equal(sig))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+
+	expected := []struct {
+		name   string
+		result string
+	}{
+		{"ring.Sign", ResultOK},
+		{"ring.Verify", ResultOK},
+		{"ring.Verify", ResultInvalid},
+		{"ring.Deserialize", ResultOK},
+	}
+	for i, span := range spans {
+		require.Equal(t, expected[i].name, span.Name())
+
+		attrs := attributes(span)
+		require.Equal(t, "ed25519", attrs[CurveKey].AsString())
+		require.Equal(t, int64(4), attrs[SizeKey].AsInt64())
+		require.Equal(t, expected[i].result, attrs[ResultKey].AsString())
+		require.Contains(t, attrs, DurationKey)
+	}
+}
+
+func TestTracer_Errors(t *testing.T) {
+	tracer, recorder := newTestTracer()
+	ctx := context.Background()
+
+	curve := ring.Secp256k1()
+	keyring, err := ring.NewKeyRing(curve, 2, curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+
+	// not a member of the ring
+	_, err = tracer.Sign(ctx, keyring, testMsg, curve.NewRandomScalar())
+	require.Error(t, err)
+
+	sig, err := tracer.Deserialize(ctx, curve, []byte{1, 2, 3})
+	require.Error(t, err)
+	require.Nil(t, sig)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	for _, span := range spans {
+		require.Equal(t, ResultError, attributes(span)[ResultKey].AsString())
+		require.Equal(t, codes.Error, span.Status().Code)
+	}
+}
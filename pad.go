@@ -0,0 +1,151 @@
+package ring
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// DecoyProvider supplies decoy public keys used to pad a ring to a target size.
+type DecoyProvider interface {
+	// Decoys returns at least n candidate decoy public keys on the given curve.
+	// It may return more than n keys; keys that are already in the ring, or
+	// that are returned more than once, are skipped.
+	Decoys(curve types.Curve, n int) ([]types.Point, error)
+}
+
+// StaticDecoys is a DecoyProvider that always returns the same list of keys,
+// in order.
+type StaticDecoys []types.Point
+
+// Decoys implements DecoyProvider.
+func (d StaticDecoys) Decoys(_ types.Curve, _ int) ([]types.Point, error) {
+	return d, nil
+}
+
+// DecoyProviderFunc is a DecoyProvider implemented by a function, eg. a
+// callback querying candidate keys from a chain.
+type DecoyProviderFunc func(curve types.Curve, n int) ([]types.Point, error)
+
+// Decoys implements DecoyProvider.
+func (f DecoyProviderFunc) Decoys(curve types.Curve, n int) ([]types.Point, error) {
+	return f(curve, n)
+}
+
+// PadTo returns a new ring of the given size containing the ring's public keys
+// and decoys drawn from the provider, which is useful when a protocol mandates
+// a fixed ring size.
+//
+// The keys of the returned ring are sorted by their encoding, so the position
+// of the original keys does not reveal which keys were added. H_p(P_i) values
+// already cached on the ring are reused, so only those of the decoys are
+// computed. The ring itself is not modified, and is returned as-is if it is
+// already of the given size.
+func (r *Ring) PadTo(size int, decoySource DecoyProvider) (*Ring, error) {
+	if size < r.Size() {
+		return nil, fmt.Errorf("ring size %d is larger than target size %d", r.Size(), size)
+	}
+
+	if size > MaxRingSize {
+		return nil, fmt.Errorf("target size %d exceeds maximum ring size %d", size, MaxRingSize)
+	}
+
+	if size == r.Size() {
+		return r, nil
+	}
+
+	if decoySource == nil {
+		return nil, errors.New("nil decoy provider")
+	}
+
+	need := size - r.Size()
+	candidates, err := decoySource.Decoys(r.curve, need)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decoys: %w", err)
+	}
+
+	seen := make(map[string]struct{}, size)
+	keys := make([]paddedKey, 0, size)
+	r.ensureHP()
+	cached := r.cachedHP()
+	for i, pk := range r.pubkeys {
+		enc := encodePoint(pk)
+		seen[string(enc)] = struct{}{}
+
+		var hp types.Point
+		if cached != nil {
+			hp = cached[i]
+		}
+		keys = append(keys, paddedKey{pk: pk, hp: hp, enc: enc})
+	}
+
+	var decoys []types.Point
+	for _, pk := range candidates {
+		if len(decoys) == need {
+			break
+		}
+
+		if pk == nil {
+			continue
+		}
+
+		// decoding the key also checks that it's on the ring's curve
+		enc := encodePoint(pk)
+		decoy, err := r.curve.DecodeToPoint(enc)
+		if err != nil {
+			continue
+		}
+
+		if _, ok := seen[string(enc)]; ok {
+			continue
+		}
+		seen[string(enc)] = struct{}{}
+
+		decoys = append(decoys, decoy)
+		keys = append(keys, paddedKey{pk: decoy, enc: enc})
+	}
+
+	if len(decoys) < need {
+		return nil, fmt.Errorf("decoy provider returned %d usable decoys, need %d", len(decoys), need)
+	}
+
+	cache := cached != nil && size <= hpCacheMaxSize
+	if cache {
+		hp := make([]types.Point, len(decoys))
+		computeHP(decoys, hp)
+		for i := range decoys {
+			keys[r.Size()+i].hp = hp[i]
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i].enc, keys[j].enc) < 0
+	})
+
+	padded := &Ring{
+		pubkeys: make([]types.Point, size),
+		curve:   r.curve,
+	}
+	for i, k := range keys {
+		padded.pubkeys[i] = k.pk
+	}
+
+	if cache {
+		hp := make([]types.Point, size)
+		for i, k := range keys {
+			hp[i] = k.hp
+		}
+		padded.setHP(hp)
+	}
+
+	return padded, nil
+}
+
+type paddedKey struct {
+	pk  types.Point
+	hp  types.Point
+	enc []byte
+}
@@ -0,0 +1,121 @@
+package ring
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"github.com/stretchr/testify/require"
+)
+
+func randomPubkeys(curve types.Curve, n int) []types.Point {
+	pubkeys := make([]types.Point, n)
+	for i := range pubkeys {
+		pubkeys[i] = curve.ScalarBaseMul(curve.NewRandomScalar())
+	}
+	return pubkeys
+}
+
+func TestPadTo(t *testing.T) {
+	for _, curve := range []types.Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 3, privKey, 1)
+		require.NoError(t, err)
+
+		decoys := randomPubkeys(curve, 5)
+		padded, err := keyring.PadTo(8, StaticDecoys(decoys))
+		require.NoError(t, err)
+		require.Equal(t, 8, padded.Size())
+		require.Equal(t, 3, keyring.Size())
+
+		// the padded ring contains all of the original keys and the decoys, in
+		// canonical order
+		for _, pk := range append(keyring.PublicKeys(), decoys...) {
+			found := false
+			for _, p := range padded.pubkeys {
+				found = found || p.Equals(pk)
+			}
+			require.True(t, found)
+		}
+		for i := 1; i < padded.Size(); i++ {
+			require.Equal(t, -1, bytes.Compare(padded.pubkeys[i-1].Encode(), padded.pubkeys[i].Encode()))
+		}
+
+		// the cached H_p values match the padded ring
+		require.NotNil(t, padded.hp)
+		for i, pk := range padded.pubkeys {
+			require.True(t, padded.hp[i].Equals(hashToCurve(pk)))
+		}
+
+		sig, err := padded.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		require.True(t, sig.Verify(testMsg))
+
+		same, err := padded.PadTo(8, nil)
+		require.NoError(t, err)
+		require.Same(t, padded, same)
+	}
+}
+
+func TestPadTo_SkipsDuplicates(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 2, privKey, 0)
+	require.NoError(t, err)
+
+	decoys := randomPubkeys(curve, 2)
+	candidates := []types.Point{keyring.pubkeys[1], decoys[0], nil, decoys[0], Ed25519().BasePoint(), decoys[1]}
+
+	var requested int
+	provider := DecoyProviderFunc(func(_ types.Curve, n int) ([]types.Point, error) {
+		requested = n
+		return candidates, nil
+	})
+
+	padded, err := keyring.PadTo(4, provider)
+	require.NoError(t, err)
+	require.Equal(t, 2, requested)
+	require.Equal(t, 4, padded.Size())
+
+	_, err = keyring.PadTo(5, provider)
+	require.EqualError(t, err, "decoy provider returned 2 usable decoys, need 3")
+}
+
+func TestPadTo_Errors(t *testing.T) {
+	curve := Ed25519()
+	keyring, err := NewKeyRing(curve, 4, curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+
+	_, err = keyring.PadTo(3, StaticDecoys(nil))
+	require.Error(t, err)
+
+	_, err = keyring.PadTo(MaxRingSize+1, StaticDecoys(nil))
+	require.Error(t, err)
+
+	_, err = keyring.PadTo(5, nil)
+	require.Error(t, err)
+
+	_, err = keyring.PadTo(5, DecoyProviderFunc(func(types.Curve, int) ([]types.Point, error) {
+		return nil, errors.New("unavailable")
+	}))
+	require.EqualError(t, err, "failed to get decoys: unavailable")
+}
+
+func TestPadTo_Uncached(t *testing.T) {
+	defer func(size int) { hpCacheMaxSize = size }(hpCacheMaxSize)
+	hpCacheMaxSize = 4
+
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 3, privKey, 2)
+	require.NoError(t, err)
+
+	padded, err := keyring.PadTo(6, StaticDecoys(randomPubkeys(curve, 3)))
+	require.NoError(t, err)
+	require.Nil(t, padded.hp)
+
+	sig, err := padded.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	require.True(t, sig.Verify(testMsg))
+}
@@ -0,0 +1,138 @@
+package ring
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq/types"
+	dsecp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/cryptobyte"
+	cbasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1      = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// NewRingFromPEM builds a ring from the public keys of PEM-encoded X.509
+// certificates ("CERTIFICATE" blocks) and PKIX public keys ("PUBLIC KEY"
+// blocks), in the order of the blocks. Other blocks are ignored.
+//
+// The keys must be secp256k1 ECDSA keys or ed25519 keys, all on the same
+// curve, and distinct. Certificates are not verified: only their public keys
+// are used, so callers must check them beforehand if needed.
+func NewRingFromPEM(certsOrKeys []byte) (*Ring, error) {
+	var (
+		curveID CurveID
+		pubkeys []types.Point
+		seen    = make(map[string]bool)
+	)
+
+	rest := certsOrKeys
+	for i := 0; ; i++ {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		var spki []byte
+		switch block.Type {
+		case "CERTIFICATE":
+			var err error
+			if spki, err = certificatePublicKeyInfo(block.Bytes); err != nil {
+				return nil, fmt.Errorf("PEM block %d: %w", i, err)
+			}
+		case "PUBLIC KEY":
+			spki = block.Bytes
+		default:
+			continue
+		}
+
+		pk, err := parsePublicKeyInfo(spki)
+		if err != nil {
+			return nil, fmt.Errorf("PEM block %d: %w", i, err)
+		}
+
+		id := CurveIDOfPoint(pk)
+		if curveID == CurveUnknown {
+			curveID = id
+		} else if id != curveID {
+			return nil, fmt.Errorf("PEM block %d: all keys must be on the same curve", i)
+		}
+
+		enc := string(encodePoint(pk))
+		if seen[enc] {
+			return nil, fmt.Errorf("PEM block %d: duplicate public key", i)
+		}
+		seen[enc] = true
+
+		pubkeys = append(pubkeys, pk)
+	}
+
+	if len(pubkeys) == 0 {
+		return nil, errors.New("no public keys found in PEM data")
+	}
+
+	curve, err := curveID.Curve()
+	if err != nil {
+		return nil, err
+	}
+	return NewFixedKeyRingFromPublicKeys(curve, pubkeys)
+}
+
+// certificatePublicKeyInfo returns the DER-encoded SubjectPublicKeyInfo of a
+// DER-encoded certificate. The certificate is parsed by hand, as
+// crypto/x509 rejects certificates with secp256k1 keys.
+func certificatePublicKeyInfo(der []byte) ([]byte, error) {
+	input := cryptobyte.String(der)
+	var cert, tbs cryptobyte.String
+	var spki []byte
+	if !input.ReadASN1(&cert, cbasn1.SEQUENCE) ||
+		!cert.ReadASN1(&tbs, cbasn1.SEQUENCE) ||
+		!tbs.SkipOptionalASN1(cbasn1.Tag(0).Constructed().ContextSpecific()) ||
+		!tbs.SkipASN1(cbasn1.INTEGER) || // serialNumber
+		!tbs.SkipASN1(cbasn1.SEQUENCE) || // signature
+		!tbs.SkipASN1(cbasn1.SEQUENCE) || // issuer
+		!tbs.SkipASN1(cbasn1.SEQUENCE) || // validity
+		!tbs.SkipASN1(cbasn1.SEQUENCE) || // subject
+		!tbs.ReadASN1Element((*cryptobyte.String)(&spki), cbasn1.SEQUENCE) {
+		return nil, errors.New("malformed certificate")
+	}
+	return spki, nil
+}
+
+// parsePublicKeyInfo parses a DER-encoded SubjectPublicKeyInfo holding a
+// secp256k1 or ed25519 public key.
+func parsePublicKeyInfo(spki []byte) (types.Point, error) {
+	input := cryptobyte.String(spki)
+	var info, alg cryptobyte.String
+	var algOID, paramOID asn1.ObjectIdentifier
+	var key asn1.BitString
+	if !input.ReadASN1(&info, cbasn1.SEQUENCE) ||
+		!info.ReadASN1(&alg, cbasn1.SEQUENCE) ||
+		!alg.ReadASN1ObjectIdentifier(&algOID) ||
+		!info.ReadASN1BitString(&key) {
+		return nil, errors.New("malformed public key")
+	}
+
+	// crypto/x509 doesn't support secp256k1
+	if algOID.Equal(oidPublicKeyECDSA) && alg.PeekASN1Tag(cbasn1.OBJECT_IDENTIFIER) &&
+		alg.ReadASN1ObjectIdentifier(&paramOID) && paramOID.Equal(oidSecp256k1) {
+		pk, err := dsecp256k1.ParsePubKey(key.RightAlign())
+		if err != nil {
+			return nil, err
+		}
+		return Secp256k1().DecodeToPoint(pk.SerializeCompressed())
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(spki)
+	if err != nil {
+		return nil, err
+	}
+	return PointFromPublicKey(pub)
+}
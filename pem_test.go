@@ -0,0 +1,152 @@
+package ring
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/cryptobyte"
+	cbasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+func ed25519CertPEM(t *testing.T) (ed25519.PublicKey, []byte) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "member"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pub, priv)
+	require.NoError(t, err)
+	return pub, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func secp256k1PublicKeyInfo(t *testing.T, p Point) []byte {
+	pub, err := ECDSAPubFromPoint(p)
+	require.NoError(t, err)
+
+	uncompressed := make([]byte, 65)
+	uncompressed[0] = 4
+	pub.X.FillBytes(uncompressed[1:33])
+	pub.Y.FillBytes(uncompressed[33:])
+
+	param, err := asn1.Marshal(oidSecp256k1)
+	require.NoError(t, err)
+	spki, err := asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidPublicKeyECDSA, Parameters: asn1.RawValue{FullBytes: param}},
+		PublicKey: asn1.BitString{Bytes: uncompressed, BitLength: 8 * len(uncompressed)},
+	})
+	require.NoError(t, err)
+	return spki
+}
+
+// withPublicKeyInfo returns a copy of the certificate with its public key
+// replaced, as crypto/x509 can't create certificates with secp256k1 keys. The
+// certificate's signature is not valid anymore.
+func withPublicKeyInfo(t *testing.T, certDER, spki []byte) []byte {
+	input := cryptobyte.String(certDER)
+	var cert, tbs, rest cryptobyte.String
+	var version, serial, sigAlg, issuer, validity, subject, oldSPKI cryptobyte.String
+	require.True(t, input.ReadASN1(&cert, cbasn1.SEQUENCE))
+	require.True(t, cert.ReadASN1(&tbs, cbasn1.SEQUENCE))
+	require.True(t, tbs.ReadOptionalASN1(&version, nil, cbasn1.Tag(0).Constructed().ContextSpecific()))
+	for _, el := range []*cryptobyte.String{&serial, &sigAlg, &issuer, &validity, &subject, &oldSPKI} {
+		require.True(t, tbs.ReadAnyASN1Element(el, nil))
+	}
+	rest = tbs
+
+	var b cryptobyte.Builder
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			for _, el := range [][]byte{serial, sigAlg, issuer, validity, subject, spki, rest} {
+				b.AddBytes(el)
+			}
+		})
+		b.AddBytes(cert)
+	})
+	return b.BytesOrPanic()
+}
+
+func TestNewRingFromPEM_Ed25519(t *testing.T) {
+	pub1, cert1 := ed25519CertPEM(t)
+	pub2, cert2 := ed25519CertPEM(t)
+	pub3, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	spki3, err := x509.MarshalPKIXPublicKey(pub3)
+	require.NoError(t, err)
+
+	data := append(append(cert1, "garbage between blocks\n"...), cert2...)
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: spki3})...)
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "EC PARAMETERS", Bytes: []byte{1}})...)
+
+	keyring, err := NewRingFromPEM(data)
+	require.NoError(t, err)
+	require.Equal(t, CurveEd25519, CurveIDOf(keyring.Curve()))
+	require.Equal(t, 3, keyring.Size())
+	for i, pub := range []ed25519.PublicKey{pub1, pub2, pub3} {
+		require.Equal(t, []byte(pub), keyring.pubkeys[i].Encode())
+	}
+}
+
+func TestNewRingFromPEM_Secp256k1(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	other := curve.ScalarBaseMul(curve.NewRandomScalar())
+
+	_, edCert := ed25519CertPEM(t)
+	block, _ := pem.Decode(edCert)
+	certDER := withPublicKeyInfo(t, block.Bytes, secp256k1PublicKeyInfo(t, curve.ScalarBaseMul(privKey)))
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: secp256k1PublicKeyInfo(t, other)})...)
+
+	keyring, err := NewRingFromPEM(data)
+	require.NoError(t, err)
+	require.Equal(t, CurveSecp256k1, CurveIDOf(keyring.Curve()))
+	require.True(t, keyring.pubkeys[0].Equals(curve.ScalarBaseMul(privKey)))
+	require.True(t, keyring.pubkeys[1].Equals(other))
+
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	require.True(t, sig.Verify(testMsg))
+}
+
+func TestNewRingFromPEM_Errors(t *testing.T) {
+	_, edCert := ed25519CertPEM(t)
+	secpKey := pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: secp256k1PublicKeyInfo(t, Secp256k1().ScalarBaseMul(Secp256k1().NewRandomScalar())),
+	})
+
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	spki, err := x509.MarshalPKIXPublicKey(&p256.PublicKey)
+	require.NoError(t, err)
+	p256Key := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: spki})
+
+	for name, data := range map[string][]byte{
+		"empty":       nil,
+		"mixed":       append(append([]byte{}, edCert...), secpKey...),
+		"duplicate":   append(append([]byte{}, edCert...), edCert...),
+		"unsupported": p256Key,
+		"malformed":   pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{0x30, 0}}),
+	} {
+		_, err := NewRingFromPEM(data)
+		require.Error(t, err, name)
+	}
+}
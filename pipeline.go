@@ -0,0 +1,190 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// ErrPipelineClosed is returned by Pipeline.Enqueue after Close.
+var ErrPipelineClosed = errors.New("pipeline is closed")
+
+// PipelineOpts are the options of NewPipeline.
+type PipelineOpts struct {
+	// Workers is the number of signatures verified concurrently. It defaults
+	// to GOMAXPROCS.
+	Workers int
+
+	// QueueSize is the number of signatures that can be enqueued before
+	// their results are read, after which Enqueue blocks. It defaults to
+	// 4*Workers.
+	QueueSize int
+
+	// Verify are the options each signature is verified with, as by
+	// VerifyWithOpts. It may be nil.
+	Verify *VerifyOpts
+}
+
+// PipelineResult is the result of the verification of an enqueued signature.
+type PipelineResult struct {
+	// Seq is the position of the signature in the order of the calls to
+	// Enqueue, starting at 0.
+	Seq uint64
+	Sig *RingSig
+	Msg [32]byte
+	// Err is nil if the signature is valid, and the error of VerifyWithOpts
+	// otherwise.
+	Err error
+}
+
+// Pipeline verifies signatures concurrently, with bounded memory, eg. for
+// block processors verifying the signatures of many transactions. Signatures
+// are enqueued with Enqueue, which blocks while the queue is full, and their
+// results are read from Results in the order they were enqueued.
+//
+// Close stops accepting signatures; Results is closed once the results of all
+// the signatures enqueued before have been read:
+//
+//	p := ring.NewPipeline(ctx, nil)
+//	go func() {
+//		defer p.Close()
+//		for _, tx := range block.Txs {
+//			if err := p.Enqueue(tx.Sig, tx.Msg); err != nil {
+//				return
+//			}
+//		}
+//	}()
+//	for res := range p.Results() {
+//		...
+//	}
+//
+// Canceling the context makes Enqueue fail, and discards the results that
+// haven't been read; Results is still closed only after Close.
+type Pipeline struct {
+	ctx  context.Context
+	opts *VerifyOpts
+
+	mu      sync.Mutex
+	closed  bool
+	seq     uint64
+	jobs    chan *pipelineJob
+	pending chan *pipelineJob
+	results chan PipelineResult
+}
+
+type pipelineJob struct {
+	res  PipelineResult
+	done chan struct{}
+}
+
+// NewPipeline starts a pipeline with the given options, which may be nil. Its
+// goroutines exit after Close, once the results have been read or the context
+// has been canceled.
+func NewPipeline(ctx context.Context, opts *PipelineOpts) *Pipeline {
+	if opts == nil {
+		opts = &PipelineOpts{}
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	queueSize := opts.QueueSize
+	if queueSize <= 0 {
+		queueSize = 4 * workers
+	}
+
+	p := &Pipeline{
+		ctx:     ctx,
+		opts:    opts.Verify,
+		jobs:    make(chan *pipelineJob, queueSize),
+		pending: make(chan *pipelineJob, queueSize),
+		results: make(chan PipelineResult),
+	}
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	go p.sequence()
+	return p
+}
+
+// Enqueue adds a signature to verify for the given message. It blocks while
+// the queue is full, and returns ErrPipelineClosed after Close, and the
+// context's error once it's canceled. It's safe for concurrent use; the
+// order of the results is that in which the calls to Enqueue returned.
+func (p *Pipeline) Enqueue(sig *RingSig, m [32]byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrPipelineClosed
+	}
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+
+	job := &pipelineJob{
+		res:  PipelineResult{Seq: p.seq, Sig: sig, Msg: m},
+		done: make(chan struct{}),
+	}
+	select {
+	case p.pending <- job:
+	case <-p.ctx.Done():
+		return p.ctx.Err()
+	}
+	// jobs has the same capacity as pending, and jobs leave it before
+	// leaving pending, so this never blocks
+	p.jobs <- job
+	p.seq++
+	return nil
+}
+
+// Close stops accepting signatures. The signatures already enqueued are still
+// verified, and their results sent on Results, which is closed after the
+// last one. It's safe to call Close more than once.
+func (p *Pipeline) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.closed {
+		p.closed = true
+		close(p.jobs)
+		close(p.pending)
+	}
+}
+
+// Results returns the channel of results, in the order the signatures were
+// enqueued. It must be read until it's closed, unless the context is
+// canceled.
+func (p *Pipeline) Results() <-chan PipelineResult {
+	return p.results
+}
+
+func (p *Pipeline) work() {
+	for job := range p.jobs {
+		switch {
+		case p.ctx.Err() != nil:
+			job.res.Err = p.ctx.Err()
+		case job.res.Sig == nil:
+			job.res.Err = ErrInvalidSignature
+		default:
+			job.res.Err = job.res.Sig.VerifyWithOpts(job.res.Msg, p.opts)
+		}
+		close(job.done)
+	}
+}
+
+// sequence sends the results in order.
+func (p *Pipeline) sequence() {
+	defer close(p.results)
+	for job := range p.pending {
+		<-job.done
+		if p.ctx.Err() != nil {
+			continue
+		}
+		select {
+		case p.results <- job.res:
+		case <-p.ctx.Done():
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package ring
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipeline(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 2)
+	require.NoError(t, err)
+
+	const n = 50
+	msgs := make([][32]byte, n)
+	sigs := make([]*RingSig, n)
+	for i := range sigs {
+		msgs[i] = [32]byte{byte(i)}
+		sigs[i], err = keyring.Sign(msgs[i], privKey)
+		require.NoError(t, err)
+	}
+	// every seventh signature is over another message
+	for i := 0; i < n; i += 7 {
+		msgs[i][31] = 1
+	}
+
+	p := NewPipeline(context.Background(), &PipelineOpts{Workers: 4, QueueSize: 3})
+	go func() {
+		defer p.Close()
+		for i := range sigs {
+			if err := p.Enqueue(sigs[i], msgs[i]); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		if err := p.Enqueue(nil, testMsg); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	var seq uint64
+	for res := range p.Results() {
+		require.Equal(t, seq, res.Seq)
+		if seq == n {
+			require.ErrorIs(t, res.Err, ErrInvalidSignature)
+		} else {
+			require.Same(t, sigs[seq], res.Sig)
+			require.Equal(t, msgs[seq], res.Msg)
+			if seq%7 == 0 {
+				require.ErrorIs(t, res.Err, ErrInvalidSignature)
+			} else {
+				require.NoError(t, res.Err)
+			}
+		}
+		seq++
+	}
+	require.Equal(t, uint64(n+1), seq)
+
+	require.ErrorIs(t, p.Enqueue(sigs[0], msgs[0]), ErrPipelineClosed)
+	p.Close()
+}
+
+func TestPipeline_Backpressure(t *testing.T) {
+	sig := createSigWithCurve(t, Secp256k1(), 2, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	p := NewPipeline(ctx, &PipelineOpts{Workers: 1, QueueSize: 2})
+
+	// with no reader, the sequencer holds one result, and the queue two more
+	for i := 0; i < 3; i++ {
+		require.NoError(t, p.Enqueue(sig, testMsg))
+	}
+	blocked := make(chan error)
+	go func() {
+		blocked <- p.Enqueue(sig, testMsg)
+	}()
+	select {
+	case err := <-blocked:
+		t.Fatalf("Enqueue returned %v with a full queue", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	require.ErrorIs(t, <-blocked, context.Canceled)
+	require.ErrorIs(t, p.Enqueue(sig, testMsg), context.Canceled)
+
+	p.Close()
+	for range p.Results() {
+	}
+}
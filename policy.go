@@ -0,0 +1,147 @@
+package ring
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrPolicyViolation is returned by Sign, wrapped with the details, when
+	// the ring doesn't satisfy the SignerPolicy passed with WithSignerPolicy.
+	ErrPolicyViolation = errors.New("ring violates the signer policy")
+
+	// ErrSignLimitReached is returned by Sign when the signer already signed
+	// the maximum number of times in the scope allowed by its SignerPolicy.
+	ErrSignLimitReached = errors.New("signature limit reached for the scope")
+)
+
+// SignerPolicy restricts what a signer agrees to sign, to protect it from
+// protocols (or counterparties) that would deanonymize it: rings that are too
+// small, rings assembled in a signer-specific order, or signing repeatedly in
+// a context where each signature narrows the anonymity set.
+//
+// A policy is passed to Sign with WithSignerPolicy. It's safe for concurrent
+// use, and intended to be shared by all the signers of a process; it must not
+// be copied after first use.
+type SignerPolicy struct {
+	// MinRingSize and MaxRingSize bound the size of the rings the signer signs
+	// with. Zero means no bound.
+	MinRingSize, MaxRingSize int
+
+	// RequireCanonical makes the signer refuse rings that aren't in canonical
+	// order (see Ring.Canonicalize), or that contain duplicate public keys.
+	// A ring whose order was chosen by someone else can be tailored to each
+	// signer, eg. to tell which ring a signature was made with.
+	RequireCanonical bool
+
+	// MaxPerScope is the maximum number of signatures each signer makes in a
+	// scope, as passed to WithSignerPolicy. Zero means no limit.
+	MaxPerScope int
+
+	// Usage counts the signatures made in each scope. It defaults to an
+	// in-memory counter, which forgets the counts when the process exits;
+	// set it to a persistent implementation to enforce MaxPerScope across
+	// restarts.
+	Usage UsageCounter
+
+	once sync.Once
+	mem  *MemoryUsageCounter
+}
+
+// UsageCounter counts the signatures of each signer in each scope, for
+// SignerPolicy.MaxPerScope. Implementations must be safe for concurrent use.
+type UsageCounter interface {
+	// Use increments the count of the signer, identified by the encoding of
+	// its public key, in the scope, if it's less than limit, and returns
+	// whether it did. The check and the increment must be atomic.
+	Use(signer []byte, scope string, limit int) (bool, error)
+}
+
+// WithSignerPolicy makes Sign check the ring against the policy before
+// signing, and count the signature in the given scope, eg. the identifier of
+// a poll or of an epoch. Sign fails with an error wrapping ErrPolicyViolation
+// or ErrSignLimitReached if the policy isn't satisfied. Signatures are
+// counted before they're created, so a signing call that fails afterwards
+// still counts.
+func WithSignerPolicy(p *SignerPolicy, scope string) SignOption {
+	return func(o *signOptions) {
+		o.policy = p
+		o.scope = scope
+	}
+}
+
+// check checks the ring against the policy, and counts a signature by the
+// public key in the scope.
+func (p *SignerPolicy) check(r *Ring, pubkey []byte, scope string) error {
+	size := r.Size()
+	if p.MinRingSize > 0 && size < p.MinRingSize {
+		return fmt.Errorf("%w: ring size %d is less than the minimum of %d", ErrPolicyViolation, size, p.MinRingSize)
+	}
+	if p.MaxRingSize > 0 && size > p.MaxRingSize {
+		return fmt.Errorf("%w: ring size %d is more than the maximum of %d", ErrPolicyViolation, size, p.MaxRingSize)
+	}
+
+	if p.RequireCanonical {
+		prev := encodePoint(r.pubkeys[0])
+		for i := 1; i < size; i++ {
+			enc := encodePoint(r.pubkeys[i])
+			if bytes.Compare(prev, enc) >= 0 {
+				return fmt.Errorf("%w: ring is not in canonical order at index %d", ErrPolicyViolation, i)
+			}
+			prev = enc
+		}
+	}
+
+	if p.MaxPerScope <= 0 {
+		return nil
+	}
+
+	usage := p.Usage
+	if usage == nil {
+		p.once.Do(func() {
+			p.mem = NewMemoryUsageCounter()
+		})
+		usage = p.mem
+	}
+
+	ok, err := usage.Use(pubkey, scope, p.MaxPerScope)
+	if err != nil {
+		return fmt.Errorf("failed to count signature: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: %d signatures in scope %q", ErrSignLimitReached, p.MaxPerScope, scope)
+	}
+	return nil
+}
+
+// MemoryUsageCounter is a UsageCounter keeping the counts in memory.
+type MemoryUsageCounter struct {
+	mu     sync.Mutex
+	counts map[usageKey]int
+}
+
+type usageKey struct {
+	signer, scope string
+}
+
+var _ UsageCounter = (*MemoryUsageCounter)(nil)
+
+// NewMemoryUsageCounter returns a MemoryUsageCounter with no signatures.
+func NewMemoryUsageCounter() *MemoryUsageCounter {
+	return &MemoryUsageCounter{counts: make(map[usageKey]int)}
+}
+
+// Use implements UsageCounter.
+func (c *MemoryUsageCounter) Use(signer []byte, scope string, limit int) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := usageKey{signer: string(signer), scope: scope}
+	if c.counts[key] >= limit {
+		return false, nil
+	}
+	c.counts[key]++
+	return true, nil
+}
@@ -0,0 +1,124 @@
+package ring
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignerPolicy_RingSize(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	small, err := NewKeyRing(curve, 3, privKey, 0)
+	require.NoError(t, err)
+	large, err := NewKeyRing(curve, 9, privKey, 0)
+	require.NoError(t, err)
+	fits, err := NewKeyRing(curve, 5, privKey, 0)
+	require.NoError(t, err)
+
+	policy := &SignerPolicy{MinRingSize: 4, MaxRingSize: 8}
+	_, err = small.Sign(testMsg, privKey, WithSignerPolicy(policy, ""))
+	require.ErrorIs(t, err, ErrPolicyViolation)
+	_, err = large.Sign(testMsg, privKey, WithSignerPolicy(policy, ""))
+	require.ErrorIs(t, err, ErrPolicyViolation)
+
+	sig, err := fits.Sign(testMsg, privKey, WithSignerPolicy(policy, ""))
+	require.NoError(t, err)
+	require.True(t, sig.Verify(testMsg))
+}
+
+func TestSignerPolicy_RequireCanonical(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 6, privKey, 2)
+	require.NoError(t, err)
+	canonical, perm := keyring.Canonicalize()
+
+	policy := &SignerPolicy{RequireCanonical: true}
+	isCanonical := true
+	for i, j := range perm {
+		isCanonical = isCanonical && i == j
+	}
+	if !isCanonical {
+		_, err = keyring.Sign(testMsg, privKey, WithSignerPolicy(policy, ""))
+		require.ErrorIs(t, err, ErrPolicyViolation)
+	}
+
+	sig, err := canonical.Sign(testMsg, privKey, WithSignerPolicy(policy, ""))
+	require.NoError(t, err)
+	require.True(t, sig.Verify(testMsg))
+
+	// duplicate keys are not canonical
+	pk := curve.ScalarBaseMul(privKey)
+	dup, err := NewFixedKeyRingFromPublicKeys(curve, []Point{pk, pk.Copy()})
+	require.NoError(t, err)
+	_, err = dup.Sign(testMsg, privKey, WithSignerPolicy(policy, ""))
+	require.ErrorIs(t, err, ErrPolicyViolation)
+}
+
+func TestSignerPolicy_MaxPerScope(t *testing.T) {
+	curve := Secp256k1()
+	privKey, otherKey := curve.NewRandomScalar(), curve.NewRandomScalar()
+	keyring, err := NewFixedKeyRingFromPublicKeys(curve, []Point{
+		curve.ScalarBaseMul(privKey), curve.ScalarBaseMul(otherKey),
+	})
+	require.NoError(t, err)
+
+	policy := &SignerPolicy{MaxPerScope: 2}
+	for i := 0; i < 2; i++ {
+		_, err = keyring.Sign(testMsg, privKey, WithSignerPolicy(policy, "poll-1"))
+		require.NoError(t, err)
+	}
+	_, err = keyring.Sign(testMsg, privKey, WithSignerPolicy(policy, "poll-1"))
+	require.ErrorIs(t, err, ErrSignLimitReached)
+
+	// other scopes and other signers have their own counts
+	_, err = keyring.Sign(testMsg, privKey, WithSignerPolicy(policy, "poll-2"))
+	require.NoError(t, err)
+	_, err = keyring.Sign(testMsg, otherKey, WithSignerPolicy(policy, "poll-1"))
+	require.NoError(t, err)
+
+	// without the policy, signing isn't limited
+	_, err = keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+}
+
+// recordingCounter is a UsageCounter standing in for a persistent store.
+type recordingCounter struct {
+	mu    sync.Mutex
+	uses  []string
+	inner *MemoryUsageCounter
+	err   error
+}
+
+func (c *recordingCounter) Use(signer []byte, scope string, limit int) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return false, c.err
+	}
+	c.uses = append(c.uses, scope)
+	return c.inner.Use(signer, scope, limit)
+}
+
+func TestSignerPolicy_Usage(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 1)
+	require.NoError(t, err)
+
+	counter := &recordingCounter{inner: NewMemoryUsageCounter()}
+	policy := &SignerPolicy{MaxPerScope: 1, Usage: counter}
+	_, err = keyring.Sign(testMsg, privKey, WithSignerPolicy(policy, "epoch-7"))
+	require.NoError(t, err)
+	_, err = keyring.Sign(testMsg, privKey, WithSignerPolicy(policy, "epoch-7"))
+	require.ErrorIs(t, err, ErrSignLimitReached)
+	require.Equal(t, []string{"epoch-7", "epoch-7"}, counter.uses)
+
+	// the policy fails closed if the counter fails
+	counter.err = errors.New("disk full")
+	_, err = keyring.Sign(testMsg, privKey, WithSignerPolicy(policy, "epoch-8"))
+	require.ErrorIs(t, err, counter.err)
+}
@@ -0,0 +1,113 @@
+package ring
+
+import (
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	mrand "math/rand/v2"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// ErrHashPointMismatch is returned by SetHashPoints if a supplied H_p(P_i)
+// value doesn't match the one recomputed by the check.
+var ErrHashPointMismatch = errors.New("precomputed hash point mismatch")
+
+// HashPointCheck checks H_p(P_i) values supplied to SetHashPoints for the
+// members of a ring, which has the same length. It's only called with
+// values that are points of the ring's curve, other than the identity.
+type HashPointCheck func(r *Ring, hps []types.Point) error
+
+// SampleHashPoints returns a HashPointCheck that recomputes n values chosen at
+// random, or all of them if the ring has at most n members. If k of the
+// values are wrong, the check misses them with a probability of at most
+// (1-k/size)^n, eg. for 100 wrong values among 10000, 37% with n = 100 and
+// 0.005% with n = 1000.
+func SampleHashPoints(n int) HashPointCheck {
+	return func(r *Ring, hps []types.Point) error {
+		var seed [32]byte
+		if _, err := crand.Read(seed[:]); err != nil {
+			return err
+		}
+		rng := mrand.New(mrand.NewChaCha8(seed))
+
+		indices := rng.Perm(len(hps))
+		if n < len(indices) {
+			indices = indices[:max(n, 0)]
+		}
+		for _, i := range indices {
+			if !equalPoints(hashToCurve(r.pubkeys[i]), hps[i]) {
+				return fmt.Errorf("%w at index %d", ErrHashPointMismatch, i)
+			}
+		}
+		return nil
+	}
+}
+
+// TrustHashPoints is a HashPointCheck that accepts the values without
+// recomputing any, for values whose origin is authenticated, eg. by a signed
+// precompute manifest (see the manifest package).
+func TrustHashPoints(*Ring, []types.Point) error {
+	return nil
+}
+
+// HashPoints returns H_p(P_i) for each public key in the ring, as computed by
+// HashToCurve, computing and caching them like Precompute if they aren't. It's
+// meant for distributing the values to other verifiers of the ring, which
+// supply them to SetHashPoints instead of computing them.
+func (r *Ring) HashPoints() []types.Point {
+	hps := r.hashPoints()
+	out := make([]types.Point, len(hps))
+	for i, hp := range hps {
+		out[i] = hp.Copy()
+	}
+	return out
+}
+
+// SetHashPoints caches the supplied H_p(P_i) values on the ring, so that
+// signing and verification don't compute them, eg. when verifiers of a large
+// ring share the precomputation instead of each hashing 10k keys to the curve.
+// Unlike Precompute, it caches the values of rings of any size.
+//
+// The values are checked with check, eg. SampleHashPoints(64): a wrong value
+// doesn't let anyone forge signatures, but lets the member sign with key images
+// that don't link with its other signatures, so values from untrusted sources
+// must be checked. It returns an error, without caching the values, if there
+// isn't one value per member, if one isn't a valid point of the ring's curve,
+// or if the check fails. If the values are already cached, eg. by an earlier
+// call to Sign, they're kept and the supplied ones are discarded.
+func (r *Ring) SetHashPoints(hps []types.Point, check HashPointCheck) (err error) {
+	defer recoverInternal(&err)
+
+	if len(hps) != len(r.pubkeys) {
+		return fmt.Errorf("%d hash points for a ring of %d members", len(hps), len(r.pubkeys))
+	}
+	if check == nil {
+		return errors.New("nil hash point check")
+	}
+
+	own := make([]types.Point, len(hps))
+	for i, hp := range hps {
+		if hp == nil {
+			return fmt.Errorf("hash point %d is nil", i)
+		}
+		// decoding the encoding checks that the point is on the ring's curve,
+		// and copies it so that the caller can't modify the cached value
+		own[i], err = r.curve.DecodeToPoint(hp.Encode())
+		if err != nil {
+			return fmt.Errorf("invalid hash point %d: %w", i, err)
+		}
+		if isIdentity(own[i]) {
+			return fmt.Errorf("hash point %d is the identity", i)
+		}
+	}
+	if err := check(r, own); err != nil {
+		return err
+	}
+
+	if task := r.hpPending.Load(); task != nil {
+		<-task.done
+	}
+	r.setHP(own)
+	return nil
+}
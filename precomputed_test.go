@@ -0,0 +1,135 @@
+package ring
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashPoints(t *testing.T) {
+	for _, curve := range []types.Curve{Secp256k1(), Ed25519()} {
+		keyring, err := NewKeyRing(curve, 5, curve.NewRandomScalar(), 2)
+		require.NoError(t, err)
+
+		hps := keyring.HashPoints()
+		require.Len(t, hps, 5)
+		for i, pk := range keyring.PublicKeys() {
+			require.True(t, hps[i].Equals(HashToCurve(pk)))
+		}
+
+		// the returned values are copies
+		hps[0] = hps[0].Add(curve.BasePoint())
+		require.True(t, keyring.HashPoints()[0].Equals(HashToCurve(keyring.pubkeys[0])))
+	}
+}
+
+func TestSetHashPoints(t *testing.T) {
+	for _, curve := range []types.Curve{Secp256k1(), Ed25519()} {
+		sig := createSigWithCurve(t, curve, 8, 3)
+		hps := sig.Ring().HashPoints()
+
+		// a verifier decoding the ring gets the values from elsewhere
+		enc, err := sig.Serialize()
+		require.NoError(t, err)
+		decoded := new(RingSig)
+		require.NoError(t, decoded.Deserialize(curve, enc))
+		require.NoError(t, decoded.Ring().SetHashPoints(hps, SampleHashPoints(4)))
+		require.NotNil(t, decoded.Ring().cachedHP())
+		require.True(t, decoded.Verify(testMsg))
+	}
+}
+
+func TestSetHashPoints_Wrong(t *testing.T) {
+	curve := Secp256k1()
+	sig := createSigWithCurve(t, curve, 8, 3)
+	hps := sig.Ring().HashPoints()
+	hps[5] = hps[5].Add(curve.BasePoint())
+
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+	decode := func() *RingSig {
+		decoded := new(RingSig)
+		require.NoError(t, decoded.Deserialize(curve, enc))
+		return decoded
+	}
+
+	// sampling all the members finds the wrong value, and nothing is cached
+	decoded := decode()
+	err = decoded.Ring().SetHashPoints(hps, SampleHashPoints(8))
+	require.ErrorIs(t, err, ErrHashPointMismatch)
+	require.EqualError(t, err, "precomputed hash point mismatch at index 5")
+	require.Nil(t, decoded.Ring().cachedHP())
+	require.True(t, decoded.Verify(testMsg))
+
+	// trusted values are used as is
+	decoded = decode()
+	require.NoError(t, decoded.Ring().SetHashPoints(hps, TrustHashPoints))
+	require.False(t, decoded.Verify(testMsg))
+	require.NoError(t, decode().Ring().SetHashPoints(hps, SampleHashPoints(0)))
+
+	// values already cached are kept
+	decoded = decode()
+	require.True(t, decoded.Verify(testMsg))
+	require.NoError(t, decoded.Ring().SetHashPoints(hps, TrustHashPoints))
+	require.True(t, decoded.Verify(testMsg))
+}
+
+func TestSetHashPoints_Invalid(t *testing.T) {
+	curve := Ed25519()
+	keyring, err := NewKeyRing(curve, 3, curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+	hps := keyring.HashPoints()
+
+	err = keyring.SetHashPoints(hps[:2], TrustHashPoints)
+	require.EqualError(t, err, "2 hash points for a ring of 3 members")
+	err = keyring.SetHashPoints(hps, nil)
+	require.EqualError(t, err, "nil hash point check")
+	err = keyring.SetHashPoints([]types.Point{hps[0], nil, hps[2]}, TrustHashPoints)
+	require.EqualError(t, err, "hash point 1 is nil")
+	identity := curve.ScalarBaseMul(curve.ScalarFromInt(0))
+	err = keyring.SetHashPoints([]types.Point{hps[0], hps[1], identity}, TrustHashPoints)
+	require.EqualError(t, err, "hash point 2 is the identity")
+	err = keyring.SetHashPoints([]types.Point{hps[0], Secp256k1().BasePoint(), hps[2]}, TrustHashPoints)
+	require.ErrorContains(t, err, "invalid hash point 1")
+}
+
+func TestSetHashPoints_LargeRing(t *testing.T) {
+	defaultMax := hpCacheMaxSize
+	hpCacheMaxSize = 4
+	t.Cleanup(func() { hpCacheMaxSize = defaultMax })
+
+	curve := Ed25519()
+	sig := createSigWithCurve(t, curve, 16, 7)
+	require.Nil(t, sig.Ring().cachedHP())
+	hps := sig.Ring().HashPoints()
+	hps[2] = hps[2].Add(curve.BasePoint())
+
+	// rings too large for Precompute still cache supplied values, which
+	// verification uses
+	require.NoError(t, sig.Ring().SetHashPoints(hps, TrustHashPoints))
+	require.NotNil(t, sig.Ring().cachedHP())
+	require.False(t, sig.Verify(testMsg))
+}
+
+func TestSetHashPoints_Concurrent(t *testing.T) {
+	defaultMax := hpCacheMaxSize
+	hpCacheMaxSize = 4
+	t.Cleanup(func() { hpCacheMaxSize = defaultMax })
+
+	sig := createSigWithCurve(t, Secp256k1(), 16, 7)
+	hps := sig.Ring().HashPoints()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.True(t, sig.Verify(testMsg))
+		}()
+	}
+	require.NoError(t, sig.Ring().SetHashPoints(hps, SampleHashPoints(2)))
+	wg.Wait()
+	require.True(t, sig.Verify(testMsg))
+}
@@ -0,0 +1,79 @@
+package ring
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq/types"
+	dsecp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// PointFromPublicKey converts a standard library public key into a public key
+// that can be used in a ring over the key's curve: a secp256k1
+// *ecdsa.PublicKey into a Secp256k1 point, or an ed25519.PublicKey (or a
+// pointer to one) into an Ed25519 point. It's the counterpart of
+// PublicKeyFromPoint.
+//
+// It returns an error for other key types, including ECDSA keys on other
+// curves.
+func PointFromPublicKey(pub crypto.PublicKey) (types.Point, error) {
+	switch pub := pub.(type) {
+	case *ecdsa.PublicKey:
+		return PointFromECDSAPub(pub)
+	case ed25519.PublicKey:
+		return PointFromEd25519PublicKey(pub)
+	case *ed25519.PublicKey:
+		if pub == nil {
+			return nil, errors.New("nil public key")
+		}
+		return PointFromEd25519PublicKey(*pub)
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", pub)
+	}
+}
+
+// PublicKeyFromPoint converts a ring public key into the standard library
+// public key type of its curve: an *ecdsa.PublicKey on secp256k1, and an
+// ed25519.PublicKey on ed25519.
+func PublicKeyFromPoint(p types.Point) (crypto.PublicKey, error) {
+	switch CurveIDOfPoint(p) {
+	case CurveSecp256k1:
+		return ECDSAPubFromPoint(p)
+	case CurveEd25519:
+		return Ed25519PublicKeyFromPoint(p)
+	default:
+		return nil, fmt.Errorf("unsupported point type %T", p)
+	}
+}
+
+// ECDSAPubFromPoint converts a Secp256k1 point into an *ecdsa.PublicKey on
+// secp256k1, as used by go-ethereum or decred. It's the inverse of
+// PointFromECDSAPub.
+//
+// It returns an error if the point is not on secp256k1 or is the identity.
+func ECDSAPubFromPoint(p types.Point) (*ecdsa.PublicKey, error) {
+	if CurveIDOfPoint(p) != CurveSecp256k1 {
+		return nil, errors.New("point is not on secp256k1")
+	}
+
+	pk, err := dsecp256k1.ParsePubKey(encodePoint(p))
+	if err != nil {
+		return nil, err
+	}
+	return pk.ToECDSA(), nil
+}
+
+// Ed25519PublicKeyFromPoint converts an Ed25519 point into a standard
+// crypto/ed25519 public key. It's the inverse of PointFromEd25519PublicKey.
+//
+// It returns an error if the point is not on ed25519.
+func Ed25519PublicKeyFromPoint(p types.Point) (ed25519.PublicKey, error) {
+	if CurveIDOfPoint(p) != CurveEd25519 {
+		return nil, errors.New("point is not on ed25519")
+	}
+
+	return ed25519.PublicKey(encodePoint(p)), nil
+}
@@ -0,0 +1,70 @@
+package ring
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	dsecp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicKeyConversions_Secp256k1(t *testing.T) {
+	priv, err := dsecp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	ecdsaPub := priv.ToECDSA().Public()
+
+	p, err := PointFromPublicKey(ecdsaPub)
+	require.NoError(t, err)
+	require.Equal(t, CurveSecp256k1, CurveIDOfPoint(p))
+
+	pub, err := PublicKeyFromPoint(p)
+	require.NoError(t, err)
+	require.IsType(t, &ecdsa.PublicKey{}, pub)
+	require.Zero(t, pub.(*ecdsa.PublicKey).X.Cmp(priv.PubKey().X()))
+	require.Zero(t, pub.(*ecdsa.PublicKey).Y.Cmp(priv.PubKey().Y()))
+
+	_, err = Ed25519PublicKeyFromPoint(p)
+	require.Error(t, err)
+}
+
+func TestPublicKeyConversions_Ed25519(t *testing.T) {
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ed, err := NewEd25519(Ed25519ImplEdwards25519)
+	require.NoError(t, err)
+	p, err := PointFromPublicKey(&edPub)
+	require.NoError(t, err)
+
+	for _, point := range []Point{p, ed.ScalarBaseMul(ed.NewRandomScalar())} {
+		require.Equal(t, CurveEd25519, CurveIDOfPoint(point))
+		pub, err := PublicKeyFromPoint(point)
+		require.NoError(t, err)
+		require.Equal(t, ed25519.PublicKey(point.Encode()), pub)
+
+		back, err := PointFromPublicKey(pub)
+		require.NoError(t, err)
+		require.Equal(t, point.Encode(), back.Encode())
+	}
+	require.Equal(t, []byte(edPub), p.Encode())
+
+	_, err = ECDSAPubFromPoint(p)
+	require.Error(t, err)
+}
+
+func TestPointFromPublicKey_Unsupported(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, err = PointFromPublicKey(&p256.PublicKey)
+	require.Error(t, err)
+
+	_, err = PointFromPublicKey("not a key")
+	require.Error(t, err)
+	_, err = PointFromPublicKey((*ed25519.PublicKey)(nil))
+	require.Error(t, err)
+	_, err = PublicKeyFromPoint(nil)
+	require.Error(t, err)
+}
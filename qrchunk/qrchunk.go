@@ -0,0 +1,317 @@
+// Package qrchunk splits rings and signatures into sequenced, checksummed
+// parts that fit in QR codes, and reassembles them, so that air-gapped devices
+// can take part in ring signing flows: the ring is scanned into the device,
+// and the signature scanned out of it.
+//
+// Parts are modeled on Uniform Resources (UR), as used by air-gapped wallets:
+//
+//	UR:RING-SIG/2-5/<base32 data>
+//
+// with the type of the value, the sequence number of the part and the total
+// number of parts. The data is the base32 encoding (RFC 4648, without padding)
+// of the length and the CRC-32 of the whole value, the chunk, and the CRC-32
+// of the part. Parts are uppercase and use only characters of the QR
+// alphanumeric mode, which makes the codes denser; they're decoded case
+// insensitively.
+//
+//	parts, err := qrchunk.EncodeSignature(sig, 300)
+//	...
+//	d := qrchunk.NewDecoder()
+//	for !d.Done() {
+//		err := d.Add(scan())
+//		...
+//	}
+//	sig, err := d.Signature()
+package qrchunk
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// Types of the values encoded by EncodeRing and EncodeSignature. Their data is
+// the curve ID followed by the encoding of the ring (ring.Ring.Bytes) or of the
+// signature (ring.RingSig.Serialize).
+const (
+	TypeRing      = "ring"
+	TypeSignature = "ring-sig"
+)
+
+const (
+	scheme = "UR:"
+
+	// headerLen is the length of the length and checksum of the value that
+	// start the data of each part.
+	headerLen = 4 + 4
+	// checksumLen is the length of the checksum of the part.
+	checksumLen = crc32.Size
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var (
+	// ErrOtherValue is returned by Decoder.Add for parts of another value than
+	// that of the previous parts.
+	ErrOtherValue = errors.New("part of another value")
+	// ErrIncomplete is returned by the Decoder for values whose parts haven't
+	// all been added.
+	ErrIncomplete = errors.New("incomplete value")
+)
+
+// Split splits the value of the given type into parts of at most maxPartLen
+// characters. The type is made of lowercase letters, digits and hyphens.
+func Split(typ string, value []byte, maxPartLen int) ([]string, error) {
+	if !validType(typ) {
+		return nil, fmt.Errorf("invalid type %q", typ)
+	}
+	if len(value) == 0 {
+		return nil, errors.New("empty value")
+	}
+
+	chunkLen, total, err := chunking(typ, len(value), maxPartLen)
+	if err != nil {
+		return nil, err
+	}
+
+	var header [headerLen]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(value)))
+	binary.BigEndian.PutUint32(header[4:], crc32.ChecksumIEEE(value))
+
+	parts := make([]string, total)
+	for i := range parts {
+		chunk := value[i*chunkLen : min((i+1)*chunkLen, len(value))]
+		prefix := partPrefix(typ, i+1, total)
+		data := append(header[:], chunk...)
+		data = binary.BigEndian.AppendUint32(data, partChecksum(prefix, data))
+		parts[i] = prefix + encoding.EncodeToString(data)
+	}
+	return parts, nil
+}
+
+// chunking returns the length of the chunks of a value and the number of
+// parts, such that the parts are at most maxPartLen characters.
+func chunking(typ string, valueLen, maxPartLen int) (chunkLen, total int, err error) {
+	// the length of the prefix depends on the number of digits of the total
+	for digits := 1; ; digits++ {
+		prefixLen := len(scheme) + len(typ) + 1 + 2*digits + 1 + 1
+		dataLen := (maxPartLen - prefixLen) * 5 / 8
+		chunkLen = dataLen - headerLen - checksumLen
+		if chunkLen < 1 {
+			return 0, 0, fmt.Errorf("maximum part length %d too short", maxPartLen)
+		}
+		total = (valueLen + chunkLen - 1) / chunkLen
+		if len(strconv.Itoa(total)) <= digits {
+			return chunkLen, total, nil
+		}
+	}
+}
+
+func partPrefix(typ string, seq, total int) string {
+	return strings.ToUpper(scheme+typ) + "/" + strconv.Itoa(seq) + "-" + strconv.Itoa(total) + "/"
+}
+
+// partChecksum returns the checksum of a part, which covers its prefix so
+// that parts can't be renumbered.
+func partChecksum(prefix string, data []byte) uint32 {
+	return crc32.Update(crc32.ChecksumIEEE([]byte(prefix)), crc32.IEEETable, data)
+}
+
+func validType(typ string) bool {
+	if typ == "" {
+		return false
+	}
+	for _, c := range typ {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeRing splits the ring into parts of type TypeRing of at most
+// maxPartLen characters.
+func EncodeRing(r *ring.Ring, maxPartLen int) ([]string, error) {
+	id := ring.CurveIDOf(r.Curve())
+	if id == ring.CurveUnknown {
+		return nil, errors.New("unsupported curve")
+	}
+	return Split(TypeRing, append([]byte{byte(id)}, r.Bytes()...), maxPartLen)
+}
+
+// EncodeSignature splits the signature into parts of type TypeSignature of at
+// most maxPartLen characters.
+func EncodeSignature(sig *ring.RingSig, maxPartLen int) ([]string, error) {
+	id := ring.CurveIDOf(sig.Ring().Curve())
+	if id == ring.CurveUnknown {
+		return nil, errors.New("unsupported curve")
+	}
+	enc, err := sig.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return Split(TypeSignature, append([]byte{byte(id)}, enc...), maxPartLen)
+}
+
+// Decoder reassembles a value from its parts, which may be added in any order
+// and more than once, eg. as a scanner cycles through animated QR codes. The
+// zero value is ready to use.
+type Decoder struct {
+	typ      string
+	total    int
+	header   []byte
+	chunks   [][]byte
+	received int
+}
+
+// NewDecoder returns a new Decoder.
+func NewDecoder() *Decoder {
+	return new(Decoder)
+}
+
+// Add adds a part. Parts that were already added are ignored. It returns an
+// error if the part is malformed or its checksum doesn't match, in which case
+// it should be scanned again, or wraps ErrOtherValue if it's a part of another
+// value than the previous parts.
+func (d *Decoder) Add(part string) error {
+	part = strings.ToUpper(strings.TrimSpace(part))
+	rest, ok := strings.CutPrefix(part, scheme)
+	if !ok {
+		return errors.New("not a UR part")
+	}
+	fields := strings.Split(rest, "/")
+	if len(fields) != 3 {
+		return errors.New("malformed part")
+	}
+	typ := strings.ToLower(fields[0])
+	if !validType(typ) {
+		return fmt.Errorf("invalid type %q", typ)
+	}
+	seqStr, totalStr, ok := strings.Cut(fields[1], "-")
+	if !ok {
+		return errors.New("malformed sequence")
+	}
+	seq, err1 := strconv.Atoi(seqStr)
+	total, err2 := strconv.Atoi(totalStr)
+	if err1 != nil || err2 != nil || total < 1 || seq < 1 || seq > total {
+		return fmt.Errorf("invalid sequence %q", fields[1])
+	}
+
+	data, err := encoding.DecodeString(fields[2])
+	if err != nil {
+		return fmt.Errorf("malformed data: %w", err)
+	}
+	if len(data) < headerLen+1+checksumLen {
+		return errors.New("part too short")
+	}
+	checksum := binary.BigEndian.Uint32(data[len(data)-checksumLen:])
+	data = data[:len(data)-checksumLen]
+	if checksum != partChecksum(partPrefix(typ, seq, total), data) {
+		return errors.New("part checksum mismatch")
+	}
+	header, chunk := data[:headerLen], data[headerLen:]
+
+	if d.chunks == nil {
+		d.typ, d.total, d.header = typ, total, header
+		d.chunks = make([][]byte, total)
+	} else if typ != d.typ || total != d.total || string(header) != string(d.header) {
+		return fmt.Errorf("%w: %s/%d-%d", ErrOtherValue, typ, seq, total)
+	}
+	if d.chunks[seq-1] == nil {
+		d.chunks[seq-1] = chunk
+		d.received++
+	}
+	return nil
+}
+
+// Progress returns the number of distinct parts added and the total number of
+// parts, which is 0 until the first part is added.
+func (d *Decoder) Progress() (received, total int) {
+	return d.received, d.total
+}
+
+// Done returns whether all the parts have been added.
+func (d *Decoder) Done() bool {
+	return d.total > 0 && d.received == d.total
+}
+
+// Reset discards the added parts, eg. to start decoding another value after
+// ErrOtherValue.
+func (d *Decoder) Reset() {
+	*d = Decoder{}
+}
+
+// Result returns the type and the reassembled value. It returns an error
+// wrapping ErrIncomplete if some parts are missing, or an error if the value
+// doesn't match its length or checksum.
+func (d *Decoder) Result() (typ string, value []byte, err error) {
+	if !d.Done() {
+		return "", nil, fmt.Errorf("%w: %d of %d parts", ErrIncomplete, d.received, d.total)
+	}
+
+	for _, chunk := range d.chunks {
+		value = append(value, chunk...)
+	}
+	if len(value) != int(binary.BigEndian.Uint32(d.header)) {
+		return "", nil, errors.New("value length mismatch")
+	}
+	if crc32.ChecksumIEEE(value) != binary.BigEndian.Uint32(d.header[4:]) {
+		return "", nil, errors.New("value checksum mismatch")
+	}
+	return d.typ, value, nil
+}
+
+// Ring returns the reassembled ring, which must be of type TypeRing.
+func (d *Decoder) Ring() (*ring.Ring, error) {
+	id, enc, err := d.result(TypeRing)
+	if err != nil {
+		return nil, err
+	}
+	return ring.RingFromBytes(id, enc)
+}
+
+// Signature returns the reassembled signature, which must be of type
+// TypeSignature. It isn't verified.
+func (d *Decoder) Signature() (*ring.RingSig, error) {
+	id, enc, err := d.result(TypeSignature)
+	if err != nil {
+		return nil, err
+	}
+	curve, err := id.Curve()
+	if err != nil {
+		return nil, err
+	}
+	sig := new(ring.RingSig)
+	if err := sig.Deserialize(curve, enc); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+func (d *Decoder) result(want string) (ring.CurveID, []byte, error) {
+	typ, value, err := d.Result()
+	if err != nil {
+		return ring.CurveUnknown, nil, err
+	}
+	if typ != want {
+		return ring.CurveUnknown, nil, fmt.Errorf("value is a %s, expected a %s", typ, want)
+	}
+	return ring.CurveID(value[0]), value[1:], nil
+}
+
+// Join reassembles a value from all its parts, in any order.
+func Join(parts []string) (typ string, value []byte, err error) {
+	d := NewDecoder()
+	for _, part := range parts {
+		if err := d.Add(part); err != nil {
+			return "", nil, err
+		}
+	}
+	return d.Result()
+}
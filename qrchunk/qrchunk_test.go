@@ -0,0 +1,123 @@
+package qrchunk
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+func newSig(t *testing.T, curve ring.Curve, size int) *ring.RingSig {
+	privKey := curve.NewRandomScalar()
+	keyring, err := ring.NewKeyRing(curve, size, privKey, size/2)
+	require.NoError(t, err)
+	sig, err := keyring.Sign([32]byte{1, 2, 3}, privKey)
+	require.NoError(t, err)
+	return sig
+}
+
+func TestEncodeSignature(t *testing.T) {
+	for _, curve := range []ring.Curve{ring.Secp256k1(), ring.Ed25519()} {
+		sig := newSig(t, curve, 32)
+		parts, err := EncodeSignature(sig, 200)
+		require.NoError(t, err)
+		require.Greater(t, len(parts), 10)
+		for _, part := range parts {
+			require.LessOrEqual(t, len(part), 200)
+			// QR alphanumeric mode
+			require.Empty(t, strings.Trim(part, "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"), part)
+		}
+		require.True(t, strings.HasPrefix(parts[0], "UR:RING-SIG/1-"))
+
+		// parts are scanned in any order, repeatedly
+		d := NewDecoder()
+		rng := rand.New(rand.NewSource(1))
+		for !d.Done() {
+			require.NoError(t, d.Add(parts[rng.Intn(len(parts))]))
+		}
+		received, total := d.Progress()
+		require.Equal(t, len(parts), received)
+		require.Equal(t, len(parts), total)
+
+		decoded, err := d.Signature()
+		require.NoError(t, err)
+		require.True(t, decoded.Verify([32]byte{1, 2, 3}))
+		_, err = d.Ring()
+		require.ErrorContains(t, err, "expected a ring")
+	}
+}
+
+func TestEncodeRing(t *testing.T) {
+	keyring := newSig(t, ring.Ed25519(), 8).Ring()
+	parts, err := EncodeRing(keyring, 100)
+	require.NoError(t, err)
+
+	d := NewDecoder()
+	for _, part := range parts {
+		require.NoError(t, d.Add(strings.ToLower(part)))
+	}
+	decoded, err := d.Ring()
+	require.NoError(t, err)
+	require.True(t, decoded.Equals(keyring))
+}
+
+func TestSplit(t *testing.T) {
+	value := []byte(strings.Repeat("ring-go", 100))
+	parts, err := Split("test", value, 40)
+	require.NoError(t, err)
+	require.Greater(t, len(parts), 9) // two-digit sequence numbers
+	for _, part := range parts {
+		require.LessOrEqual(t, len(part), 40)
+	}
+
+	typ, got, err := Join(parts)
+	require.NoError(t, err)
+	require.Equal(t, "test", typ)
+	require.Equal(t, value, got)
+
+	_, _, err = Join(parts[1:])
+	require.ErrorIs(t, err, ErrIncomplete)
+
+	for _, invalid := range []struct {
+		typ    string
+		maxLen int
+	}{{"", 100}, {"Test", 100}, {"a/b", 100}, {"test", 20}} {
+		_, err := Split(invalid.typ, value, invalid.maxLen)
+		require.Error(t, err, invalid)
+	}
+	_, err = Split("test", nil, 100)
+	require.Error(t, err)
+}
+
+func TestDecoder_Add_Invalid(t *testing.T) {
+	parts, err := Split("test", []byte("hello, air-gapped world"), 50)
+	require.NoError(t, err)
+	other, err := Split("test", []byte("another value, just as long"), 50)
+	require.NoError(t, err)
+
+	d := NewDecoder()
+	require.NoError(t, d.Add(parts[0]))
+	require.ErrorIs(t, d.Add(other[1]), ErrOtherValue)
+	d.Reset()
+	require.NoError(t, d.Add(other[1]))
+
+	corrupted := []byte(parts[1])
+	corrupted[len(corrupted)-3] ^= 'A' ^ 'B'
+	renumbered := strings.Replace(parts[0], "/1-", "/2-", 1)
+	for _, part := range []string{
+		"",
+		"ur:test",
+		"UR:TEST/1-2",
+		"UR:TEST/0-2/AAAA",
+		"UR:TEST/3-2/AAAA",
+		"UR:TEST/1-2/1",
+		"UR:TEST/1-2/AAAA",
+		string(corrupted),
+		renumbered,
+	} {
+		require.Error(t, NewDecoder().Add(part), part)
+	}
+}
@@ -0,0 +1,403 @@
+package ring
+
+import (
+	"crypto/rand"
+	"flag"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+)
+
+// The tests in this file cross-check signatures and key images produced by the
+// go-dleq backed implementation against a minimal, independent reference
+// implementation written with math/big only. They are slow and therefore
+// optional; enable them with:
+//
+//	go test -run TestDifferential -difftest=100
+var diffTestIterations = flag.Int("difftest", 0, "number of differential test iterations to run against the math/big reference implementation")
+
+// refPoint is an affine point. inf is only used by short Weierstrass curves,
+// twisted Edwards curves represent the identity as (0, 1).
+type refPoint struct {
+	x, y *big.Int
+	inf  bool
+}
+
+// refCurve is the reference implementation of the operations a ring signature
+// verifier needs.
+type refCurve interface {
+	identity() refPoint
+	base() refPoint
+	add(a, b refPoint) refPoint
+	decodePoint(in []byte) (refPoint, bool)
+	encodePoint(p refPoint) []byte
+	decodeScalar(in []byte) *big.Int
+	hashToPoint(p refPoint) refPoint
+	hashToScalar(in []byte) *big.Int
+}
+
+func refMul(curve refCurve, k *big.Int, p refPoint) refPoint {
+	res := curve.identity()
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		res = curve.add(res, res)
+		if k.Bit(i) == 1 {
+			res = curve.add(res, p)
+		}
+	}
+	return res
+}
+
+// refVerify is a straightforward transcription of the LSAG verification
+// equations over the reference curve.
+func refVerify(curve refCurve, m [32]byte, pubkeys [][]byte, image []byte, c []byte, s [][]byte) bool {
+	img, ok := curve.decodePoint(image)
+	if !ok {
+		return false
+	}
+
+	c0 := curve.decodeScalar(c)
+	ci := c0
+	for i, enc := range pubkeys {
+		pk, ok := curve.decodePoint(enc)
+		if !ok {
+			return false
+		}
+		si := curve.decodeScalar(s[i])
+
+		l := curve.add(refMul(curve, si, curve.base()), refMul(curve, ci, pk))
+		r := curve.add(refMul(curve, si, curve.hashToPoint(pk)), refMul(curve, ci, img))
+
+		t := append(append(append([]byte{}, m[:]...), curve.encodePoint(l)...), curve.encodePoint(r)...)
+		ci = curve.hashToScalar(t)
+	}
+
+	return ci.Cmp(c0) == 0
+}
+
+type refSecp256k1 struct {
+	p, n, gx, gy *big.Int
+}
+
+func newRefSecp256k1() *refSecp256k1 {
+	hexInt := func(s string) *big.Int {
+		i, ok := new(big.Int).SetString(s, 16)
+		if !ok {
+			panic("bad constant")
+		}
+		return i
+	}
+	return &refSecp256k1{
+		p:  hexInt("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f"),
+		n:  hexInt("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"),
+		gx: hexInt("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"),
+		gy: hexInt("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"),
+	}
+}
+
+func (c *refSecp256k1) identity() refPoint { return refPoint{inf: true} }
+
+func (c *refSecp256k1) base() refPoint { return refPoint{x: c.gx, y: c.gy} }
+
+func (c *refSecp256k1) add(a, b refPoint) refPoint {
+	if a.inf {
+		return b
+	}
+	if b.inf {
+		return a
+	}
+
+	var lambda *big.Int
+	if a.x.Cmp(b.x) == 0 {
+		if new(big.Int).Add(a.y, b.y).Mod(new(big.Int).Add(a.y, b.y), c.p).Sign() == 0 {
+			return refPoint{inf: true}
+		}
+		// lambda = 3x^2 / 2y
+		num := new(big.Int).Mul(big.NewInt(3), new(big.Int).Mul(a.x, a.x))
+		den := new(big.Int).ModInverse(new(big.Int).Lsh(a.y, 1), c.p)
+		lambda = num.Mul(num, den)
+	} else {
+		// lambda = (y2 - y1) / (x2 - x1)
+		num := new(big.Int).Sub(b.y, a.y)
+		den := new(big.Int).Sub(b.x, a.x)
+		den.Mod(den, c.p)
+		lambda = num.Mul(num, den.ModInverse(den, c.p))
+	}
+	lambda.Mod(lambda, c.p)
+
+	x := new(big.Int).Mul(lambda, lambda)
+	x.Sub(x, a.x).Sub(x, b.x).Mod(x, c.p)
+	y := new(big.Int).Sub(a.x, x)
+	y.Mul(y, lambda).Sub(y, a.y).Mod(y, c.p)
+	return refPoint{x: x, y: y}
+}
+
+// liftX returns the point with the given x-coordinate and y-parity.
+func (c *refSecp256k1) liftX(x *big.Int, odd bool) (refPoint, bool) {
+	if x.Cmp(c.p) >= 0 {
+		return refPoint{}, false
+	}
+
+	y2 := new(big.Int).Exp(x, big.NewInt(3), c.p)
+	y2.Add(y2, big.NewInt(7)).Mod(y2, c.p)
+	exp := new(big.Int).Add(c.p, big.NewInt(1))
+	exp.Rsh(exp, 2)
+	y := new(big.Int).Exp(y2, exp, c.p)
+	if new(big.Int).Exp(y, big.NewInt(2), c.p).Cmp(y2) != 0 {
+		return refPoint{}, false
+	}
+
+	if (y.Bit(0) == 1) != odd {
+		y.Sub(c.p, y)
+	}
+	return refPoint{x: x, y: y}, true
+}
+
+func (c *refSecp256k1) decodePoint(in []byte) (refPoint, bool) {
+	if len(in) != 33 || (in[0] != 2 && in[0] != 3) {
+		return refPoint{}, false
+	}
+	return c.liftX(new(big.Int).SetBytes(in[1:]), in[0] == 3)
+}
+
+func (c *refSecp256k1) encodePoint(p refPoint) []byte {
+	out := make([]byte, 33)
+	if p.inf {
+		out[0] = 2
+		return out
+	}
+	out[0] = 2 + byte(p.y.Bit(0))
+	p.x.FillBytes(out[1:])
+	return out
+}
+
+func (c *refSecp256k1) decodeScalar(in []byte) *big.Int {
+	return new(big.Int).Mod(new(big.Int).SetBytes(in), c.n)
+}
+
+func (c *refSecp256k1) hashToPoint(p refPoint) refPoint {
+	hash := sha3.Sum256(c.encodePoint(p))
+	for {
+		x := new(big.Int).Mod(new(big.Int).SetBytes(hash[:]), c.p)
+		if point, ok := c.liftX(x, false); ok {
+			return point
+		}
+		hash = sha3.Sum256(hash[:])
+	}
+}
+
+// hashToScalar reproduces go-dleq's secp256k1 HashToScalar, including its
+// quirk of left-aligning the reduced hash in a 32-byte buffer: when the reduced
+// value has leading zero bytes, the resulting scalar is shifted left by those
+// bytes instead of being the reduced hash itself. This is part of the challenge
+// transcript of every signature produced so far, so it is reproduced here
+// rather than "fixed".
+func (c *refSecp256k1) hashToScalar(in []byte) *big.Int {
+	h := sha3.Sum512(in)
+	reduced := new(big.Int).Mod(new(big.Int).SetBytes(h[:]), c.n)
+
+	var buf [32]byte
+	copy(buf[:], reduced.Bytes())
+	return new(big.Int).Mod(new(big.Int).SetBytes(buf[:]), c.n)
+}
+
+type refEd25519 struct {
+	p, l, d, sqrtM1 *big.Int
+	b               refPoint
+}
+
+func newRefEd25519() *refEd25519 {
+	c := &refEd25519{}
+	c.p = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	c.l, _ = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+
+	// d = -121665 / 121666
+	c.d = new(big.Int).ModInverse(big.NewInt(121666), c.p)
+	c.d.Mul(c.d, big.NewInt(-121665)).Mod(c.d, c.p)
+
+	// sqrt(-1) = 2^((p-1)/4)
+	exp := new(big.Int).Sub(c.p, big.NewInt(1))
+	c.sqrtM1 = new(big.Int).Exp(big.NewInt(2), exp.Rsh(exp, 2), c.p)
+
+	// the base point has y = 4/5 and a positive x
+	y := new(big.Int).ModInverse(big.NewInt(5), c.p)
+	y.Mul(y, big.NewInt(4)).Mod(y, c.p)
+	enc := make([]byte, 32)
+	y.FillBytes(enc)
+	reverseBytes(enc)
+	b, ok := c.decodePoint(enc)
+	if !ok {
+		panic("failed to decode ed25519 base point")
+	}
+	c.b = b
+	return c
+}
+
+func reverseBytes(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
+
+func (c *refEd25519) identity() refPoint { return refPoint{x: big.NewInt(0), y: big.NewInt(1)} }
+
+func (c *refEd25519) base() refPoint { return c.b }
+
+func (c *refEd25519) add(a, b refPoint) refPoint {
+	// x3 = (x1*y2 + y1*x2) / (1 + d*x1*x2*y1*y2)
+	// y3 = (y1*y2 + x1*x2) / (1 - d*x1*x2*y1*y2)
+	dxy := new(big.Int).Mul(c.d, a.x)
+	dxy.Mul(dxy, b.x).Mul(dxy, a.y).Mul(dxy, b.y).Mod(dxy, c.p)
+
+	xNum := new(big.Int).Add(new(big.Int).Mul(a.x, b.y), new(big.Int).Mul(a.y, b.x))
+	xDen := new(big.Int).Add(big.NewInt(1), dxy)
+	yNum := new(big.Int).Add(new(big.Int).Mul(a.y, b.y), new(big.Int).Mul(a.x, b.x))
+	yDen := new(big.Int).Sub(big.NewInt(1), dxy)
+
+	x := xNum.Mul(xNum, xDen.ModInverse(xDen.Mod(xDen, c.p), c.p)).Mod(xNum, c.p)
+	y := yNum.Mul(yNum, yDen.ModInverse(yDen.Mod(yDen, c.p), c.p)).Mod(yNum, c.p)
+	return refPoint{x: x, y: y}
+}
+
+func (c *refEd25519) decodePoint(in []byte) (refPoint, bool) {
+	if len(in) != 32 {
+		return refPoint{}, false
+	}
+
+	enc := make([]byte, 32)
+	copy(enc, in)
+	negative := enc[31]>>7 == 1
+	enc[31] &= 0x7f
+	reverseBytes(enc)
+	y := new(big.Int).SetBytes(enc)
+	y.Mod(y, c.p)
+
+	// x^2 = (y^2 - 1) / (d*y^2 + 1)
+	y2 := new(big.Int).Mul(y, y)
+	num := new(big.Int).Sub(y2, big.NewInt(1))
+	den := new(big.Int).Mul(c.d, y2)
+	den.Add(den, big.NewInt(1)).Mod(den, c.p)
+	x2 := num.Mul(num, den.ModInverse(den, c.p)).Mod(num, c.p)
+
+	// candidate root x = x2^((p+3)/8), fixed up by sqrt(-1) if needed
+	exp := new(big.Int).Add(c.p, big.NewInt(3))
+	x := new(big.Int).Exp(x2, exp.Rsh(exp, 3), c.p)
+	if new(big.Int).Exp(x, big.NewInt(2), c.p).Cmp(x2) != 0 {
+		x.Mul(x, c.sqrtM1).Mod(x, c.p)
+	}
+	if new(big.Int).Exp(x, big.NewInt(2), c.p).Cmp(x2) != 0 {
+		return refPoint{}, false
+	}
+
+	if (x.Bit(0) == 1) != negative {
+		x.Sub(c.p, x).Mod(x, c.p)
+	}
+	return refPoint{x: x, y: y}, true
+}
+
+func (c *refEd25519) encodePoint(p refPoint) []byte {
+	out := make([]byte, 32)
+	p.y.FillBytes(out)
+	reverseBytes(out)
+	out[31] |= byte(p.x.Bit(0)) << 7
+	return out
+}
+
+func (c *refEd25519) decodeScalar(in []byte) *big.Int {
+	le := make([]byte, len(in))
+	copy(le, in)
+	reverseBytes(le)
+	return new(big.Int).Mod(new(big.Int).SetBytes(le), c.l)
+}
+
+func (c *refEd25519) hashToPoint(p refPoint) refPoint {
+	hash := sha3.Sum256(c.encodePoint(p))
+	for {
+		if point, ok := c.decodePoint(hash[:]); ok {
+			return refMul(c, big.NewInt(8), point)
+		}
+		hash = sha3.Sum256(hash[:])
+	}
+}
+
+func (c *refEd25519) hashToScalar(in []byte) *big.Int {
+	h := sha3.Sum512(in)
+	return c.decodeScalar(h[:])
+}
+
+func testDifferential(t *testing.T, curve Curve, ref refCurve) {
+	if *diffTestIterations == 0 {
+		t.Skip("differential tests disabled, enable with -difftest=N")
+	}
+
+	for i := 0; i < *diffTestIterations; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(15))
+		require.NoError(t, err)
+		size := int(n.Int64()) + 2
+		idx := i % size
+
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, size, privKey, idx)
+		require.NoError(t, err)
+
+		var msg [32]byte
+		_, err = rand.Read(msg[:])
+		require.NoError(t, err)
+
+		sig, err := keyring.Sign(msg, privKey)
+		require.NoError(t, err)
+		require.True(t, sig.Verify(msg))
+
+		pubkeys := make([][]byte, size)
+		s := make([][]byte, size)
+		for j := 0; j < size; j++ {
+			pubkeys[j] = keyring.pubkeys[j].Encode()
+			s[j] = sig.s[j].Encode()
+		}
+
+		// the key image must match x * H_p(P) computed by the reference
+		x := ref.decodeScalar(privKey.Encode())
+		pub := refMul(ref, x, ref.base())
+		require.Equal(t, pubkeys[idx], ref.encodePoint(pub))
+		image := refMul(ref, x, ref.hashToPoint(pub))
+		require.Equal(t, ref.encodePoint(image), sig.image.Encode())
+
+		// the signature must be valid for the reference verifier, and only
+		// for the signed message
+		require.True(t, refVerify(ref, msg, pubkeys, sig.image.Encode(), sig.c.Encode(), s))
+		msg[0] ^= 0xff
+		require.False(t, refVerify(ref, msg, pubkeys, sig.image.Encode(), sig.c.Encode(), s))
+	}
+}
+
+func TestHashToScalar_Secp256k1_LeadingZero(t *testing.T) {
+	curve := Secp256k1()
+	ref := newRefSecp256k1()
+
+	// find an input whose reduced hash has a leading zero byte, which happens
+	// with probability 1/256
+	for i := 0; ; i++ {
+		in := []byte{byte(i), byte(i >> 8)}
+		h := sha3.Sum512(in)
+		reduced := new(big.Int).Mod(new(big.Int).SetBytes(h[:]), ref.n)
+		if reduced.BitLen() > 248 {
+			continue
+		}
+
+		s, err := curve.HashToScalar(in)
+		require.NoError(t, err)
+		got := new(big.Int).SetBytes(s.Encode())
+		require.NotEqual(t, reduced, got)
+		require.Equal(t, ref.hashToScalar(in), got)
+		return
+	}
+}
+
+func TestDifferential_Secp256k1(t *testing.T) {
+	testDifferential(t, Secp256k1(), newRefSecp256k1())
+}
+
+func TestDifferential_Ed25519(t *testing.T) {
+	testDifferential(t, Ed25519(), newRefEd25519())
+}
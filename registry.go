@@ -0,0 +1,148 @@
+package ring
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// MinRegisteredCurveID is the smallest ID of curves registered with
+// RegisterCurve. Smaller IDs are reserved for the curves of this package.
+const MinRegisteredCurveID = 0x80
+
+// RegisterCurve registers a third-party curve implementation, eg. ristretto255,
+// under the given ID, so that it participates in the curve-tagged encodings
+// of this package: CurveID.Curve, CurveIDOf and CurveIDOfPoint know it, and
+// so do the functions built on them, such as the byte-slice API, the text and
+// armored encodings, keyring files and the CLI. newCurve returns a new
+// instance of the curve; all its instances must use the same point type.
+//
+// The curve's name, as returned by CurveID.String and accepted by
+// ParseCurveID, is that returned by its Name method if it has one, and
+// "curve<ID>" otherwise. Signing and verifying require the hash to curve
+// H_p of public keys, which the curve provides by implementing HPProvider;
+// its groups must have prime order, or its DecodeToPoint must reject points
+// outside the prime-order subgroup.
+//
+// RegisterCurve is meant to be called from init functions. It panics if the
+// ID is less than MinRegisteredCurveID, or if the ID or the name is already
+// registered.
+func RegisterCurve(id byte, newCurve func() types.Curve) {
+	if id < MinRegisteredCurveID {
+		panic(fmt.Sprintf("ring: curve ID %d is reserved", id))
+	}
+
+	curve := newCurve()
+	reg := &registeredCurve{
+		id:        CurveID(id),
+		name:      "curve" + strconv.Itoa(int(id)),
+		newCurve:  newCurve,
+		pointType: reflect.TypeOf(curve.BasePoint()),
+		sample:    curve,
+	}
+	if named, ok := curve.(interface{ Name() string }); ok {
+		reg.name = named.Name()
+	}
+	if _, err := ParseCurveID(reg.name); err == nil || reg.name == CurveUnknown.String() {
+		panic(fmt.Sprintf("ring: curve name %q is already in use", reg.name))
+	}
+
+	curveRegistry.Lock()
+	defer curveRegistry.Unlock()
+	for _, other := range curveRegistry.curves {
+		switch {
+		case other.id == reg.id:
+			panic(fmt.Sprintf("ring: curve ID %d registered twice", id))
+		case other.name == reg.name:
+			panic(fmt.Sprintf("ring: curve name %q registered twice", reg.name))
+		}
+	}
+	curveRegistry.curves = append(curveRegistry.curves, reg)
+	sort.Slice(curveRegistry.curves, func(i, j int) bool {
+		return curveRegistry.curves[i].id < curveRegistry.curves[j].id
+	})
+}
+
+// RegisteredCurves returns the IDs of the curves registered with RegisterCurve,
+// in increasing order.
+func RegisteredCurves() []CurveID {
+	curveRegistry.RLock()
+	defer curveRegistry.RUnlock()
+	ids := make([]CurveID, len(curveRegistry.curves))
+	for i, reg := range curveRegistry.curves {
+		ids[i] = reg.id
+	}
+	return ids
+}
+
+// registeredCurve is a curve registered with RegisterCurve.
+type registeredCurve struct {
+	id        CurveID
+	name      string
+	newCurve  func() types.Curve
+	pointType reflect.Type
+	// sample is an instance of the curve, for identifying other instances
+	sample types.Curve
+}
+
+// curveRegistry holds the registered curves, sorted by ID.
+var curveRegistry struct {
+	sync.RWMutex
+	curves []*registeredCurve
+}
+
+// findCurve returns the registered curve for which match returns true, or nil.
+func findCurve(match func(*registeredCurve) bool) *registeredCurve {
+	curveRegistry.RLock()
+	defer curveRegistry.RUnlock()
+	for _, reg := range curveRegistry.curves {
+		if match(reg) {
+			return reg
+		}
+	}
+	return nil
+}
+
+func registeredCurveByID(id CurveID) *registeredCurve {
+	return findCurve(func(reg *registeredCurve) bool {
+		return reg.id == id
+	})
+}
+
+func registeredCurveOf(curve types.Curve) *registeredCurve {
+	if curve == nil || len(RegisteredCurves()) == 0 {
+		return nil
+	}
+	pointType := reflect.TypeOf(curve.BasePoint())
+	return findCurve(func(reg *registeredCurve) bool {
+		return reg.pointType == pointType && sameCurve(reg.sample, curve)
+	})
+}
+
+func registeredCurveOfPoint(p types.Point) *registeredCurve {
+	if p == nil {
+		return nil
+	}
+	pointType := reflect.TypeOf(p)
+	return findCurve(func(reg *registeredCurve) bool {
+		return reg.pointType == pointType
+	})
+}
+
+// registeredHashToCurve returns H_p(pk) for a point of a registered curve
+// implementing HPProvider, and false if there's none.
+func registeredHashToCurve(pk types.Point) (types.Point, bool) {
+	reg := registeredCurveOfPoint(pk)
+	if reg == nil {
+		return nil, false
+	}
+	hasher, ok := reg.sample.(HPProvider)
+	if !ok {
+		return nil, false
+	}
+	return hasher.HashToCurve(pk), true
+}
@@ -0,0 +1,115 @@
+package ring
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testCurveID is the ID of wrappedCurve in the tests.
+const testCurveID = 0xf0
+
+// wrappedCurve is ed25519 with its own point type, standing in for a
+// third-party curve implementation.
+type wrappedCurve struct {
+	types.Curve
+}
+
+type wrappedPoint struct {
+	inner types.Point
+}
+
+var registerTestCurve = sync.OnceFunc(func() {
+	RegisterCurve(testCurveID, func() types.Curve {
+		return &wrappedCurve{Curve: Ed25519()}
+	})
+})
+
+func unwrap(p types.Point) types.Point {
+	return p.(*wrappedPoint).inner
+}
+
+func (*wrappedCurve) Name() string { return "wrapped-ed25519" }
+
+func (c *wrappedCurve) BasePoint() types.Point {
+	return &wrappedPoint{c.Curve.BasePoint()}
+}
+
+func (c *wrappedCurve) AltBasePoint() types.Point {
+	return &wrappedPoint{c.Curve.AltBasePoint()}
+}
+
+func (c *wrappedCurve) ScalarBaseMul(s types.Scalar) types.Point {
+	return &wrappedPoint{c.Curve.ScalarBaseMul(s)}
+}
+
+func (c *wrappedCurve) ScalarMul(s types.Scalar, p types.Point) types.Point {
+	return &wrappedPoint{c.Curve.ScalarMul(s, unwrap(p))}
+}
+
+func (c *wrappedCurve) DecodeToPoint(in []byte) (types.Point, error) {
+	p, err := c.Curve.DecodeToPoint(in)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedPoint{p}, nil
+}
+
+func (c *wrappedCurve) HashToCurve(pk types.Point) types.Point {
+	return &wrappedPoint{hashToCurve(unwrap(pk))}
+}
+
+func (p *wrappedPoint) Copy() types.Point             { return &wrappedPoint{p.inner.Copy()} }
+func (p *wrappedPoint) Add(q types.Point) types.Point { return &wrappedPoint{p.inner.Add(unwrap(q))} }
+func (p *wrappedPoint) Sub(q types.Point) types.Point { return &wrappedPoint{p.inner.Sub(unwrap(q))} }
+func (p *wrappedPoint) ScalarMul(s types.Scalar) types.Point {
+	return &wrappedPoint{p.inner.ScalarMul(s)}
+}
+func (p *wrappedPoint) Encode() []byte            { return p.inner.Encode() }
+func (p *wrappedPoint) IsZero() bool              { return p.inner.IsZero() }
+func (p *wrappedPoint) Equals(q types.Point) bool { return p.inner.Equals(unwrap(q)) }
+
+func TestRegisterCurve(t *testing.T) {
+	registerTestCurve()
+	id := CurveID(testCurveID)
+	require.Contains(t, RegisteredCurves(), id)
+	require.Equal(t, "wrapped-ed25519", id.String())
+	parsed, err := ParseCurveID("wrapped-ed25519")
+	require.NoError(t, err)
+	require.Equal(t, id, parsed)
+
+	curve, err := id.Curve()
+	require.NoError(t, err)
+	require.Equal(t, id, CurveIDOf(curve))
+	require.Equal(t, id, CurveIDOfPoint(curve.BasePoint()))
+	require.Equal(t, CurveEd25519, CurveIDOf(Ed25519()))
+
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 1)
+	require.NoError(t, err)
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	require.True(t, sig.Verify(testMsg))
+
+	// the curve-tagged encodings know the curve
+	parsedSig, err := ParseRingSig(sig.String())
+	require.NoError(t, err)
+	require.True(t, parsedSig.Verify(testMsg))
+	require.Equal(t, id, CurveIDOf(parsedSig.Ring().Curve()))
+
+	sigBytes, err := SignBytes(id, keyring.Bytes(), privKey.Encode(), testMsg[:])
+	require.NoError(t, err)
+	require.True(t, VerifyBytes(id, sigBytes, testMsg[:]))
+}
+
+func TestRegisterCurve_Panics(t *testing.T) {
+	registerTestCurve()
+	newCurve := func() types.Curve { return &wrappedCurve{Curve: Ed25519()} }
+	require.Panics(t, func() { RegisterCurve(byte(CurveEd25519), newCurve) })
+	require.Panics(t, func() { RegisterCurve(testCurveID, newCurve) })
+	// same name under another ID
+	require.Panics(t, func() { RegisterCurve(testCurveID+1, newCurve) })
+	require.NotContains(t, RegisteredCurves(), CurveID(testCurveID+1))
+}
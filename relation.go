@@ -0,0 +1,91 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// Relation is the verification relation of a signature: every value a verifier
+// computes, in order, for proving in zero knowledge that a signature is valid,
+// eg. with a SNARK circuit. For each ring member i, with c_0 the signature's
+// challenge:
+//
+//	L_i     = s_i*G + c_i*P_i
+//	R_i     = s_i*H_p(P_i) + c_i*I
+//	c_{i+1} = HashToScalar(m || L_i || R_i)
+//
+// where m is the transcript message and the points are compressed, and the
+// signature is valid if c_n = c_0. See package zkwitness for an export format.
+type Relation struct {
+	Curve CurveID
+	// Message is the transcript message m: the signed message, or a hash of it
+	// and the signature's extension fields if it has any.
+	Message [32]byte
+	// KeyImage is the signature's key image I.
+	KeyImage types.Point
+	// Members holds the values of each ring member, in order.
+	Members []RelationMember
+}
+
+// RelationMember holds the values of the relation for a ring member.
+type RelationMember struct {
+	// PublicKey is P_i, and HashPoint H_p(P_i).
+	PublicKey, HashPoint types.Point
+	// Challenge is c_i, and Response s_i.
+	Challenge, Response types.Scalar
+	// L and R are L_i and R_i.
+	L, R types.Point
+}
+
+// Relation returns the verification relation of the signature for the given
+// message. It returns ErrInvalidSignature if the signature isn't valid, as the
+// relation of an invalid signature doesn't hold. The validity window isn't
+// checked.
+func (sig *RingSig) Relation(m [32]byte) (_ *Relation, err error) {
+	defer recoverInternal(&err)
+
+	ring := sig.ring
+	size := len(ring.pubkeys)
+	switch {
+	case size == 0:
+		return nil, errors.New("empty ring")
+	case len(sig.s) != size:
+		return nil, fmt.Errorf("%d responses for a ring of %d members", len(sig.s), size)
+	case !isTorsionFree(sig.image):
+		return nil, ErrInvalidSignature
+	}
+
+	curve := ring.curve
+	rel := &Relation{
+		Curve:    CurveIDOf(curve),
+		Message:  sig.ext.message(m),
+		KeyImage: sig.image.Copy(),
+		Members:  make([]RelationMember, 0, size),
+	}
+
+	ring.ensureHP()
+	ch := newChallenger(curve, rel.Message)
+	c := sig.c
+	_ = ring.forEachHP(0, size, func(i int, hp types.Point) error {
+		pk := ring.pubkeys[i]
+		l := ch.ops.doubleScalarBaseMul(c, pk, sig.s[i])
+		r := ch.ops.twoScalarMul(c, sig.image, sig.s[i], hp)
+		rel.Members = append(rel.Members, RelationMember{
+			PublicKey: pk.Copy(),
+			HashPoint: hp.Copy(),
+			Challenge: c,
+			Response:  sig.s[i],
+			L:         l,
+			R:         r,
+		})
+		c = ch.challenge(l, r)
+		return nil
+	})
+
+	if !sig.c.Eq(c) {
+		return nil, ErrInvalidSignature
+	}
+	return rel, nil
+}
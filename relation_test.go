@@ -0,0 +1,46 @@
+package ring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelation(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 4, privKey, 1)
+		require.NoError(t, err)
+
+		// expired signatures still have a relation
+		sig, err := keyring.Sign(testMsg, privKey, WithValidity(time.Time{}, time.Now().Add(-time.Hour)))
+		require.NoError(t, err)
+		require.False(t, sig.Verify(testMsg))
+
+		rel, err := sig.Relation(testMsg)
+		require.NoError(t, err)
+		require.Equal(t, CurveIDOf(curve), rel.Curve)
+		require.Equal(t, sig.ext.message(testMsg), rel.Message)
+		require.True(t, rel.KeyImage.Equals(sig.KeyImage()))
+		require.Len(t, rel.Members, 4)
+		require.True(t, rel.Members[0].Challenge.Eq(sig.c))
+
+		ch := newChallenger(curve, rel.Message)
+		for i, m := range rel.Members {
+			require.True(t, m.PublicKey.Equals(keyring.pubkeys[i]))
+			require.True(t, m.HashPoint.Equals(hashToCurve(m.PublicKey)))
+			require.True(t, m.L.Equals(curve.ScalarBaseMul(m.Response).Add(curve.ScalarMul(m.Challenge, m.PublicKey))))
+			require.True(t, m.R.Equals(curve.ScalarMul(m.Response, m.HashPoint).Add(curve.ScalarMul(m.Challenge, rel.KeyImage))))
+
+			next := rel.Members[0].Challenge
+			if i+1 < len(rel.Members) {
+				next = rel.Members[i+1].Challenge
+			}
+			require.True(t, ch.challenge(m.L, m.R).Eq(next))
+		}
+
+		_, err = sig.Relation([32]byte{1})
+		require.ErrorIs(t, err, ErrInvalidSignature)
+	}
+}
@@ -0,0 +1,155 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// VerificationReport describes the verification of a signature in detail, for
+// debugging signatures that fail to verify. See RingSig.Report.
+type VerificationReport struct {
+	// Valid is true if the signature is valid, ie. if Verify returns true.
+	Valid bool
+	// Err is the reason verification failed, or nil if the signature is valid.
+	Err error
+	// FailedIndex is the index of the ring member at which verification broke,
+	// or -1 if the signature is valid or failed before the ring was processed.
+	// It's the first member whose checks failed if any did; otherwise, the
+	// chain of challenges didn't close, which is attributed to the last member.
+	FailedIndex int
+
+	// KeyImageValid is true if the key image has no small-order component.
+	KeyImageValid bool
+	// ValidityChecked is true if the signature has a validity window, in which
+	// case WithinValidity reports whether it contains the verification time.
+	ValidityChecked, WithinValidity bool
+
+	// Members holds the details of each ring member, in order. It's empty if
+	// verification failed before the ring was processed.
+	Members []MemberReport
+
+	// Duration is the total time taken to verify the signature.
+	Duration time.Duration
+}
+
+// MemberReport describes the verification step of a single ring member.
+type MemberReport struct {
+	Index int
+
+	// PublicKeyValid is true if the public key is not the identity and has no
+	// small-order component. Verify doesn't check public keys, so a signature
+	// can be valid even if some aren't, but such rings are usually a mistake.
+	PublicKeyValid bool
+
+	// Challenge is the encoding of the challenge c_i used for this member, and
+	// NextChallenge that of c_{i+1} = H(m, L_i, R_i).
+	Challenge, NextChallenge []byte
+	// L and R are the encodings of L_i = s_i*G + c_i*P_i and
+	// R_i = s_i*H_p(P_i) + c_i*I.
+	L, R []byte
+
+	// Duration is the time taken by this member's step.
+	Duration time.Duration
+}
+
+// String summarizes the report, eg. for logging.
+func (r *VerificationReport) String() string {
+	if r.Valid {
+		return fmt.Sprintf("valid signature (%d members, %s)", len(r.Members), r.Duration)
+	}
+	if r.FailedIndex >= 0 {
+		return fmt.Sprintf("invalid signature: %s at member %d", r.Err, r.FailedIndex)
+	}
+	return fmt.Sprintf("invalid signature: %s", r.Err)
+}
+
+// Report verifies the signature for the given message like Verify, and returns
+// a detailed report of the verification: the recomputed chain of challenges,
+// the checks of each point, timings, and where verification failed. It's much
+// slower than Verify and meant for debugging.
+func (sig *RingSig) Report(m [32]byte) *VerificationReport {
+	start := time.Now()
+	report := sig.report(m, start)
+	report.Duration = time.Since(start)
+	report.Valid = report.Err == nil
+	if report.Valid {
+		report.FailedIndex = -1
+	}
+	return report
+}
+
+func (sig *RingSig) report(m [32]byte, at time.Time) (report *VerificationReport) {
+	report = &VerificationReport{FailedIndex: -1}
+	defer recoverInternal(&report.Err)
+
+	if sig.ext.validity != nil {
+		report.ValidityChecked = true
+		report.WithinValidity = sig.ext.validity.contains(at)
+	}
+
+	report.KeyImageValid = isTorsionFree(sig.image)
+
+	ring := sig.ring
+	size := len(ring.pubkeys)
+	switch {
+	case report.ValidityChecked && !report.WithinValidity:
+		report.Err = ErrNotValidAt
+		return report
+	case size == 0:
+		report.Err = errors.New("empty ring")
+		return report
+	case len(sig.s) != size:
+		report.Err = fmt.Errorf("%d responses for a ring of %d members", len(sig.s), size)
+		return report
+	case !report.KeyImageValid:
+		report.Err = errors.New("invalid key image")
+		return report
+	}
+
+	curve := ring.curve
+	ring.ensureHP()
+	ch, err := sig.ext.challenger(curve, sig.ext.message(m))
+	if err != nil {
+		report.Err = err
+		return report
+	}
+	report.Members = make([]MemberReport, 0, size)
+
+	c := sig.c
+	ops := opsOf(curve)
+	_ = ring.forEachHP(0, size, func(i int, hp types.Point) error {
+		start := time.Now()
+		pk := ring.pubkeys[i]
+		member := MemberReport{
+			Index:          i,
+			PublicKeyValid: !isIdentity(pk) && isTorsionFree(pk),
+			Challenge:      c.Encode(),
+		}
+
+		l := ops.doubleScalarBaseMul(c, pk, sig.s[i])
+		r := ops.twoScalarMul(c, sig.image, sig.s[i], hp)
+		c = ch.challenge(l, r)
+
+		member.L, member.R = l.Encode(), r.Encode()
+		member.NextChallenge = c.Encode()
+		member.Duration = time.Since(start)
+		report.Members = append(report.Members, member)
+		return nil
+	})
+
+	if !sig.c.Eq(c) {
+		report.Err = ErrInvalidSignature
+		report.FailedIndex = size - 1
+		for _, member := range report.Members {
+			if !member.PublicKeyValid {
+				report.FailedIndex = member.Index
+				break
+			}
+		}
+	}
+
+	return report
+}
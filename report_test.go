@@ -0,0 +1,89 @@
+package ring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport_Valid(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		sig := createSigWithCurve(t, curve, 6, 2)
+
+		report := sig.Report(testMsg)
+		require.True(t, report.Valid)
+		require.NoError(t, report.Err)
+		require.Equal(t, -1, report.FailedIndex)
+		require.True(t, report.KeyImageValid)
+		require.False(t, report.ValidityChecked)
+		require.Positive(t, report.Duration)
+		require.Contains(t, report.String(), "valid signature")
+
+		// the chain of challenges starts and ends with c_0
+		require.Len(t, report.Members, 6)
+		require.Equal(t, sig.c.Encode(), report.Members[0].Challenge)
+		require.Equal(t, sig.c.Encode(), report.Members[5].NextChallenge)
+		for i, member := range report.Members {
+			require.Equal(t, i, member.Index)
+			require.True(t, member.PublicKeyValid)
+			require.NotEmpty(t, member.L)
+			require.NotEmpty(t, member.R)
+			if i > 0 {
+				require.Equal(t, report.Members[i-1].NextChallenge, member.Challenge)
+			}
+		}
+	}
+}
+
+func TestReport_Invalid(t *testing.T) {
+	sig := createSigWithCurve(t, Ed25519(), 4, 1)
+
+	report := sig.Report([32]byte{9})
+	require.False(t, report.Valid)
+	require.ErrorIs(t, report.Err, ErrInvalidSignature)
+	require.Equal(t, 3, report.FailedIndex)
+	require.Len(t, report.Members, 4)
+	require.Contains(t, report.String(), "at member 3")
+	require.Equal(t, sig.Verify([32]byte{9}), report.Valid)
+}
+
+func TestReport_InvalidPublicKey(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	pubkeys := []Point{
+		curve.ScalarBaseMul(curve.NewRandomScalar()),
+		// the identity
+		curve.ScalarBaseMul(curve.ScalarFromInt(0)),
+	}
+	keyring, err := NewKeyRingFromPublicKeys(curve, pubkeys, privKey, 0)
+	require.NoError(t, err)
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+
+	// the signature is valid, but the report flags the member
+	report := sig.Report(testMsg)
+	require.True(t, report.Valid)
+	require.False(t, report.Members[2].PublicKeyValid)
+
+	report = sig.Report([32]byte{1})
+	require.False(t, report.Valid)
+	require.Equal(t, 2, report.FailedIndex)
+}
+
+func TestReport_Validity(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 2, privKey, 0)
+	require.NoError(t, err)
+	sig, err := keyring.Sign(testMsg, privKey, WithValidity(time.Time{}, time.Now().Add(-time.Hour)))
+	require.NoError(t, err)
+
+	report := sig.Report(testMsg)
+	require.False(t, report.Valid)
+	require.True(t, report.ValidityChecked)
+	require.False(t, report.WithinValidity)
+	require.ErrorIs(t, report.Err, ErrNotValidAt)
+	require.Equal(t, -1, report.FailedIndex)
+	require.Empty(t, report.Members)
+}
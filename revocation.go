@@ -0,0 +1,43 @@
+package ring
+
+import (
+	"bytes"
+
+	"github.com/pokt-network/ring-go/revocation"
+)
+
+// ContainsRevoked returns true if any of the ring's public keys is revoked in
+// the given list.
+func (r *Ring) ContainsRevoked(list *revocation.List) bool {
+	for _, pk := range r.pubkeys {
+		if list.ContainsPublicKey(pk) {
+			return true
+		}
+	}
+	return false
+}
+
+// RevokedSigner returns whether the signature was created by a member whose
+// key image is revoked in the given list.
+//
+// If the list also records the public key the image belongs to, and that key
+// is in the signature's ring, idx is its index in the ring, ie. the signer is
+// deanonymized; otherwise idx is -1. This applies to every signature the
+// member ever created, not only those created after the revocation; see the
+// revocation package documentation.
+func (r *RingSig) RevokedSigner(list *revocation.List) (idx int, revoked bool) {
+	owner, revoked := list.KeyImageOwner(NormalizeKeyImage(r.image))
+	if !revoked {
+		return -1, false
+	}
+
+	if owner != nil {
+		for i, pk := range r.ring.pubkeys {
+			if bytes.Equal(encodePoint(pk), owner) {
+				return i, true
+			}
+		}
+	}
+
+	return -1, true
+}
@@ -0,0 +1,273 @@
+// Package revocation implements lists of revoked ring members.
+//
+// Members can be revoked by public key, which prevents rings containing them
+// from being accepted, or by key image, which identifies signatures created by
+// them.
+//
+// Revoking a member by key image must be done with care: as a member's key
+// image is the same for every signature it creates, in every ring, publishing
+// it links all of the member's past signatures to each other, and, if the
+// image is published along with the member's public key, to the member
+// itself. This deanonymizes the member's old signatures, not only its future
+// ones. Revoking by public key only doesn't have this issue, but doesn't allow
+// rejecting signatures over rings created before the revocation.
+package revocation
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// ErrRevoked is returned by the policy returned by List.Policy for revoked
+// public keys.
+var ErrRevoked = errors.New("public key is revoked")
+
+const (
+	formatVersion = 1
+
+	// maxEntryLen is the maximum length of an encoded point in a list.
+	maxEntryLen = 255
+)
+
+// List is a set of revoked public keys and key images. Points are stored by
+// their encodings, eg. compressed public keys for secp256k1, so a List can
+// hold points of any curve, but each point only matches points of the same
+// curve.
+//
+// Key images should be normalized with ring.NormalizeKeyImage before being
+// added; images of valid signatures are already normalized.
+//
+// A List is safe for concurrent use.
+type List struct {
+	mu      sync.RWMutex
+	pubkeys map[string]struct{}
+	// images maps key image encodings to the encoding of the public key they
+	// belong to, or "" if it is unknown.
+	images map[string]string
+}
+
+// NewList returns an empty revocation list.
+func NewList() *List {
+	return &List{
+		pubkeys: make(map[string]struct{}),
+		images:  make(map[string]string),
+	}
+}
+
+// AddPublicKey revokes the given public key.
+func (l *List) AddPublicKey(pubkey types.Point) {
+	l.AddPublicKeyBytes(encode(pubkey))
+}
+
+// AddPublicKeyBytes revokes the public key with the given encoding, eg. a
+// 33-byte compressed secp256k1 key.
+func (l *List) AddPublicKeyBytes(pubkey []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pubkeys[string(pubkey)] = struct{}{}
+}
+
+// AddKeyImage revokes the given key image. pubkey is the public key the image
+// belongs to, if known, or nil. See the package documentation for the privacy
+// implications of revoking key images.
+func (l *List) AddKeyImage(image, pubkey types.Point) {
+	var pk []byte
+	if pubkey != nil {
+		pk = encode(pubkey)
+	}
+	l.AddKeyImageBytes(encode(image), pk)
+}
+
+// AddKeyImageBytes revokes the key image with the given encoding, like
+// AddKeyImage. pubkey may be nil.
+func (l *List) AddKeyImageBytes(image, pubkey []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.images[string(image)] = string(pubkey)
+}
+
+// ContainsPublicKey returns true if the public key is revoked.
+func (l *List) ContainsPublicKey(pubkey types.Point) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	_, ok := l.pubkeys[string(encode(pubkey))]
+	return ok
+}
+
+// ContainsKeyImage returns true if the key image is revoked.
+func (l *List) ContainsKeyImage(image types.Point) bool {
+	_, ok := l.KeyImageOwner(image)
+	return ok
+}
+
+// KeyImageOwner returns whether the key image is revoked, and if so, the
+// encoding of the public key it belongs to, or nil if it is unknown.
+func (l *List) KeyImageOwner(image types.Point) (pubkey []byte, revoked bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	pk, ok := l.images[string(encode(image))]
+	if !ok || pk == "" {
+		return nil, ok
+	}
+	return []byte(pk), true
+}
+
+// Len returns the number of revoked public keys and key images.
+func (l *List) Len() (pubkeys, images int) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.pubkeys), len(l.images)
+}
+
+// Policy returns a policy for ring.RingSig.VerifyWithPolicy rejecting rings
+// that contain a revoked public key with ErrRevoked.
+func (l *List) Policy() func(i int, pubkey types.Point) error {
+	return func(_ int, pubkey types.Point) error {
+		if l.ContainsPublicKey(pubkey) {
+			return ErrRevoked
+		}
+		return nil
+	}
+}
+
+// Serialize encodes the list. The encoding is deterministic: equal lists have
+// equal encodings.
+//
+// The format is a version byte, followed by the number of public keys as a
+// big-endian uint32 and the public keys, then the number of key images and
+// the key images, each followed by its public key. Each point is encoded as
+// its length as a single byte followed by its encoding; unknown public keys
+// have a length of 0.
+func (l *List) Serialize() ([]byte, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	pubkeys := make([]string, 0, len(l.pubkeys))
+	for pk := range l.pubkeys {
+		pubkeys = append(pubkeys, pk)
+	}
+	sort.Strings(pubkeys)
+
+	images := make([]string, 0, len(l.images))
+	for image := range l.images {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+
+	out := []byte{formatVersion}
+	out = binary.BigEndian.AppendUint32(out, uint32(len(pubkeys)))
+	for _, pk := range pubkeys {
+		var err error
+		if out, err = appendEntry(out, pk); err != nil {
+			return nil, err
+		}
+	}
+
+	out = binary.BigEndian.AppendUint32(out, uint32(len(images)))
+	for _, image := range images {
+		var err error
+		if out, err = appendEntry(out, image); err != nil {
+			return nil, err
+		}
+		if out, err = appendEntry(out, l.images[image]); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// Deserialize decodes a list encoded with Serialize.
+func Deserialize(in []byte) (*List, error) {
+	if len(in) == 0 {
+		return nil, errors.New("input too short")
+	}
+
+	if in[0] != formatVersion {
+		return nil, fmt.Errorf("unsupported format version %d", in[0])
+	}
+
+	d := decoder{in: in[1:]}
+	l := NewList()
+
+	n, err := d.uint32()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < n; i++ {
+		pk, err := d.entry()
+		if err != nil {
+			return nil, err
+		}
+		if len(pk) == 0 {
+			return nil, errors.New("empty public key")
+		}
+		l.pubkeys[pk] = struct{}{}
+	}
+
+	n, err = d.uint32()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < n; i++ {
+		image, err := d.entry()
+		if err != nil {
+			return nil, err
+		}
+		if len(image) == 0 {
+			return nil, errors.New("empty key image")
+		}
+		pk, err := d.entry()
+		if err != nil {
+			return nil, err
+		}
+		l.images[image] = pk
+	}
+
+	if len(d.in) != 0 {
+		return nil, errors.New("input too long")
+	}
+
+	return l, nil
+}
+
+func appendEntry(out []byte, entry string) ([]byte, error) {
+	if len(entry) > maxEntryLen {
+		return nil, errors.New("point encoding too long")
+	}
+	out = append(out, byte(len(entry)))
+	return append(out, entry...), nil
+}
+
+type decoder struct {
+	in []byte
+}
+
+func (d *decoder) uint32() (uint32, error) {
+	if len(d.in) < 4 {
+		return 0, errors.New("input too short")
+	}
+	n := binary.BigEndian.Uint32(d.in)
+	d.in = d.in[4:]
+	return n, nil
+}
+
+func (d *decoder) entry() (string, error) {
+	if len(d.in) < 1 || len(d.in) < 1+int(d.in[0]) {
+		return "", errors.New("input too short")
+	}
+	entry := string(d.in[1 : 1+int(d.in[0])])
+	d.in = d.in[1+int(d.in[0]):]
+	return entry, nil
+}
+
+// encode returns the encoding of p without modifying p, as the points may be
+// shared with other goroutines.
+func encode(p types.Point) []byte {
+	return p.Copy().Encode()
+}
@@ -0,0 +1,98 @@
+package revocation
+
+import (
+	"testing"
+
+	"github.com/athanorlabs/go-dleq/ed25519"
+	"github.com/athanorlabs/go-dleq/secp256k1"
+	"github.com/athanorlabs/go-dleq/types"
+	"github.com/stretchr/testify/require"
+)
+
+func randomPoint(curve types.Curve) types.Point {
+	return curve.ScalarBaseMul(curve.NewRandomScalar())
+}
+
+func TestList(t *testing.T) {
+	for _, curve := range []types.Curve{secp256k1.NewCurve(), ed25519.NewCurve()} {
+		l := NewList()
+		pk, image, imageOwner, other := randomPoint(curve), randomPoint(curve), randomPoint(curve), randomPoint(curve)
+
+		l.AddPublicKey(pk)
+		l.AddKeyImage(image, imageOwner)
+		l.AddKeyImage(other, nil)
+
+		require.True(t, l.ContainsPublicKey(pk))
+		require.False(t, l.ContainsPublicKey(image))
+		require.True(t, l.ContainsKeyImage(image))
+		require.True(t, l.ContainsKeyImage(other))
+		require.False(t, l.ContainsKeyImage(pk))
+
+		owner, ok := l.KeyImageOwner(image)
+		require.True(t, ok)
+		require.Equal(t, imageOwner.Encode(), owner)
+
+		owner, ok = l.KeyImageOwner(other)
+		require.True(t, ok)
+		require.Nil(t, owner)
+
+		policy := l.Policy()
+		require.ErrorIs(t, policy(0, pk), ErrRevoked)
+		require.NoError(t, policy(0, other))
+	}
+}
+
+func TestList_Serialize(t *testing.T) {
+	curve := secp256k1.NewCurve()
+	l := NewList()
+	for i := 0; i < 3; i++ {
+		l.AddPublicKey(randomPoint(curve))
+	}
+	l.AddKeyImage(randomPoint(curve), randomPoint(curve))
+	l.AddKeyImage(randomPoint(curve), nil)
+
+	enc, err := l.Serialize()
+	require.NoError(t, err)
+
+	res, err := Deserialize(enc)
+	require.NoError(t, err)
+	require.Equal(t, l.pubkeys, res.pubkeys)
+	require.Equal(t, l.images, res.images)
+
+	pubkeys, images := res.Len()
+	require.Equal(t, 3, pubkeys)
+	require.Equal(t, 2, images)
+
+	// the encoding doesn't depend on insertion order
+	again, err := res.Serialize()
+	require.NoError(t, err)
+	require.Equal(t, enc, again)
+
+	empty, err := NewList().Serialize()
+	require.NoError(t, err)
+	res, err = Deserialize(empty)
+	require.NoError(t, err)
+	pubkeys, images = res.Len()
+	require.Zero(t, pubkeys+images)
+}
+
+func TestDeserialize_Invalid(t *testing.T) {
+	l := NewList()
+	l.AddPublicKey(randomPoint(ed25519.NewCurve()))
+	enc, err := l.Serialize()
+	require.NoError(t, err)
+
+	_, err = Deserialize(nil)
+	require.Error(t, err)
+
+	_, err = Deserialize(append([]byte{2}, enc[1:]...))
+	require.EqualError(t, err, "unsupported format version 2")
+
+	for i := 1; i < len(enc); i++ {
+		_, err = Deserialize(enc[:i])
+		require.Error(t, err)
+	}
+
+	_, err = Deserialize(append(enc, 0))
+	require.EqualError(t, err, "input too long")
+}
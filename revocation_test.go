@@ -0,0 +1,62 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/ring-go/revocation"
+)
+
+func TestContainsRevoked(t *testing.T) {
+	sig := createSig(t, 4, 1)
+	list := revocation.NewList()
+	require.False(t, sig.Ring().ContainsRevoked(list))
+
+	list.AddPublicKey(sig.ring.pubkeys[3])
+	require.True(t, sig.Ring().ContainsRevoked(list))
+
+	err := sig.VerifyWithPolicy(testMsg, list.Policy())
+	require.ErrorIs(t, err, revocation.ErrRevoked)
+}
+
+func TestRevokedSigner(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 4, privKey, 2)
+		require.NoError(t, err)
+		sig, err := keyring.Sign(testMsg, privKey)
+		require.NoError(t, err)
+
+		list := revocation.NewList()
+		idx, revoked := sig.RevokedSigner(list)
+		require.False(t, revoked)
+		require.Equal(t, -1, idx)
+
+		// an image without a known owner only tells that the signer is revoked
+		list.AddKeyImage(sig.KeyImage(), nil)
+		idx, revoked = sig.RevokedSigner(list)
+		require.True(t, revoked)
+		require.Equal(t, -1, idx)
+
+		// an image with a known owner identifies the signer, even in a
+		// signature over a ring created earlier
+		list.AddKeyImage(sig.KeyImage(), curve.ScalarBaseMul(privKey))
+		idx, revoked = sig.RevokedSigner(list)
+		require.True(t, revoked)
+		require.Equal(t, 2, idx)
+
+		// the revoked member's other signatures are revoked as well
+		other, err := NewKeyRing(curve, 3, privKey, 0)
+		require.NoError(t, err)
+		otherSig, err := other.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		idx, revoked = otherSig.RevokedSigner(list)
+		require.True(t, revoked)
+		require.Equal(t, 0, idx)
+
+		unrelated := createSigWithCurve(t, curve, 3, 1)
+		_, revoked = unrelated.RevokedSigner(list)
+		require.False(t, revoked)
+	}
+}
@@ -1,17 +1,45 @@
 package ring
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/athanorlabs/go-dleq/ed25519"
 	"github.com/athanorlabs/go-dleq/types"
+	"golang.org/x/crypto/hkdf"
 )
 
 // Ring represents a group of public keys such that one of the group created a signature.
+//
+// A Ring is immutable once constructed and is safe for concurrent use, in
+// particular for concurrent calls to Sign, and to Verify on signatures that
+// share the ring.
 type Ring struct {
 	pubkeys []types.Point
 	curve   types.Curve
+
+	// hp caches H_p(P_i) for each public key; see ensureHP. hpReady is set
+	// once it's cached, and hpPending while PrecomputeAsync computes it.
+	hp        []types.Point
+	hpOnce    sync.Once
+	hpReady   atomic.Bool
+	hpPending atomic.Pointer[hpTask]
+
+	// index maps the encodings of the public keys to their indices; see
+	// IndexOf.
+	index     map[string]int
+	indexOnce sync.Once
+
+	// weights are the members' weights, or nil; see WithWeights.
+	weights []uint64
 }
 
 // Size returns the size of the ring, ie. the number of public keys in it.
@@ -19,38 +47,72 @@ func (r *Ring) Size() int {
 	return len(r.pubkeys)
 }
 
+// Curve returns the curve of the ring's public keys.
+func (r *Ring) Curve() types.Curve {
+	return r.curve
+}
+
+// PublicKeys returns a copy of the ring's public keys.
+func (r *Ring) PublicKeys() []types.Point {
+	ret := make([]types.Point, len(r.pubkeys))
+	for i, pk := range r.pubkeys {
+		ret[i] = pk.Copy()
+	}
+	return ret
+}
+
 // Equals checks whether the supplied ring is equal to the current ring.
-// The ring's public keys must be in the same order for the rings to be equal
+// The ring's public keys must be in the same order for the rings to be equal.
+// Rings over different curves are never equal.
 func (r *Ring) Equals(other *Ring) bool {
 	if r.Size() != other.Size() {
 		return false
 	}
 
+	// the curve must be checked first, as points of different curves
+	// cannot be compared with each other
+	if !sameCurve(r.curve, other.curve) {
+		return false
+	}
+
 	for i, p := range r.pubkeys {
-		if !p.Equals(other.pubkeys[i]) {
+		if !equalPoints(p, other.pubkeys[i]) {
 			return false
 		}
 	}
-	bp, abp := r.curve.BasePoint(), r.curve.AltBasePoint()
-	obp, oabp := other.curve.BasePoint(), other.curve.AltBasePoint()
-	return bp.Equals(obp) && abp.Equals(oabp)
+	return true
+}
+
+// sameCurve returns true if the two curves have the same base and alternate
+// base points. The points are compared by their encodings, so curves
+// implemented by different types can be compared without panicking.
+func sameCurve(a, b types.Curve) bool {
+	return bytes.Equal(encodePoint(a.BasePoint()), encodePoint(b.BasePoint())) &&
+		bytes.Equal(encodePoint(a.AltBasePoint()), encodePoint(b.AltBasePoint()))
 }
 
 // RingSig represents a ring signature.
+//
+// A RingSig is immutable once created by Sign or Deserialize, and is safe for
+// concurrent use by multiple goroutines (eg. concurrent calls to Verify).
+// Deserialize itself must not be called concurrently on the same RingSig.
 type RingSig struct {
 	ring  *Ring          // array of public keys
 	c     types.Scalar   // ring signature challenge
 	s     []types.Scalar // ring signature values
 	image types.Point    // key image
+	ext   extensions     // optional fields bound into the transcript
 }
 
 // PublicKeys returns a copy of the ring signature's public keys.
 func (r *RingSig) PublicKeys() []types.Point {
-	ret := make([]types.Point, len(r.ring.pubkeys))
-	for i, pk := range r.ring.pubkeys {
-		ret[i] = pk.Copy()
-	}
-	return ret
+	return r.ring.PublicKeys()
+}
+
+// KeyImage returns a copy of the ring signature's key image. Key images of
+// valid signatures are already normalized (see NormalizeKeyImage).
+func (r *RingSig) KeyImage() types.Point {
+	return r.image.Copy()
 }
 
 // Ring returns the ring from the RingSig struct
@@ -61,7 +123,11 @@ func (r *RingSig) Ring() *Ring {
 // NewKeyRingFromPublicKeys takes public key ring and places the public key corresponding to `privKey`
 // in index idx of the ring.
 // It returns a ring of public keys of length `len(ring)+1`.
-func NewKeyRingFromPublicKeys(curve types.Curve, pubkeys []types.Point, privKey types.Scalar, idx int) (*Ring, error) {
+// If idx follows a fixed convention, shuffle the ring with ShuffledCopy before
+// signing, so that the signer's position doesn't reveal it.
+func NewKeyRingFromPublicKeys(curve types.Curve, pubkeys []types.Point, privKey types.Scalar, idx int) (_ *Ring, err error) {
+	defer recoverInternal(&err)
+
 	size := len(pubkeys) + 1
 	newRing := make([]types.Point, size)
 	pubkey := curve.ScalarBaseMul(privKey)
@@ -75,7 +141,7 @@ func NewKeyRingFromPublicKeys(curve types.Curve, pubkeys []types.Point, privKey
 	}
 
 	// ensure that privkey is nonzero
-	if privkey.IsZero() {
+	if privKey.IsZero() {
 		return nil, errors.New("private key is zero")
 	}
 
@@ -107,7 +173,9 @@ func NewKeyRingFromPublicKeys(curve types.Curve, pubkeys []types.Point, privKey
 }
 
 // NewFixedKeyRingFromPublicKeys takes public keys and a curve to create a ring
-func NewFixedKeyRingFromPublicKeys(curve types.Curve, pubkeys []types.Point) (*Ring, error) {
+func NewFixedKeyRingFromPublicKeys(curve types.Curve, pubkeys []types.Point) (_ *Ring, err error) {
+	defer recoverInternal(&err)
+
 	pubkeysMap := make(map[types.Point]struct{})
 
 	size := len(pubkeys)
@@ -130,13 +198,25 @@ func NewFixedKeyRingFromPublicKeys(curve types.Curve, pubkeys []types.Point) (*R
 // NewKeyRing creates a ring with size specified by `size` and places the public key corresponding
 // to `privKey` in index idx of the ring.
 // It returns a ring of public keys of length `size`.
-func NewKeyRing(curve types.Curve, size int, privKey types.Scalar, idx int) (*Ring, error) {
+// The decoys of large rings are generated in parallel, across GOMAXPROCS
+// goroutines.
+func NewKeyRing(curve types.Curve, size int, privKey types.Scalar, idx int) (_ *Ring, err error) {
+	defer recoverInternal(&err)
+
+	if size > MaxRingSize {
+		return nil, fmt.Errorf("ring size %d exceeds MaxRingSize", size)
+	}
+
 	if idx >= size {
 		return nil, errors.New("index out of bounds")
 	}
 
+	if idx < 0 {
+		return nil, errors.New("index out of bounds: idx < 0")
+	}
+
 	// ensure that privkey is nonzero
-	if privkey.IsZero() {
+	if privKey.IsZero() {
 		return nil, errors.New("private key is zero")
 	}
 
@@ -144,12 +224,11 @@ func NewKeyRing(curve types.Curve, size int, privKey types.Scalar, idx int) (*Ri
 	pubkey := curve.ScalarBaseMul(privKey)
 	ring[idx] = pubkey
 
-	for i := 0; i < size; i++ {
-		if i == idx {
-			continue
-		}
-		priv := curve.NewRandomScalar()
-		ring[i] = curve.ScalarBaseMul(priv)
+	err = generateDecoys(curve, ring, idx, func(int) (types.Scalar, error) {
+		return curve.NewRandomScalar(), nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &Ring{
@@ -158,103 +237,344 @@ func NewKeyRing(curve types.Curve, size int, privKey types.Scalar, idx int) (*Ri
 	}, nil
 }
 
-// Sign creates a ring signature on the given message using the public key ring
-// and a private key of one of the members of the ring.
-func (r *Ring) Sign(m [32]byte, privKey types.Scalar) (*RingSig, error) {
-	ourIdx := -1
-	pubkey := r.curve.ScalarBaseMul(privKey)
-	for i, pk := range r.pubkeys {
-		if pk.Equals(pubkey) {
-			ourIdx = i
-			break
-		}
+// NewKeyRingDeterministic creates a ring like NewKeyRing, but derives the decoy
+// public keys from the given seed using HKDF-SHA256, so that the same seed
+// always results in the same ring. The decoy at index i does not depend on the
+// ring size or on the signer's key or index.
+//
+// This is intended for test fixtures and test vectors; the decoys' private keys
+// can be recomputed by anyone knowing the seed.
+func NewKeyRingDeterministic(curve types.Curve, size int, privKey types.Scalar, idx int, seed []byte) (_ *Ring, err error) {
+	defer recoverInternal(&err)
+
+	if size > MaxRingSize {
+		return nil, fmt.Errorf("ring size %d exceeds MaxRingSize", size)
+	}
+
+	if idx >= size {
+		return nil, errors.New("index out of bounds")
 	}
 
-	if ourIdx == -1 {
-		return nil, errors.New("failed to find given key in public key set")
+	if idx < 0 {
+		return nil, errors.New("index out of bounds: idx < 0")
+	}
+
+	// ensure that privkey is nonzero
+	if privKey.IsZero() {
+		return nil, errors.New("private key is zero")
 	}
 
-	return Sign(m, r, privKey, ourIdx)
+	ring := make([]types.Point, size)
+	ring[idx] = curve.ScalarBaseMul(privKey)
+
+	err = generateDecoys(curve, ring, idx, func(i int) (types.Scalar, error) {
+		return deterministicDecoy(curve, seed, i)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ring{
+		pubkeys: ring,
+		curve:   curve,
+	}, nil
 }
 
-// Sign creates a ring signature on the given message using the provided private key
-// and ring of public keys.
-func Sign(m [32]byte, ring *Ring, privKey types.Scalar, ourIdx int) (*RingSig, error) {
-	size := len(ring.pubkeys)
-	if size < 2 {
-		return nil, errors.New("size of ring less than two")
+// deterministicDecoy derives the private key of the decoy at index i from seed.
+func deterministicDecoy(curve types.Curve, seed []byte, i int) (types.Scalar, error) {
+	info := make([]byte, len(decoyInfo)+4)
+	copy(info, decoyInfo)
+	binary.BigEndian.PutUint32(info[len(decoyInfo):], uint32(i))
+
+	var okm [64]byte
+	if _, err := io.ReadFull(hkdf.New(sha256.New, seed, nil, info), okm[:]); err != nil {
+		return nil, err
 	}
 
-	if ourIdx >= size {
-		return nil, errors.New("secret index out of range of ring size")
+	priv, err := curve.HashToScalar(okm[:])
+	if err != nil {
+		return nil, err
+	}
+
+	if priv.IsZero() {
+		return nil, fmt.Errorf("derived zero decoy private key at index %d", i)
+	}
+
+	return priv, nil
+}
+
+const decoyInfo = "ring-go/decoy"
+
+// decoyMinPerWorker is the minimum number of decoys generated by a single
+// goroutine, below which parallelizing isn't worth it.
+const decoyMinPerWorker = 16
+
+// generateDecoys sets ring[i] to the public key of newPriv(i) for each index i
+// but idx, across up to GOMAXPROCS goroutines. Each decoy is written to its own
+// index, so the order doesn't depend on the scheduling. It returns the error
+// of the lowest worker that failed, if any.
+func generateDecoys(curve types.Curve, ring []types.Point, idx int, newPriv func(i int) (types.Scalar, error)) error {
+	workers := min(runtime.GOMAXPROCS(0), len(ring)/decoyMinPerWorker)
+	if workers <= 1 {
+		return generateDecoysFrom(curve, ring, idx, 0, 1, newPriv)
+	}
+
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			errs[w] = generateDecoysFrom(curve, ring, idx, w, workers, newPriv)
+		}(w)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateDecoysFrom generates the decoys at indices start, start+step, ...
+// It recovers panics itself, as they can't be recovered by the caller when it
+// runs in another goroutine.
+func generateDecoysFrom(curve types.Curve, ring []types.Point, idx, start, step int, newPriv func(i int) (types.Scalar, error)) (err error) {
+	defer recoverInternal(&err)
+
+	for i := start; i < len(ring); i += step {
+		if i == idx {
+			continue
+		}
+		priv, err := newPriv(i)
+		if err != nil {
+			return err
+		}
+		ring[i] = curve.ScalarBaseMul(priv)
+	}
+	return nil
+}
+
+// ErrSingletonRing is returned by Sign for rings of a single member, unless
+// AllowSingletonRing is used.
+var ErrSingletonRing = errors.New("size of ring less than two")
+
+// Sign creates a ring signature on the given message using the public key ring
+// and a private key of one of the members of the ring.
+//
+// The signer's position in the ring is looked up from its public key, unless
+// it's given with WithSignerIndex. It returns ErrSignerNotInRing if the key
+// is not a member of the ring.
+func (r *Ring) Sign(m [32]byte, privKey types.Scalar, opts ...SignOption) (_ *RingSig, err error) {
+	defer recoverInternal(&err)
+
+	options := newSignOptions(opts)
+	if options.err != nil {
+		return nil, options.err
+	}
+
+	size := len(r.pubkeys)
+	switch {
+	case size == 0:
+		return nil, errors.New("ring is empty")
+	case size == 1 && !options.allowSingleton:
+		return nil, ErrSingletonRing
 	}
 
 	// ensure that privkey is nonzero
-	if privkey.IsZero() {
+	if privKey.IsZero() {
 		return nil, errors.New("private key is zero")
 	}
 
-	// check that key at index s is indeed the signer
-	pubkey := ring.curve.ScalarBaseMul(privKey)
-	if !ring.pubkeys[ourIdx].Equals(pubkey) {
+	pubkey := r.curve.ScalarBaseMul(privKey)
+	ourIdx := options.signerIdx
+	switch {
+	case !options.signerIdxSet:
+		var ok bool
+		if ourIdx, ok = r.IndexOf(pubkey); !ok {
+			return nil, ErrSignerNotInRing
+		}
+	case ourIdx < 0 || ourIdx >= size:
+		return nil, errors.New("secret index out of range of ring size")
+	case !equalPoints(r.pubkeys[ourIdx], pubkey):
+		// check that key at index s is indeed the signer
 		return nil, errors.New("secret index in ring is not signer")
 	}
 
+	if options.minWeight != nil {
+		if err := r.RequireWeight(*options.minWeight); err != nil {
+			return nil, err
+		}
+	}
+	if options.policy != nil {
+		if err := options.policy.check(r, encodePoint(pubkey), options.scope); err != nil {
+			return nil, err
+		}
+	}
+
+	return sign(r, m, privKey, pubkey, ourIdx, options)
+}
+
+// Sign creates a ring signature on the given message using the provided private key
+// and ring of public keys. ourIdx is the index of the signer's public key in
+// the ring.
+//
+// Deprecated: use Ring.Sign, with WithSignerIndex if the index is known.
+func Sign(m [32]byte, ring *Ring, privKey types.Scalar, ourIdx int, opts ...SignOption) (*RingSig, error) {
+	return ring.Sign(m, privKey, append(opts[:len(opts):len(opts)], WithSignerIndex(ourIdx))...)
+}
+
+// sign creates a ring signature with the private key of the ring member with
+// the given index and public key.
+func sign(ring *Ring, m [32]byte, privKey types.Scalar, pubkey types.Point, ourIdx int, options *signOptions) (*RingSig, error) {
+	size := len(ring.pubkeys)
+
 	// setup
 	curve := ring.curve
 	h := hashToCurve(pubkey)
 	sig := &RingSig{
 		ring: ring,
+		ext:  options.ext,
+	}
+	if options.keyImage != nil {
+		// the image was computed beforehand; check its proof instead
+		if !options.keyImageProof.Verify(curve, pubkey, options.keyImage) {
+			return nil, ErrInvalidKeyImage
+		}
+		sig.image = options.keyImage.Copy()
+	} else {
 		// calculate key image I = x * H_p(P) where H_p is a hash-to-curve function
-		image: curve.ScalarMul(privKey, h),
+		sig.image = curve.ScalarMul(privKey, h)
+	}
+	if options.embedDigest {
+		digest := m
+		sig.ext.digest = &digest
 	}
 
-	// start at c[j]
-	c := make([]types.Scalar, size)
+	// the extension fields are bound by signing a message derived from them
+	msg := m
+	m = sig.ext.message(m)
+
+	// H_p maps into the prime-order subgroup, so the image must be there too;
+	// Verify rejects images that are not
+	if !isTorsionFree(sig.image) {
+		// this should not happen
+		return nil, errors.New("key image is not in the prime-order subgroup")
+	}
+
+	ring.ensureHP()
 	s := make([]types.Scalar, size)
 
-	// pick random scalar u, calculate L[j] = u*G
-	u := curve.NewRandomScalar()
+	// the random scalars must all be distinct and nonzero, otherwise the
+	// signature could leak the private key; fail closed if the RNG is broken
+	guard := make(scalarGuard, size)
+
+	// pick random scalar u, or derive it from the private key, the ring and
+	// the transcript message; calculate L[j] = u*G
+	var (
+		u        types.Scalar
+		ringHash [32]byte
+	)
+	if options.deterministic {
+		ringHash = ring.Hash()
+		var err error
+		if u, err = deterministicNonce(curve, privKey, ringHash, m, 0); err != nil {
+			return nil, err
+		}
+	} else {
+		u = curve.NewRandomScalar()
+	}
+	if err := guard.check(u); err != nil {
+		return nil, err
+	}
 	l := curve.ScalarBaseMul(u)
 
+	if options.monitor != nil {
+		if err := options.monitor.observe(l); err != nil {
+			return nil, err
+		}
+	}
+
 	// compute R[j] = u*H_p(P[j])
 	r := curve.ScalarMul(u, h)
 
 	// calculate challenge c[j+1] = H(m, L_j, R_j)
-	idx := (ourIdx + 1) % size
-	c[idx] = challenge(ring.curve, m, l, r)
+	ch, err := sig.ext.challenger(curve, m)
+	if err != nil {
+		return nil, err
+	}
+	cNext := ch.challenge(l, r)
+
+	// c holds the challenge of the current ring member, c0 the challenge c[0]
+	// that is included in the signature
+	c, c0 := cNext, types.Scalar(nil)
+	done := 0
+	step := func(idx int, hp types.Point) error {
+		if idx == 0 {
+			c0 = c
+		}
 
-	// start loop at j+1
-	for i := 1; i < size; i++ {
-		idx := (ourIdx + i) % size
 		if ring.pubkeys[idx] == nil {
-			return nil, fmt.Errorf("no public key at index %d", idx)
+			return fmt.Errorf("no public key at index %d", idx)
 		}
 
-		// pick random scalar s_i
-		s[idx] = curve.NewRandomScalar()
+		// pick random scalar s_i, or derive it from the audit seed or the
+		// private key
+		var err error
+		switch {
+		case options.auditSeed != nil:
+			s[idx], err = options.auditSeed.decoy(curve, m, idx)
+		case options.deterministic:
+			s[idx], err = deterministicNonce(curve, privKey, ringHash, m, idx+1)
+		default:
+			s[idx] = curve.NewRandomScalar()
+		}
+		if err != nil {
+			return err
+		}
+		if err := guard.check(s[idx]); err != nil {
+			return err
+		}
 
 		// calculate L_i = s_i*G + c_i*P_i
-		cP := curve.ScalarMul(c[idx], ring.pubkeys[idx])
+		cP := curve.ScalarMul(c, ring.pubkeys[idx])
 		sG := curve.ScalarBaseMul(s[idx])
 		l := cP.Add(sG)
 
 		// calculate R_i = s_i*H_p(P_i) + c_i*I
-		cI := curve.ScalarMul(c[idx], sig.image)
-		hp := hashToCurve(ring.pubkeys[idx])
+		cI := curve.ScalarMul(c, sig.image)
 		sH := curve.ScalarMul(s[idx], hp)
 		r := cI.Add(sH)
 
 		// calculate c[i+1] = H(m, L_i, R_i)
-		c[(idx+1)%size] = challenge(curve, m, l, r)
+		c = ch.challenge(l, r)
+
+		if options.progress != nil {
+			done++
+			return options.progress(done)
+		}
+		return nil
+	}
+
+	// loop from j+1 around the ring back to j
+	if err := ring.forEachHP(ourIdx+1, size, step); err != nil {
+		return nil, err
+	}
+	if err := ring.forEachHP(0, ourIdx, step); err != nil {
+		return nil, err
+	}
+	if ourIdx == 0 {
+		c0 = c
 	}
 
 	// close ring by finding s[j] = u - c[j]*x
-	cx := c[ourIdx].Mul(privKey)
+	cx := c.Mul(privKey)
 	s[ourIdx] = u.Sub(cx)
 
 	// check that u*G = s[j]*G + c[j]*P[j]
-	cP := curve.ScalarMul(c[ourIdx], pubkey)
+	cP := curve.ScalarMul(c, pubkey)
 	sG := curve.ScalarBaseMul(s[ourIdx])
 	lNew := cP.Add(sG)
 	if !lNew.Equals(l) {
@@ -263,7 +583,7 @@ func Sign(m [32]byte, ring *Ring, privKey types.Scalar, ourIdx int) (*RingSig, e
 	}
 
 	// check that u*H_p(P[j]) = s[j]*H_p(P[j]) + c[j]*I
-	cI := curve.ScalarMul(c[ourIdx], sig.image)
+	cI := curve.ScalarMul(c, sig.image)
 	sH := curve.ScalarMul(s[ourIdx], h)
 	rNew := cI.Add(sH)
 	if !rNew.Equals(r) {
@@ -272,69 +592,195 @@ func Sign(m [32]byte, ring *Ring, privKey types.Scalar, ourIdx int) (*RingSig, e
 	}
 
 	// check that H(m, L[j], R[j]) == c[j+1]
-	cCheck := challenge(ring.curve, m, l, r)
-	if !cCheck.Eq(c[(ourIdx+1)%size]) {
+	cCheck := ch.challenge(l, r)
+	if !cCheck.Eq(cNext) {
 		return nil, errors.New("challenge check failed")
 	}
 
 	// everything ok, add values to signature
 	sig.s = s
-	sig.c = c[0]
+	sig.c = c0
+
+	// a fault during signing could produce an invalid signature leaking
+	// information about the private key, so don't return it
+	if options.selfCheck(size) && sig.verifyTranscript(msg, nil, nil, RequireTorsionFree) != nil {
+		return nil, errors.New("signature failed self-check")
+	}
+
 	return sig, nil
 }
 
+// ErrInvalidSignature is returned by VerifyWithPolicy when a signature is not
+// valid for the message.
+var ErrInvalidSignature = errors.New("invalid ring signature")
+
 // Verify verifies the ring signature for the given message.
 // It returns true if a valid signature, false otherwise.
+//
+// If the signature has a validity window (see WithValidity), it is checked
+// against the current time; use VerifyAt to check it against another time.
 func (sig *RingSig) Verify(m [32]byte) bool {
+	return sig.verify(m, time.Now(), nil) == nil
+}
+
+// VerifyWithPolicy verifies the ring signature for the given message like
+// Verify, and additionally calls policy with the index and a copy of each
+// public key of the ring, in order, during verification. This allows rejecting
+// rings containing revoked or unknown keys without iterating over the ring
+// twice.
+//
+// It returns nil if the signature is valid and the policy accepted every ring
+// member, the policy's error (wrapped) if it rejected a member, ErrNotValidAt
+// if the current time is outside the signature's validity window, and
+// ErrInvalidSignature otherwise. Verification stops at the first member the
+// policy rejects.
+func (sig *RingSig) VerifyWithPolicy(m [32]byte, policy func(i int, pub types.Point) error) error {
+	return sig.verify(m, time.Now(), policy)
+}
+
+func (sig *RingSig) verify(m [32]byte, at time.Time, policy func(i int, pub types.Point) error) error {
+	if sig.ext.validity != nil && !sig.ext.validity.contains(at) {
+		return ErrNotValidAt
+	}
+
+	return sig.verifyTranscript(m, policy, nil, RequireTorsionFree)
+}
+
+// verifyTranscript verifies the signature for the given message, without
+// checking its validity window. H_p(P_i) is taken from hps if it's not nil,
+// and from the ring otherwise. The key image is checked according to cofactor.
+func (sig *RingSig) verifyTranscript(m [32]byte, policy func(i int, pub types.Point) error, hps HPProvider, cofactor CofactorPolicy) (err error) {
+	defer recoverInternal(&err)
+
 	// setup
 	ring := sig.ring
 	size := len(ring.pubkeys)
-	c := make([]types.Scalar, size)
-	c[0] = sig.c
+	if size == 0 || len(sig.s) != size {
+		return ErrInvalidSignature
+	}
+
+	m = sig.ext.message(m)
+
+	// by default, reject key images with a small-order component, as they
+	// could be used to create signatures that don't link with the signer's
+	// other signatures
+	if !cofactor.accepts(sig.image) {
+		return ErrInvalidSignature
+	}
+
 	curve := ring.curve
+	ch, err := sig.ext.challenger(curve, m)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
 
 	// calculate c[i+1] = H(m, s[i]*G + c[i]*P[i])
-	// and c[0] = H)(m, s[n-1]*G + c[n-1]*P[n-1]) where n is the ring size
-	for i := 0; i < size; i++ {
-		// calculate L_i = s_i*G + c_i*P_i
-		cP := curve.ScalarMul(c[i], ring.pubkeys[i])
-		sG := curve.ScalarBaseMul(sig.s[i])
-		l := cP.Add(sG)
+	// and c[0] = H)(m, s[n-1]*G + c[n-1]*P[n-1]) where n is the ring size.
+	// only the current challenge is kept, so memory usage doesn't depend on
+	// the ring size beyond the signature itself.
+	c := sig.c
+	ops := opsOf(curve)
+	err = ring.forEachHPFrom(hps, func(i int, hp types.Point) error {
+		if policy != nil {
+			if err := policy(i, ring.pubkeys[i].Copy()); err != nil {
+				return fmt.Errorf("ring member %d rejected by policy: %w", i, err)
+			}
+		}
 
-		// calculate R_i = s_i*H_p(P_i) + c_i*I
-		cI := curve.ScalarMul(c[i], sig.image)
-		h := hashToCurve(ring.pubkeys[i])
-		sH := curve.ScalarMul(sig.s[i], h)
-		r := cI.Add(sH)
+		// calculate L_i = s_i*G + c_i*P_i; all the inputs are public, so
+		// this can use a variable-time double-scalar multiplication
+		l := ops.doubleScalarBaseMul(c, ring.pubkeys[i], sig.s[i])
+
+		// calculate R_i = s_i*H_p(P_i) + c_i*I, likewise in variable time
+		r := ops.twoScalarMul(c, sig.image, sig.s[i], hp)
 
 		// calculate c[i+1] = H(m, L_i, R_i)
-		if i == size-1 {
-			c[0] = challenge(curve, m, l, r)
-		} else {
-			c[i+1] = challenge(curve, m, l, r)
-		}
+		c = ch.challenge(l, r)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return sig.c.Eq(c[0])
+	if !sig.c.Eq(c) {
+		return ErrInvalidSignature
+	}
+	return nil
 }
 
 // Link returns true if the two signatures were created by the same signer,
-// false otherwise.
+// false otherwise. It clears any torsion component of the key images before
+// comparing them, like ClearOnLink.Link, so it links signatures accepted under
+// any CofactorPolicy.
 func Link(sigA, sigB *RingSig) bool {
-	switch sigA.Ring().curve.(type) {
-	case *ed25519.CurveImpl:
-		cofactor := Ed25519().ScalarFromInt(8)
-		imageA := sigA.image.ScalarMul(cofactor)
-		imageB := sigB.image.ScalarMul(cofactor)
-		return imageA.Equals(imageB)
+	return ClearOnLink.Link(sigA, sigB)
+}
+
+var (
+	ed25519Cofactor    = ed25519.NewCurve().ScalarFromInt(8)
+	ed25519CofactorInv = ed25519Cofactor.Inverse()
+)
+
+// NormalizeKeyImage returns the canonical form of a key image, suitable for
+// use as a key in key image registries.
+//
+// For ed25519, this is the projection of the image onto the prime-order
+// subgroup, ie. any small-order (torsion) component is removed. Images created
+// by Sign are already in the prime-order subgroup, so for them this is the
+// identity function. For curves with a cofactor of 1 (secp256k1), the image is
+// returned as-is.
+func NormalizeKeyImage(image types.Point) types.Point {
+	switch image.(type) {
+	case *ed25519.PointImpl, *edPoint:
+		// 8^-1 * (8 * I) clears the torsion component of I while leaving the
+		// prime-order component unchanged
+		return image.ScalarMul(ed25519Cofactor).ScalarMul(ed25519CofactorInv)
 	default:
-		return sigA.image.Equals(sigB.image)
+		return image.Copy()
 	}
 }
 
-func challenge(curve types.Curve, m [32]byte, l, r types.Point) types.Scalar {
-	t := append(m[:], append(l.Encode(), r.Encode()...)...)
-	c, err := curve.HashToScalar(t)
+// isTorsionFree returns true if the key image has no small-order component.
+func isTorsionFree(image types.Point) bool {
+	return equalPoints(NormalizeKeyImage(image), image)
+}
+
+// challenger computes the challenges H(m, L, R) for a message, reusing its
+// transcript buffer across calls so that signing and verification don't
+// allocate a new one for each ring member. It must not be used concurrently.
+type challenger struct {
+	curve types.Curve
+	ops   curveOps
+	m     [32]byte
+	buf   []byte
+
+	// tagged is true if the challenges are BIP-340 tagged hashes; see
+	// WithTaggedHashes.
+	tagged bool
+}
+
+func newChallenger(curve types.Curve, m [32]byte) *challenger {
+	return &challenger{
+		curve: curve,
+		ops:   opsOf(curve),
+		m:     m,
+		buf:   make([]byte, 0, 32+2*(curve.CompressedPointSize()+1)),
+	}
+}
+
+func (ch *challenger) challenge(l, r types.Point) types.Scalar {
+	ch.buf = append(ch.buf[:0], ch.m[:]...)
+	ch.buf = ch.ops.appendPoint(ch.buf, l)
+	ch.buf = ch.ops.appendPoint(ch.buf, r)
+	return ch.hash()
+}
+
+// hash returns the challenge for the transcript in ch.buf.
+func (ch *challenger) hash() types.Scalar {
+	if ch.tagged {
+		return taggedChallenge(ch.curve, ch.buf)
+	}
+	c, err := ch.curve.HashToScalar(ch.buf)
 	if err != nil {
 		// this should not happen
 		panic(err)
@@ -3,6 +3,7 @@ package ring
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"math/big"
 	"testing"
 
@@ -79,6 +80,79 @@ func TestNewKeyRing_IdxOutOfBounds(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestNewKeyRingDeterministic(t *testing.T) {
+	for _, curve := range []types.Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		seed := []byte("fixture seed")
+
+		keyring, err := NewKeyRingDeterministic(curve, 8, privKey, 3, seed)
+		require.NoError(t, err)
+		require.Equal(t, 8, keyring.Size())
+		require.True(t, keyring.pubkeys[3].Equals(curve.ScalarBaseMul(privKey)))
+
+		same, err := NewKeyRingDeterministic(curve, 8, privKey, 3, seed)
+		require.NoError(t, err)
+		require.True(t, keyring.Equals(same))
+
+		// decoys don't depend on the ring size
+		larger, err := NewKeyRingDeterministic(curve, 10, privKey, 3, seed)
+		require.NoError(t, err)
+		for i := 0; i < 8; i++ {
+			require.True(t, keyring.pubkeys[i].Equals(larger.pubkeys[i]))
+		}
+
+		other, err := NewKeyRingDeterministic(curve, 8, privKey, 3, []byte("other seed"))
+		require.NoError(t, err)
+		require.False(t, keyring.Equals(other))
+
+		sig, err := keyring.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		require.True(t, sig.Verify(testMsg))
+	}
+}
+
+// TestNewKeyRing_Parallel checks that rings large enough to generate their
+// decoys in parallel have them in order.
+func TestNewKeyRing_Parallel(t *testing.T) {
+	const size = 40 * decoyMinPerWorker
+	for _, curve := range []types.Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		seed := []byte("fixture seed")
+		keyring, err := NewKeyRingDeterministic(curve, size, privKey, 100, seed)
+		require.NoError(t, err)
+		for i, pk := range keyring.pubkeys {
+			if i == 100 {
+				require.True(t, pk.Equals(curve.ScalarBaseMul(privKey)))
+				continue
+			}
+			priv, err := deterministicDecoy(curve, seed, i)
+			require.NoError(t, err)
+			require.True(t, pk.Equals(curve.ScalarBaseMul(priv)), i)
+		}
+
+		keyring, err = NewKeyRing(curve, size, privKey, size-1)
+		require.NoError(t, err)
+		pubkeys := make(map[string]struct{}, size)
+		for _, pk := range keyring.pubkeys {
+			require.NotNil(t, pk)
+			pubkeys[string(pk.Encode())] = struct{}{}
+		}
+		require.Len(t, pubkeys, size)
+	}
+
+	_, err := NewKeyRing(Ed25519(), MaxRingSize+1, Ed25519().NewRandomScalar(), 0)
+	require.ErrorContains(t, err, "MaxRingSize")
+}
+
+func TestNewKeyRingDeterministic_IdxOutOfBounds(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	_, err := NewKeyRingDeterministic(curve, 2, privKey, 2, nil)
+	require.Error(t, err)
+	_, err = NewKeyRingDeterministic(curve, 2, privKey, -1, nil)
+	require.Error(t, err)
+}
+
 func TestGenKeyRing(t *testing.T) {
 	curve := Secp256k1()
 	privKey := curve.NewRandomScalar()
@@ -122,6 +196,27 @@ func TestRing_Equals(t *testing.T) {
 	require.True(t, keyring.Equals(keyring3))
 }
 
+func TestRing_Equals_DifferentCurves(t *testing.T) {
+	secp := Secp256k1()
+	secpRing, err := NewKeyRing(secp, 4, secp.NewRandomScalar(), 0)
+	require.NoError(t, err)
+
+	ed := Ed25519()
+	edRing, err := NewKeyRing(ed, 4, ed.NewRandomScalar(), 0)
+	require.NoError(t, err)
+
+	require.False(t, secpRing.Equals(edRing))
+	require.False(t, edRing.Equals(secpRing))
+
+	// separately instantiated curves of the same type are equal
+	secpRing2, err := NewFixedKeyRingFromPublicKeys(Secp256k1(), secpRing.pubkeys)
+	require.NoError(t, err)
+	require.True(t, secpRing.Equals(secpRing2))
+	edRing2, err := NewFixedKeyRingFromPublicKeys(Ed25519(), edRing.pubkeys)
+	require.NoError(t, err)
+	require.True(t, edRing.Equals(edRing2))
+}
+
 func TestSig_RingEquals(t *testing.T) {
 	curve := Secp256k1()
 	privKey := curve.NewRandomScalar()
@@ -224,6 +319,32 @@ func TestLinkabilityTrue_imageSmallSubgroup(t *testing.T) {
 	require.True(t, Link(sig1, sig2))
 }
 
+func TestVerify_imageSmallSubgroup(t *testing.T) {
+	// a signature whose image has a small subgroup point added to it must not
+	// verify, even though it still links with the signer's other signatures.
+	// this is the encoding of (0, -1), which has order 2.
+	order2Bytes, err := hex.DecodeString("ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	require.NoError(t, err)
+	order2, err := Ed25519().DecodeToPoint(order2Bytes)
+	require.NoError(t, err)
+
+	sig := createSigWithCurve(t, Ed25519(), 3, 1)
+	require.True(t, sig.Verify(testMsg))
+
+	image := sig.image
+	sig.image = image.Add(order2)
+	require.False(t, sig.Verify(testMsg))
+	require.True(t, NormalizeKeyImage(sig.image).Equals(image))
+}
+
+func TestNormalizeKeyImage(t *testing.T) {
+	for _, curve := range []types.Curve{Secp256k1(), Ed25519()} {
+		sig := createSigWithCurve(t, curve, 2, 0)
+		image := sig.KeyImage()
+		require.True(t, NormalizeKeyImage(image).Equals(image))
+	}
+}
+
 func TestLinkabilityFalse(t *testing.T) {
 	curve := Secp256k1()
 	privKey1 := curve.NewRandomScalar()
@@ -256,6 +377,75 @@ func TestSign_OneKey_Fails(t *testing.T) {
 	require.NotNil(t, keyring)
 	require.Equal(t, 1, len(keyring.pubkeys))
 	_, err = keyring.Sign(testMsg, privKey)
-	require.Error(t, err)
+	require.ErrorIs(t, err, ErrSingletonRing)
 	require.Equal(t, "size of ring less than two", err.Error())
 }
+
+func TestSign_SingletonRing(t *testing.T) {
+	for _, curve := range []types.Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 1, privKey, 0)
+		require.NoError(t, err)
+
+		sig, err := keyring.Sign(testMsg, privKey, AllowSingletonRing())
+		require.NoError(t, err)
+		require.True(t, sig.Verify(testMsg))
+		require.False(t, sig.Verify([32]byte{}))
+
+		enc, err := sig.Serialize()
+		require.NoError(t, err)
+		require.Equal(t, []byte{0, 0, 0, 1}, enc[:4])
+		decoded := new(RingSig)
+		require.NoError(t, decoded.Deserialize(curve, enc))
+		require.True(t, decoded.Verify(testMsg))
+
+		// the key image links it with signatures over larger rings
+		largerRing, err := NewKeyRing(curve, 3, privKey, 2)
+		require.NoError(t, err)
+		larger, err := largerRing.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		require.True(t, Link(sig, larger))
+		sig2 := createSigWithCurve(t, curve, 3, 1)
+		require.False(t, Link(sig, sig2))
+
+		_, err = keyring.Sign(testMsg, curve.NewRandomScalar(), AllowSingletonRing())
+		require.ErrorIs(t, err, ErrSignerNotInRing)
+	}
+
+	empty, err := NewFixedKeyRingFromPublicKeys(Ed25519(), nil)
+	require.NoError(t, err)
+	_, err = empty.Sign(testMsg, Ed25519().NewRandomScalar(), AllowSingletonRing())
+	require.ErrorContains(t, err, "empty")
+}
+
+func TestVerifyWithPolicy(t *testing.T) {
+	sig := createSig(t, 5, 3)
+	require.NoError(t, sig.VerifyWithPolicy(testMsg, nil))
+
+	var visited []int
+	err := sig.VerifyWithPolicy(testMsg, func(i int, pub types.Point) error {
+		require.True(t, pub.Equals(sig.ring.pubkeys[i]))
+		visited = append(visited, i)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2, 3, 4}, visited)
+
+	// the policy's error is returned, and verification stops at the rejected
+	// member
+	errRevoked := errors.New("revoked")
+	visited = nil
+	err = sig.VerifyWithPolicy(testMsg, func(i int, _ types.Point) error {
+		visited = append(visited, i)
+		if i == 2 {
+			return errRevoked
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, errRevoked)
+	require.EqualError(t, err, "ring member 2 rejected by policy: revoked")
+	require.Equal(t, []int{0, 1, 2}, visited)
+
+	err = sig.VerifyWithPolicy([32]byte{}, func(int, types.Point) error { return nil })
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
@@ -0,0 +1,197 @@
+// Package ringenc encrypts messages to a ring, such that any member of the
+// ring can decrypt them, eg. for anonymous reply channels alongside ring
+// signatures.
+//
+// The message is encrypted with ChaCha20-Poly1305 under a random content key,
+// which is wrapped for each member of the ring with a key derived from a
+// Diffie-Hellman exchange between a single ephemeral key and the member's
+// public key. The wrapped keys are in a random order, so the ciphertext
+// doesn't reveal which one belongs to which member; members find theirs by
+// trial decryption.
+package ringenc
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+const (
+	formatVersion = 1
+
+	keyLen        = chacha20poly1305.KeySize
+	wrappedKeyLen = keyLen + chacha20poly1305.Overhead
+
+	wrapInfo = "ring-go/ringenc/wrap"
+)
+
+// Encrypt encrypts the plaintext to the members of the ring. The additional
+// data is authenticated but not encrypted, and must be passed to Decrypt
+// unchanged.
+//
+// The ciphertext consists of a version byte, the curve ID, the ephemeral
+// public key, the number of wrapped keys as a big-endian uint32, the wrapped
+// keys in a random order, and the encrypted message.
+func Encrypt(keyring *ring.Ring, plaintext, additionalData []byte) ([]byte, error) {
+	curveID := ring.CurveIDOf(keyring.Curve())
+	if curveID == ring.CurveUnknown {
+		return nil, errors.New("unsupported curve")
+	}
+
+	if keyring.Size() == 0 {
+		return nil, errors.New("empty ring")
+	}
+
+	curve := keyring.Curve()
+	ephemeral := curve.NewRandomScalar()
+	ephemeralPub := curve.ScalarBaseMul(ephemeral).Encode()
+
+	var contentKey [keyLen]byte
+	if _, err := io.ReadFull(rand.Reader, contentKey[:]); err != nil {
+		return nil, err
+	}
+
+	// wrap the key in a random order, rather than the ring's, which is public
+	shuffled, _, err := keyring.ShuffledCopy(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []byte{formatVersion, byte(curveID)}
+	out = append(out, ephemeralPub...)
+	out = binary.BigEndian.AppendUint32(out, uint32(keyring.Size()))
+
+	for _, pk := range shuffled.PublicKeys() {
+		shared := curve.ScalarMul(ephemeral, pk)
+		wrap, err := wrappingAEAD(shared.Encode(), ephemeralPub, pk.Encode())
+		if err != nil {
+			return nil, err
+		}
+
+		// each wrapping key is only used once, so a zero nonce is safe
+		var nonce [chacha20poly1305.NonceSize]byte
+		out = wrap.Seal(out, nonce[:], contentKey[:], nil)
+	}
+
+	aead, err := chacha20poly1305.NewX(contentKey[:])
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	// the header, including the wrapped keys, is authenticated along with the
+	// additional data
+	header := out
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, append(header[:len(header):len(header)], additionalData...)), nil
+}
+
+// Decrypt decrypts a ciphertext created by Encrypt with the private key of
+// one of the ring's members.
+func Decrypt(curve ring.Curve, privKey ring.Scalar, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < 2 {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	if ciphertext[0] != formatVersion {
+		return nil, fmt.Errorf("unsupported format version %d", ciphertext[0])
+	}
+
+	if id := ring.CurveID(ciphertext[1]); id != ring.CurveIDOf(curve) {
+		return nil, fmt.Errorf("ciphertext is for curve %s, not %s", id, ring.CurveIDOf(curve))
+	}
+
+	pointLen := curve.CompressedPointSize()
+	r := bytes.NewReader(ciphertext[2:])
+
+	ephemeralPub := make([]byte, pointLen)
+	if _, err := io.ReadFull(r, ephemeralPub); err != nil {
+		return nil, errors.New("ciphertext too short")
+	}
+	ephemeral, err := curve.DecodeToPoint(ephemeralPub)
+	if err != nil {
+		return nil, err
+	}
+	// reject the identity, whose shared secret is known to anyone, and points
+	// with a small-order component, which would leak the private key modulo
+	// the cofactor to an attacker observing which ciphertexts decrypt
+	identity := ephemeral.Copy()
+	identity = identity.Sub(identity)
+	if ephemeral.Equals(identity) || !ring.NormalizeKeyImage(ephemeral).Equals(ephemeral) {
+		return nil, errors.New("invalid ephemeral key")
+	}
+
+	var count uint32
+	if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+		return nil, errors.New("ciphertext too short")
+	}
+	if uint64(count) > uint64(r.Len())/wrappedKeyLen {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	pubkey := curve.ScalarBaseMul(privKey).Encode()
+	shared := curve.ScalarMul(privKey, ephemeral)
+	wrap, err := wrappingAEAD(shared.Encode(), ephemeralPub, pubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		contentKey []byte
+		wrapped    = make([]byte, wrappedKeyLen)
+		nonce      [chacha20poly1305.NonceSize]byte
+	)
+	for i := uint32(0); i < count; i++ {
+		_, _ = io.ReadFull(r, wrapped)
+		// try every key, even after a match, so that the decryption time
+		// doesn't depend on the member's position
+		key, err := wrap.Open(nil, nonce[:], wrapped, nil)
+		if err == nil && contentKey == nil {
+			contentKey = key
+		}
+	}
+	if contentKey == nil {
+		return nil, errors.New("private key is not a recipient of the ciphertext")
+	}
+
+	aead, err := chacha20poly1305.NewX(contentKey)
+	if err != nil {
+		return nil, err
+	}
+
+	headerLen := len(ciphertext) - r.Len()
+	if r.Len() < aead.NonceSize()+aead.Overhead() {
+		return nil, errors.New("ciphertext too short")
+	}
+	header := ciphertext[:headerLen:headerLen]
+	body := ciphertext[headerLen:]
+
+	return aead.Open(nil, body[:aead.NonceSize()], body[aead.NonceSize():], append(header, additionalData...))
+}
+
+// wrappingAEAD returns the AEAD wrapping the content key for the member with
+// the given public key.
+func wrappingAEAD(shared, ephemeralPub, pubkey []byte) (cipher.AEAD, error) {
+	secret := append(append(append([]byte{}, shared...), ephemeralPub...), pubkey...)
+
+	key := make([]byte, keyLen)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, []byte(wrapInfo)), key); err != nil {
+		return nil, err
+	}
+
+	return chacha20poly1305.New(key)
+}
@@ -0,0 +1,120 @@
+package ringenc
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	for _, curve := range []ring.Curve{ring.Secp256k1(), ring.Ed25519()} {
+		privKeys := make([]ring.Scalar, 4)
+		pubkeys := make([]ring.Point, len(privKeys))
+		for i := range privKeys {
+			privKeys[i] = curve.NewRandomScalar()
+			pubkeys[i] = curve.ScalarBaseMul(privKeys[i])
+		}
+		keyring, err := ring.NewFixedKeyRingFromPublicKeys(curve, pubkeys)
+		require.NoError(t, err)
+
+		plaintext := []byte("reply to the anonymous signer")
+		aad := []byte("channel 7")
+		ciphertext, err := Encrypt(keyring, plaintext, aad)
+		require.NoError(t, err)
+
+		for _, privKey := range privKeys {
+			res, err := Decrypt(curve, privKey, ciphertext, aad)
+			require.NoError(t, err)
+			require.Equal(t, plaintext, res)
+		}
+
+		_, err = Decrypt(curve, curve.NewRandomScalar(), ciphertext, aad)
+		require.EqualError(t, err, "private key is not a recipient of the ciphertext")
+
+		_, err = Decrypt(curve, privKeys[0], ciphertext, []byte("channel 8"))
+		require.Error(t, err)
+
+		// tampering with any byte is detected
+		for i := range ciphertext {
+			tampered := append([]byte{}, ciphertext...)
+			tampered[i] ^= 1
+			_, err = Decrypt(curve, privKeys[1], tampered, aad)
+			require.Error(t, err, "byte %d", i)
+		}
+
+		for i := 0; i < len(ciphertext); i++ {
+			_, err = Decrypt(curve, privKeys[1], ciphertext[:i], aad)
+			require.Error(t, err)
+		}
+	}
+}
+
+func TestDecrypt_WrongCurve(t *testing.T) {
+	curve := ring.Ed25519()
+	keyring, err := ring.NewKeyRing(curve, 2, curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+	ciphertext, err := Encrypt(keyring, []byte("hi"), nil)
+	require.NoError(t, err)
+
+	other := ring.Secp256k1()
+	_, err = Decrypt(other, other.NewRandomScalar(), ciphertext, nil)
+	require.EqualError(t, err, "ciphertext is for curve ed25519, not secp256k1")
+}
+
+func TestEncrypt_SlotOrder(t *testing.T) {
+	curve := ring.Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := ring.NewKeyRing(curve, 4, privKey, 0)
+	require.NoError(t, err)
+	pointLen := curve.CompressedPointSize()
+
+	// the signer's key is wrapped at a random position, not at its index in
+	// the ring
+	positions := make(map[int]bool)
+	for i := 0; i < 16; i++ {
+		ciphertext, err := Encrypt(keyring, []byte("hi"), nil)
+		require.NoError(t, err)
+
+		ephemeralPub := ciphertext[2 : 2+pointLen]
+		ephemeral, err := curve.DecodeToPoint(ephemeralPub)
+		require.NoError(t, err)
+		shared := curve.ScalarMul(privKey, ephemeral)
+		wrap, err := wrappingAEAD(shared.Encode(), ephemeralPub, curve.ScalarBaseMul(privKey).Encode())
+		require.NoError(t, err)
+
+		var nonce [12]byte
+		slots := ciphertext[2+pointLen+4:]
+		for j := 0; j < keyring.Size(); j++ {
+			if _, err := wrap.Open(nil, nonce[:], slots[j*wrappedKeyLen:(j+1)*wrappedKeyLen], nil); err == nil {
+				positions[j] = true
+			}
+		}
+	}
+	require.Greater(t, len(positions), 1)
+}
+
+func TestDecrypt_SmallOrderEphemeral(t *testing.T) {
+	curve := ring.Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := ring.NewKeyRing(curve, 3, privKey, 1)
+	require.NoError(t, err)
+	ciphertext, err := Encrypt(keyring, []byte("hi"), nil)
+	require.NoError(t, err)
+
+	order2Bytes, err := hex.DecodeString("ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	require.NoError(t, err)
+	order2, err := curve.DecodeToPoint(order2Bytes)
+	require.NoError(t, err)
+	ephemeral, err := curve.DecodeToPoint(ciphertext[2:34])
+	require.NoError(t, err)
+
+	for _, p := range []ring.Point{order2, ephemeral.Add(order2), curve.ScalarBaseMul(curve.ScalarFromInt(0))} {
+		tampered := append([]byte{}, ciphertext...)
+		copy(tampered[2:34], p.Encode())
+		_, err = Decrypt(curve, privKey, tampered, nil)
+		require.EqualError(t, err, "invalid ephemeral key")
+	}
+}
@@ -0,0 +1,155 @@
+// Package ringjose registers ring signatures as JOSE signing algorithms for
+// github.com/golang-jwt/jwt/v5, so that services can issue anonymous but
+// linkable bearer tokens: the token is signed by one of the members of a ring,
+// without revealing which, and tokens signed by the same member share a key
+// image.
+//
+// Importing the package registers the algorithms. Tokens are signed with a
+// SigningKey, and verified against the trusted ring returned by the Keyfunc:
+//
+//	token := jwt.NewWithClaims(ringjose.SigningMethodSecp256k1, claims)
+//	s, err := token.SignedString(&ringjose.SigningKey{Ring: keyring, PrivateKey: privKey})
+//	...
+//	token, err := jwt.Parse(s, func(*jwt.Token) (interface{}, error) { return keyring, nil },
+//		jwt.WithValidMethods([]string{ringjose.AlgSecp256k1}))
+//	image, err := ringjose.KeyImage(token)
+//
+// The signed message is the SHA-256 digest of the JWS signing input, and the
+// signature is the serialized ring signature, which includes the ring.
+package ringjose
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// Algorithm identifiers, used in the "alg" header.
+const (
+	AlgSecp256k1 = "RING-LSAG-SECP256K1"
+	AlgEd25519   = "RING-LSAG-ED25519"
+)
+
+// Signing methods for each curve.
+var (
+	SigningMethodSecp256k1 = &SigningMethod{alg: AlgSecp256k1, curve: ring.CurveSecp256k1}
+	SigningMethodEd25519   = &SigningMethod{alg: AlgEd25519, curve: ring.CurveEd25519}
+)
+
+func init() {
+	for _, m := range []*SigningMethod{SigningMethodSecp256k1, SigningMethodEd25519} {
+		m := m
+		jwt.RegisterSigningMethod(m.alg, func() jwt.SigningMethod {
+			return m
+		})
+	}
+}
+
+// SigningKey is the key passed to jwt.Token.SignedString for ring signing
+// methods.
+type SigningKey struct {
+	// Ring is the ring to sign with. Its curve must match the signing method.
+	Ring *ring.Ring
+	// PrivateKey is the private key of a member of Ring.
+	PrivateKey ring.Scalar
+	// Options are passed to ring.Sign.
+	Options []ring.SignOption
+}
+
+// SigningMethod implements jwt.SigningMethod with ring signatures over a curve.
+type SigningMethod struct {
+	alg   string
+	curve ring.CurveID
+}
+
+var _ jwt.SigningMethod = (*SigningMethod)(nil)
+
+// Alg returns the method's algorithm identifier.
+func (m *SigningMethod) Alg() string {
+	return m.alg
+}
+
+// Sign signs the signing string. The key must be a *SigningKey whose ring is
+// over the method's curve.
+func (m *SigningMethod) Sign(signingString string, key interface{}) ([]byte, error) {
+	k, ok := key.(*SigningKey)
+	if !ok || k == nil || k.Ring == nil || k.PrivateKey == nil {
+		return nil, jwt.ErrInvalidKeyType
+	}
+	if id := ring.CurveIDOf(k.Ring.Curve()); id != m.curve {
+		return nil, fmt.Errorf("%w: ring is over %s, want %s", jwt.ErrInvalidKey, id, m.curve)
+	}
+
+	sig, err := k.Ring.Sign(sha256.Sum256([]byte(signingString)), k.PrivateKey, k.Options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return sig.Serialize()
+}
+
+// Verify verifies the signature of the signing string. The key must be the
+// *ring.Ring the token is expected to be signed with; tokens signed with any
+// other ring are rejected.
+func (m *SigningMethod) Verify(signingString string, sig []byte, key interface{}) error {
+	expected, ok := key.(*ring.Ring)
+	if !ok || expected == nil {
+		return jwt.ErrInvalidKeyType
+	}
+
+	rs, err := m.decode(sig)
+	if err != nil {
+		return fmt.Errorf("%w: %w", jwt.ErrSignatureInvalid, err)
+	}
+
+	if !rs.Ring().Equals(expected) {
+		return fmt.Errorf("%w: signature is over a different ring", jwt.ErrSignatureInvalid)
+	}
+
+	if !rs.Verify(sha256.Sum256([]byte(signingString))) {
+		return jwt.ErrSignatureInvalid
+	}
+
+	return nil
+}
+
+func (m *SigningMethod) decode(sig []byte) (*ring.RingSig, error) {
+	curve, err := m.curve.Curve()
+	if err != nil {
+		return nil, err
+	}
+
+	rs := new(ring.RingSig)
+	if err := rs.Deserialize(curve, sig); err != nil {
+		return nil, err
+	}
+
+	return rs, nil
+}
+
+// RingSig decodes the ring signature of a token signed with one of this
+// package's signing methods. Tokens returned by jwt.Parse have already been
+// verified.
+func RingSig(token *jwt.Token) (*ring.RingSig, error) {
+	m, ok := token.Method.(*SigningMethod)
+	if !ok {
+		return nil, errors.New("token is not signed with a ring signing method")
+	}
+
+	return m.decode(token.Signature)
+}
+
+// KeyImage returns the normalized key image of a token's ring signature, which
+// is the same for all tokens signed by the same private key.
+func KeyImage(token *jwt.Token) (ring.Point, error) {
+	sig, err := RingSig(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return ring.NormalizeKeyImage(sig.KeyImage()), nil
+}
@@ -0,0 +1,97 @@
+package ringjose
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+func newTestRing(t *testing.T, curve ring.Curve) (*ring.Ring, ring.Scalar) {
+	privKey := curve.NewRandomScalar()
+	keyring, err := ring.NewKeyRing(curve, 4, privKey, 1)
+	require.NoError(t, err)
+	return keyring, privKey
+}
+
+func keyfunc(keyring *ring.Ring) jwt.Keyfunc {
+	return func(*jwt.Token) (interface{}, error) {
+		return keyring, nil
+	}
+}
+
+func TestSignAndParse(t *testing.T) {
+	for _, method := range []*SigningMethod{SigningMethodSecp256k1, SigningMethodEd25519} {
+		curve, err := method.curve.Curve()
+		require.NoError(t, err)
+		keyring, privKey := newTestRing(t, curve)
+
+		claims := jwt.MapClaims{
+			"sub": "anonymous",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+		key := &SigningKey{Ring: keyring, PrivateKey: privKey}
+		s1, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		require.NoError(t, err)
+		s2, err := jwt.NewWithClaims(method, jwt.MapClaims{"sub": "other"}).SignedString(key)
+		require.NoError(t, err)
+
+		token1, err := jwt.Parse(s1, keyfunc(keyring), jwt.WithValidMethods([]string{method.Alg()}))
+		require.NoError(t, err)
+		require.True(t, token1.Valid)
+		require.Equal(t, "anonymous", token1.Claims.(jwt.MapClaims)["sub"])
+
+		token2, err := jwt.Parse(s2, keyfunc(keyring), jwt.WithValidMethods([]string{method.Alg()}))
+		require.NoError(t, err)
+
+		// tokens signed by the same key are linkable
+		image1, err := KeyImage(token1)
+		require.NoError(t, err)
+		image2, err := KeyImage(token2)
+		require.NoError(t, err)
+		require.True(t, image1.Equals(image2))
+	}
+}
+
+func TestRegistered(t *testing.T) {
+	require.Equal(t, SigningMethodSecp256k1, jwt.GetSigningMethod(AlgSecp256k1))
+	require.Equal(t, SigningMethodEd25519, jwt.GetSigningMethod(AlgEd25519))
+}
+
+func TestParse_Rejects(t *testing.T) {
+	keyring, privKey := newTestRing(t, ring.Secp256k1())
+	s, err := jwt.NewWithClaims(SigningMethodSecp256k1, jwt.MapClaims{"sub": "a"}).
+		SignedString(&SigningKey{Ring: keyring, PrivateKey: privKey})
+	require.NoError(t, err)
+
+	// a different trusted ring
+	other, _ := newTestRing(t, ring.Secp256k1())
+	_, err = jwt.Parse(s, keyfunc(other))
+	require.ErrorIs(t, err, jwt.ErrSignatureInvalid)
+
+	// a tampered payload
+	tampered := []byte(s)
+	tampered[len(s)/2] ^= 1
+	_, err = jwt.Parse(string(tampered), keyfunc(keyring))
+	require.Error(t, err)
+
+	// the wrong key type
+	_, err = jwt.Parse(s, func(*jwt.Token) (interface{}, error) { return privKey, nil })
+	require.ErrorIs(t, err, jwt.ErrInvalidKeyType)
+}
+
+func TestSign_Errors(t *testing.T) {
+	keyring, privKey := newTestRing(t, ring.Ed25519())
+
+	_, err := SigningMethodEd25519.Sign("x", privKey)
+	require.ErrorIs(t, err, jwt.ErrInvalidKeyType)
+
+	_, err = SigningMethodSecp256k1.Sign("x", &SigningKey{Ring: keyring, PrivateKey: privKey})
+	require.ErrorIs(t, err, jwt.ErrInvalidKey)
+
+	_, err = SigningMethodEd25519.Sign("x", &SigningKey{Ring: keyring, PrivateKey: ring.Ed25519().NewRandomScalar()})
+	require.Error(t, err)
+}
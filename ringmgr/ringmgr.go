@@ -0,0 +1,231 @@
+// Package ringmgr manages rings that rotate every epoch, the operational
+// pattern of most deployments of linkable ring signatures: members sign with
+// the ring of the current epoch, and verifiers keep accepting signatures made
+// with the rings of a few previous epochs while they propagate.
+//
+//	m := ringmgr.New(ringmgr.WithOverlap(1))
+//	err := m.Rotate(epoch, ringForEpoch)
+//	sig, epoch, err := m.SignCurrent(msgHash, privKey)
+//	...
+//	epoch, err := m.VerifyAnyEpoch(sig, msgHash)
+//
+// The signed message binds the epoch, so a signature is only valid for the
+// epoch it was made in. The H_p values of new rings are precomputed in the
+// background, so that the first signatures of an epoch don't pay for them.
+package ringmgr
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/sha3"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+const messageDomain = "ring-go/ringmgr"
+
+// Epoch is a ring rotation period, eg. a block height or a day number.
+type Epoch uint64
+
+var (
+	// ErrNoRing is returned when no ring has been set yet.
+	ErrNoRing = errors.New("no current ring")
+	// ErrUnknownRing is returned by VerifyAnyEpoch when a signature's ring
+	// isn't the ring of any retained epoch.
+	ErrUnknownRing = errors.New("signature ring is not the ring of a retained epoch")
+)
+
+// Option is an option for New.
+type Option func(*Manager)
+
+// WithOverlap sets the number of previous epochs whose rings are retained and
+// accepted by VerifyAnyEpoch, in addition to the current one. The default is
+// 1.
+func WithOverlap(n int) Option {
+	return func(m *Manager) {
+		m.overlap = max(n, 0)
+	}
+}
+
+// Manager holds the rings of the current and previous epochs. It's safe for
+// concurrent use.
+type Manager struct {
+	overlap int
+
+	mu      sync.RWMutex
+	current Epoch
+	hasRing bool
+	rings   map[Epoch]*ring.Ring
+
+	precompute sync.WaitGroup
+}
+
+// New returns a manager without rings.
+func New(opts ...Option) *Manager {
+	m := &Manager{
+		overlap: 1,
+		rings:   make(map[Epoch]*ring.Ring),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Rotate makes r the ring of the given epoch, which becomes the current one,
+// and drops the rings of epochs older than the overlap. The epoch must be
+// after the current one. The ring's H_p values are precomputed in the
+// background.
+func (m *Manager) Rotate(epoch Epoch, r *ring.Ring) error {
+	if r == nil {
+		return errors.New("ring is nil")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.hasRing && epoch <= m.current {
+		return fmt.Errorf("epoch %d is not after the current epoch %d", epoch, m.current)
+	}
+
+	m.current, m.hasRing = epoch, true
+	m.rings[epoch] = r
+	for e := range m.rings {
+		if epoch-e > Epoch(m.overlap) {
+			delete(m.rings, e)
+		}
+	}
+
+	m.precompute.Add(1)
+	go func() {
+		defer m.precompute.Done()
+		r.Precompute()
+	}()
+	return nil
+}
+
+// Wait blocks until the background precomputation of the rings set by Rotate
+// has finished.
+func (m *Manager) Wait() {
+	m.precompute.Wait()
+}
+
+// Current returns the current epoch and its ring, or ErrNoRing if Rotate
+// hasn't been called yet.
+func (m *Manager) Current() (Epoch, *ring.Ring, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.hasRing {
+		return 0, nil, ErrNoRing
+	}
+	return m.current, m.rings[m.current], nil
+}
+
+// Ring returns the ring of the given epoch, if it's retained.
+func (m *Manager) Ring(epoch Epoch) (*ring.Ring, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, ok := m.rings[epoch]
+	return r, ok
+}
+
+// Message returns the message signed for m in the given epoch.
+func Message(epoch Epoch, m [32]byte) [32]byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte(messageDomain))
+	_, _ = h.Write(binary.BigEndian.AppendUint64(nil, uint64(epoch)))
+	_, _ = h.Write(m[:])
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// SignCurrent signs m with the ring of the current epoch, and returns the
+// signature and the epoch.
+func (m *Manager) SignCurrent(msg [32]byte, privKey ring.Scalar, opts ...ring.SignOption) (*ring.RingSig, Epoch, error) {
+	epoch, r, err := m.Current()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sig, err := r.Sign(Message(epoch, msg), privKey, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return sig, epoch, nil
+}
+
+// VerifyAnyEpoch verifies a signature made with SignCurrent with the ring of
+// any retained epoch, and returns the epoch. It returns ErrUnknownRing if the
+// signature's ring isn't that of a retained epoch, and the error of
+// ring.RingSig.VerifyWithPolicy if the signature is invalid.
+func (m *Manager) VerifyAnyEpoch(sig *ring.RingSig, msg [32]byte) (Epoch, error) {
+	// the same ring may be used in several epochs, so each of them must be
+	// tried; there are only a few
+	m.mu.RLock()
+	var epochs []Epoch
+	for epoch, r := range m.rings {
+		if sig.Ring().Equals(r) {
+			epochs = append(epochs, epoch)
+		}
+	}
+	m.mu.RUnlock()
+
+	if len(epochs) == 0 {
+		return 0, ErrUnknownRing
+	}
+
+	var err error
+	for _, epoch := range epochs {
+		if err = sig.VerifyWithPolicy(Message(epoch, msg), nil); err == nil {
+			return epoch, nil
+		}
+	}
+	return 0, err
+}
+
+// Source returns the current epoch and its ring, eg. from a chain or a
+// membership service.
+type Source func(ctx context.Context) (Epoch, *ring.Ring, error)
+
+// Run polls the source every interval, and rotates to the ring it returns
+// when its epoch is after the current one, until the context is done. Errors
+// from the source are passed to onError, if not nil, and otherwise ignored.
+// It returns the context's error.
+func (m *Manager) Run(ctx context.Context, interval time.Duration, source Source, onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.poll(ctx, source); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Manager) poll(ctx context.Context, source Source) error {
+	epoch, r, err := source(ctx)
+	if err != nil {
+		return err
+	}
+
+	current, _, err := m.Current()
+	if err == nil && epoch <= current {
+		return nil
+	}
+	return m.Rotate(epoch, r)
+}
@@ -0,0 +1,120 @@
+package ringmgr
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+var testMsg = [32]byte{1, 2, 3}
+
+func newRing(t *testing.T, privKey ring.Scalar) *ring.Ring {
+	r, err := ring.NewKeyRing(ring.Ed25519(), 4, privKey, 1)
+	require.NoError(t, err)
+	return r
+}
+
+func TestManager(t *testing.T) {
+	privKey := ring.Ed25519().NewRandomScalar()
+	m := New(WithOverlap(1))
+	defer m.Wait()
+
+	_, _, err := m.SignCurrent(testMsg, privKey)
+	require.ErrorIs(t, err, ErrNoRing)
+
+	r1, r2, r3 := newRing(t, privKey), newRing(t, privKey), newRing(t, privKey)
+	require.NoError(t, m.Rotate(1, r1))
+	sig1, epoch, err := m.SignCurrent(testMsg, privKey)
+	require.NoError(t, err)
+	require.Equal(t, Epoch(1), epoch)
+
+	require.NoError(t, m.Rotate(2, r2))
+	sig2, epoch, err := m.SignCurrent(testMsg, privKey)
+	require.NoError(t, err)
+	require.Equal(t, Epoch(2), epoch)
+
+	// the previous epoch is still accepted
+	epoch, err = m.VerifyAnyEpoch(sig1, testMsg)
+	require.NoError(t, err)
+	require.Equal(t, Epoch(1), epoch)
+	epoch, err = m.VerifyAnyEpoch(sig2, testMsg)
+	require.NoError(t, err)
+	require.Equal(t, Epoch(2), epoch)
+	_, err = m.VerifyAnyEpoch(sig2, [32]byte{})
+	require.ErrorIs(t, err, ring.ErrInvalidSignature)
+
+	// but not after it's out of the overlap
+	require.NoError(t, m.Rotate(3, r3))
+	_, err = m.VerifyAnyEpoch(sig1, testMsg)
+	require.ErrorIs(t, err, ErrUnknownRing)
+	_, ok := m.Ring(1)
+	require.False(t, ok)
+	r, ok := m.Ring(2)
+	require.True(t, ok)
+	require.Equal(t, r2, r)
+
+	require.Error(t, m.Rotate(3, r3))
+	require.Error(t, m.Rotate(4, nil))
+}
+
+func TestManager_SameRing(t *testing.T) {
+	privKey := ring.Ed25519().NewRandomScalar()
+	r := newRing(t, privKey)
+	m := New(WithOverlap(2))
+	defer m.Wait()
+
+	require.NoError(t, m.Rotate(10, r))
+	sig, _, err := m.SignCurrent(testMsg, privKey)
+	require.NoError(t, err)
+	require.NoError(t, m.Rotate(11, r))
+
+	// the signature binds the epoch it was made in
+	epoch, err := m.VerifyAnyEpoch(sig, testMsg)
+	require.NoError(t, err)
+	require.Equal(t, Epoch(10), epoch)
+}
+
+func TestManager_Run(t *testing.T) {
+	privKey := ring.Ed25519().NewRandomScalar()
+	rings := []*ring.Ring{newRing(t, privKey), newRing(t, privKey)}
+	m := New()
+	defer m.Wait()
+
+	var calls atomic.Int64
+	source := func(context.Context) (Epoch, *ring.Ring, error) {
+		n := calls.Add(1)
+		switch {
+		case n == 2:
+			return 0, nil, errors.New("unavailable")
+		case n < 3:
+			return 1, rings[0], nil
+		default:
+			return 2, rings[1], nil
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var errs atomic.Int64
+	done := make(chan error)
+	go func() {
+		done <- m.Run(ctx, time.Millisecond, source, func(error) { errs.Add(1) })
+	}()
+
+	require.Eventually(t, func() bool {
+		epoch, _, err := m.Current()
+		return err == nil && epoch == 2
+	}, 5*time.Second, time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.Equal(t, int64(1), errs.Load())
+
+	_, r, err := m.Current()
+	require.NoError(t, err)
+	require.Equal(t, rings[1], r)
+}
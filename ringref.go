@@ -0,0 +1,167 @@
+package ring
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// ErrUnknownRing is returned by RingResolvers, wrapped, when they don't know
+// the ring with a given hash.
+var ErrUnknownRing = errors.New("unknown ring")
+
+// RingResolver resolves ring hashes (see Ring.Hash) to rings, eg. from a
+// registry of the rings of a system, so that signatures can refer to their
+// ring instead of carrying its public keys. See SerializeByRef.
+type RingResolver interface {
+	Resolve(hash [32]byte) (*Ring, error)
+}
+
+// RingMap is a RingResolver over a fixed set of rings, keyed by their hash.
+type RingMap map[[32]byte]*Ring
+
+var _ RingResolver = RingMap(nil)
+
+// NewRingMap returns a RingMap resolving the given rings.
+func NewRingMap(rings ...*Ring) RingMap {
+	m := make(RingMap, len(rings))
+	for _, r := range rings {
+		m[r.Hash()] = r
+	}
+	return m
+}
+
+// Resolve returns the ring with the given hash, or ErrUnknownRing.
+func (m RingMap) Resolve(hash [32]byte) (*Ring, error) {
+	r, ok := m[hash]
+	if !ok {
+		return nil, fmt.Errorf("%w %x", ErrUnknownRing, hash)
+	}
+	return r, nil
+}
+
+// SerializeByRef encodes the signature like Serialize, but refers to its ring
+// by hash instead of including the public keys, which makes the encoding
+// about half as long. The hint, of at most 255 bytes, is carried as is, eg. to
+// tell verifiers which registry holds the ring; it isn't signed.
+//
+// The encoding is the 4-byte header of Serialize, the ring hash, the length of
+// the hint (1 byte) and the hint, the challenge, the key image, the extension
+// fields, and the responses. It's decoded with DeserializeByRef, given a
+// RingResolver knowing the ring.
+func (sig *RingSig) SerializeByRef(hint []byte) ([]byte, error) {
+	size := len(sig.ring.pubkeys)
+	if size > MaxRingSize {
+		return nil, errors.New("ring size exceeds MaxRingSize")
+	}
+	if len(hint) > math.MaxUint8 {
+		return nil, errors.New("hint longer than 255 bytes")
+	}
+
+	out := binary.BigEndian.AppendUint32(nil, uint32(sig.ext.flags())<<24|uint32(size))
+	ringHash := sig.ring.Hash()
+	out = append(out, ringHash[:]...)
+	out = append(out, byte(len(hint)))
+	out = append(out, hint...)
+	out = append(out, sig.c.Encode()...)
+	out = append(out, encodePoint(sig.image)...)
+	out = append(out, sig.ext.encode()...)
+	for _, s := range sig.s {
+		out = append(out, s.Encode()...)
+	}
+	return out, nil
+}
+
+// ParseRingRef returns the ring hash and the hint of a signature encoded with
+// SerializeByRef, eg. to select a resolver before decoding it.
+func ParseRingRef(in []byte) (hash [32]byte, hint []byte, err error) {
+	if len(in) < 4+len(hash)+1 {
+		return hash, nil, errors.New("input too short")
+	}
+	copy(hash[:], in[4:])
+	n := int(in[4+len(hash)])
+	rest := in[4+len(hash)+1:]
+	if len(rest) < n {
+		return hash, nil, errors.New("input too short")
+	}
+	return hash, bytes.Clone(rest[:n]), nil
+}
+
+// DeserializeByRef decodes a signature over the given curve encoded with
+// SerializeByRef, resolving its ring with resolver. It returns an error if
+// the resolved ring doesn't have the signature's ring hash or size, or isn't
+// over the curve.
+func (sig *RingSig) DeserializeByRef(curve types.Curve, in []byte, resolver RingResolver) error {
+	hash, hint, err := ParseRingRef(in)
+	if err != nil {
+		return err
+	}
+	header := binary.BigEndian.Uint32(in)
+	enc, flags, err := splitFlags(byte(header >> 24))
+	if err != nil {
+		return err
+	}
+	if enc != PointCompressed {
+		return errors.New("unsupported point encoding")
+	}
+	size := int(header & MaxRingSize)
+
+	scalarLen, pointLen, extLen := ScalarSize(curve), curve.CompressedPointSize(), extensionsLen(flags)
+	r := bytes.NewBuffer(in[4+len(hash)+1+len(hint):])
+	if expected := scalarLen + pointLen + extLen + size*scalarLen; r.Len() != expected {
+		return fmt.Errorf("invalid length %d, expected %d", len(in), len(in)-r.Len()+expected)
+	}
+
+	ring, err := resolver.Resolve(hash)
+	if err != nil {
+		return err
+	}
+	switch {
+	case ring == nil:
+		return fmt.Errorf("%w %x", ErrUnknownRing, hash)
+	case !sameCurve(ring.curve, curve):
+		return errors.New("resolved ring is over another curve")
+	case ring.Size() != size:
+		return fmt.Errorf("resolved ring has %d members, expected %d", ring.Size(), size)
+	case ring.Hash() != hash:
+		return errors.New("resolved ring doesn't have the requested hash")
+	}
+
+	res := &RingSig{ring: ring, s: make([]types.Scalar, size)}
+	if res.c, err = curve.DecodeToScalar(r.Next(scalarLen)); err != nil {
+		return err
+	}
+	if res.image, err = curve.DecodeToPoint(r.Next(pointLen)); err != nil {
+		return err
+	}
+	if res.ext, err = decodeExtensions(flags, r.Next(extLen)); err != nil {
+		return err
+	}
+	for i := range res.s {
+		if res.s[i], err = curve.DecodeToScalar(r.Next(scalarLen)); err != nil {
+			return err
+		}
+	}
+
+	*sig = *res
+	return nil
+}
+
+// VerifyByRef decodes a signature encoded with SerializeByRef, resolving its
+// ring with resolver, and verifies it for the message with VerifyWithOpts and
+// the given options, which may be nil. It returns the decoded signature if
+// it's valid.
+func VerifyByRef(curve types.Curve, m [32]byte, in []byte, resolver RingResolver, opts *VerifyOpts) (*RingSig, error) {
+	sig := new(RingSig)
+	if err := sig.DeserializeByRef(curve, in, resolver); err != nil {
+		return nil, err
+	}
+	if err := sig.VerifyWithOpts(m, opts); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
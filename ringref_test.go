@@ -0,0 +1,71 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerializeByRef(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 8, privKey, 3)
+		require.NoError(t, err)
+		sig, err := keyring.Sign(testMsg, privKey, WithChainID(7))
+		require.NoError(t, err)
+
+		full, err := sig.Serialize()
+		require.NoError(t, err)
+		enc, err := sig.SerializeByRef([]byte("registry-1"))
+		require.NoError(t, err)
+		require.Less(t, len(enc), len(full)*2/3)
+
+		hash, hint, err := ParseRingRef(enc)
+		require.NoError(t, err)
+		require.Equal(t, keyring.Hash(), hash)
+		require.Equal(t, []byte("registry-1"), hint)
+
+		other := createSigWithCurve(t, curve, 8, 0).Ring()
+		resolver := NewRingMap(other, keyring)
+		decoded, err := VerifyByRef(curve, testMsg, enc, resolver, nil)
+		require.NoError(t, err)
+		require.Same(t, keyring, decoded.Ring())
+		require.True(t, Link(sig, decoded))
+		decodedFull, err := decoded.Serialize()
+		require.NoError(t, err)
+		require.Equal(t, full, decodedFull)
+
+		_, err = VerifyByRef(curve, [32]byte{}, enc, resolver, nil)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+		_, err = VerifyByRef(curve, testMsg, enc, NewRingMap(other), nil)
+		require.ErrorIs(t, err, ErrUnknownRing)
+		_, err = VerifyByRef(curve, testMsg, enc[:len(enc)-1], resolver, nil)
+		require.Error(t, err)
+	}
+}
+
+// lyingResolver resolves every hash to the same ring.
+type lyingResolver struct {
+	ring *Ring
+}
+
+func (l lyingResolver) Resolve([32]byte) (*Ring, error) {
+	return l.ring, nil
+}
+
+func TestDeserializeByRef_WrongRing(t *testing.T) {
+	sig := createSigWithCurve(t, Ed25519(), 4, 1)
+	enc, err := sig.SerializeByRef(nil)
+	require.NoError(t, err)
+
+	other := createSigWithCurve(t, Ed25519(), 4, 1).Ring()
+	err = new(RingSig).DeserializeByRef(Ed25519(), enc, lyingResolver{other})
+	require.ErrorContains(t, err, "hash")
+
+	smaller := createSigWithCurve(t, Ed25519(), 3, 1).Ring()
+	err = new(RingSig).DeserializeByRef(Ed25519(), enc, lyingResolver{smaller})
+	require.ErrorContains(t, err, "members")
+
+	_, err = sig.SerializeByRef(make([]byte, 256))
+	require.Error(t, err)
+}
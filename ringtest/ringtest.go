@@ -0,0 +1,112 @@
+// Package ringtest provides fakes of the ring package's RingSigner and
+// RingVerifier interfaces, for unit-testing code that signs and verifies ring
+// signatures without doing curve arithmetic.
+//
+// The fakes only work with each other: a fake signature is the message and
+// the signer's fake key image, which the fake verifier checks and returns.
+// They provide no security whatsoever.
+//
+//	signer := ringtest.NewSigner("alice")
+//	verifier := ringtest.NewVerifier()
+//	sig, _ := signer.SignMessage(m)
+//	keyImage, err := verifier.VerifyMessage(m, sig) // keyImage == signer.KeyImage
+package ringtest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// prefix starts every fake signature.
+const prefix = "ringtest"
+
+var (
+	_ ring.RingSigner   = (*Signer)(nil)
+	_ ring.RingVerifier = (*Verifier)(nil)
+)
+
+// Signer is a fake ring.RingSigner.
+type Signer struct {
+	// KeyImage is the fake key image of the signer, which the fake verifier
+	// returns for its signatures.
+	KeyImage []byte
+	// Err, if set, is returned by SignMessage instead of signing.
+	Err error
+
+	mu     sync.Mutex
+	signed [][32]byte
+}
+
+// NewSigner returns a fake signer whose key image is derived from the given
+// name, so that signers with the same name are linked.
+func NewSigner(name string) *Signer {
+	image := sha256.Sum256([]byte(name))
+	return &Signer{KeyImage: image[:]}
+}
+
+// SignMessage returns a fake signature of the message, or s.Err if it's set.
+// The options are ignored.
+func (s *Signer) SignMessage(m [32]byte, _ ...ring.SignOption) ([]byte, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+
+	s.mu.Lock()
+	s.signed = append(s.signed, m)
+	s.mu.Unlock()
+
+	sig := make([]byte, 0, len(prefix)+len(m)+len(s.KeyImage))
+	sig = append(sig, prefix...)
+	sig = append(sig, m[:]...)
+	return append(sig, s.KeyImage...), nil
+}
+
+// Signed returns the messages signed so far, in order.
+func (s *Signer) Signed() [][32]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][32]byte(nil), s.signed...)
+}
+
+// Verifier is a fake ring.RingVerifier, which accepts the fake signatures of
+// Signer.
+type Verifier struct {
+	// Err, if set, is returned by VerifyMessage instead of verifying.
+	Err error
+	// Reject, if set, is called with the key image of each signature that is
+	// otherwise valid, and rejects it if it returns true, eg. to simulate a
+	// signer that isn't a member of the trusted ring.
+	Reject func(keyImage []byte) bool
+}
+
+// NewVerifier returns a fake verifier accepting all fake signatures.
+func NewVerifier() *Verifier {
+	return &Verifier{}
+}
+
+// VerifyMessage checks that sig is a fake signature of m, and returns the
+// signer's key image. It returns an error wrapping ring.ErrInvalidSignature
+// if it isn't, or if v.Reject rejects it, and v.Err if it's set.
+func (v *Verifier) VerifyMessage(m [32]byte, sig []byte) ([]byte, error) {
+	if v.Err != nil {
+		return nil, v.Err
+	}
+
+	rest, ok := bytes.CutPrefix(sig, []byte(prefix))
+	if !ok || len(rest) < len(m) {
+		return nil, fmt.Errorf("%w: not a fake signature", ring.ErrInvalidSignature)
+	}
+	if !bytes.Equal(rest[:len(m)], m[:]) {
+		return nil, ring.ErrInvalidSignature
+	}
+
+	keyImage := bytes.Clone(rest[len(m):])
+	if v.Reject != nil && v.Reject(keyImage) {
+		return nil, fmt.Errorf("%w: rejected signer", ring.ErrInvalidSignature)
+	}
+	return keyImage, nil
+}
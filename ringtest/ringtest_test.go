@@ -0,0 +1,53 @@
+package ringtest
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+func TestFakes(t *testing.T) {
+	alice, bob := NewSigner("alice"), NewSigner("bob")
+	verifier := NewVerifier()
+	m := [32]byte{1, 2, 3}
+
+	sig, err := alice.SignMessage(m)
+	require.NoError(t, err)
+	image, err := verifier.VerifyMessage(m, sig)
+	require.NoError(t, err)
+	require.Equal(t, alice.KeyImage, image)
+	require.Equal(t, [][32]byte{m}, alice.Signed())
+
+	// signers with the same name are linked
+	require.Equal(t, NewSigner("alice").KeyImage, alice.KeyImage)
+	require.NotEqual(t, bob.KeyImage, alice.KeyImage)
+
+	_, err = verifier.VerifyMessage([32]byte{4}, sig)
+	require.ErrorIs(t, err, ring.ErrInvalidSignature)
+	_, err = verifier.VerifyMessage(m, []byte("garbage"))
+	require.ErrorIs(t, err, ring.ErrInvalidSignature)
+
+	verifier.Reject = func(keyImage []byte) bool { return bytes.Equal(keyImage, alice.KeyImage) }
+	_, err = verifier.VerifyMessage(m, sig)
+	require.ErrorIs(t, err, ring.ErrInvalidSignature)
+	bobSig, err := bob.SignMessage(m)
+	require.NoError(t, err)
+	_, err = verifier.VerifyMessage(m, bobSig)
+	require.NoError(t, err)
+}
+
+func TestFakes_Errors(t *testing.T) {
+	errSign, errVerify := errors.New("hsm offline"), errors.New("timeout")
+	signer := &Signer{Err: errSign}
+	_, err := signer.SignMessage([32]byte{})
+	require.ErrorIs(t, err, errSign)
+	require.Empty(t, signer.Signed())
+
+	verifier := &Verifier{Err: errVerify}
+	_, err = verifier.VerifyMessage([32]byte{}, nil)
+	require.ErrorIs(t, err, errVerify)
+}
@@ -0,0 +1,145 @@
+package ring
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"golang.org/x/crypto/sha3"
+)
+
+const rotationDomain = "ring-go/rotation"
+
+// ErrInvalidRotation is returned by RotationProof.Verify when the proof is
+// invalid or over other rings.
+var ErrInvalidRotation = errors.New("invalid rotation proof")
+
+// RotationProof links the key image of a member of an old ring to that of a
+// member of a new ring, proving that they belong to the same party, without
+// revealing either member. It lets linkability survive key rotation and
+// membership churn: a registry that has seen the old key image can treat the
+// new one as the same signer (see imagestore.Registry.RegisterRotation).
+//
+// It's made of two ring signatures, one by the old key over the old ring and
+// one by the new key over the new ring, each over a message binding both key
+// images, so that it can only be created by a party holding both keys.
+type RotationProof struct {
+	old, new *RingSig
+}
+
+// ProveRotation creates a proof linking the key oldPriv, a member of oldRing,
+// to the key newPriv, a member of newRing. The rings must be over the same
+// curve, and the keys must differ.
+func ProveRotation(oldRing *Ring, oldPriv types.Scalar, newRing *Ring, newPriv types.Scalar) (_ *RotationProof, err error) {
+	defer recoverInternal(&err)
+
+	if oldRing == nil || newRing == nil {
+		return nil, errors.New("ring is nil")
+	}
+	curve := oldRing.curve
+	if CurveIDOf(curve) != CurveIDOf(newRing.curve) {
+		return nil, errors.New("rings are over different curves")
+	}
+	if oldPriv.Eq(newPriv) {
+		return nil, errors.New("new key must differ from the old one")
+	}
+
+	oldImage := curve.ScalarMul(oldPriv, hashToCurve(curve.ScalarBaseMul(oldPriv)))
+	newImage := curve.ScalarMul(newPriv, hashToCurve(curve.ScalarBaseMul(newPriv)))
+	m := rotationMessage(oldImage, newImage)
+
+	oldSig, err := oldRing.Sign(m, oldPriv)
+	if err != nil {
+		return nil, fmt.Errorf("signing with the old key: %w", err)
+	}
+	newSig, err := newRing.Sign(m, newPriv)
+	if err != nil {
+		return nil, fmt.Errorf("signing with the new key: %w", err)
+	}
+
+	return &RotationProof{old: oldSig, new: newSig}, nil
+}
+
+// rotationMessage returns the message signed by both signatures of a rotation
+// proof.
+func rotationMessage(oldImage, newImage types.Point) [32]byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte(rotationDomain))
+	_, _ = h.Write(encodePoint(NormalizeKeyImage(oldImage)))
+	_, _ = h.Write(encodePoint(NormalizeKeyImage(newImage)))
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// Verify checks that the proof links a member of oldRing to a member of
+// newRing. It returns ErrInvalidRotation if it doesn't.
+func (p *RotationProof) Verify(oldRing, newRing *Ring) error {
+	switch {
+	case !p.old.ring.Equals(oldRing):
+		return fmt.Errorf("%w: not over the old ring", ErrInvalidRotation)
+	case !p.new.ring.Equals(newRing):
+		return fmt.Errorf("%w: not over the new ring", ErrInvalidRotation)
+	case Link(p.old, p.new):
+		return fmt.Errorf("%w: same key", ErrInvalidRotation)
+	}
+
+	m := rotationMessage(p.old.image, p.new.image)
+	if !p.old.Verify(m) || !p.new.Verify(m) {
+		return ErrInvalidRotation
+	}
+	return nil
+}
+
+// OldKeyImage returns the key image of the old key.
+func (p *RotationProof) OldKeyImage() types.Point {
+	return p.old.KeyImage()
+}
+
+// NewKeyImage returns the key image of the new key.
+func (p *RotationProof) NewKeyImage() types.Point {
+	return p.new.KeyImage()
+}
+
+// Serialize encodes the proof as its two signatures, the old one first, each
+// prefixed with its length as 4 bytes, big-endian.
+func (p *RotationProof) Serialize() ([]byte, error) {
+	var out []byte
+	for _, sig := range []*RingSig{p.old, p.new} {
+		enc, err := sig.Serialize()
+		if err != nil {
+			return nil, err
+		}
+		out = binary.BigEndian.AppendUint32(out, uint32(len(enc)))
+		out = append(out, enc...)
+	}
+	return out, nil
+}
+
+// Deserialize decodes a proof over the given curve encoded with Serialize.
+func (p *RotationProof) Deserialize(curve types.Curve, in []byte) error {
+	r := bytes.NewBuffer(in)
+	var sigs [2]*RingSig
+	for i := range sigs {
+		if r.Len() < 4 {
+			return errors.New("input too short")
+		}
+		n := binary.BigEndian.Uint32(r.Next(4))
+		if uint64(r.Len()) < uint64(n) {
+			return errors.New("input too short")
+		}
+		sigs[i] = new(RingSig)
+		if err := sigs[i].Deserialize(curve, r.Next(int(n))); err != nil {
+			return err
+		}
+	}
+	if r.Len() != 0 {
+		return errors.New("input too long")
+	}
+
+	p.old, p.new = sigs[0], sigs[1]
+	return nil
+}
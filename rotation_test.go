@@ -0,0 +1,65 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProveRotation(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		oldPriv, newPriv := curve.NewRandomScalar(), curve.NewRandomScalar()
+		oldRing, err := NewKeyRing(curve, 4, oldPriv, 1)
+		require.NoError(t, err)
+		newRing, err := NewKeyRing(curve, 5, newPriv, 3)
+		require.NoError(t, err)
+
+		proof, err := ProveRotation(oldRing, oldPriv, newRing, newPriv)
+		require.NoError(t, err)
+		require.NoError(t, proof.Verify(oldRing, newRing))
+
+		oldSig, err := oldRing.Sign(testMsg, oldPriv)
+		require.NoError(t, err)
+		newSig, err := newRing.Sign(testMsg, newPriv)
+		require.NoError(t, err)
+		require.True(t, proof.OldKeyImage().Equals(oldSig.KeyImage()))
+		require.True(t, proof.NewKeyImage().Equals(newSig.KeyImage()))
+
+		enc, err := proof.Serialize()
+		require.NoError(t, err)
+		res := new(RotationProof)
+		require.NoError(t, res.Deserialize(curve, enc))
+		require.NoError(t, res.Verify(oldRing, newRing))
+		require.Error(t, res.Deserialize(curve, enc[:len(enc)-1]))
+		require.Error(t, res.Deserialize(curve, append(enc, 0)))
+
+		// the rings are checked
+		require.ErrorIs(t, proof.Verify(newRing, oldRing), ErrInvalidRotation)
+	}
+}
+
+func TestProveRotation_Rejects(t *testing.T) {
+	curve := Secp256k1()
+	oldPriv, newPriv := curve.NewRandomScalar(), curve.NewRandomScalar()
+	oldRing, err := NewKeyRing(curve, 3, oldPriv, 0)
+	require.NoError(t, err)
+	newRing, err := NewKeyRing(curve, 3, newPriv, 2)
+	require.NoError(t, err)
+
+	_, err = ProveRotation(oldRing, oldPriv, oldRing, oldPriv)
+	require.Error(t, err)
+	_, err = ProveRotation(oldRing, newPriv, newRing, newPriv)
+	require.Error(t, err)
+
+	// signatures with other keys can't be combined into a proof
+	proof, err := ProveRotation(oldRing, oldPriv, newRing, newPriv)
+	require.NoError(t, err)
+	otherPriv := curve.NewRandomScalar()
+	otherRing, err := NewKeyRing(curve, 3, otherPriv, 1)
+	require.NoError(t, err)
+	other, err := ProveRotation(oldRing, oldPriv, otherRing, otherPriv)
+	require.NoError(t, err)
+
+	mixed := &RotationProof{old: proof.old, new: other.new}
+	require.ErrorIs(t, mixed.Verify(oldRing, otherRing), ErrInvalidRotation)
+}
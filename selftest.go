@@ -0,0 +1,330 @@
+package ring
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"golang.org/x/crypto/sha3"
+)
+
+// Names of the checks run by SelfTest, in order.
+const (
+	SelfTestEncoding    = "encoding"
+	SelfTestHashToCurve = "hash-to-curve"
+	SelfTestSign        = "sign-verify"
+	SelfTestReject      = "reject"
+	SelfTestLink        = "link"
+	SelfTestSerialize   = "serialize"
+)
+
+// Inputs of the self-test. The signer's key and the decoys are derived from
+// fixed labels, so that the signature is reproducible with
+// WithDeterministicNonces.
+const (
+	selfTestKeyLabel   = "ring-go/selftest/key"
+	selfTestOtherLabel = "ring-go/selftest/other-key"
+	selfTestDecoySeed  = "ring-go/selftest/decoys"
+	selfTestRingSize   = 4
+	selfTestSignerIdx  = 1
+)
+
+var selfTestMsg = sha3.Sum256([]byte("ring-go/selftest/message"))
+
+// selfTestVector holds the known answers of the self-test for a curve.
+type selfTestVector struct {
+	// hp is the encoding of H_p of the signer's public key.
+	hp string
+	// sig is the SHA3-256 hash of the serialized signature.
+	sig string
+}
+
+// selfTestVectors are the known answers for the curves with a CurveID; other
+// curves only get the consistency checks.
+var selfTestVectors = map[CurveID]selfTestVector{
+	CurveSecp256k1: {
+		hp:  "02d5b6dbf6601dde6b127b6ff45e789334338c4f816294ad46c1e6c443927ef643",
+		sig: "4970591dddc2709da3481a75a80a48171f95e70576c03276d57e8ed513d43209",
+	},
+	CurveEd25519: {
+		hp:  "76125d21598b76c3854074215ff333a3e0503dc63264f089af975085a51ca029",
+		sig: "e16ba4749f1b3f7dbd681eaf9dda0723b6d8ada45b1c2c828e717791526b58fe",
+	},
+}
+
+// SelfTestResult is the outcome of a check of SelfTest.
+type SelfTestResult struct {
+	// Name is one of the SelfTest* constants.
+	Name string
+	// Err is the reason the check failed, or nil if it passed.
+	Err error
+	// KnownAnswer is true if the check compared its output with a known
+	// answer, which is only the case for the curves of the package.
+	KnownAnswer bool
+	Duration    time.Duration
+}
+
+// SelfTestReport is the outcome of SelfTest.
+type SelfTestReport struct {
+	// Curve is the name of the curve, as returned by CurveID.String.
+	Curve   string
+	Results []SelfTestResult
+}
+
+// Passed returns whether all the checks passed.
+func (r *SelfTestReport) Passed() bool {
+	return r.Err() == nil
+}
+
+// Err returns the errors of the checks that failed, joined, or nil if all
+// passed.
+func (r *SelfTestReport) Err() error {
+	var errs []error
+	for _, res := range r.Results {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("%s self-test %s: %w", r.Curve, res.Name, res.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SelfTest checks that the operations of the package are consistent on the
+// curve, in the style of the power-on self-tests of FIPS modules, so that
+// processes can refuse to start if the curve implementation or the build is
+// broken:
+//
+//   - encoding: scalars and points survive their encodings;
+//   - hash-to-curve: H_p of a fixed public key is a valid point of the
+//     prime-order subgroup, and matches its known answer;
+//   - sign-verify: a signature with deterministic nonces over a fixed ring
+//     verifies, and matches its known answer;
+//   - reject: the signature doesn't verify for another message, or with a
+//     response changed;
+//   - link: signatures by the same key link, and by different keys don't;
+//   - serialize: the signature survives serialization.
+//
+// Known answers are only checked on secp256k1 and ed25519; on other curves,
+// eg. registered with RegisterCurve, the checks are consistency checks only.
+// All the checks run, even if some fail; panics are reported as failures.
+//
+// It takes a few milliseconds:
+//
+//	if err := ring.SelfTest(ring.Secp256k1()).Err(); err != nil {
+//		log.Fatal(err)
+//	}
+func SelfTest(curve types.Curve) *SelfTestReport {
+	st := &selfTest{curve: curve}
+	st.vector, st.hasVector = selfTestVectors[CurveIDOf(curve)]
+	report := &SelfTestReport{Curve: CurveIDOf(curve).String()}
+	for _, check := range []struct {
+		name string
+		run  func() error
+	}{
+		{SelfTestEncoding, st.encoding},
+		{SelfTestHashToCurve, st.hashToCurve},
+		{SelfTestSign, st.sign},
+		{SelfTestReject, st.reject},
+		{SelfTestLink, st.link},
+		{SelfTestSerialize, st.serialize},
+	} {
+		start := time.Now()
+		err := runSelfTestCheck(check.run)
+		report.Results = append(report.Results, SelfTestResult{
+			Name:        check.name,
+			Err:         err,
+			KnownAnswer: st.hasVector && (check.name == SelfTestHashToCurve || check.name == SelfTestSign),
+			Duration:    time.Since(start),
+		})
+	}
+	return report
+}
+
+// runSelfTestCheck runs a check, converting panics into errors even in strict
+// mode, as the self-test reports failures rather than crashing.
+func runSelfTestCheck(check func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrInternal, r)
+		}
+	}()
+	return check()
+}
+
+// selfTest holds the state shared by the checks of SelfTest; the signature
+// created by sign is used by the following checks.
+type selfTest struct {
+	curve     types.Curve
+	vector    selfTestVector
+	hasVector bool
+
+	privKey types.Scalar
+	ring    *Ring
+	sig     *RingSig
+}
+
+func (st *selfTest) key(label string) (types.Scalar, error) {
+	return st.curve.HashToScalar([]byte(label))
+}
+
+func (st *selfTest) encoding() error {
+	priv, err := st.key(selfTestKeyLabel)
+	if err != nil {
+		return err
+	}
+	decoded, err := st.curve.DecodeToScalar(priv.Encode())
+	if err != nil {
+		return err
+	}
+	if !decoded.Eq(priv) {
+		return errors.New("scalar encoding round trip mismatch")
+	}
+
+	pub := st.curve.ScalarBaseMul(priv)
+	decodedPub, err := st.curve.DecodeToPoint(pub.Encode())
+	if err != nil {
+		return err
+	}
+	if !decodedPub.Equals(pub) {
+		return errors.New("point encoding round trip mismatch")
+	}
+	if !st.curve.ScalarBaseMul(priv.Add(priv)).Equals(pub.Add(pub)) {
+		return errors.New("scalar multiplication isn't linear")
+	}
+	return nil
+}
+
+func (st *selfTest) hashToCurve() error {
+	priv, err := st.key(selfTestKeyLabel)
+	if err != nil {
+		return err
+	}
+	pub := st.curve.ScalarBaseMul(priv)
+	hp := hashToCurve(pub)
+	switch {
+	case hp == nil || isIdentity(hp):
+		return errors.New("H_p returned the identity")
+	case !isTorsionFree(hp):
+		return errors.New("H_p isn't in the prime-order subgroup")
+	case !hashToCurve(pub.Copy()).Equals(hp):
+		return errors.New("H_p isn't deterministic")
+	case st.hasVector && hex.EncodeToString(hp.Encode()) != st.vector.hp:
+		return fmt.Errorf("H_p = %x, expected %s", hp.Encode(), st.vector.hp)
+	}
+	return nil
+}
+
+func (st *selfTest) sign() error {
+	var err error
+	if st.privKey, err = st.key(selfTestKeyLabel); err != nil {
+		return err
+	}
+	st.ring, err = NewKeyRingDeterministic(st.curve, selfTestRingSize, st.privKey, selfTestSignerIdx, []byte(selfTestDecoySeed))
+	if err != nil {
+		return err
+	}
+	sig, err := st.ring.Sign(selfTestMsg, st.privKey, WithDeterministicNonces(), WithSelfCheck(false))
+	if err != nil {
+		return err
+	}
+	if err := sig.VerifyWithOpts(selfTestMsg, nil); err != nil {
+		return err
+	}
+	st.sig = sig
+
+	if st.hasVector {
+		enc, err := sig.Serialize()
+		if err != nil {
+			return err
+		}
+		if h := sha3.Sum256(enc); hex.EncodeToString(h[:]) != st.vector.sig {
+			return fmt.Errorf("signature hash %x, expected %s", h, st.vector.sig)
+		}
+	}
+	return nil
+}
+
+// errSelfTestNoSig is returned by the checks that need the signature of the
+// sign-verify check if it failed.
+var errSelfTestNoSig = errors.New("skipped, as sign-verify failed")
+
+func (st *selfTest) reject() error {
+	if st.sig == nil {
+		return errSelfTestNoSig
+	}
+	other := selfTestMsg
+	other[0] ^= 1
+	if st.sig.Verify(other) {
+		return errors.New("signature verified for another message")
+	}
+
+	tampered := *st.sig
+	tampered.s = append([]types.Scalar(nil), st.sig.s...)
+	tampered.s[0] = tampered.s[0].Add(st.curve.ScalarFromInt(1))
+	if tampered.Verify(selfTestMsg) {
+		return errors.New("signature verified with a changed response")
+	}
+	return nil
+}
+
+func (st *selfTest) link() error {
+	if st.sig == nil {
+		return errSelfTestNoSig
+	}
+	// another signature by the same key, over another ring
+	r, err := NewKeyRingDeterministic(st.curve, selfTestRingSize, st.privKey, 0, []byte(selfTestOtherLabel))
+	if err != nil {
+		return err
+	}
+	same, err := r.Sign(selfTestMsg, st.privKey, WithDeterministicNonces(), WithSelfCheck(false))
+	if err != nil {
+		return err
+	}
+	if !Link(st.sig, same) {
+		return errors.New("signatures by the same key don't link")
+	}
+
+	otherKey, err := st.key(selfTestOtherLabel)
+	if err != nil {
+		return err
+	}
+	r, err = NewKeyRingDeterministic(st.curve, selfTestRingSize, otherKey, 0, []byte(selfTestDecoySeed))
+	if err != nil {
+		return err
+	}
+	other, err := r.Sign(selfTestMsg, otherKey, WithDeterministicNonces(), WithSelfCheck(false))
+	if err != nil {
+		return err
+	}
+	if Link(st.sig, other) {
+		return errors.New("signatures by different keys link")
+	}
+	return nil
+}
+
+func (st *selfTest) serialize() error {
+	if st.sig == nil {
+		return errSelfTestNoSig
+	}
+	enc, err := st.sig.Serialize()
+	if err != nil {
+		return err
+	}
+	decoded := new(RingSig)
+	if err := decoded.Deserialize(st.curve, enc); err != nil {
+		return err
+	}
+	reenc, err := decoded.Serialize()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(enc, reenc) {
+		return errors.New("serialization round trip mismatch")
+	}
+	if !decoded.Verify(selfTestMsg) {
+		return errors.New("deserialized signature doesn't verify")
+	}
+	return nil
+}
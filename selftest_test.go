@@ -0,0 +1,48 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	for _, curve := range []types.Curve{Secp256k1(), Ed25519()} {
+		report := SelfTest(curve)
+		require.NoError(t, report.Err())
+		require.True(t, report.Passed())
+		require.Equal(t, CurveIDOf(curve).String(), report.Curve)
+
+		var names []string
+		for _, res := range report.Results {
+			names = append(names, res.Name)
+			require.Equal(t, res.Name == SelfTestHashToCurve || res.Name == SelfTestSign, res.KnownAnswer, res.Name)
+		}
+		require.Equal(t, []string{SelfTestEncoding, SelfTestHashToCurve, SelfTestSign, SelfTestReject, SelfTestLink, SelfTestSerialize}, names)
+	}
+}
+
+func TestSelfTest_RegisteredCurve(t *testing.T) {
+	registerTestCurve()
+	report := SelfTest(&wrappedCurve{Curve: Ed25519()})
+	require.NoError(t, report.Err())
+	for _, res := range report.Results {
+		require.False(t, res.KnownAnswer)
+	}
+}
+
+func TestSelfTest_KnownAnswerMismatch(t *testing.T) {
+	saved := selfTestVectors[CurveEd25519]
+	defer func() { selfTestVectors[CurveEd25519] = saved }()
+	selfTestVectors[CurveEd25519] = selfTestVector{hp: saved.hp, sig: "00"}
+
+	report := SelfTest(Ed25519())
+	require.False(t, report.Passed())
+	require.ErrorContains(t, report.Err(), "ed25519 self-test sign-verify: signature hash")
+	for _, res := range report.Results {
+		if res.Name != SelfTestSign {
+			require.NoError(t, res.Err, res.Name)
+		}
+	}
+}
@@ -8,47 +8,140 @@ import (
 	"github.com/athanorlabs/go-dleq/types"
 )
 
+// MaxRingSize is the largest ring size supported by the signature encoding.
+// The ring size is encoded in the low 24 bits of the 4-byte header; the top
+// byte holds format flags indicating which extension fields are present.
+const MaxRingSize = 1<<24 - 1
+
 // Serialize converts the signature to a byte array.
 func (r *RingSig) Serialize() ([]byte, error) {
+	return r.SerializeWith()
+}
+
+// SerializeOption is an option for SerializeWith.
+type SerializeOption func(*serializeOptions)
+
+type serializeOptions struct {
+	encoding PointEncoding
+}
+
+// WithPointEncoding sets the encoding of the points of the serialized
+// signature. The default is PointCompressed. The encoding is signalled in the
+// header, so Deserialize accepts all of them.
+func WithPointEncoding(enc PointEncoding) SerializeOption {
+	return func(o *serializeOptions) {
+		o.encoding = enc
+	}
+}
+
+// SerializeWith converts the signature to a byte array, with the given
+// options.
+func (r *RingSig) SerializeWith(opts ...SerializeOption) ([]byte, error) {
+	var o serializeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if len(r.ring.pubkeys) > MaxRingSize {
+		return nil, errors.New("ring size exceeds MaxRingSize")
+	}
+
+	if o.encoding == PointCompressed {
+		return r.encode(), nil
+	}
+	return r.encodeWith(o.encoding)
+}
+
+// encodeWith encodes the signature with the given point encoding.
+func (r *RingSig) encodeWith(enc PointEncoding) ([]byte, error) {
+	curve := r.ring.curve
+	pointLen, err := enc.pointLen(curve)
+	if err != nil {
+		return nil, err
+	}
+
+	size := len(r.ring.pubkeys)
+	ext := r.ext.encode()
+	sig := make([]byte, 4, 4+32+pointLen+len(ext)+size*(32+pointLen))
+	flags := byte(enc)<<encodingShift | r.ext.flags()
+	binary.BigEndian.PutUint32(sig, uint32(flags)<<24|uint32(size))
+	sig = append(sig, r.c.Encode()...)
+	if sig, err = enc.appendPoint(sig, curve, r.image); err != nil {
+		return nil, err
+	}
+	sig = append(sig, ext...)
+
+	for i := 0; i < size; i++ {
+		sig = append(sig, r.s[i].Encode()...)
+		if sig, err = enc.appendPoint(sig, curve, r.ring.pubkeys[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return sig, nil
+}
+
+func (r *RingSig) encode() []byte {
 	sig := []byte{}
 	size := len(r.ring.pubkeys)
 
 	b := make([]byte, 4)
-	binary.BigEndian.PutUint32(b, uint32(size))
+	binary.BigEndian.PutUint32(b, uint32(r.ext.flags())<<24|uint32(size))
 	sig = append(sig, b[:]...)
 	sig = append(sig, r.c.Encode()...)
-	sig = append(sig, r.image.Encode()...)
+	sig = append(sig, encodePoint(r.image)...)
+	sig = append(sig, r.ext.encode()...)
 
 	for i := 0; i < size; i++ {
 		sig = append(sig, r.s[i].Encode()...)
-		sig = append(sig, r.ring.pubkeys[i].Encode()...)
+		sig = append(sig, encodePoint(r.ring.pubkeys[i])...)
 	}
 
-	return sig, nil
+	return sig
 }
 
 // Deserialize converts the byteified signature into a *RingSig.
 func (sig *RingSig) Deserialize(curve Curve, in []byte) error {
+	if len(in) < 4 {
+		return errors.New("input too short")
+	}
+
 	reader := bytes.NewBuffer(in)
-	pointLen := curve.CompressedPointSize()
 
-	size := binary.BigEndian.Uint32(reader.Next(4))
-	if len(in) < int(size)*pointLen {
-		return errors.New("input too short")
+	header := binary.BigEndian.Uint32(reader.Next(4))
+	enc, flags, err := splitFlags(byte(header >> 24))
+	if err != nil {
+		return err
 	}
+	pointLen, err := enc.pointLen(curve)
+	if err != nil {
+		return err
+	}
+	size := int(header & MaxRingSize)
+	extLen := extensionsLen(flags)
 
-	// WARN: this assumes the groups have an encoded scalar length of 32!
-	// which is fine for ed25519 and secp256k1, but may need to be changed
-	// if other curves are added.
-	const scalarLen = 32
+	scalarLen := ScalarSize(curve)
+
+	// the size is at most 2^24, so this can't overflow
+	expected := 4 + scalarLen + pointLen + extLen + size*(scalarLen+pointLen)
+	if len(in) < expected {
+		return errors.New("input too short")
+	}
+	if len(in) > expected {
+		return errors.New("input too long")
+	}
 
-	var err error
 	sig.c, err = curve.DecodeToScalar(reader.Next(scalarLen))
 	if err != nil {
 		return err
 	}
 
-	sig.image, err = curve.DecodeToPoint(reader.Next(pointLen))
+	sig.image, err = enc.decodePoint(curve, reader.Next(pointLen))
+	if err != nil {
+		return err
+	}
+
+	sig.ext, err = decodeExtensions(flags, reader.Next(extLen))
 	if err != nil {
 		return err
 	}
@@ -59,13 +152,13 @@ func (sig *RingSig) Deserialize(curve Curve, in []byte) error {
 	}
 	sig.s = make([]types.Scalar, size)
 
-	for i := 0; i < int(size); i++ {
+	for i := 0; i < size; i++ {
 		sig.s[i], err = curve.DecodeToScalar(reader.Next(scalarLen))
 		if err != nil {
 			return err
 		}
 
-		sig.ring.pubkeys[i], err = curve.DecodeToPoint(reader.Next(pointLen))
+		sig.ring.pubkeys[i], err = enc.decodePoint(curve, reader.Next(pointLen))
 		if err != nil {
 			return err
 		}
@@ -54,3 +54,34 @@ func TestSerializeAndDeserialize_Ed25519(t *testing.T) {
 		testSerializeAndDeserialize(t, curve, i, int(idx.Int64()))
 	}
 }
+
+func TestDeserialize_InvalidLength(t *testing.T) {
+	curve := Secp256k1()
+	sig := createSig(t, 3, 1)
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+
+	res := new(RingSig)
+	require.EqualError(t, res.Deserialize(curve, enc[:len(enc)-1]), "input too short")
+	require.EqualError(t, res.Deserialize(curve, append(enc, 0)), "input too long")
+	require.EqualError(t, res.Deserialize(curve, enc[:3]), "input too short")
+	require.NoError(t, res.Deserialize(curve, enc))
+}
+
+func TestDeserialize_ReservedFlags(t *testing.T) {
+	// all the bits of the header byte outside the point encoding are
+	// assigned; new extensions need a new header format
+	require.Equal(t, byte(0xff&^encodingMask), knownFlags)
+
+	// the tagged hash flag decodes on any curve, but only verifies on
+	// secp256k1
+	curve := Ed25519()
+	sig := createSigWithCurve(t, curve, 2, 0)
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+
+	enc[0] = flagTaggedHash
+	res := new(RingSig)
+	require.NoError(t, res.Deserialize(curve, enc))
+	require.False(t, res.Verify(testMsg))
+}
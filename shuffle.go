@@ -0,0 +1,136 @@
+package ring
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	shuffleCommitDomain = "ring-go/shuffle/commit"
+	shuffleDomain       = "ring-go/shuffle"
+)
+
+// ShuffleProof proves that a ring's order was derived from a seed committed to
+// in advance, so that a ring construction service can show it didn't bias the
+// position of any member, in particular the signer's.
+//
+// The service generates a seed with NewShuffleProof and publishes its
+// Commitment before learning which ring it'll shuffle (or who will sign with
+// it), then shuffles the ring with Shuffle and reveals the proof. Anyone can
+// then check the shuffled ring with VerifyShuffle. To also prevent the service
+// from choosing a seed favorable to it in advance, the seed can be derived
+// from a public randomness beacon value published after the commitment, eg.
+// with ShuffleProof{Seed: sha3.Sum256(append(secret, beacon...))}.
+type ShuffleProof struct {
+	Seed [32]byte
+}
+
+// NewShuffleProof returns a proof with a seed read from rand.
+func NewShuffleProof(rand io.Reader) (*ShuffleProof, error) {
+	p := &ShuffleProof{}
+	if _, err := io.ReadFull(rand, p.Seed[:]); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Commitment returns the commitment to the proof's seed, to be published
+// before shuffling.
+func (p *ShuffleProof) Commitment() [32]byte {
+	return sha3.Sum256(append([]byte(shuffleCommitDomain), p.Seed[:]...))
+}
+
+// Shuffle returns a new ring with the ring's public keys in an order derived
+// from the proof's seed. The order doesn't depend on the order of the keys in
+// the ring, only on the set of keys and the seed. The ring itself is not
+// modified.
+func (r *Ring) Shuffle(proof *ShuffleProof) *Ring {
+	r.ensureHP()
+
+	return r.permute(shufflePermutation(r.pubkeys, proof.Seed))
+}
+
+// ShuffledCopy returns a copy of the ring with its public keys in a uniformly
+// random order read from rand, and the mapping of its indices: the i-th
+// public key of the returned ring is the perm[i]-th public key of r. If rand
+// is nil, crypto/rand.Reader is used.
+//
+// Rings built with the signer at a known position, eg. by
+// NewKeyRingFromPublicKeys, should be shuffled before signing, as the position
+// of the signer would otherwise reveal it to anyone who knows the convention.
+func (r *Ring) ShuffledCopy(rand io.Reader) (shuffled *Ring, perm []int, err error) {
+	if rand == nil {
+		rand = crand.Reader
+	}
+
+	perm = make([]int, len(r.pubkeys))
+	for i := range perm {
+		perm[i] = i
+	}
+	for i := len(perm) - 1; i > 0; i-- {
+		j, err := uniformIndex(rand, uint64(i)+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		perm[i], perm[j] = perm[j], perm[i]
+	}
+
+	return r.permute(perm), perm, nil
+}
+
+// VerifyShuffle returns true if shuffled is the result of shuffling a ring
+// with the same public keys as original with the proof, and the proof matches
+// the commitment.
+func VerifyShuffle(original, shuffled *Ring, proof *ShuffleProof, commitment [32]byte) bool {
+	if proof.Commitment() != commitment {
+		return false
+	}
+
+	if original.Size() != shuffled.Size() || !sameCurve(original.curve, shuffled.curve) {
+		return false
+	}
+
+	return original.Shuffle(proof).Equals(shuffled)
+}
+
+// shufflePermutation returns a permutation of the indices of pubkeys: a
+// Fisher-Yates shuffle of the keys in canonical order (sorted by encoding),
+// driven by a SHAKE256 stream keyed with the seed and the canonical ring.
+func shufflePermutation(pubkeys []types.Point, seed [32]byte) []int {
+	perm, encs := canonicalOrder(pubkeys)
+
+	stream := sha3.NewShake256()
+	_, _ = stream.Write([]byte(shuffleDomain))
+	_, _ = stream.Write(seed[:])
+	for _, i := range perm {
+		_, _ = stream.Write(encs[i])
+	}
+
+	for i := len(perm) - 1; i > 0; i-- {
+		// reading from a SHAKE stream never fails
+		j, _ := uniformIndex(stream, uint64(i)+1)
+		perm[i], perm[j] = perm[j], perm[i]
+	}
+	return perm
+}
+
+// uniformIndex returns a uniformly distributed integer in [0, n) read from
+// the stream, using rejection sampling.
+func uniformIndex(stream io.Reader, n uint64) (uint64, error) {
+	// the largest multiple of n that fits in a uint64
+	limit := ^uint64(0) - ^uint64(0)%n
+	var buf [8]byte
+	for {
+		if _, err := io.ReadFull(stream, buf[:]); err != nil {
+			return 0, err
+		}
+		v := binary.BigEndian.Uint64(buf[:])
+		if v < limit {
+			return v % n, nil
+		}
+	}
+}
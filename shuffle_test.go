@@ -0,0 +1,108 @@
+package ring
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShuffle(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 16, privKey, 0)
+		require.NoError(t, err)
+
+		proof, err := NewShuffleProof(rand.Reader)
+		require.NoError(t, err)
+		commitment := proof.Commitment()
+
+		shuffled := keyring.Shuffle(proof)
+		require.Equal(t, keyring.Size(), shuffled.Size())
+		require.False(t, shuffled.Equals(keyring))
+		require.True(t, VerifyShuffle(keyring, shuffled, proof, commitment))
+
+		// the order doesn't depend on the input order
+		require.True(t, shuffled.Equals(shuffled.Shuffle(proof)))
+		require.True(t, VerifyShuffle(shuffled, shuffled, proof, commitment))
+
+		sig, err := shuffled.Sign(testMsg, privKey)
+		require.NoError(t, err)
+		require.True(t, sig.Verify(testMsg))
+
+		// a different seed, commitment or ring doesn't verify
+		other, err := NewShuffleProof(rand.Reader)
+		require.NoError(t, err)
+		require.False(t, VerifyShuffle(keyring, shuffled, other, other.Commitment()))
+		require.False(t, VerifyShuffle(keyring, shuffled, proof, other.Commitment()))
+		require.False(t, VerifyShuffle(keyring, keyring, proof, commitment))
+
+		padded, err := keyring.PadTo(17, StaticDecoys(randomPubkeys(curve, 1)))
+		require.NoError(t, err)
+		require.False(t, VerifyShuffle(padded, shuffled, proof, commitment))
+	}
+}
+
+func TestShufflePermutation_Uniform(t *testing.T) {
+	curve := Ed25519()
+	keyring, err := NewKeyRing(curve, 3, curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+
+	// each of the 6 orders of a 3-member ring should be about equally likely
+	const trials = 6000
+	counts := make(map[[3]int]int)
+	for i := 0; i < trials; i++ {
+		proof, err := NewShuffleProof(rand.Reader)
+		require.NoError(t, err)
+		perm := shufflePermutation(keyring.pubkeys, proof.Seed)
+		counts[[3]int{perm[0], perm[1], perm[2]}]++
+	}
+
+	require.Len(t, counts, 6)
+	for _, n := range counts {
+		require.InDelta(t, trials/6, n, 200)
+	}
+}
+
+func TestShuffledCopy(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 16, privKey, 0)
+	require.NoError(t, err)
+	keyring.Precompute()
+
+	shuffled, perm, err := keyring.ShuffledCopy(nil)
+	require.NoError(t, err)
+	require.True(t, shuffled.EqualsSet(keyring))
+	for i, j := range perm {
+		require.True(t, shuffled.pubkeys[i].Equals(keyring.pubkeys[j]))
+	}
+
+	sig, err := shuffled.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	require.True(t, sig.Verify(testMsg))
+
+	_, _, err = keyring.ShuffledCopy(iotest.ErrReader(errors.New("no randomness")))
+	require.EqualError(t, err, "no randomness")
+}
+
+func TestShuffledCopy_SignerPosition(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 0)
+	require.NoError(t, err)
+
+	positions := make(map[int]bool)
+	for i := 0; i < 100 && len(positions) < 4; i++ {
+		_, perm, err := keyring.ShuffledCopy(rand.Reader)
+		require.NoError(t, err)
+		for pos, j := range perm {
+			if j == 0 {
+				positions[pos] = true
+			}
+		}
+	}
+	require.Len(t, positions, 4)
+}
@@ -0,0 +1,111 @@
+package ring
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// SignerOpts are the options accepted by Signer.Sign. They implement
+// crypto.SignerOpts.
+type SignerOpts struct {
+	// Hash is the hash function used to compute the digest, returned by
+	// HashFunc. If set, the digest's length must match its size.
+	Hash crypto.Hash
+
+	// Ring is the ring to sign with. If nil, the signer's ring is used.
+	Ring *Ring
+
+	// Options are passed to Sign.
+	Options []SignOption
+}
+
+// HashFunc returns o.Hash.
+func (o *SignerOpts) HashFunc() crypto.Hash {
+	return o.Hash
+}
+
+// Signer implements crypto.Signer with ring signatures, so that a ring member's
+// private key can be used with APIs that accept a crypto.Signer. The
+// signatures it returns are serialized RingSigs, which are verified by
+// deserializing them and calling Verify with the digest.
+type Signer struct {
+	ring    *Ring
+	privKey types.Scalar
+	pubkey  types.Point
+}
+
+var _ crypto.Signer = (*Signer)(nil)
+
+// NewSigner returns a Signer for the given private key, which signs with the
+// given ring unless another one is passed in SignerOpts. The key must be a
+// member of the ring.
+func NewSigner(ring *Ring, privKey types.Scalar) (*Signer, error) {
+	if ring == nil {
+		return nil, errors.New("ring is nil")
+	}
+
+	pubkey := ring.curve.ScalarBaseMul(privKey)
+	if _, ok := ring.IndexOf(pubkey); !ok {
+		return nil, ErrSignerNotInRing
+	}
+
+	return &Signer{
+		ring:    ring,
+		privKey: privKey,
+		pubkey:  pubkey,
+	}, nil
+}
+
+// Public returns the signer's public key, as a Point. Note that ring signatures
+// are verified against the ring rather than this key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.pubkey.Copy()
+}
+
+// Ring returns the ring the signer signs with by default.
+func (s *Signer) Ring() *Ring {
+	return s.ring
+}
+
+// Sign creates a ring signature over the 32-byte digest and returns it
+// serialized. If opts is a *SignerOpts, its ring and sign options are used;
+// otherwise, opts is only used to check the digest length. rand is ignored,
+// as signing uses the curve's source of randomness.
+func (s *Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	ring := s.ring
+	var (
+		hash     crypto.Hash
+		signOpts []SignOption
+	)
+	switch o := opts.(type) {
+	case *SignerOpts:
+		if o != nil {
+			if o.Ring != nil {
+				ring = o.Ring
+			}
+			hash = o.Hash
+			signOpts = o.Options
+		}
+	case nil:
+	default:
+		hash = o.HashFunc()
+	}
+
+	if hash != 0 && len(digest) != hash.Size() {
+		return nil, fmt.Errorf("digest length %d does not match hash function %s", len(digest), hash)
+	}
+	if len(digest) != 32 {
+		return nil, fmt.Errorf("digest must be 32 bytes, got %d", len(digest))
+	}
+
+	sig, err := ring.Sign([32]byte(digest), s.privKey, signOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return sig.Serialize()
+}
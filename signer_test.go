@@ -0,0 +1,87 @@
+package ring
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 8, privKey, 5)
+		require.NoError(t, err)
+
+		signer, err := NewSigner(keyring, privKey)
+		require.NoError(t, err)
+		require.True(t, signer.Public().(Point).Equals(curve.ScalarBaseMul(privKey)))
+
+		var cs crypto.Signer = signer
+		digest := sha256.Sum256([]byte("hello"))
+		enc, err := cs.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.NoError(t, err)
+
+		sig := new(RingSig)
+		require.NoError(t, sig.Deserialize(curve, enc))
+		require.True(t, sig.Verify(digest))
+		require.True(t, sig.Ring().Equals(keyring))
+	}
+}
+
+func TestSigner_Opts(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 0)
+	require.NoError(t, err)
+	other, err := NewKeyRing(curve, 6, privKey, 3)
+	require.NoError(t, err)
+
+	signer, err := NewSigner(keyring, privKey)
+	require.NoError(t, err)
+
+	notAfter := time.Now().Add(time.Hour)
+	enc, err := signer.Sign(nil, testMsg[:], &SignerOpts{
+		Ring:    other,
+		Options: []SignOption{WithValidity(time.Time{}, notAfter)},
+	})
+	require.NoError(t, err)
+
+	sig := new(RingSig)
+	require.NoError(t, sig.Deserialize(curve, enc))
+	require.True(t, sig.Ring().Equals(other))
+	require.True(t, sig.Verify(testMsg))
+	_, gotNotAfter, ok := sig.Validity()
+	require.True(t, ok)
+	require.Equal(t, notAfter.Unix(), gotNotAfter.Unix())
+}
+
+func TestSigner_Errors(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 0)
+	require.NoError(t, err)
+
+	_, err = NewSigner(nil, privKey)
+	require.Error(t, err)
+	_, err = NewSigner(keyring, curve.NewRandomScalar())
+	require.Error(t, err)
+
+	signer, err := NewSigner(keyring, privKey)
+	require.NoError(t, err)
+
+	_, err = signer.Sign(nil, make([]byte, 64), crypto.SHA512)
+	require.Error(t, err)
+	_, err = signer.Sign(nil, make([]byte, 32), crypto.SHA512)
+	require.Error(t, err)
+	_, err = signer.Sign(nil, make([]byte, 31), nil)
+	require.Error(t, err)
+
+	other, err := NewKeyRing(curve, 4, curve.NewRandomScalar(), 0)
+	require.NoError(t, err)
+	_, err = signer.Sign(nil, testMsg[:], &SignerOpts{Ring: other})
+	require.Error(t, err)
+}
@@ -0,0 +1,203 @@
+package ring
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// SigReader reads a serialized signature one ring member at a time, so that
+// pipelines validating or transforming signatures with large rings never hold
+// the whole decoded signature in memory. NewSigReader reads the header, the
+// first challenge, the key image and the extensions; Next then returns the
+// members in order:
+//
+//	sr, err := ring.NewSigReader(curve, r)
+//	...
+//	for {
+//		s, pub, err := sr.Next()
+//		if err == io.EOF {
+//			break
+//		}
+//		...
+//	}
+//
+// It doesn't verify the signature; see VerifyStream.
+type SigReader struct {
+	curve     types.Curve
+	br        *bufio.Reader
+	enc       PointEncoding
+	scalarLen int
+	pointLen  int
+	size      int
+	next      int
+	buf       []byte
+
+	c     types.Scalar
+	image types.Point
+	ext   extensions
+}
+
+// NewSigReader reads the beginning of the serialized signature over the curve
+// from r, up to its first ring member. It returns an error wrapping
+// ErrInvalidSignature if the ring is empty.
+func NewSigReader(curve types.Curve, r io.Reader) (_ *SigReader, err error) {
+	defer recoverInternal(&err)
+
+	sr := &SigReader{
+		curve:     curve,
+		br:        bufio.NewReader(r),
+		scalarLen: ScalarSize(curve),
+	}
+
+	var header [4]byte
+	if _, err := io.ReadFull(sr.br, header[:]); err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	var flags byte
+	if sr.enc, flags, err = splitFlags(header[0]); err != nil {
+		return nil, err
+	}
+	if sr.pointLen, err = sr.enc.pointLen(curve); err != nil {
+		return nil, err
+	}
+	sr.size = int(binary.BigEndian.Uint32(header[:]) & MaxRingSize)
+	if sr.size == 0 {
+		return nil, fmt.Errorf("%w: empty ring", ErrInvalidSignature)
+	}
+	sr.buf = make([]byte, sr.scalarLen+max(sr.pointLen, extensionsLen(flags)))
+
+	b, err := sr.read(sr.scalarLen)
+	if err != nil {
+		return nil, err
+	}
+	if sr.c, err = curve.DecodeToScalar(b); err != nil {
+		return nil, err
+	}
+
+	if b, err = sr.read(sr.pointLen); err != nil {
+		return nil, err
+	}
+	if sr.image, err = sr.enc.decodePoint(curve, b); err != nil {
+		return nil, err
+	}
+
+	if b, err = sr.read(extensionsLen(flags)); err != nil {
+		return nil, err
+	}
+	if sr.ext, err = decodeExtensions(flags, b); err != nil {
+		return nil, err
+	}
+	return sr, nil
+}
+
+// read reads the next n bytes into the reader's buffer, which they're only
+// valid until the next read.
+func (sr *SigReader) read(n int) ([]byte, error) {
+	if _, err := io.ReadFull(sr.br, sr.buf[:n]); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return sr.buf[:n], nil
+}
+
+// Size returns the size of the signature's ring.
+func (sr *SigReader) Size() int {
+	return sr.size
+}
+
+// Index returns the index of the ring member returned by the next call to
+// Next, which is Size once all the members are read.
+func (sr *SigReader) Index() int {
+	return sr.next
+}
+
+// Encoding returns the encoding of the signature's points.
+func (sr *SigReader) Encoding() PointEncoding {
+	return sr.enc
+}
+
+// Challenge returns the first challenge of the signature, c_0.
+func (sr *SigReader) Challenge() types.Scalar {
+	return sr.c
+}
+
+// KeyImage returns the key image of the signature. It isn't checked for
+// small-order components, as by Verify.
+func (sr *SigReader) KeyImage() types.Point {
+	return sr.image.Copy()
+}
+
+// ValidAt returns whether t is within the signature's validity window, if it
+// has one (see WithValidity), or true otherwise.
+func (sr *SigReader) ValidAt(t time.Time) bool {
+	return sr.ext.validity == nil || sr.ext.validity.contains(t)
+}
+
+// Next returns the response and the public key of the next ring member. Once
+// all the members are read, it returns io.EOF, or an error if there's data
+// after the signature.
+func (sr *SigReader) Next() (s types.Scalar, pub types.Point, err error) {
+	defer recoverInternal(&err)
+
+	s, b, err := sr.nextEncoded()
+	if err != nil {
+		return nil, nil, err
+	}
+	if pub, err = sr.enc.decodePoint(sr.curve, b); err != nil {
+		return nil, nil, fmt.Errorf("ring member %d: %w", sr.next-1, err)
+	}
+	return s, pub, nil
+}
+
+// nextEncoded is like Next, but returns the public key as encoded in the
+// signature, which is only valid until the next read.
+func (sr *SigReader) nextEncoded() (types.Scalar, []byte, error) {
+	if sr.next == sr.size {
+		return nil, nil, sr.end()
+	}
+
+	b, err := sr.read(sr.scalarLen + sr.pointLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := sr.curve.DecodeToScalar(b[:sr.scalarLen])
+	if err != nil {
+		return nil, nil, fmt.Errorf("ring member %d: %w", sr.next, err)
+	}
+	sr.next++
+	return s, b[sr.scalarLen:], nil
+}
+
+// Skip skips the next n ring members without decoding them, eg. to resume
+// reading from a given member when paginating over the signature.
+func (sr *SigReader) Skip(n int) error {
+	if n < 0 || n > sr.size-sr.next {
+		return fmt.Errorf("can't skip %d of the %d remaining members", n, sr.size-sr.next)
+	}
+	if _, err := sr.br.Discard(n * (sr.scalarLen + sr.pointLen)); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	sr.next += n
+	return nil
+}
+
+// end returns io.EOF if the input ends after the signature.
+func (sr *SigReader) end() error {
+	if _, err := sr.br.ReadByte(); err == nil {
+		return errors.New("input too long")
+	} else if err != io.EOF {
+		return err
+	}
+	return io.EOF
+}
@@ -0,0 +1,138 @@
+package ring
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigReader(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		sig := createSigWithCurve(t, curve, 7, 3)
+		for _, enc := range []PointEncoding{PointCompressed, PointUncompressed} {
+			data, err := sig.SerializeWith(WithPointEncoding(enc))
+			require.NoError(t, err)
+
+			sr, err := NewSigReader(curve, bytes.NewReader(data))
+			require.NoError(t, err)
+			require.Equal(t, 7, sr.Size())
+			require.Equal(t, enc, sr.Encoding())
+			require.True(t, sr.Challenge().Eq(sig.c))
+			require.True(t, sr.KeyImage().Equals(sig.KeyImage()))
+			require.True(t, sr.ValidAt(time.Now()))
+
+			for i := 0; i < 7; i++ {
+				require.Equal(t, i, sr.Index())
+				s, pub, err := sr.Next()
+				require.NoError(t, err)
+				require.True(t, s.Eq(sig.s[i]))
+				require.True(t, pub.Equals(sig.PublicKeys()[i]))
+			}
+			require.Equal(t, 7, sr.Index())
+			_, _, err = sr.Next()
+			require.Equal(t, io.EOF, err)
+			_, _, err = sr.Next()
+			require.Equal(t, io.EOF, err)
+		}
+	}
+}
+
+func TestSigReader_Skip(t *testing.T) {
+	curve := Secp256k1()
+	sig := createSigWithCurve(t, curve, 10, 4)
+	data, err := sig.Serialize()
+	require.NoError(t, err)
+
+	// read the members in pages of 4
+	const pageSize = 4
+	for start := 0; start < 10; start += pageSize {
+		sr, err := NewSigReader(curve, bytes.NewReader(data))
+		require.NoError(t, err)
+		require.NoError(t, sr.Skip(start))
+		for i := start; i < min(start+pageSize, 10); i++ {
+			require.Equal(t, i, sr.Index())
+			s, pub, err := sr.Next()
+			require.NoError(t, err)
+			require.True(t, s.Eq(sig.s[i]))
+			require.True(t, pub.Equals(sig.PublicKeys()[i]))
+		}
+	}
+
+	sr, err := NewSigReader(curve, bytes.NewReader(data))
+	require.NoError(t, err)
+	require.EqualError(t, sr.Skip(11), "can't skip 11 of the 10 remaining members")
+	require.Error(t, sr.Skip(-1))
+	require.NoError(t, sr.Skip(10))
+	_, _, err = sr.Next()
+	require.Equal(t, io.EOF, err)
+
+	sr, err = NewSigReader(curve, bytes.NewReader(data[:len(data)-1]))
+	require.NoError(t, err)
+	require.ErrorIs(t, sr.Skip(10), io.ErrUnexpectedEOF)
+}
+
+func TestSigReader_Malformed(t *testing.T) {
+	curve := Ed25519()
+	sig := createSigWithCurve(t, curve, 3, 1)
+	data, err := sig.Serialize()
+	require.NoError(t, err)
+
+	for _, in := range [][]byte{nil, data[:3], data[:4+32+31]} {
+		_, err := NewSigReader(curve, bytes.NewReader(in))
+		require.Error(t, err)
+	}
+
+	empty := append([]byte{}, data...)
+	copy(empty, []byte{0, 0, 0, 0})
+	_, err = NewSigReader(curve, bytes.NewReader(empty))
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	// truncated member
+	sr, err := NewSigReader(curve, bytes.NewReader(data[:len(data)-1]))
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, _, err = sr.Next()
+		require.NoError(t, err)
+	}
+	_, _, err = sr.Next()
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// trailing data
+	sr, err = NewSigReader(curve, bytes.NewReader(append(append([]byte{}, data...), 0)))
+	require.NoError(t, err)
+	require.NoError(t, sr.Skip(3))
+	_, _, err = sr.Next()
+	require.EqualError(t, err, "input too long")
+
+	// invalid public key
+	secp := Secp256k1()
+	data, err = createSigWithCurve(t, secp, 3, 1).Serialize()
+	require.NoError(t, err)
+	data[len(data)-33] = 0x05
+	sr, err = NewSigReader(secp, bytes.NewReader(data))
+	require.NoError(t, err)
+	require.NoError(t, sr.Skip(2))
+	_, _, err = sr.Next()
+	require.ErrorContains(t, err, "ring member 2")
+}
+
+func TestSigReader_Validity(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 3, privKey, 2)
+	require.NoError(t, err)
+
+	notAfter := time.Now().Add(time.Hour)
+	sig, err := keyring.Sign(testMsg, privKey, WithValidity(time.Time{}, notAfter))
+	require.NoError(t, err)
+	data, err := sig.Serialize()
+	require.NoError(t, err)
+
+	sr, err := NewSigReader(curve, bytes.NewReader(data))
+	require.NoError(t, err)
+	require.True(t, sr.ValidAt(time.Now()))
+	require.False(t, sr.ValidAt(notAfter.Add(time.Second)))
+}
@@ -0,0 +1,79 @@
+package ring
+
+import (
+	"time"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// RingStats summarizes how a ring has been used, for deciding when to rotate
+// it. See Ring.Stats.
+type RingStats struct {
+	// Size is the number of members of the ring.
+	Size int
+	// Signatures is the number of signatures over the ring, and Ignored the
+	// number of those passed to Stats that were over another ring.
+	Signatures, Ignored int
+	// DistinctSigners is the number of distinct key images, ie. of members
+	// that have signed.
+	DistinctSigners int
+	// AnonymityMargin is Size - DistinctSigners: the number of members that
+	// haven't signed yet. A member's first signature carries a new key image,
+	// which tells observers that the signer is one of these members, so the
+	// anonymity set of new signers shrinks as the ring is used.
+	AnonymityMargin int
+	// LastUsed is the latest validity start of the signatures that have a
+	// validity window with a start, which is usually their signing time. It's
+	// zero if there are none.
+	LastUsed time.Time
+	// Signers describes the usage of each key image, in order of first
+	// appearance.
+	Signers []SignerStats
+}
+
+// SignerStats describes the signatures made by the same member.
+type SignerStats struct {
+	// KeyImage is the normalized key image of the member.
+	KeyImage types.Point
+	// Count is the number of signatures carrying the key image.
+	Count int
+	// Last is the index, in the slice passed to Stats, of the last signature
+	// carrying the key image.
+	Last int
+}
+
+// Stats summarizes the usage of the ring by the given signatures, which are
+// usually all the signatures accepted over it. Signatures over other rings
+// (see Equals) are counted as ignored. Stats doesn't verify the signatures,
+// which should have been verified when they were accepted.
+func (r *Ring) Stats(sigs []*RingSig) *RingStats {
+	stats := &RingStats{Size: r.Size()}
+	signers := make(map[string]int)
+
+	for i, sig := range sigs {
+		if sig == nil || !sig.ring.Equals(r) {
+			stats.Ignored++
+			continue
+		}
+		stats.Signatures++
+
+		if notBefore, _, ok := sig.Validity(); ok && notBefore.After(stats.LastUsed) {
+			stats.LastUsed = notBefore
+		}
+
+		image := NormalizeKeyImage(sig.image)
+		key := string(encodePoint(image))
+		j, ok := signers[key]
+		if !ok {
+			j = len(stats.Signers)
+			signers[key] = j
+			stats.Signers = append(stats.Signers, SignerStats{KeyImage: image})
+		}
+		stats.Signers[j].Count++
+		stats.Signers[j].Last = i
+	}
+
+	stats.DistinctSigners = len(stats.Signers)
+	stats.AnonymityMargin = max(stats.Size-stats.DistinctSigners, 0)
+	return stats
+}
@@ -0,0 +1,54 @@
+package ring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRing_Stats(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKeys := []Scalar{curve.NewRandomScalar(), curve.NewRandomScalar()}
+		pubkeys := []Point{curve.ScalarBaseMul(privKeys[0]), curve.ScalarBaseMul(privKeys[1])}
+		for i := 0; i < 3; i++ {
+			pubkeys = append(pubkeys, curve.ScalarBaseMul(curve.NewRandomScalar()))
+		}
+		keyring, err := NewFixedKeyRingFromPublicKeys(curve, pubkeys)
+		require.NoError(t, err)
+
+		stats := keyring.Stats(nil)
+		require.Equal(t, 5, stats.Size)
+		require.Equal(t, 5, stats.AnonymityMargin)
+		require.True(t, stats.LastUsed.IsZero())
+
+		t1 := time.Unix(1700000000, 0)
+		t2 := t1.Add(time.Hour)
+		sig0, err := keyring.Sign(testMsg, privKeys[0], WithValidity(t2, time.Time{}))
+		require.NoError(t, err)
+		sig1, err := keyring.Sign(testMsg, privKeys[1], WithValidity(t1, time.Time{}))
+		require.NoError(t, err)
+		sig2, err := keyring.Sign([32]byte{1}, privKeys[0])
+		require.NoError(t, err)
+
+		other, err := NewKeyRing(curve, 5, privKeys[0], 0)
+		require.NoError(t, err)
+		sig3, err := other.Sign(testMsg, privKeys[0])
+		require.NoError(t, err)
+
+		stats = keyring.Stats([]*RingSig{sig0, sig1, sig3, sig2})
+		require.Equal(t, 5, stats.Size)
+		require.Equal(t, 3, stats.Signatures)
+		require.Equal(t, 1, stats.Ignored)
+		require.Equal(t, 2, stats.DistinctSigners)
+		require.Equal(t, 3, stats.AnonymityMargin)
+		require.True(t, t2.Equal(stats.LastUsed))
+
+		require.Len(t, stats.Signers, 2)
+		require.True(t, stats.Signers[0].KeyImage.Equals(NormalizeKeyImage(sig0.KeyImage())))
+		require.Equal(t, 2, stats.Signers[0].Count)
+		require.Equal(t, 3, stats.Signers[0].Last)
+		require.Equal(t, 1, stats.Signers[1].Count)
+		require.Equal(t, 1, stats.Signers[1].Last)
+	}
+}
@@ -0,0 +1,87 @@
+package ring
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// MemberFunc returns the public key P_i and H_p(P_i) of the ring member with
+// the given index and encoded public key, as read from a signature by
+// VerifyStream. It can serve them from a cache or precomputed storage, and
+// reject the signature (by returning an error) if the member isn't the
+// expected one. pubkey is only valid until the function returns.
+type MemberFunc func(i int, pubkey []byte) (pk, hp types.Point, err error)
+
+// VerifyStream verifies a serialized signature read from r for the given
+// message, one ring member at a time, so that memory usage doesn't depend on
+// the ring size. It's meant for rings too large to hold in memory; for other
+// rings, deserializing the signature and calling Verify is simpler.
+//
+// If member is nil, each public key is decoded and hashed to the curve during
+// verification; otherwise, member provides them.
+//
+// It returns nil if the signature is valid, ErrNotValidAt if the current time
+// is outside the signature's validity window, ErrInvalidSignature if the
+// signature is not valid, and any other error if the signature can't be read
+// or decoded, or member fails.
+func VerifyStream(curve types.Curve, m [32]byte, r io.Reader, member MemberFunc) (err error) {
+	defer recoverInternal(&err)
+
+	sr, err := NewSigReader(curve, r)
+	if err != nil {
+		return err
+	}
+	if !sr.ValidAt(time.Now()) {
+		return ErrNotValidAt
+	}
+	if !isTorsionFree(sr.image) {
+		return ErrInvalidSignature
+	}
+
+	ch, err := sr.ext.challenger(curve, sr.ext.message(m))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	c := sr.c
+	for i := 0; i < sr.size; i++ {
+		s, b, err := sr.nextEncoded()
+		if err != nil {
+			return err
+		}
+
+		var pk, hp types.Point
+		switch {
+		case member != nil && sr.enc == PointCompressed:
+			pk, hp, err = member(i, b)
+		case member != nil:
+			// members are always identified by their compressed encoding
+			if pk, err = sr.enc.decodePoint(curve, b); err == nil {
+				pk, hp, err = member(i, encodePoint(pk))
+			}
+		default:
+			if pk, err = sr.enc.decodePoint(curve, b); err == nil {
+				hp = hashToCurve(pk)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("ring member %d: %w", i, err)
+		}
+
+		// L_i = s_i*G + c_i*P_i, R_i = s_i*H_p(P_i) + c_i*I
+		l := ch.ops.doubleScalarBaseMul(c, pk, s)
+		r := ch.ops.twoScalarMul(c, sr.image, s, hp)
+		c = ch.challenge(l, r)
+	}
+
+	if _, _, err := sr.nextEncoded(); err != io.EOF {
+		return err
+	}
+
+	if !sr.c.Eq(c) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package ring
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/athanorlabs/go-dleq/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyStream(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		sig := createSigWithCurve(t, curve, 7, 3)
+		enc, err := sig.Serialize()
+		require.NoError(t, err)
+
+		require.NoError(t, VerifyStream(curve, testMsg, bytes.NewReader(enc), nil))
+		require.ErrorIs(t, VerifyStream(curve, [32]byte{1}, bytes.NewReader(enc), nil), ErrInvalidSignature)
+
+		// with the members provided by the caller
+		var seen []int
+		member := func(i int, pubkey []byte) (types.Point, types.Point, error) {
+			seen = append(seen, i)
+			require.Equal(t, sig.PublicKeys()[i].Encode(), pubkey)
+			pk := sig.PublicKeys()[i]
+			return pk, HashToCurve(pk), nil
+		}
+		require.NoError(t, VerifyStream(curve, testMsg, bytes.NewReader(enc), member))
+		require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, seen)
+
+		errReject := errors.New("not a member")
+		reject := func(i int, pubkey []byte) (types.Point, types.Point, error) {
+			return nil, nil, errReject
+		}
+		require.ErrorIs(t, VerifyStream(curve, testMsg, bytes.NewReader(enc), reject), errReject)
+	}
+}
+
+func TestVerifyStream_Malformed(t *testing.T) {
+	curve := Ed25519()
+	sig := createSigWithCurve(t, curve, 3, 1)
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+
+	for _, in := range [][]byte{
+		nil,
+		enc[:3],
+		enc[:len(enc)-1],
+		append(append([]byte{}, enc...), 0),
+	} {
+		require.Error(t, VerifyStream(curve, testMsg, bytes.NewReader(in), nil))
+	}
+
+	flagged := append([]byte{}, enc...)
+	flagged[0] = 0x80
+	require.Error(t, VerifyStream(curve, testMsg, bytes.NewReader(flagged), nil))
+}
+
+func TestVerifyStream_Validity(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 3, privKey, 2)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		notAfter time.Time
+		err      error
+	}{
+		{time.Now().Add(time.Hour), nil},
+		{time.Now().Add(-time.Hour), ErrNotValidAt},
+	} {
+		sig, err := keyring.Sign(testMsg, privKey, WithValidity(time.Time{}, tc.notAfter))
+		require.NoError(t, err)
+		enc, err := sig.Serialize()
+		require.NoError(t, err)
+		require.ErrorIs(t, VerifyStream(curve, testMsg, bytes.NewReader(enc), nil), tc.err)
+	}
+}
@@ -0,0 +1,55 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrInternal is returned, wrapped, when an operation fails on a condition
+// that the package doesn't expect to happen with well-formed inputs, eg. a
+// point or scalar of another backend than the ring's curve, or a failure of
+// the curve's hash-to-scalar. Such conditions used to panic; the error's
+// message holds the value the operation panicked with.
+var ErrInternal = errors.New("internal error")
+
+// strictMode is set by Strict.
+var strictMode atomic.Bool
+
+// Strict makes operations panic on internal errors instead of returning
+// ErrInternal, so that tests and fuzzers see the stack trace of the failure.
+// It applies to the whole process, and returns a function restoring the
+// previous mode, eg. for t.Cleanup:
+//
+//	t.Cleanup(ring.Strict())
+//
+// It's unrelated to WithStrict, which configures the checks of a Context.
+func Strict() (restore func()) {
+	prev := strictMode.Swap(true)
+	return func() {
+		strictMode.Store(prev)
+	}
+}
+
+// recoverInternal converts a panic of the calling function into an error
+// wrapping ErrInternal, stored in *err, unless strict mode is enabled. It must
+// be deferred directly by the function returning *err.
+func recoverInternal(err *error) {
+	if strictMode.Load() {
+		return
+	}
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("%w: %v", ErrInternal, r)
+	}
+}
+
+// recoverFalse is recoverInternal for functions returning a bool: a panic makes
+// the calling function return false.
+func recoverFalse(ok *bool) {
+	if strictMode.Load() {
+		return
+	}
+	if r := recover(); r != nil {
+		*ok = false
+	}
+}
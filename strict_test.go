@@ -0,0 +1,61 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInternalErrors(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 1)
+	require.NoError(t, err)
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+
+	// a scalar of another curve
+	edKey := Ed25519().NewRandomScalar()
+	_, err = keyring.Sign(testMsg, edKey, WithSignerIndex(1))
+	require.ErrorIs(t, err, ErrInternal)
+	_, err = NewKeyRing(curve, 4, edKey, 1)
+	require.ErrorIs(t, err, ErrInternal)
+	_, _, err = ProveKeyImage(curve, edKey)
+	require.ErrorIs(t, err, ErrInternal)
+
+	// a key image of another curve
+	bad := *sig
+	bad.image = Ed25519().ScalarBaseMul(edKey)
+	require.False(t, bad.Verify(testMsg))
+	require.ErrorIs(t, bad.VerifyWithPolicy(testMsg, nil), ErrInternal)
+	require.ErrorIs(t, bad.Report(testMsg).Err, ErrInternal)
+	_, err = bad.Relation(testMsg)
+	require.ErrorIs(t, err, ErrInternal)
+
+	_, err = NewKeyRing(curve, 4, privKey, -1)
+	require.Error(t, err)
+}
+
+func TestStrict(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 1)
+	require.NoError(t, err)
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+
+	bad := *sig
+	bad.image = Ed25519().ScalarBaseMul(Ed25519().NewRandomScalar())
+
+	restore := Strict()
+	require.Panics(t, func() { bad.Verify(testMsg) })
+	require.Panics(t, func() {
+		_, _ = keyring.Sign(testMsg, Ed25519().NewRandomScalar(), WithSignerIndex(1))
+	})
+
+	// strict mode doesn't change the behavior on valid inputs
+	require.True(t, sig.Verify(testMsg))
+
+	restore()
+	require.False(t, bad.Verify(testMsg))
+}
@@ -0,0 +1,79 @@
+package ring
+
+import (
+	"crypto/sha256"
+	"errors"
+	"slices"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// challengeTag is the BIP-340 tag of the challenge hash of signatures created
+// with WithTaggedHashes.
+const challengeTag = "ring-go/LSAG/challenge"
+
+// errTaggedHashCurve is returned when tagged hashes are used on a curve other
+// than secp256k1.
+var errTaggedHashCurve = errors.New("tagged hashes are only supported on secp256k1")
+
+// WithTaggedHashes computes the challenges with the BIP-340 tagged hash
+// SHA256(SHA256(tag) || SHA256(tag) || data), interpreted as a big-endian
+// integer modulo the group order, instead of the default SHA3-512 based hash,
+// for ecosystems that standardize on tagged hashes, such as Bitcoin's Taproot.
+// The tag is "ring-go/LSAG/challenge", and the data is the message and the
+// commitments L and R, as for the default hash.
+//
+// It's only supported on secp256k1: Sign returns an error on other curves.
+// Signatures using tagged hashes set a flag in the encoding's header, so
+// verifiers select the hash from the signature, and can't be decoded by
+// versions of this package that predate it.
+func WithTaggedHashes() SignOption {
+	return func(o *signOptions) {
+		o.ext.tagged = true
+	}
+}
+
+// TaggedHashes returns whether the signature's challenges are computed with
+// BIP-340 tagged hashes; see WithTaggedHashes.
+func (r *RingSig) TaggedHashes() bool {
+	return r.ext.tagged
+}
+
+// TaggedHash returns the BIP-340 tagged hash of the data:
+// SHA256(SHA256(tag) || SHA256(tag) || data[0] || data[1] || ...).
+func TaggedHash(tag string, data ...[]byte) [32]byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	_, _ = h.Write(tagHash[:])
+	_, _ = h.Write(tagHash[:])
+	for _, d := range data {
+		_, _ = h.Write(d)
+	}
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// challenger returns the challenger for the transcript message m of a
+// signature with the extensions e over the given curve, or an error if they
+// select a hash the curve doesn't support.
+func (e *extensions) challenger(curve types.Curve, m [32]byte) (*challenger, error) {
+	ch := newChallenger(curve, m)
+	if e.tagged {
+		if CurveIDOf(curve) != CurveSecp256k1 {
+			return nil, errTaggedHashCurve
+		}
+		ch.tagged = true
+	}
+	return ch, nil
+}
+
+// taggedChallenge returns the challenge of the transcript data with the
+// tagged hash, reduced modulo the order of secp256k1.
+func taggedChallenge(curve types.Curve, data []byte) types.Scalar {
+	h := TaggedHash(challengeTag, data)
+	// go-dleq's secp256k1 ScalarFromBytes takes little-endian bytes
+	slices.Reverse(h[:])
+	return curve.ScalarFromBytes(h)
+}
@@ -0,0 +1,68 @@
+package ring
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaggedHash(t *testing.T) {
+	tagHash := sha256.Sum256([]byte("BIP0340/challenge"))
+	expected := sha256.Sum256(append(append(tagHash[:], tagHash[:]...), "abcdef"...))
+	require.Equal(t, expected, TaggedHash("BIP0340/challenge", []byte("abc"), []byte("def")))
+	require.NotEqual(t, expected, TaggedHash("BIP0340/aux", []byte("abcdef")))
+}
+
+func TestSign_WithTaggedHashes(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 5, privKey, 2)
+	require.NoError(t, err)
+
+	sig, err := keyring.Sign(testMsg, privKey, WithTaggedHashes())
+	require.NoError(t, err)
+	require.True(t, sig.TaggedHashes())
+	require.True(t, sig.Verify(testMsg))
+	require.False(t, sig.Verify([32]byte{1}))
+
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+	require.Equal(t, flagTaggedHash, enc[0])
+	decoded := new(RingSig)
+	require.NoError(t, decoded.Deserialize(curve, enc))
+	require.True(t, decoded.TaggedHashes())
+	require.True(t, decoded.Verify(testMsg))
+	require.NoError(t, VerifyStream(curve, testMsg, bytes.NewReader(enc), nil))
+	ok, err := VerifyEncoded(VerifyVersion1, byte(CurveSecp256k1), enc, testMsg)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// the hash is selected by the header, which is bound into the transcript
+	enc[0] &^= flagTaggedHash
+	require.NoError(t, decoded.Deserialize(curve, enc))
+	require.False(t, decoded.Verify(testMsg))
+
+	// signatures with the default hash are unchanged
+	plain, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	require.False(t, plain.TaggedHashes())
+	require.True(t, Link(sig, plain))
+}
+
+func TestSign_WithTaggedHashes_Ed25519(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 3, privKey, 0)
+	require.NoError(t, err)
+	_, err = keyring.Sign(testMsg, privKey, WithTaggedHashes())
+	require.ErrorIs(t, err, errTaggedHashCurve)
+
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	sig.ext.tagged = true
+	require.False(t, sig.Verify(testMsg))
+	require.ErrorIs(t, sig.VerifyWithOpts(testMsg, nil), ErrInvalidSignature)
+	require.Error(t, sig.Report(testMsg).Err)
+}
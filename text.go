@@ -0,0 +1,191 @@
+package ring
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// Signatures, key images and public keys have a text encoding, for values that
+// are copied through logs, command lines and configuration files. It's
+//
+//	<kind><version>:<base64url(curve ID || encoding || checksum)>
+//
+// where kind is "ringsig", "ringimg" or "ringpub", version is 1, the encoding
+// is that of Serialize or the compressed point, the curve ID is a single byte
+// (see CurveID), and the checksum is the first 4 bytes of the SHA-256 hash of
+// everything before it, including the prefix. Base64url is used without
+// padding. The checksum catches truncated or mistyped values, and the prefix
+// values of the wrong kind; neither is a security measure.
+
+const (
+	textVersion     = "1"
+	textChecksumLen = 4
+
+	sigTextKind    = "ringsig"
+	imageTextKind  = "ringimg"
+	pubkeyTextKind = "ringpub"
+)
+
+// ErrInvalidText is returned when parsing a malformed text encoding, including
+// one with a wrong checksum.
+var ErrInvalidText = errors.New("invalid text encoding")
+
+var (
+	_ encoding.TextMarshaler   = (*RingSig)(nil)
+	_ encoding.TextUnmarshaler = (*RingSig)(nil)
+	_ fmt.Stringer             = (*RingSig)(nil)
+)
+
+// String returns the text encoding of the signature, which is parsed by
+// ParseRingSig. If the signature can't be serialized, it returns a description
+// of the error instead.
+func (sig *RingSig) String() string {
+	text, err := sig.MarshalText()
+	if err != nil {
+		return fmt.Sprintf("<invalid ring signature: %s>", err)
+	}
+	return string(text)
+}
+
+// MarshalText implements encoding.TextMarshaler with the text encoding of the
+// signature, so that signatures are encoded as strings in JSON.
+func (sig *RingSig) MarshalText() ([]byte, error) {
+	enc, err := sig.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return encodeText(sigTextKind, CurveIDOf(sig.ring.curve), enc)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, decoding the text
+// encoding of a signature into sig.
+func (sig *RingSig) UnmarshalText(text []byte) error {
+	curveID, enc, err := decodeText(sigTextKind, string(text))
+	if err != nil {
+		return err
+	}
+	curve, err := curveID.Curve()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidText, err)
+	}
+	return sig.Deserialize(curve, enc)
+}
+
+// ParseRingSig parses the text encoding of a signature returned by
+// RingSig.String.
+func ParseRingSig(s string) (*RingSig, error) {
+	sig := new(RingSig)
+	if err := sig.UnmarshalText([]byte(s)); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// KeyImageString returns the text encoding of a key image, which is parsed by
+// ParseKeyImage. The image is encoded as given; use NormalizeKeyImage first if
+// the strings are compared.
+func KeyImageString(image types.Point) string {
+	return pointText(imageTextKind, image)
+}
+
+// ParseKeyImage parses the text encoding of a key image returned by
+// KeyImageString.
+func ParseKeyImage(s string) (types.Point, error) {
+	return parsePointText(imageTextKind, s)
+}
+
+// PublicKeyString returns the text encoding of a public key, which is parsed
+// by ParsePublicKey.
+func PublicKeyString(pubkey types.Point) string {
+	return pointText(pubkeyTextKind, pubkey)
+}
+
+// ParsePublicKey parses the text encoding of a public key returned by
+// PublicKeyString.
+func ParsePublicKey(s string) (types.Point, error) {
+	return parsePointText(pubkeyTextKind, s)
+}
+
+func pointText(kind string, p types.Point) string {
+	id := CurveIDOfPoint(p)
+	if id == CurveUnknown {
+		return fmt.Sprintf("<unsupported point type %T>", p)
+	}
+	text, err := encodeText(kind, id, encodePoint(p))
+	if err != nil {
+		return fmt.Sprintf("<invalid point: %s>", err)
+	}
+	return string(text)
+}
+
+func parsePointText(kind, s string) (types.Point, error) {
+	curveID, enc, err := decodeText(kind, s)
+	if err != nil {
+		return nil, err
+	}
+	curve, err := curveID.Curve()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidText, err)
+	}
+	if len(enc) != curve.CompressedPointSize() {
+		return nil, fmt.Errorf("%w: point length %d", ErrInvalidText, len(enc))
+	}
+	return curve.DecodeToPoint(enc)
+}
+
+func textChecksum(prefix string, body []byte) []byte {
+	h := sha256.New()
+	_, _ = h.Write([]byte(prefix))
+	_, _ = h.Write(body)
+	return h.Sum(nil)[:textChecksumLen]
+}
+
+func encodeText(kind string, curveID CurveID, enc []byte) ([]byte, error) {
+	if curveID == CurveUnknown {
+		return nil, errors.New("unsupported curve")
+	}
+	prefix := kind + textVersion + ":"
+
+	body := make([]byte, 0, 1+len(enc)+textChecksumLen)
+	body = append(body, byte(curveID))
+	body = append(body, enc...)
+	body = append(body, textChecksum(prefix, body)...)
+
+	out := make([]byte, len(prefix), len(prefix)+base64.RawURLEncoding.EncodedLen(len(body)))
+	copy(out, prefix)
+	return base64.RawURLEncoding.AppendEncode(out, body), nil
+}
+
+// decodeText checks the prefix and checksum of a text encoding of the given
+// kind, and returns the curve ID and encoding it holds.
+func decodeText(kind, s string) (CurveID, []byte, error) {
+	prefix, data, ok := strings.Cut(s, ":")
+	if !ok || !strings.HasPrefix(prefix, kind) {
+		return 0, nil, fmt.Errorf("%w: expected prefix %q", ErrInvalidText, kind+textVersion+":")
+	}
+	if version := prefix[len(kind):]; version != textVersion {
+		return 0, nil, fmt.Errorf("%w: unsupported version %q", ErrInvalidText, version)
+	}
+
+	body, err := base64.RawURLEncoding.Strict().DecodeString(data)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: %w", ErrInvalidText, err)
+	}
+	if len(body) < 1+textChecksumLen {
+		return 0, nil, fmt.Errorf("%w: too short", ErrInvalidText)
+	}
+
+	body, checksum := body[:len(body)-textChecksumLen], body[len(body)-textChecksumLen:]
+	if !bytes.Equal(checksum, textChecksum(prefix+":", body)) {
+		return 0, nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidText)
+	}
+
+	return CurveID(body[0]), body[1:], nil
+}
@@ -0,0 +1,91 @@
+package ring
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingSig_String(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 4, privKey, 2)
+		require.NoError(t, err)
+		sig, err := keyring.Sign(testMsg, privKey, WithChainID(3))
+		require.NoError(t, err)
+
+		s := sig.String()
+		require.True(t, strings.HasPrefix(s, "ringsig1:"))
+		res, err := ParseRingSig(s)
+		require.NoError(t, err)
+		require.True(t, res.Equal(sig))
+		require.Equal(t, curve, res.Ring().Curve())
+
+		// signatures are strings in JSON
+		type message struct {
+			Sig *RingSig `json:"sig"`
+		}
+		enc, err := json.Marshal(message{sig})
+		require.NoError(t, err)
+		require.Equal(t, `{"sig":"`+s+`"}`, string(enc))
+		var decoded message
+		require.NoError(t, json.Unmarshal(enc, &decoded))
+		require.True(t, decoded.Sig.Equal(sig))
+
+		image := sig.KeyImage()
+		imageStr := KeyImageString(image)
+		require.True(t, strings.HasPrefix(imageStr, "ringimg1:"))
+		parsedImage, err := ParseKeyImage(imageStr)
+		require.NoError(t, err)
+		require.True(t, parsedImage.Equals(image))
+
+		pubkey := curve.ScalarBaseMul(privKey)
+		pubStr := PublicKeyString(pubkey)
+		require.True(t, strings.HasPrefix(pubStr, "ringpub1:"))
+		parsedPub, err := ParsePublicKey(pubStr)
+		require.NoError(t, err)
+		require.True(t, parsedPub.Equals(pubkey))
+
+		// the kinds can't be confused
+		_, err = ParsePublicKey(imageStr)
+		require.ErrorIs(t, err, ErrInvalidText)
+		_, err = ParseKeyImage("ringimg1:" + strings.TrimPrefix(pubStr, "ringpub1:"))
+		require.ErrorIs(t, err, ErrInvalidText)
+	}
+}
+
+func TestParseText_Errors(t *testing.T) {
+	curve := Secp256k1()
+	pubkey := curve.ScalarBaseMul(curve.NewRandomScalar())
+	s := PublicKeyString(pubkey)
+
+	for name, in := range map[string]string{
+		"empty":     "",
+		"no prefix": strings.TrimPrefix(s, "ringpub1:"),
+		"version":   strings.Replace(s, "ringpub1:", "ringpub2:", 1),
+		"base64":    s + "!",
+		"padding":   s + "=",
+		"short":     "ringpub1:AAAA",
+		"truncated": s[:len(s)-2],
+	} {
+		_, err := ParsePublicKey(in)
+		require.ErrorIs(t, err, ErrInvalidText, name)
+	}
+
+	// any single-character change is caught by the checksum
+	for i := len("ringpub1:"); i < len(s); i++ {
+		b := []byte(s)
+		if b[i] == 'A' {
+			b[i] = 'B'
+		} else {
+			b[i] = 'A'
+		}
+		_, err := ParsePublicKey(string(b))
+		require.Error(t, err, "position %d", i)
+	}
+
+	_, err := ParseRingSig("ringsig1:" + strings.TrimPrefix(s, "ringpub1:"))
+	require.ErrorIs(t, err, ErrInvalidText)
+}
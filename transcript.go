@@ -0,0 +1,184 @@
+package ring
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Signatures may carry extension fields that are bound into the signing
+// transcript, such as a validity window. Each extension present in a signature
+// is signalled by a flag in the top byte of the encoded header, and its field
+// is encoded after the key image, in the order of the flag bits.
+//
+// Extensions are bound by signing a message derived from the message and the
+// extension fields, rather than the message itself (see extensions.message),
+// so signatures without extensions are unchanged.
+
+// Format flags, stored in the top byte of the signature header.
+const (
+	// flagValidity indicates that the signature has a validity window.
+	flagValidity byte = 1 << iota
+	// flagDigest indicates that the signature embeds the signed message.
+	flagDigest
+	// flagChainID indicates that the signature is bound to a chain ID.
+	flagChainID
+	// flagNetwork indicates that the signature is bound to a network name.
+	flagNetwork
+	// flagAudit indicates that the signature commits to the seed its decoy
+	// responses were derived from.
+	flagAudit
+	// flagTaggedHash indicates that the challenges are BIP-340 tagged
+	// hashes. It has no field.
+	flagTaggedHash
+)
+
+// knownFlags is the set of format flags supported by Deserialize.
+const knownFlags = flagValidity | flagDigest | flagChainID | flagNetwork | flagAudit | flagTaggedHash
+
+const transcriptDomain = "ring-go/transcript"
+
+// extensions holds the optional fields of a signature. A nil field is absent.
+type extensions struct {
+	validity *validity
+	digest   *[32]byte
+	chainID  *uint64
+	network  *[networkLen]byte
+	audit    *[auditCommitmentLen]byte
+	tagged   bool
+}
+
+// flags returns the format flags of the fields present.
+func (e *extensions) flags() byte {
+	var flags byte
+	if e.validity != nil {
+		flags |= flagValidity
+	}
+	if e.digest != nil {
+		flags |= flagDigest
+	}
+	if e.chainID != nil {
+		flags |= flagChainID
+	}
+	if e.network != nil {
+		flags |= flagNetwork
+	}
+	if e.audit != nil {
+		flags |= flagAudit
+	}
+	if e.tagged {
+		flags |= flagTaggedHash
+	}
+	return flags
+}
+
+// encode returns the encoding of the fields present, in flag bit order.
+func (e *extensions) encode() []byte {
+	var out []byte
+	if e.validity != nil {
+		out = e.validity.appendEncoding(out)
+	}
+	if e.digest != nil {
+		out = append(out, e.digest[:]...)
+	}
+	if e.chainID != nil {
+		out = binary.BigEndian.AppendUint64(out, *e.chainID)
+	}
+	if e.network != nil {
+		out = append(out, e.network[:]...)
+	}
+	if e.audit != nil {
+		out = append(out, e.audit[:]...)
+	}
+	return out
+}
+
+// extensionsLen returns the length of the encoding of the fields indicated by
+// the given flags.
+func extensionsLen(flags byte) int {
+	var n int
+	if flags&flagValidity != 0 {
+		n += validityLen
+	}
+	if flags&flagDigest != 0 {
+		n += digestLen
+	}
+	if flags&flagChainID != 0 {
+		n += chainIDLen
+	}
+	if flags&flagNetwork != 0 {
+		n += networkLen
+	}
+	if flags&flagAudit != 0 {
+		n += auditCommitmentLen
+	}
+	return n
+}
+
+// decodeExtensions decodes the fields indicated by flags from in, which must
+// be extensionsLen(flags) bytes long.
+func decodeExtensions(flags byte, in []byte) (extensions, error) {
+	var e extensions
+	if len(in) != extensionsLen(flags) {
+		return e, errors.New("invalid extensions length")
+	}
+
+	if flags&flagValidity != 0 {
+		v, err := decodeValidity(in[:validityLen])
+		if err != nil {
+			return e, err
+		}
+		e.validity = v
+		in = in[validityLen:]
+	}
+
+	if flags&flagDigest != 0 {
+		digest := [digestLen]byte(in[:digestLen])
+		e.digest = &digest
+		in = in[digestLen:]
+	}
+
+	if flags&flagChainID != 0 {
+		id := binary.BigEndian.Uint64(in[:chainIDLen])
+		if id == 0 {
+			return e, errors.New("invalid chain ID")
+		}
+		e.chainID = &id
+		in = in[chainIDLen:]
+	}
+
+	if flags&flagNetwork != 0 {
+		network := [networkLen]byte(in[:networkLen])
+		e.network = &network
+		in = in[networkLen:]
+	}
+
+	if flags&flagAudit != 0 {
+		commitment := [auditCommitmentLen]byte(in[:auditCommitmentLen])
+		e.audit = &commitment
+	}
+
+	e.tagged = flags&flagTaggedHash != 0
+
+	return e, nil
+}
+
+// message returns the message that is signed in place of m: m itself if no
+// fields are present, or a hash of m and the fields otherwise.
+func (e *extensions) message(m [32]byte) [32]byte {
+	flags := e.flags()
+	if flags == 0 {
+		return m
+	}
+
+	h := sha3.New256()
+	_, _ = h.Write([]byte(transcriptDomain))
+	_, _ = h.Write([]byte{flags})
+	_, _ = h.Write(e.encode())
+	_, _ = h.Write(m[:])
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
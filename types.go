@@ -1,6 +1,8 @@
 package ring
 
 import (
+	"fmt"
+
 	"github.com/athanorlabs/go-dleq/ed25519"
 	"github.com/athanorlabs/go-dleq/secp256k1"
 	"github.com/athanorlabs/go-dleq/types"
@@ -9,14 +11,107 @@ import (
 type (
 	// Curve represents an elliptic curve that can be used for signing.
 	Curve = types.Curve
+	// Scalar represents a scalar of a Curve, eg. a private key.
+	Scalar = types.Scalar
+	// Point represents a point on a Curve, eg. a public key or key image.
+	Point = types.Point
 )
 
-// Ed25519 returns a new ed25519 curve instance.
+// Ed25519 returns a new ed25519 curve instance. It implements CurveBackend.
 func Ed25519() types.Curve {
-	return ed25519.NewCurve()
+	return &ed25519Backend{Curve: ed25519.NewCurve()}
 }
 
-// Secp256k1 returns a new secp256k1 curve instance
+// Secp256k1 returns a new secp256k1 curve instance. It implements CurveBackend.
 func Secp256k1() types.Curve {
-	return secp256k1.NewCurve()
+	return &secp256k1Backend{Curve: secp256k1.NewCurve()}
+}
+
+// CurveID identifies one of the curves supported by this package, eg. in
+// encodings, metrics and traces.
+type CurveID byte
+
+const (
+	// CurveUnknown is returned by CurveIDOf for curves not supported by this
+	// package.
+	CurveUnknown CurveID = iota
+	// CurveSecp256k1 identifies the secp256k1 curve.
+	CurveSecp256k1
+	// CurveEd25519 identifies the ed25519 curve.
+	CurveEd25519
+)
+
+// CurveIDOf returns the ID of the given curve, or CurveUnknown if it is not one
+// of the curves returned by Secp256k1, Ed25519 or NewEd25519, the go-dleq
+// curves they are built on, or a curve registered with RegisterCurve.
+func CurveIDOf(curve types.Curve) CurveID {
+	switch curve.(type) {
+	case *secp256k1Backend, *secp256k1.CurveImpl:
+		return CurveSecp256k1
+	case *ed25519Backend, *edwards25519Backend, *ed25519.CurveImpl:
+		return CurveEd25519
+	}
+	if reg := registeredCurveOf(curve); reg != nil {
+		return reg.id
+	}
+	return CurveUnknown
+}
+
+// CurveIDOfPoint returns the ID of the curve of the given point, or
+// CurveUnknown if it is not a point of one of the curves supported by this
+// package or registered with RegisterCurve.
+func CurveIDOfPoint(p types.Point) CurveID {
+	switch p.(type) {
+	case *secp256k1.PointImpl:
+		return CurveSecp256k1
+	case *ed25519.PointImpl, *edPoint:
+		return CurveEd25519
+	}
+	if reg := registeredCurveOfPoint(p); reg != nil {
+		return reg.id
+	}
+	return CurveUnknown
+}
+
+// Curve returns a new instance of the curve with the given ID.
+func (id CurveID) Curve() (types.Curve, error) {
+	switch id {
+	case CurveSecp256k1:
+		return Secp256k1(), nil
+	case CurveEd25519:
+		return Ed25519(), nil
+	}
+	if reg := registeredCurveByID(id); reg != nil {
+		return reg.newCurve(), nil
+	}
+	return nil, fmt.Errorf("unknown curve ID %d", id)
+}
+
+// String returns the curve's name, eg. "secp256k1".
+func (id CurveID) String() string {
+	switch id {
+	case CurveSecp256k1:
+		return "secp256k1"
+	case CurveEd25519:
+		return "ed25519"
+	}
+	if reg := registeredCurveByID(id); reg != nil {
+		return reg.name
+	}
+	return "unknown"
+}
+
+// ParseCurveID returns the ID of the curve with the given name, as returned by
+// CurveID.String.
+func ParseCurveID(name string) (CurveID, error) {
+	switch name {
+	case "secp256k1":
+		return CurveSecp256k1, nil
+	case "ed25519":
+		return CurveEd25519, nil
+	}
+	if reg := findCurve(func(reg *registeredCurve) bool { return reg.name == name }); reg != nil {
+		return reg.id, nil
+	}
+	return CurveUnknown, fmt.Errorf("unknown curve %q", name)
 }
@@ -0,0 +1,34 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurveID(t *testing.T) {
+	for _, id := range []CurveID{CurveSecp256k1, CurveEd25519} {
+		curve, err := id.Curve()
+		require.NoError(t, err)
+		require.Equal(t, id, CurveIDOf(curve))
+	}
+
+	require.Equal(t, "secp256k1", CurveIDOf(Secp256k1()).String())
+	require.Equal(t, "ed25519", CurveIDOf(Ed25519()).String())
+	require.Equal(t, CurveUnknown, CurveIDOf(nil))
+	require.Equal(t, "unknown", CurveUnknown.String())
+
+	_, err := CurveUnknown.Curve()
+	require.Error(t, err)
+}
+
+func TestParseCurveID(t *testing.T) {
+	for _, id := range []CurveID{CurveSecp256k1, CurveEd25519} {
+		parsed, err := ParseCurveID(id.String())
+		require.NoError(t, err)
+		require.Equal(t, id, parsed)
+	}
+
+	_, err := ParseCurveID("unknown")
+	require.Error(t, err)
+}
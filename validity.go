@@ -0,0 +1,103 @@
+package ring
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrNotValidAt is returned by VerifyWithPolicy when a signature has a
+// validity window that doesn't contain the verification time.
+var ErrNotValidAt = errors.New("signature is not valid at the given time")
+
+const validityLen = 16
+
+// validity is a signature validity window, in Unix seconds, inclusive.
+type validity struct {
+	notBefore, notAfter uint64
+}
+
+// WithValidity binds a validity window into the signature, so that it only
+// verifies at times t with notBefore <= t <= notAfter, at a granularity of a
+// second. A zero notBefore or notAfter leaves that side of the window open.
+// Protocols using epoch numbers rather than timestamps can map epochs onto
+// times, eg. time.Unix(epoch, 0).
+//
+// The window is part of the signed transcript, so it can't be changed without
+// invalidating the signature. Sign fails if the window is empty or if either
+// time is before the Unix epoch.
+func WithValidity(notBefore, notAfter time.Time) SignOption {
+	return func(o *signOptions) {
+		v := &validity{notBefore: 0, notAfter: math.MaxUint64}
+		if !notBefore.IsZero() {
+			if notBefore.Unix() < 0 {
+				o.setErr(errors.New("validity start is before the Unix epoch"))
+				return
+			}
+			v.notBefore = uint64(notBefore.Unix())
+		}
+
+		if !notAfter.IsZero() {
+			if notAfter.Unix() < 0 {
+				o.setErr(errors.New("validity end is before the Unix epoch"))
+				return
+			}
+			v.notAfter = uint64(notAfter.Unix())
+		}
+
+		if v.notAfter < v.notBefore {
+			o.setErr(errors.New("validity end is before validity start"))
+			return
+		}
+
+		o.ext.validity = v
+	}
+}
+
+func (v *validity) contains(t time.Time) bool {
+	// times before the Unix epoch are only within windows without a start
+	u := uint64(max(t.Unix(), 0))
+	return v.notBefore <= u && u <= v.notAfter
+}
+
+func (v *validity) appendEncoding(out []byte) []byte {
+	out = binary.BigEndian.AppendUint64(out, v.notBefore)
+	return binary.BigEndian.AppendUint64(out, v.notAfter)
+}
+
+func decodeValidity(in []byte) (*validity, error) {
+	v := &validity{
+		notBefore: binary.BigEndian.Uint64(in[:8]),
+		notAfter:  binary.BigEndian.Uint64(in[8:16]),
+	}
+	if v.notAfter < v.notBefore {
+		return nil, errors.New("invalid validity window")
+	}
+	return v, nil
+}
+
+// Validity returns the signature's validity window, as set with WithValidity.
+// ok is false if the signature has no validity window. An open side of the
+// window is returned as the zero time.
+func (r *RingSig) Validity() (notBefore, notAfter time.Time, ok bool) {
+	v := r.ext.validity
+	if v == nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	if v.notBefore != 0 {
+		notBefore = time.Unix(int64(min(v.notBefore, math.MaxInt64)), 0)
+	}
+	if v.notAfter != math.MaxUint64 {
+		notAfter = time.Unix(int64(min(v.notAfter, math.MaxInt64)), 0)
+	}
+	return notBefore, notAfter, true
+}
+
+// VerifyAt verifies the ring signature for the given message like Verify, but
+// checks the signature's validity window, if any, against t rather than the
+// current time.
+func (sig *RingSig) VerifyAt(m [32]byte, t time.Time) bool {
+	return sig.verify(m, t, nil) == nil
+}
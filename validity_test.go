@@ -0,0 +1,95 @@
+package ring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithValidity(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1(), Ed25519()} {
+		privKey := curve.NewRandomScalar()
+		keyring, err := NewKeyRing(curve, 3, privKey, 1)
+		require.NoError(t, err)
+
+		notBefore := time.Unix(1_700_000_000, 0)
+		notAfter := notBefore.Add(time.Hour)
+		sig, err := keyring.Sign(testMsg, privKey, WithValidity(notBefore, notAfter))
+		require.NoError(t, err)
+
+		nb, na, ok := sig.Validity()
+		require.True(t, ok)
+		require.True(t, nb.Equal(notBefore))
+		require.True(t, na.Equal(notAfter))
+
+		require.True(t, sig.VerifyAt(testMsg, notBefore))
+		require.True(t, sig.VerifyAt(testMsg, notBefore.Add(time.Minute)))
+		require.True(t, sig.VerifyAt(testMsg, notAfter))
+		require.False(t, sig.VerifyAt(testMsg, notBefore.Add(-time.Second)))
+		require.False(t, sig.VerifyAt(testMsg, notAfter.Add(time.Second)))
+		require.False(t, sig.VerifyAt([32]byte{}, notBefore))
+
+		// the window has passed
+		require.False(t, sig.Verify(testMsg))
+		require.ErrorIs(t, sig.VerifyWithPolicy(testMsg, nil), ErrNotValidAt)
+
+		// the window survives serialization
+		enc, err := sig.Serialize()
+		require.NoError(t, err)
+		res := new(RingSig)
+		require.NoError(t, res.Deserialize(curve, enc))
+		require.True(t, res.Equal(sig))
+		require.True(t, res.VerifyAt(testMsg, notBefore))
+
+		// the window is bound into the transcript, so it can't be changed
+		enc[4+32+curve.CompressedPointSize()+15]++
+		require.NoError(t, res.Deserialize(curve, enc))
+		require.False(t, res.VerifyAt(testMsg, notBefore))
+	}
+}
+
+func TestWithValidity_OpenEnded(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 2, privKey, 0)
+	require.NoError(t, err)
+
+	notBefore := time.Now().Add(-time.Minute)
+	sig, err := keyring.Sign(testMsg, privKey, WithValidity(notBefore, time.Time{}))
+	require.NoError(t, err)
+	require.True(t, sig.Verify(testMsg))
+	require.False(t, sig.VerifyAt(testMsg, notBefore.Add(-time.Minute)))
+
+	_, na, ok := sig.Validity()
+	require.True(t, ok)
+	require.True(t, na.IsZero())
+
+	notAfter := time.Now().Add(time.Minute)
+	sig, err = keyring.Sign(testMsg, privKey, WithValidity(time.Time{}, notAfter))
+	require.NoError(t, err)
+	require.True(t, sig.Verify(testMsg))
+	require.True(t, sig.VerifyAt(testMsg, time.Unix(0, 0)))
+	require.False(t, sig.VerifyAt(testMsg, notAfter.Add(time.Minute)))
+}
+
+func TestWithValidity_Invalid(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 2, privKey, 0)
+	require.NoError(t, err)
+
+	now := time.Now()
+	_, err = keyring.Sign(testMsg, privKey, WithValidity(now, now.Add(-time.Hour)))
+	require.EqualError(t, err, "validity end is before validity start")
+
+	_, err = keyring.Sign(testMsg, privKey, WithValidity(time.Unix(-1, 0), now))
+	require.Error(t, err)
+
+	// signatures without a window have none
+	sig, err := keyring.Sign(testMsg, privKey)
+	require.NoError(t, err)
+	_, _, ok := sig.Validity()
+	require.False(t, ok)
+	require.True(t, sig.VerifyAt(testMsg, time.Unix(0, 0)))
+}
@@ -0,0 +1,301 @@
+// Package vc creates and verifies anonymous W3C verifiable credential proofs:
+// a "proof" block whose value is a ring signature over the credential, made
+// by one of the members of a ring of issuers identified by did:key
+// identifiers (see package did).
+//
+// The credential is signed in a canonical JSON form, so that re-serializing it
+// doesn't invalidate the proof:
+//
+//	signed, err := vc.Sign(credential, issuers, privKey)
+//	...
+//	trusted, err := vc.NewIssuerList(trustedDIDs)
+//	proof, sig, err := vc.Verify(signed, trusted)
+//
+// The canonical form sorts object keys by their UTF-8 encoding, removes
+// insignificant whitespace, and formats numbers like JavaScript. It matches
+// the JSON Canonicalization Scheme (RFC 8785) for credentials whose keys are
+// in the Basic Multilingual Plane.
+package vc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/sha3"
+
+	ring "github.com/pokt-network/ring-go"
+	"github.com/pokt-network/ring-go/did"
+)
+
+const (
+	// ProofType is the type of the proofs created by Sign.
+	ProofType = "RingSignatureLSAG2024"
+
+	// DefaultProofPurpose is the proof purpose used unless another one is
+	// set with WithProofPurpose.
+	DefaultProofPurpose = "assertionMethod"
+
+	messageDomain = "ring-go/vc"
+)
+
+// ErrUntrustedIssuer is returned by Verify when a member of the proof's ring is
+// not a trusted issuer.
+var ErrUntrustedIssuer = errors.New("ring member is not a trusted issuer")
+
+// Proof is the proof block added to credentials by Sign.
+type Proof struct {
+	Type         string `json:"type"`
+	Created      string `json:"created"`
+	ProofPurpose string `json:"proofPurpose"`
+	// Curve is the name of the ring's curve, eg. "ed25519".
+	Curve string `json:"curve"`
+	// Ring holds the did:key identifiers of the ring members, in order.
+	Ring []string `json:"ring"`
+	// ProofValue is the multibase base64url ("u" prefix) encoding of the
+	// serialized ring signature.
+	ProofValue string `json:"proofValue,omitempty"`
+}
+
+// Option is an option for Sign.
+type Option func(*options)
+
+type options struct {
+	created     time.Time
+	purpose     string
+	signOptions []ring.SignOption
+}
+
+// WithCreated sets the creation time of the proof, which defaults to the
+// current time.
+func WithCreated(t time.Time) Option {
+	return func(o *options) {
+		o.created = t
+	}
+}
+
+// WithProofPurpose sets the purpose of the proof, which defaults to
+// DefaultProofPurpose.
+func WithProofPurpose(purpose string) Option {
+	return func(o *options) {
+		o.purpose = purpose
+	}
+}
+
+// WithSignOptions sets options passed to ring.Ring.Sign.
+func WithSignOptions(opts ...ring.SignOption) Option {
+	return func(o *options) {
+		o.signOptions = opts
+	}
+}
+
+// Sign adds a proof to the JSON-encoded credential, signed with the private key
+// of one of the members of the issuers' ring, and returns the credential with
+// the proof. The credential must be a JSON object without a proof.
+func Sign(credential []byte, issuers *ring.Ring, privKey ring.Scalar, opts ...Option) ([]byte, error) {
+	o := options{created: time.Now(), purpose: DefaultProofPurpose}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	doc, err := decodeObject(credential)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := doc["proof"]; ok {
+		return nil, errors.New("credential already has a proof")
+	}
+
+	members, err := did.Members(issuers)
+	if err != nil {
+		return nil, err
+	}
+	proof := &Proof{
+		Type:         ProofType,
+		Created:      o.created.UTC().Format(time.RFC3339),
+		ProofPurpose: o.purpose,
+		Curve:        ring.CurveIDOf(issuers.Curve()).String(),
+		Ring:         members,
+	}
+
+	m, err := message(doc, proof)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := issuers.Sign(m, privKey, o.signOptions...)
+	if err != nil {
+		return nil, err
+	}
+	enc, err := sig.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	proof.ProofValue = "u" + base64.RawURLEncoding.EncodeToString(enc)
+
+	doc["proof"] = proof
+	return canonicalize(doc)
+}
+
+// Verify verifies the proof of a JSON-encoded credential signed with Sign, and
+// returns the proof and its ring signature. Every member of the proof's ring
+// must be a trusted issuer, otherwise it returns ErrUntrustedIssuer.
+//
+// Callers can use the signature's key image to link credentials signed by the
+// same issuer.
+func Verify(credential []byte, trusted *IssuerList) (*Proof, *ring.RingSig, error) {
+	doc, err := decodeObject(credential)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw, ok := doc["proof"]
+	if !ok {
+		return nil, nil, errors.New("credential has no proof")
+	}
+	delete(doc, "proof")
+	proof, err := decodeProof(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, member := range proof.Ring {
+		if !trusted.Contains(member) {
+			return nil, nil, fmt.Errorf("%w: member %d", ErrUntrustedIssuer, i)
+		}
+	}
+	issuers, err := (&did.Metadata{Curve: proof.Curve, Members: proof.Ring}).Ring()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	value, ok := strings.CutPrefix(proof.ProofValue, "u")
+	if !ok {
+		return nil, nil, errors.New("proof value is not base64url multibase")
+	}
+	enc, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid proof value: %w", err)
+	}
+	sig := new(ring.RingSig)
+	if err := sig.Deserialize(issuers.Curve(), enc); err != nil {
+		return nil, nil, fmt.Errorf("invalid proof value: %w", err)
+	}
+	if !sig.Ring().Equals(issuers) {
+		return nil, nil, errors.New("signature ring does not match the proof's ring")
+	}
+
+	m, err := message(doc, proof)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := sig.VerifyWithPolicy(m, nil); err != nil {
+		return nil, nil, err
+	}
+
+	return proof, sig, nil
+}
+
+// IssuerList is a set of trusted issuers, identified by did:key identifiers.
+type IssuerList struct {
+	keys map[string]bool
+}
+
+// NewIssuerList returns a list of the issuers with the given identifiers.
+func NewIssuerList(dids []string) (*IssuerList, error) {
+	l := &IssuerList{keys: make(map[string]bool, len(dids))}
+	for _, id := range dids {
+		pk, err := did.Parse(id)
+		if err != nil {
+			return nil, err
+		}
+		l.keys[string(pk.Encode())] = true
+	}
+	return l, nil
+}
+
+// Contains returns true if the identifier is that of a trusted issuer.
+// Identifiers are compared by public key, so DID URLs with fragments match.
+func (l *IssuerList) Contains(id string) bool {
+	pk, err := did.Parse(id)
+	return err == nil && l.keys[string(pk.Encode())]
+}
+
+// message returns the message signed for the credential (without its proof)
+// and the proof (without its value): a hash of their canonical forms.
+func message(doc map[string]interface{}, proof *Proof) ([32]byte, error) {
+	config := *proof
+	config.ProofValue = ""
+
+	h := sha3.New256()
+	_, _ = h.Write([]byte(messageDomain))
+	for _, v := range []interface{}{&config, doc} {
+		b, err := canonicalize(v)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		_, _ = h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(b))))
+		_, _ = h.Write(b)
+	}
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out, nil
+}
+
+func decodeProof(v interface{}) (*Proof, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	proof := new(Proof)
+	if err := json.Unmarshal(b, proof); err != nil {
+		return nil, fmt.Errorf("invalid proof: %w", err)
+	}
+	if proof.Type != ProofType {
+		return nil, fmt.Errorf("unsupported proof type %q", proof.Type)
+	}
+	return proof, nil
+}
+
+// decodeObject decodes a JSON object.
+func decodeObject(b []byte) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	if err := d.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid credential: %w", err)
+	}
+	if doc == nil {
+		return nil, errors.New("credential is not a JSON object")
+	}
+	if d.More() {
+		return nil, errors.New("invalid credential: trailing data")
+	}
+	return doc, nil
+}
+
+// canonicalize returns the canonical JSON encoding of v. encoding/json sorts
+// map keys, and formats float64 numbers like JavaScript.
+func canonicalize(v interface{}) ([]byte, error) {
+	// round-trip through generic values so that the fields of structs are
+	// sorted too
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,93 @@
+package vc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	ring "github.com/pokt-network/ring-go"
+	"github.com/pokt-network/ring-go/did"
+)
+
+const testCredential = `{
+	"@context": ["https://www.w3.org/2018/credentials/v1"],
+	"type": ["VerifiableCredential"],
+	"credentialSubject": {"id": "did:example:holder", "age": 21.0, "over18": true}
+}`
+
+func newIssuers(t *testing.T, curve ring.Curve) (*ring.Ring, ring.Scalar, *IssuerList) {
+	privKey := curve.NewRandomScalar()
+	issuers, err := ring.NewKeyRing(curve, 4, privKey, 2)
+	require.NoError(t, err)
+	dids, err := did.Members(issuers)
+	require.NoError(t, err)
+	trusted, err := NewIssuerList(dids)
+	require.NoError(t, err)
+	return issuers, privKey, trusted
+}
+
+func TestSignAndVerify(t *testing.T) {
+	for _, curve := range []ring.Curve{ring.Ed25519(), ring.Secp256k1()} {
+		issuers, privKey, trusted := newIssuers(t, curve)
+		created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		signed, err := Sign([]byte(testCredential), issuers, privKey, WithCreated(created))
+		require.NoError(t, err)
+
+		proof, sig, err := Verify(signed, trusted)
+		require.NoError(t, err)
+		require.Equal(t, ProofType, proof.Type)
+		require.Equal(t, "2024-01-02T03:04:05Z", proof.Created)
+		require.Equal(t, DefaultProofPurpose, proof.ProofPurpose)
+		require.True(t, sig.Ring().Equals(issuers))
+
+		// re-serializing the credential doesn't invalidate the proof
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal(signed, &doc))
+		indented, err := json.MarshalIndent(doc, "", "  ")
+		require.NoError(t, err)
+		_, _, err = Verify(indented, trusted)
+		require.NoError(t, err)
+
+		// but changing it does
+		doc["credentialSubject"].(map[string]interface{})["age"] = 17
+		tampered, err := json.Marshal(doc)
+		require.NoError(t, err)
+		_, _, err = Verify(tampered, trusted)
+		require.ErrorIs(t, err, ring.ErrInvalidSignature)
+
+		// and so does changing the proof's options
+		require.NoError(t, json.Unmarshal(signed, &doc))
+		doc["proof"].(map[string]interface{})["proofPurpose"] = "authentication"
+		tampered, err = json.Marshal(doc)
+		require.NoError(t, err)
+		_, _, err = Verify(tampered, trusted)
+		require.ErrorIs(t, err, ring.ErrInvalidSignature)
+	}
+}
+
+func TestVerify_UntrustedIssuer(t *testing.T) {
+	issuers, privKey, _ := newIssuers(t, ring.Ed25519())
+	_, _, other := newIssuers(t, ring.Ed25519())
+
+	signed, err := Sign([]byte(testCredential), issuers, privKey)
+	require.NoError(t, err)
+	_, _, err = Verify(signed, other)
+	require.ErrorIs(t, err, ErrUntrustedIssuer)
+}
+
+func TestSign_Errors(t *testing.T) {
+	issuers, privKey, trusted := newIssuers(t, ring.Ed25519())
+
+	for _, credential := range []string{`[]`, `null`, `{"a": 1} {}`, `{"proof": {}}`, `{`} {
+		_, err := Sign([]byte(credential), issuers, privKey)
+		require.Error(t, err, credential)
+	}
+
+	_, _, err := Verify([]byte(testCredential), trusted)
+	require.EqualError(t, err, "credential has no proof")
+	_, _, err = Verify([]byte(`{"proof": {"type": "Ed25519Signature2020"}}`), trusted)
+	require.Error(t, err)
+}
@@ -0,0 +1,118 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// ErrInsufficientWeight is returned, wrapped with the details, when a ring
+// doesn't hold the minimum total weight required by WithMinTotalWeight or
+// Ring.RequireWeight.
+var ErrInsufficientWeight = errors.New("ring has insufficient total weight")
+
+// WithWeights returns a copy of the ring carrying a weight for each member, in
+// the order of the public keys, eg. the members' stakes. The weights are
+// local metadata: they aren't part of the signature, of its encoding or of
+// the ring's hash, and decoded rings have none. Verifiers that require a
+// minimum weight check it on their own copy of the ring, after checking that
+// it's the signature's ring with Equals.
+//
+// The copy's H_p(P_i) cache is shared with the ring. Rings derived from it
+// with Canonicalize, Shuffle and ShuffledCopy carry the weights, reordered
+// with the members; other derived rings, eg. padded with PadTo, have none.
+func (r *Ring) WithWeights(weights []uint64) (*Ring, error) {
+	if len(weights) != len(r.pubkeys) {
+		return nil, fmt.Errorf("%d weights for a ring of %d members", len(weights), len(r.pubkeys))
+	}
+	var total uint64
+	for _, w := range weights {
+		var carry uint64
+		if total, carry = bits.Add64(total, w, 0); carry != 0 {
+			return nil, errors.New("total weight overflows uint64")
+		}
+	}
+
+	r.ensureHP()
+	perm := make([]int, len(r.pubkeys))
+	for i := range perm {
+		perm[i] = i
+	}
+	weighted := r.permute(perm)
+	weighted.weights = append([]uint64(nil), weights...)
+	return weighted, nil
+}
+
+// Weights returns a copy of the ring's weights, or nil if it has none; see
+// WithWeights.
+func (r *Ring) Weights() []uint64 {
+	if r.weights == nil {
+		return nil
+	}
+	return append([]uint64(nil), r.weights...)
+}
+
+// TotalWeight returns the sum of the ring's weights. ok is false if the ring
+// has none.
+func (r *Ring) TotalWeight() (total uint64, ok bool) {
+	if r.weights == nil {
+		return 0, false
+	}
+	for _, w := range r.weights {
+		total += w
+	}
+	return total, true
+}
+
+// RequireWeight returns an error wrapping ErrInsufficientWeight if the ring
+// has no weights, or if their total is less than minimum.
+func (r *Ring) RequireWeight(minimum uint64) error {
+	total, ok := r.TotalWeight()
+	switch {
+	case !ok:
+		return fmt.Errorf("%w: ring has no weights", ErrInsufficientWeight)
+	case total < minimum:
+		return fmt.Errorf("%w: total weight %d is less than the minimum of %d", ErrInsufficientWeight, total, minimum)
+	}
+	return nil
+}
+
+// WeightedAnonymity returns the effective size of the ring's anonymity set
+// when the members are weighted, eg. when an observer expects the signer to be
+// a member with a probability proportional to its stake: the exponential of
+// the Shannon entropy of the normalized weights. It's the size of the ring if
+// all the weights are equal, and tends to 1 as a single member holds most of
+// the weight; members with zero weight don't count.
+//
+// It returns the size of the ring if it has no weights, and 0 if their total
+// is 0.
+func (r *Ring) WeightedAnonymity() float64 {
+	total, ok := r.TotalWeight()
+	if !ok {
+		return float64(len(r.pubkeys))
+	}
+	if total == 0 {
+		return 0
+	}
+
+	var entropy float64
+	for _, w := range r.weights {
+		if w == 0 {
+			continue
+		}
+		p := float64(w) / float64(total)
+		entropy -= p * math.Log(p)
+	}
+	return math.Exp(entropy)
+}
+
+// WithMinTotalWeight makes Sign refuse rings whose total weight is less than
+// minimum, or that have no weights (see Ring.WithWeights), with an error
+// wrapping ErrInsufficientWeight, for protocols requiring that signers be
+// within a set holding at least a given stake.
+func WithMinTotalWeight(minimum uint64) SignOption {
+	return func(o *signOptions) {
+		o.minWeight = &minimum
+	}
+}
@@ -0,0 +1,90 @@
+package ring
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRing_WithWeights(t *testing.T) {
+	curve := Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 4, privKey, 1)
+	require.NoError(t, err)
+	require.Nil(t, keyring.Weights())
+	_, ok := keyring.TotalWeight()
+	require.False(t, ok)
+	require.Equal(t, 4.0, keyring.WeightedAnonymity())
+
+	_, err = keyring.WithWeights([]uint64{1, 2, 3})
+	require.Error(t, err)
+	_, err = keyring.WithWeights([]uint64{math.MaxUint64, 1, 0, 0})
+	require.ErrorContains(t, err, "overflows")
+
+	weights := []uint64{10, 20, 30, 40}
+	weighted, err := keyring.WithWeights(weights)
+	require.NoError(t, err)
+	weights[0] = 0
+	require.Equal(t, []uint64{10, 20, 30, 40}, weighted.Weights())
+	require.True(t, weighted.Equals(keyring))
+	require.Equal(t, keyring.Hash(), weighted.Hash())
+	total, ok := weighted.TotalWeight()
+	require.True(t, ok)
+	require.Equal(t, uint64(100), total)
+
+	canonical, perm := weighted.Canonicalize()
+	for i, j := range perm {
+		require.Equal(t, weighted.Weights()[j], canonical.Weights()[i])
+	}
+}
+
+func TestRing_WeightedAnonymity(t *testing.T) {
+	keyring := createSigWithCurve(t, Ed25519(), 4, 0).Ring()
+	for _, tc := range []struct {
+		weights  []uint64
+		expected float64
+	}{
+		{[]uint64{5, 5, 5, 5}, 4},
+		{[]uint64{1, 1, 0, 0}, 2},
+		{[]uint64{1, 0, 0, 0}, 1},
+		{[]uint64{0, 0, 0, 0}, 0},
+	} {
+		weighted, err := keyring.WithWeights(tc.weights)
+		require.NoError(t, err)
+		require.InDelta(t, tc.expected, weighted.WeightedAnonymity(), 1e-9, tc.weights)
+	}
+
+	skewed, err := keyring.WithWeights([]uint64{97, 1, 1, 1})
+	require.NoError(t, err)
+	require.Greater(t, skewed.WeightedAnonymity(), 1.0)
+	require.Less(t, skewed.WeightedAnonymity(), 2.0)
+}
+
+func TestSign_WithMinTotalWeight(t *testing.T) {
+	curve := Ed25519()
+	privKey := curve.NewRandomScalar()
+	keyring, err := NewKeyRing(curve, 3, privKey, 2)
+	require.NoError(t, err)
+
+	_, err = keyring.Sign(testMsg, privKey, WithMinTotalWeight(1))
+	require.ErrorIs(t, err, ErrInsufficientWeight)
+
+	weighted, err := keyring.WithWeights([]uint64{100, 200, 300})
+	require.NoError(t, err)
+	_, err = weighted.Sign(testMsg, privKey, WithMinTotalWeight(601))
+	require.ErrorIs(t, err, ErrInsufficientWeight)
+
+	sig, err := weighted.Sign(testMsg, privKey, WithMinTotalWeight(600))
+	require.NoError(t, err)
+	require.True(t, sig.Verify(testMsg))
+	require.NoError(t, sig.Ring().RequireWeight(600))
+
+	// decoded rings have no weights; verifiers check their own copy
+	enc, err := sig.Serialize()
+	require.NoError(t, err)
+	decoded := new(RingSig)
+	require.NoError(t, decoded.Deserialize(curve, enc))
+	require.ErrorIs(t, decoded.Ring().RequireWeight(600), ErrInsufficientWeight)
+	require.True(t, decoded.Ring().Equals(weighted))
+}
@@ -0,0 +1,84 @@
+package ring
+
+import (
+	"errors"
+
+	"filippo.io/edwards25519"
+	"filippo.io/edwards25519/field"
+	"github.com/athanorlabs/go-dleq/ed25519"
+	"github.com/athanorlabs/go-dleq/types"
+)
+
+// X25519 public keys only encode the Montgomery u-coordinate, which
+// corresponds to two Edwards points, P and -P. The sign bit passed to
+// PointFromX25519 selects between them and must be known out of band; XEdDSA
+// (as used by Signal) always uses a sign bit of 0, and keys converted with
+// X25519FromPoint carry the sign of the original point.
+
+// PointFromX25519 converts an X25519 public key, as used by WireGuard and
+// Noise-based protocols, into a public key that can be used in an Ed25519
+// ring, using the birational map y = (u - 1) / (u + 1).
+//
+// signBit is the sign of the Edwards x-coordinate and must be 0 or 1. It
+// returns an error if u does not correspond to a point in the prime-order
+// subgroup, such as the low-order points used in small-subgroup attacks.
+func PointFromX25519(u []byte, signBit byte) (types.Point, error) {
+	if len(u) != 32 {
+		return nil, errors.New("invalid x25519 public key length")
+	}
+
+	if signBit > 1 {
+		return nil, errors.New("invalid sign bit")
+	}
+
+	// as in RFC 7748, the most significant bit of u is ignored
+	uu, err := new(field.Element).SetBytes(u)
+	if err != nil {
+		return nil, err
+	}
+
+	one := new(field.Element).One()
+	denom := new(field.Element).Add(uu, one)
+	if denom.Equal(new(field.Element).Zero()) == 1 {
+		// u = -1 is not on the curve, and has no corresponding Edwards point
+		return nil, errors.New("invalid x25519 public key")
+	}
+
+	y := new(field.Element).Subtract(uu, one)
+	y.Multiply(y, denom.Invert(denom))
+
+	enc := y.Bytes()
+	enc[31] |= signBit << 7
+
+	p, err := new(edwards25519.Point).SetBytes(enc)
+	if err != nil {
+		return nil, errors.New("invalid x25519 public key")
+	}
+
+	point := ed25519.NewPoint(p)
+	if !isTorsionFree(point) {
+		return nil, errors.New("x25519 public key is not in the prime-order subgroup")
+	}
+
+	return point, nil
+}
+
+// X25519FromPoint converts an Ed25519 public key to its X25519 encoding, using
+// the birational map u = (1 + y) / (1 - y). It also returns the sign of the
+// Edwards x-coordinate, which is needed to convert the key back with
+// PointFromX25519.
+func X25519FromPoint(p types.Point) (u []byte, signBit byte, err error) {
+	switch p.(type) {
+	case *ed25519.PointImpl, *edPoint:
+	default:
+		return nil, 0, errors.New("point is not an ed25519 point")
+	}
+
+	enc := encodePoint(p)
+	point, err := new(edwards25519.Point).SetBytes(enc)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return point.BytesMontgomery(), enc[31] >> 7, nil
+}
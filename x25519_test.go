@@ -0,0 +1,65 @@
+package ring
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPointFromX25519(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privKey, err := ScalarFromEd25519Seed(priv.Seed())
+	require.NoError(t, err)
+	pubkey := Ed25519().ScalarBaseMul(privKey)
+
+	// the X25519 key for the same clamped scalar has the same u-coordinate
+	h := sha512.Sum512(priv.Seed())
+	xpriv, err := ecdh.X25519().NewPrivateKey(h[:32])
+	require.NoError(t, err)
+
+	u, signBit, err := X25519FromPoint(pubkey)
+	require.NoError(t, err)
+	require.Equal(t, xpriv.PublicKey().Bytes(), u)
+
+	res, err := PointFromX25519(xpriv.PublicKey().Bytes(), signBit)
+	require.NoError(t, err)
+	require.True(t, res.Equals(pubkey))
+
+	// the other sign bit gives the negated point
+	res, err = PointFromX25519(u, signBit^1)
+	require.NoError(t, err)
+	require.True(t, res.Add(pubkey).Equals(pubkey.Sub(pubkey)))
+}
+
+func TestPointFromX25519_Invalid(t *testing.T) {
+	_, err := PointFromX25519(make([]byte, 31), 0)
+	require.Error(t, err)
+
+	_, err = PointFromX25519(make([]byte, 32), 2)
+	require.Error(t, err)
+
+	// u = 0 and u = 1 are low-order points
+	low := make([]byte, 32)
+	_, err = PointFromX25519(low, 0)
+	require.Error(t, err)
+	low[0] = 1
+	_, err = PointFromX25519(low, 0)
+	require.Error(t, err)
+
+	// u = -1 has no corresponding Edwards point
+	minusOne := []byte{
+		0xec, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f,
+	}
+	_, err = PointFromX25519(minusOne, 0)
+	require.EqualError(t, err, "invalid x25519 public key")
+
+	_, _, err = X25519FromPoint(Secp256k1().BasePoint())
+	require.Error(t, err)
+}
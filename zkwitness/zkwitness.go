@@ -0,0 +1,227 @@
+// Package zkwitness exports the verification relations of ring signatures as
+// circuit inputs, so that a zero-knowledge proof that "k valid ring signatures
+// exist" (eg. a SNARK proving a batch of signatures at once) can be produced
+// by a separate prover. The circuit itself isn't part of this package.
+//
+// A Batch holds the relations (see ring.Relation) of signatures over a single
+// curve, with points as affine coordinates and scalars as integers. It's
+// encoded in JSON with integers as decimal strings, the format of gnark
+// witnesses, and flattened into a vector of integers by Inputs, in a canonical
+// order:
+//
+//	k
+//	for each signature:
+//	  message (as an integer, big-endian), I.x, I.y, n
+//	  for each member i < n:
+//	    P_i.x, P_i.y, H_p(P_i).x, H_p(P_i).y, c_i, s_i, L_i.x, L_i.y, R_i.x, R_i.y
+//
+// All values are below 2^256; circuits over smaller fields must split them
+// into limbs. On secp256k1, coordinates are the affine coordinates of the
+// short Weierstrass curve; on ed25519, those of the twisted Edwards curve
+// -x^2 + y^2 = 1 + d*x^2*y^2, as in RFC 8032.
+//
+// The challenges are computed as c_{i+1} = HashToScalar(m || L_i || R_i), with
+// compressed points, where HashToScalar reduces the SHA3-512 hash of its input
+// modulo the group order. On secp256k1, the reduced value is then written
+// left-aligned into 32 bytes, which shifts it left by its leading zero bytes,
+// and reduced again. The witness includes all intermediate values, so that the
+// circuit only needs to check each equation.
+package zkwitness
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"filippo.io/edwards25519"
+	"filippo.io/edwards25519/field"
+	dsecp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+// Scheme identifies the relation and the format of the witness.
+const Scheme = "ring-go/lsag/v1"
+
+// Point is a point in affine coordinates, as decimal strings.
+type Point struct {
+	X string `json:"x"`
+	Y string `json:"y"`
+}
+
+// Member is the witness of a ring member's step of the relation.
+type Member struct {
+	PublicKey Point  `json:"publicKey"`
+	HashPoint Point  `json:"hashPoint"`
+	Challenge string `json:"challenge"`
+	Response  string `json:"response"`
+	L         Point  `json:"l"`
+	R         Point  `json:"r"`
+}
+
+// Signature is the witness of a signature's relation.
+type Signature struct {
+	// Message is the hex-encoded 32-byte transcript message.
+	Message  string   `json:"message"`
+	KeyImage Point    `json:"keyImage"`
+	Members  []Member `json:"members"`
+}
+
+// Batch is the witness of a batch of signatures over a curve.
+type Batch struct {
+	Scheme     string      `json:"scheme"`
+	Curve      string      `json:"curve"`
+	Signatures []Signature `json:"signatures"`
+}
+
+// Build returns the witness of the given signatures, each over the message
+// with the same index. The signatures must be valid and over the same curve.
+func Build(sigs []*ring.RingSig, msgs [][32]byte) (*Batch, error) {
+	if len(sigs) == 0 {
+		return nil, errors.New("no signatures")
+	}
+	if len(sigs) != len(msgs) {
+		return nil, fmt.Errorf("%d signatures for %d messages", len(sigs), len(msgs))
+	}
+
+	curve := ring.CurveIDOf(sigs[0].Ring().Curve())
+	if curve == ring.CurveUnknown {
+		return nil, errors.New("unsupported curve")
+	}
+	batch := &Batch{
+		Scheme:     Scheme,
+		Curve:      curve.String(),
+		Signatures: make([]Signature, len(sigs)),
+	}
+
+	for i, sig := range sigs {
+		if id := ring.CurveIDOf(sig.Ring().Curve()); id != curve {
+			return nil, fmt.Errorf("signature %d is over %s, want %s", i, id, curve)
+		}
+		rel, err := sig.Relation(msgs[i])
+		if err != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, err)
+		}
+		if batch.Signatures[i], err = newSignature(rel); err != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, err)
+		}
+	}
+
+	return batch, nil
+}
+
+func newSignature(rel *ring.Relation) (Signature, error) {
+	image, err := affine(rel.Curve, rel.KeyImage)
+	if err != nil {
+		return Signature{}, err
+	}
+	sig := Signature{
+		Message:  hex.EncodeToString(rel.Message[:]),
+		KeyImage: image,
+		Members:  make([]Member, len(rel.Members)),
+	}
+
+	for i, m := range rel.Members {
+		member := Member{
+			Challenge: scalarInt(rel.Curve, m.Challenge).String(),
+			Response:  scalarInt(rel.Curve, m.Response).String(),
+		}
+		for _, p := range []struct {
+			dst *Point
+			src ring.Point
+		}{
+			{&member.PublicKey, m.PublicKey},
+			{&member.HashPoint, m.HashPoint},
+			{&member.L, m.L},
+			{&member.R, m.R},
+		} {
+			if *p.dst, err = affine(rel.Curve, p.src); err != nil {
+				return Signature{}, fmt.Errorf("member %d: %w", i, err)
+			}
+		}
+		sig.Members[i] = member
+	}
+
+	return sig, nil
+}
+
+// Inputs returns the witness flattened into a vector of integers, in the order
+// described in the package documentation.
+func (b *Batch) Inputs() ([]*big.Int, error) {
+	var out []*big.Int
+	add := func(values ...string) error {
+		for _, v := range values {
+			n, ok := new(big.Int).SetString(v, 10)
+			if !ok {
+				return fmt.Errorf("invalid integer %q", v)
+			}
+			out = append(out, n)
+		}
+		return nil
+	}
+
+	out = append(out, big.NewInt(int64(len(b.Signatures))))
+	for _, sig := range b.Signatures {
+		m, err := hex.DecodeString(sig.Message)
+		if err != nil || len(m) != 32 {
+			return nil, fmt.Errorf("invalid message %q", sig.Message)
+		}
+		out = append(out, new(big.Int).SetBytes(m))
+		if err := add(sig.KeyImage.X, sig.KeyImage.Y); err != nil {
+			return nil, err
+		}
+		out = append(out, big.NewInt(int64(len(sig.Members))))
+
+		for _, m := range sig.Members {
+			if err := add(m.PublicKey.X, m.PublicKey.Y, m.HashPoint.X, m.HashPoint.Y,
+				m.Challenge, m.Response, m.L.X, m.L.Y, m.R.X, m.R.Y); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// affine returns the affine coordinates of a point.
+func affine(curve ring.CurveID, p ring.Point) (Point, error) {
+	enc := p.Copy().Encode()
+	switch curve {
+	case ring.CurveSecp256k1:
+		pk, err := dsecp256k1.ParsePubKey(enc)
+		if err != nil {
+			return Point{}, err
+		}
+		return Point{X: pk.X().String(), Y: pk.Y().String()}, nil
+	case ring.CurveEd25519:
+		q, err := new(edwards25519.Point).SetBytes(enc)
+		if err != nil {
+			return Point{}, err
+		}
+		x, y, z, _ := q.ExtendedCoordinates()
+		zInv := new(field.Element).Invert(z)
+		x.Multiply(x, zInv)
+		y.Multiply(y, zInv)
+		return Point{X: leInt(x.Bytes()).String(), Y: leInt(y.Bytes()).String()}, nil
+	default:
+		return Point{}, fmt.Errorf("unsupported curve %s", curve)
+	}
+}
+
+// scalarInt returns the integer value of a scalar.
+func scalarInt(curve ring.CurveID, s ring.Scalar) *big.Int {
+	if curve == ring.CurveEd25519 {
+		return leInt(s.Encode())
+	}
+	return new(big.Int).SetBytes(s.Encode())
+}
+
+// leInt returns the integer with the given little-endian encoding.
+func leInt(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i, x := range b {
+		be[len(b)-1-i] = x
+	}
+	return new(big.Int).SetBytes(be)
+}
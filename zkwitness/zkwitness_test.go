@@ -0,0 +1,163 @@
+package zkwitness
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+
+	ring "github.com/pokt-network/ring-go"
+)
+
+var (
+	secpP, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	secpN, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	edP      = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	edL, _   = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	// d = -121665 / 121666
+	edD = new(big.Int).Mod(new(big.Int).Mul(big.NewInt(-121665), new(big.Int).ModInverse(big.NewInt(121666), edP)), edP)
+)
+
+func toInt(t *testing.T, s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	require.True(t, ok)
+	return n
+}
+
+// onCurve checks the curve equation.
+func onCurve(t *testing.T, curve ring.CurveID, p Point) {
+	x, y := toInt(t, p.X), toInt(t, p.Y)
+	switch curve {
+	case ring.CurveSecp256k1:
+		lhs := new(big.Int).Exp(y, big.NewInt(2), secpP)
+		rhs := new(big.Int).Exp(x, big.NewInt(3), secpP)
+		rhs.Add(rhs, big.NewInt(7)).Mod(rhs, secpP)
+		require.Equal(t, lhs, rhs)
+	case ring.CurveEd25519:
+		x2 := new(big.Int).Mul(x, x)
+		y2 := new(big.Int).Mul(y, y)
+		lhs := new(big.Int).Sub(y2, x2)
+		lhs.Mod(lhs, edP)
+		rhs := new(big.Int).Mul(edD, x2)
+		rhs.Mul(rhs, y2).Add(rhs, big.NewInt(1)).Mod(rhs, edP)
+		require.Equal(t, lhs, rhs)
+	}
+}
+
+// compress encodes an affine point, independently of the ring package.
+func compress(t *testing.T, curve ring.CurveID, p Point) []byte {
+	x, y := toInt(t, p.X), toInt(t, p.Y)
+	out := make([]byte, 32)
+	switch curve {
+	case ring.CurveSecp256k1:
+		prefix := byte(2 + y.Bit(0))
+		return append([]byte{prefix}, x.FillBytes(out)...)
+	default:
+		y.FillBytes(out)
+		for i, j := 0, 31; i < j; i, j = i+1, j-1 {
+			out[i], out[j] = out[j], out[i]
+		}
+		out[31] |= byte(x.Bit(0)) << 7
+		return out
+	}
+}
+
+// challenge computes HashToScalar as described in the package documentation.
+func challenge(curve ring.CurveID, in []byte) *big.Int {
+	h := sha3.Sum512(in)
+	if curve == ring.CurveEd25519 {
+		le := make([]byte, len(h))
+		for i, b := range h {
+			le[len(h)-1-i] = b
+		}
+		return new(big.Int).Mod(new(big.Int).SetBytes(le), edL)
+	}
+	reduced := new(big.Int).Mod(new(big.Int).SetBytes(h[:]), secpN)
+	var buf [32]byte
+	copy(buf[:], reduced.Bytes())
+	return new(big.Int).Mod(new(big.Int).SetBytes(buf[:]), secpN)
+}
+
+func TestBuild(t *testing.T) {
+	for _, curve := range []ring.Curve{ring.Secp256k1(), ring.Ed25519()} {
+		id := ring.CurveIDOf(curve)
+		var (
+			sigs []*ring.RingSig
+			msgs [][32]byte
+		)
+		for i := 0; i < 3; i++ {
+			privKey := curve.NewRandomScalar()
+			keyring, err := ring.NewKeyRing(curve, 3+i, privKey, i)
+			require.NoError(t, err)
+			msg := [32]byte{byte(i)}
+			sig, err := keyring.Sign(msg, privKey)
+			require.NoError(t, err)
+			sigs, msgs = append(sigs, sig), append(msgs, msg)
+		}
+
+		batch, err := Build(sigs, msgs)
+		require.NoError(t, err)
+		require.Equal(t, Scheme, batch.Scheme)
+		require.Equal(t, id.String(), batch.Curve)
+		require.Len(t, batch.Signatures, 3)
+
+		for i, sig := range batch.Signatures {
+			require.Len(t, sig.Members, 3+i)
+			onCurve(t, id, sig.KeyImage)
+			for j, m := range sig.Members {
+				for _, p := range []Point{m.PublicKey, m.HashPoint, m.L, m.R} {
+					onCurve(t, id, p)
+				}
+				require.Equal(t, sigs[i].Ring().PublicKeys()[j].Encode(), compress(t, id, m.PublicKey))
+
+				// the challenge chain, recomputed from the witness alone
+				in, err := hex.DecodeString(sig.Message)
+				require.NoError(t, err)
+				in = append(in, compress(t, id, m.L)...)
+				in = append(in, compress(t, id, m.R)...)
+				next := sig.Members[(j+1)%len(sig.Members)].Challenge
+				require.Equal(t, toInt(t, next), challenge(id, in), "signature %d, member %d", i, j)
+			}
+		}
+
+		enc, err := json.Marshal(batch)
+		require.NoError(t, err)
+		var decoded Batch
+		require.NoError(t, json.Unmarshal(enc, &decoded))
+		require.Equal(t, *batch, decoded)
+
+		inputs, err := decoded.Inputs()
+		require.NoError(t, err)
+		require.Len(t, inputs, 1+3*4+(3+4+5)*10)
+		require.Equal(t, int64(3), inputs[0].Int64())
+		require.Equal(t, int64(3), inputs[4].Int64())
+	}
+}
+
+func TestBuild_Errors(t *testing.T) {
+	curve := ring.Secp256k1()
+	privKey := curve.NewRandomScalar()
+	keyring, err := ring.NewKeyRing(curve, 3, privKey, 0)
+	require.NoError(t, err)
+	sig, err := keyring.Sign([32]byte{1}, privKey)
+	require.NoError(t, err)
+
+	_, err = Build(nil, nil)
+	require.Error(t, err)
+	_, err = Build([]*ring.RingSig{sig}, nil)
+	require.Error(t, err)
+	_, err = Build([]*ring.RingSig{sig}, [][32]byte{{2}})
+	require.ErrorIs(t, err, ring.ErrInvalidSignature)
+
+	edCurve := ring.Ed25519()
+	edPriv := edCurve.NewRandomScalar()
+	edRing, err := ring.NewKeyRing(edCurve, 3, edPriv, 0)
+	require.NoError(t, err)
+	edSig, err := edRing.Sign([32]byte{1}, edPriv)
+	require.NoError(t, err)
+	_, err = Build([]*ring.RingSig{sig, edSig}, [][32]byte{{1}, {1}})
+	require.Error(t, err)
+}